@@ -0,0 +1,93 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package decimaldigit implements a TokenFilter which normalizes
+// the decimal digits of every script, such as the Arabic-Indic
+// digits or the full width digits, to the ASCII digits 0 to 9.
+package decimaldigit
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+const Name = "decimal_digit"
+
+type DecimalDigitFilter struct {
+}
+
+func NewDecimalDigitFilter() *DecimalDigitFilter {
+	return &DecimalDigitFilter{}
+}
+
+func (f *DecimalDigitFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		token.Term = normalizeDigits(token.Term)
+	}
+	return input
+}
+
+// normalizeDigits replaces the decimal digits of the term with ASCII
+// digits, reusing the term as the digits are never any longer.
+func normalizeDigits(term []byte) []byte {
+	j := 0
+	for i := 0; i < len(term); {
+		if term[i] < utf8.RuneSelf {
+			term[j] = term[i]
+			i++
+			j++
+			continue
+		}
+		r, size := utf8.DecodeRune(term[i:])
+		if d, ok := digitValue(r); ok {
+			term[j] = '0' + d
+			j++
+		} else {
+			copy(term[j:], term[i:i+size])
+			j += size
+		}
+		i += size
+	}
+	return term[:j]
+}
+
+// digitValue returns the value of a decimal digit, as the decimal digits
+// of each script are encoded in runs of ten, from zero to nine.
+func digitValue(r rune) (byte, bool) {
+	for _, r16 := range unicode.Nd.R16 {
+		if rune(r16.Lo) <= r && r <= rune(r16.Hi) {
+			return byte((r - rune(r16.Lo)) % 10), true
+		}
+	}
+	for _, r32 := range unicode.Nd.R32 {
+		if rune(r32.Lo) <= r && r <= rune(r32.Hi) {
+			return byte((r - rune(r32.Lo)) % 10), true
+		}
+	}
+	return 0, false
+}
+
+func DecimalDigitFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	return NewDecimalDigitFilter(), nil
+}
+
+func init() {
+	err := registry.RegisterTokenFilter(Name, DecimalDigitFilterConstructor)
+	if err != nil {
+		panic(err)
+	}
+}
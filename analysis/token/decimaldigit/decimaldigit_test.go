@@ -0,0 +1,51 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decimaldigit
+
+import (
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+)
+
+func TestDecimalDigitFilter(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		// arabic-indic
+		{input: "٠١٢٣٤٥٦٧٨٩", output: "0123456789"},
+		// extended arabic-indic, devanagari and full width
+		{input: "۴२５", output: "425"},
+		// mathematical monospace, among several sets of digits
+		{input: "x𝟶𝟿y", output: "x09y"},
+		// other numbers, such as superscripts and roman numerals, are left
+		{input: "²Ⅻ", output: "²Ⅻ"},
+		{input: "room 42", output: "room 42"},
+		{input: "", output: ""},
+	}
+
+	filter := NewDecimalDigitFilter()
+	for _, test := range tests {
+		output := filter.Filter(analysis.TokenStream{
+			&analysis.Token{
+				Term: []byte(test.input),
+			},
+		})
+		if string(output[0].Term) != test.output {
+			t.Errorf("%s: expected %s, got %s", test.input, test.output, output[0].Term)
+		}
+	}
+}
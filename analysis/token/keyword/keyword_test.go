@@ -19,6 +19,9 @@ import (
 	"testing"
 
 	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/analysis/token/porter"
+	"github.com/blevesearch/bleve/v2/analysis/tokenmap"
+	"github.com/blevesearch/bleve/v2/registry"
 )
 
 func TestKeyWordMarkerFilter(t *testing.T) {
@@ -71,3 +74,45 @@ func TestKeyWordMarkerFilter(t *testing.T) {
 		t.Errorf("expected %#v got %#v", expectedTokenStream[0].KeyWord, ouputTokenStream[0].KeyWord)
 	}
 }
+
+func TestKeyWordMarkerFilterConstructor(t *testing.T) {
+	cache := registry.NewCache()
+	_, err := cache.DefineTokenMap("protected", map[string]interface{}{
+		"type":   tokenmap.Name,
+		"tokens": []interface{}{"walking"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter, err := cache.DefineTokenFilter("protect", map[string]interface{}{
+		"type":               Name,
+		"keywords_token_map": "protected",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stemmer, err := cache.TokenFilterNamed(porter.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := analysis.TokenStream{
+		&analysis.Token{
+			Term: []byte("walking"),
+		},
+		&analysis.Token{
+			Term: []byte("talking"),
+		},
+	}
+	output := stemmer.Filter(filter.Filter(input))
+	if string(output[0].Term) != "walking" || string(output[1].Term) != "talk" {
+		t.Errorf("expected keywords not to be stemmed, got %s %s", output[0].Term, output[1].Term)
+	}
+
+	_, err = cache.DefineTokenFilter("unprotected", map[string]interface{}{
+		"type": Name,
+	})
+	if err == nil {
+		t.Errorf("expected error for missing keywords_token_map")
+	}
+}
@@ -0,0 +1,90 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package patternreplace implements a TokenFilter which rewrites
+// the terms of tokens matching a regular expression.
+package patternreplace
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+const Name = "pattern_replace"
+
+// PatternReplaceFilter replaces the matches of a regular expression
+// in the terms of tokens, every match or only the first.  The
+// replacement may refer to submatches as $1 or ${name}, as for
+// regexp.Regexp.Expand.
+type PatternReplaceFilter struct {
+	r           *regexp.Regexp
+	replacement []byte
+	all         bool
+}
+
+func NewPatternReplaceFilter(r *regexp.Regexp, replacement []byte, all bool) *PatternReplaceFilter {
+	return &PatternReplaceFilter{
+		r:           r,
+		replacement: replacement,
+		all:         all,
+	}
+}
+
+func (f *PatternReplaceFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		if f.all {
+			token.Term = f.r.ReplaceAll(token.Term, f.replacement)
+			continue
+		}
+		match := f.r.FindSubmatchIndex(token.Term)
+		if match == nil {
+			continue
+		}
+		term := make([]byte, 0, len(token.Term))
+		term = append(term, token.Term[:match[0]]...)
+		term = f.r.Expand(term, f.replacement, token.Term, match)
+		token.Term = append(term, token.Term[match[1]:]...)
+	}
+	return input
+}
+
+func PatternReplaceFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	pattern, ok := config["pattern"].(string)
+	if !ok {
+		return nil, fmt.Errorf("must specify pattern")
+	}
+	r, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build pattern replace filter: %v", err)
+	}
+	var replacement []byte
+	if replacementVal, ok := config["replacement"].(string); ok {
+		replacement = []byte(replacementVal)
+	}
+	all := true
+	if allVal, ok := config["all"].(bool); ok {
+		all = allVal
+	}
+	return NewPatternReplaceFilter(r, replacement, all), nil
+}
+
+func init() {
+	err := registry.RegisterTokenFilter(Name, PatternReplaceFilterConstructor)
+	if err != nil {
+		panic(err)
+	}
+}
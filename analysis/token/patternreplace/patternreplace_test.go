@@ -0,0 +1,87 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package patternreplace
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+func TestPatternReplaceFilter(t *testing.T) {
+	tests := []struct {
+		config map[string]interface{}
+		input  []string
+		output []string
+	}{
+		{
+			config: map[string]interface{}{
+				"pattern": "-",
+			},
+			input:  []string{"wi-fi", "e-mail-address", "plain"},
+			output: []string{"wifi", "emailaddress", "plain"},
+		},
+		{
+			config: map[string]interface{}{
+				"pattern":     "(\\d+)-(\\d+)",
+				"replacement": "$2/$1",
+			},
+			input:  []string{"12-34-56", "no-digits"},
+			output: []string{"34/12-56", "no-digits"},
+		},
+		{
+			config: map[string]interface{}{
+				"pattern":     "a",
+				"replacement": "${0}${0}",
+				"all":         false,
+			},
+			input:  []string{"banana", "cherry"},
+			output: []string{"baanana", "cherry"},
+		},
+	}
+
+	for _, test := range tests {
+		cache := registry.NewCache()
+		filter, err := PatternReplaceFilterConstructor(test.config, cache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		input := make(analysis.TokenStream, 0, len(test.input))
+		for _, term := range test.input {
+			input = append(input, &analysis.Token{Term: []byte(term)})
+		}
+		var output []string
+		for _, token := range filter.Filter(input) {
+			output = append(output, string(token.Term))
+		}
+		if !reflect.DeepEqual(output, test.output) {
+			t.Errorf("%v: expected %v, got %v", test.config, test.output, output)
+		}
+	}
+}
+
+func TestPatternReplaceFilterConstructor(t *testing.T) {
+	cache := registry.NewCache()
+	for _, config := range []map[string]interface{}{
+		{},
+		{"pattern": "("},
+	} {
+		if _, err := PatternReplaceFilterConstructor(config, cache); err == nil {
+			t.Errorf("%v: expected error", config)
+		}
+	}
+}
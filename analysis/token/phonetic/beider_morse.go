@@ -0,0 +1,221 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package phonetic
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultBeiderMorseMaxVariants is the number of codes the Beider-Morse
+// approximation gives a term at most, unless the filter config sets a
+// number of its own.
+const DefaultBeiderMorseMaxVariants = 16
+
+// beiderMorseRule rewrites a run of letters as any of the ways it may
+// be pronounced, when followed by one of the letters of before, if set.
+type beiderMorseRule struct {
+	pattern string
+	before  string
+	codes   []string
+}
+
+// beiderMorseRules are the rewrites of runs of letters, longest first,
+// where S and Z are the sounds of sh and the s of vision, and x that of
+// the German ch.  Letters without a rule of their own are kept as they
+// are.
+var beiderMorseRules = []beiderMorseRule{
+	{pattern: "tsch", codes: []string{"tS"}},
+	{pattern: "szcz", codes: []string{"StS"}},
+	{pattern: "sch", codes: []string{"S", "sk"}},
+	{pattern: "chs", codes: []string{"ks"}},
+	{pattern: "tch", codes: []string{"tS"}},
+	{pattern: "dzh", codes: []string{"dZ"}},
+	{pattern: "ch", codes: []string{"S", "x", "tS"}},
+	{pattern: "sh", codes: []string{"S"}},
+	{pattern: "zh", codes: []string{"Z"}},
+	{pattern: "kh", codes: []string{"x"}},
+	{pattern: "ph", codes: []string{"f"}},
+	{pattern: "th", codes: []string{"t"}},
+	{pattern: "gh", codes: []string{"g"}},
+	{pattern: "ck", codes: []string{"k"}},
+	{pattern: "qu", codes: []string{"kv", "k"}},
+	{pattern: "cz", codes: []string{"tS"}},
+	{pattern: "cs", codes: []string{"tS", "ks"}},
+	{pattern: "sz", codes: []string{"s", "S"}},
+	{pattern: "zs", codes: []string{"Z"}},
+	{pattern: "rz", codes: []string{"rs", "rts", "Z"}},
+	{pattern: "tz", codes: []string{"ts"}},
+	{pattern: "ei", codes: []string{"aj", "e"}},
+	{pattern: "ey", codes: []string{"aj", "e"}},
+	{pattern: "ai", codes: []string{"aj", "e"}},
+	{pattern: "ay", codes: []string{"aj", "e"}},
+	{pattern: "ie", codes: []string{"i"}},
+	{pattern: "ee", codes: []string{"i"}},
+	{pattern: "ou", codes: []string{"u"}},
+	{pattern: "oo", codes: []string{"u"}},
+	{pattern: "au", codes: []string{"au", "o"}},
+	{pattern: "eu", codes: []string{"oj", "e"}},
+	{pattern: "ae", codes: []string{"e"}},
+	{pattern: "oe", codes: []string{"e"}},
+	{pattern: "ue", codes: []string{"u", "i"}},
+	{pattern: "c", before: "eiy", codes: []string{"ts", "s"}},
+	{pattern: "c", codes: []string{"k"}},
+	{pattern: "g", before: "eiy", codes: []string{"g", "Z", "dZ"}},
+	{pattern: "j", codes: []string{"j", "Z", "dZ"}},
+	{pattern: "h", codes: []string{""}},
+	{pattern: "q", codes: []string{"k"}},
+	{pattern: "w", codes: []string{"v"}},
+	{pattern: "x", codes: []string{"ks"}},
+	{pattern: "y", before: "aeiou", codes: []string{"j"}},
+	{pattern: "y", codes: []string{"i"}},
+	{pattern: "z", codes: []string{"s", "ts"}},
+}
+
+// beiderMorseFolding spells the letters with diacritics
+// as they would be without them.
+var beiderMorseFolding = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'å': "a", 'ą': "a",
+	'ä': "ae", 'æ': "ae",
+	'ç': "c", 'ć': "c",
+	'č': "tsch",
+	'ď': "d", 'đ': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ě': "e", 'ę': "e",
+	'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'ł': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ø': "o",
+	'ö': "oe", 'œ': "oe",
+	'ř': "rz",
+	'ś': "s",
+	'š': "sh", 'ş': "sh",
+	'ß': "ss",
+	'ť': "t",
+	'ù': "u", 'ú': "u", 'û': "u", 'ů': "u",
+	'ü': "ue",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z",
+	'ž': "zh",
+}
+
+// BeiderMorse returns at most maxVariants codes of the term, for the
+// ways it may be pronounced across the common European languages, or
+// none for terms without letters.  It approximates the generic mode of
+// Beider-Morse Phonetic Matching, branching on the ambiguous spellings
+// of names such as the ch of Schwarz and the j of Jakob, and merging
+// vowels sounding alike, without the full rule sets or the detection
+// of the language of the name.
+func BeiderMorse(term string, maxVariants int) []string {
+	letters := beiderMorseLetters(term)
+	if letters == "" {
+		return nil
+	}
+	variants := []string{""}
+	for i := 0; i < len(letters); {
+		rule := beiderMorseRuleAt(letters, i)
+		next := make([]string, 0, len(variants)*len(rule.codes))
+		for _, variant := range variants {
+			for _, code := range rule.codes {
+				if len(next) < maxVariants {
+					next = append(next, variant+code)
+				}
+			}
+		}
+		variants = next
+		i += len(rule.pattern)
+	}
+
+	rv := make([]string, 0, len(variants))
+	for _, variant := range variants {
+		code := beiderMorseApproximate(variant)
+		if code == "" {
+			continue
+		}
+		found := false
+		for _, existing := range rv {
+			if existing == code {
+				found = true
+				break
+			}
+		}
+		if !found {
+			rv = append(rv, code)
+		}
+	}
+	return rv
+}
+
+// beiderMorseLetters returns the letters of the term
+// in lower case, with their diacritics folded away.
+func beiderMorseLetters(term string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(term) {
+		if r >= 'a' && r <= 'z' {
+			b.WriteRune(r)
+		} else if folded, ok := beiderMorseFolding[r]; ok {
+			b.WriteString(folded)
+		} else if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// beiderMorseRuleAt returns the rule for the letters at the index,
+// keeping the letter as it is when no rule applies.
+func beiderMorseRuleAt(letters string, index int) beiderMorseRule {
+	for _, rule := range beiderMorseRules {
+		if !strings.HasPrefix(letters[index:], rule.pattern) {
+			continue
+		}
+		if rule.before != "" {
+			end := index + len(rule.pattern)
+			if end >= len(letters) || !strings.ContainsRune(rule.before, rune(letters[end])) {
+				continue
+			}
+		}
+		return rule
+	}
+	letter := letters[index:]
+	for j := range letter {
+		if j > 0 {
+			letter = letter[:j]
+			break
+		}
+	}
+	return beiderMorseRule{pattern: letter, codes: []string{letter}}
+}
+
+// beiderMorseApproximate merges the vowels sounding alike in any of
+// the languages, e and i, and o and u, and repeated sounds, so that
+// the codes match despite the differences in spelling remaining.
+func beiderMorseApproximate(code string) string {
+	var b strings.Builder
+	var last rune
+	for _, r := range code {
+		switch r {
+		case 'e':
+			r = 'i'
+		case 'o':
+			r = 'u'
+		}
+		if r != last {
+			b.WriteRune(r)
+			last = r
+		}
+	}
+	return b.String()
+}
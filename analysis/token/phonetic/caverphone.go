@@ -0,0 +1,122 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package phonetic
+
+import (
+	"regexp"
+	"strings"
+)
+
+// caverphoneRules are the rewrites of Caverphone 2.0, applied in order
+// to the lower case letters of a term, upper case letters and digits
+// marking the letters already rewritten.
+var caverphoneRules = func() []caverphoneRule {
+	rules := [][2]string{
+		{"e$", ""},
+		{"^cough", "cou2f"},
+		{"^rough", "rou2f"},
+		{"^tough", "tou2f"},
+		{"^enough", "enou2f"},
+		{"^trough", "trou2f"},
+		{"^gn", "2n"},
+		{"mb$", "m2"},
+		{"cq", "2q"},
+		{"ci", "si"},
+		{"ce", "se"},
+		{"cy", "sy"},
+		{"tch", "2ch"},
+		{"c", "k"},
+		{"q", "k"},
+		{"x", "k"},
+		{"v", "f"},
+		{"dg", "2g"},
+		{"tio", "sio"},
+		{"tia", "sia"},
+		{"d", "t"},
+		{"ph", "fh"},
+		{"b", "p"},
+		{"sh", "s2"},
+		{"z", "s"},
+		{"^[aeiou]", "A"},
+		{"[aeiou]", "3"},
+		{"j", "y"},
+		{"^y3", "Y3"},
+		{"^y", "A"},
+		{"y", "3"},
+		{"3gh3", "3kh3"},
+		{"gh", "22"},
+		{"g", "k"},
+		{"s+", "S"},
+		{"t+", "T"},
+		{"p+", "P"},
+		{"k+", "K"},
+		{"f+", "F"},
+		{"m+", "M"},
+		{"n+", "N"},
+		{"w3", "W3"},
+		{"wh3", "Wh3"},
+		{"w$", "3"},
+		{"w", "2"},
+		{"^h", "A"},
+		{"h", "2"},
+		{"r3", "R3"},
+		{"r$", "3"},
+		{"r", "2"},
+		{"l3", "L3"},
+		{"l$", "3"},
+		{"l", "2"},
+		{"2", ""},
+		{"3$", "A"},
+		{"3", ""},
+	}
+	rv := make([]caverphoneRule, len(rules))
+	for i, rule := range rules {
+		rv[i] = caverphoneRule{
+			pattern:     regexp.MustCompile(rule[0]),
+			replacement: rule[1],
+		}
+	}
+	return rv
+}()
+
+type caverphoneRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// caverphoneCodeLen is the length of Caverphone 2.0 codes.
+const caverphoneCodeLen = 10
+
+// Caverphone returns the Caverphone 2.0 code of the term, ten characters
+// padded with 1, or the empty string when the term has no letters a to z.
+// Caverphone was designed for matching the names of New Zealand electoral
+// rolls, so works best with English names.
+func Caverphone(term string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(term) {
+		if r >= 'a' && r <= 'z' {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	code := b.String()
+	for _, rule := range caverphoneRules {
+		code = rule.pattern.ReplaceAllLiteralString(code, rule.replacement)
+	}
+	code += strings.Repeat("1", caverphoneCodeLen)
+	return code[:caverphoneCodeLen]
+}
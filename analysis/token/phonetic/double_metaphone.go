@@ -0,0 +1,654 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package phonetic
+
+import (
+	"strings"
+)
+
+// DefaultDoubleMetaphoneMaxCodeLen is the length Double Metaphone
+// codes are cut to, unless the filter config sets a length of its own.
+const DefaultDoubleMetaphoneMaxCodeLen = 4
+
+// DoubleMetaphone returns the Double Metaphone codes of the term, of at
+// most maxCodeLen characters: the primary code, for the most common
+// pronunciation, followed by the alternate code when it differs, as
+// for names pronounced differently across languages, such as Schmidt,
+// which is coded both XMT and SMT.  There are no codes for terms without
+// any letters.
+func DoubleMetaphone(term string, maxCodeLen int) []string {
+	value := []rune(strings.ToUpper(strings.TrimSpace(term)))
+	if len(value) == 0 {
+		return nil
+	}
+	e := &doubleMetaphoneEncoder{
+		value:         value,
+		maxCodeLen:    maxCodeLen,
+		slavoGermanic: isSlavoGermanic(string(value)),
+	}
+	e.encode()
+	primary, alternate := e.primary.String(), e.alternate.String()
+	switch {
+	case primary == "" && alternate == "":
+		return nil
+	case primary == alternate || alternate == "":
+		return []string{primary}
+	case primary == "":
+		return []string{alternate}
+	}
+	return []string{primary, alternate}
+}
+
+// isSlavoGermanic reports whether the upper case value
+// looks like a name of Slavic or Germanic origin.
+func isSlavoGermanic(value string) bool {
+	return strings.ContainsAny(value, "WK") ||
+		strings.Contains(value, "CZ") || strings.Contains(value, "WITZ")
+}
+
+type doubleMetaphoneEncoder struct {
+	value         []rune
+	maxCodeLen    int
+	slavoGermanic bool
+
+	primary   strings.Builder
+	alternate strings.Builder
+}
+
+func (e *doubleMetaphoneEncoder) encode() {
+	index := 0
+	if e.contains(0, "GN", "KN", "PN", "WR", "PS") {
+		// silent first letter
+		index = 1
+	}
+	for !e.complete() && index < len(e.value) {
+		switch e.value[index] {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			if index == 0 {
+				e.add("A")
+			}
+			index++
+		case 'B':
+			e.add("P")
+			index = e.skip(index, 'B')
+		case 'Ç':
+			e.add("S")
+			index++
+		case 'C':
+			index = e.handleC(index)
+		case 'D':
+			index = e.handleD(index)
+		case 'F':
+			e.add("F")
+			index = e.skip(index, 'F')
+		case 'G':
+			index = e.handleG(index)
+		case 'H':
+			index = e.handleH(index)
+		case 'J':
+			index = e.handleJ(index)
+		case 'K':
+			e.add("K")
+			index = e.skip(index, 'K')
+		case 'L':
+			index = e.handleL(index)
+		case 'M':
+			e.add("M")
+			if e.conditionM0(index) {
+				index += 2
+			} else {
+				index++
+			}
+		case 'N':
+			e.add("N")
+			index = e.skip(index, 'N')
+		case 'Ñ':
+			e.add("N")
+			index++
+		case 'P':
+			index = e.handleP(index)
+		case 'Q':
+			e.add("K")
+			index = e.skip(index, 'Q')
+		case 'R':
+			index = e.handleR(index)
+		case 'S':
+			index = e.handleS(index)
+		case 'T':
+			index = e.handleT(index)
+		case 'V':
+			e.add("F")
+			index = e.skip(index, 'V')
+		case 'W':
+			index = e.handleW(index)
+		case 'X':
+			index = e.handleX(index)
+		case 'Z':
+			index = e.handleZ(index)
+		default:
+			index++
+		}
+	}
+}
+
+// add appends the code to both the primary and the alternate codes.
+func (e *doubleMetaphoneEncoder) add(code string) {
+	e.addPrimary(code)
+	e.addAlternate(code)
+}
+
+// addBoth appends the primary and alternate codes.
+func (e *doubleMetaphoneEncoder) addBoth(primary, alternate string) {
+	e.addPrimary(primary)
+	e.addAlternate(alternate)
+}
+
+func (e *doubleMetaphoneEncoder) addPrimary(code string) {
+	appendCode(&e.primary, code, e.maxCodeLen)
+}
+
+func (e *doubleMetaphoneEncoder) addAlternate(code string) {
+	appendCode(&e.alternate, code, e.maxCodeLen)
+}
+
+func appendCode(b *strings.Builder, code string, maxCodeLen int) {
+	if remaining := maxCodeLen - b.Len(); remaining < len(code) {
+		code = code[:remaining]
+	}
+	b.WriteString(code)
+}
+
+func (e *doubleMetaphoneEncoder) complete() bool {
+	return e.primary.Len() >= e.maxCodeLen && e.alternate.Len() >= e.maxCodeLen
+}
+
+// at returns the letter at the index, or 0 outside the value.
+func (e *doubleMetaphoneEncoder) at(index int) rune {
+	if index < 0 || index >= len(e.value) {
+		return 0
+	}
+	return e.value[index]
+}
+
+// skip returns the index after the letter at the index,
+// skipping the next letter too when it is the same.
+func (e *doubleMetaphoneEncoder) skip(index int, r rune) int {
+	if e.at(index+1) == r {
+		return index + 2
+	}
+	return index + 1
+}
+
+// contains reports whether any of the candidates, all of the
+// same length, is found in the value at the start index.
+func (e *doubleMetaphoneEncoder) contains(start int, candidates ...string) bool {
+	length := len(candidates[0])
+	if start < 0 || start+length > len(e.value) {
+		return false
+	}
+	target := string(e.value[start : start+length])
+	for _, candidate := range candidates {
+		if target == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *doubleMetaphoneEncoder) isVowel(index int) bool {
+	return strings.ContainsRune("AEIOUY", e.at(index))
+}
+
+func (e *doubleMetaphoneEncoder) last() int {
+	return len(e.value) - 1
+}
+
+func (e *doubleMetaphoneEncoder) handleC(index int) int {
+	switch {
+	case e.conditionC0(index):
+		// various germanic
+		e.add("K")
+		index += 2
+	case index == 0 && e.contains(index, "CAESAR"):
+		e.add("S")
+		index += 2
+	case e.contains(index, "CH"):
+		index = e.handleCH(index)
+	case e.contains(index, "CZ") && !e.contains(index-2, "WICZ"):
+		// Czerny
+		e.addBoth("S", "X")
+		index += 2
+	case e.contains(index+1, "CIA"):
+		// focaccia
+		e.add("X")
+		index += 3
+	case e.contains(index, "CC") && !(index == 1 && e.at(0) == 'M'):
+		// double cc, but not McClelland
+		return e.handleCC(index)
+	case e.contains(index, "CK", "CG", "CQ"):
+		e.add("K")
+		index += 2
+	case e.contains(index, "CI", "CE", "CY"):
+		// Italian or English
+		if e.contains(index, "CIO", "CIE", "CIA") {
+			e.addBoth("S", "X")
+		} else {
+			e.add("S")
+		}
+		index += 2
+	default:
+		e.add("K")
+		if e.contains(index+1, " C", " Q", " G") {
+			// Mac Caffrey, Mac Gregor
+			index += 3
+		} else if e.contains(index+1, "C", "K", "Q") && !e.contains(index+1, "CE", "CI") {
+			index += 2
+		} else {
+			index++
+		}
+	}
+	return index
+}
+
+func (e *doubleMetaphoneEncoder) conditionC0(index int) bool {
+	if e.contains(index, "CHIA") {
+		return true
+	}
+	if index <= 1 || e.isVowel(index-2) || !e.contains(index-1, "ACH") {
+		return false
+	}
+	c := e.at(index + 2)
+	return (c != 'I' && c != 'E') || e.contains(index-2, "BACHER", "MACHER")
+}
+
+func (e *doubleMetaphoneEncoder) handleCC(index int) int {
+	if e.contains(index+2, "I", "E", "H") && !e.contains(index+2, "HU") {
+		// bellocchio, but not bacchus
+		if (index == 1 && e.at(index-1) == 'A') || e.contains(index-1, "UCCEE", "UCCES") {
+			// accident, accede, succeed
+			e.add("KS")
+		} else {
+			// bacci, bertucci, other Italian
+			e.add("X")
+		}
+		return index + 3
+	}
+	// Pierce's rule
+	e.add("K")
+	return index + 2
+}
+
+func (e *doubleMetaphoneEncoder) handleCH(index int) int {
+	switch {
+	case index > 0 && e.contains(index, "CHAE"):
+		// Michael
+		e.addBoth("K", "X")
+	case e.conditionCH0(index), e.conditionCH1(index):
+		// Greek roots, such as chemistry and chorus, or
+		// Germanic or otherwise ch for a kh sound
+		e.add("K")
+	case index > 0:
+		if e.contains(0, "MC") {
+			e.add("K")
+		} else {
+			e.addBoth("X", "K")
+		}
+	default:
+		e.add("X")
+	}
+	return index + 2
+}
+
+func (e *doubleMetaphoneEncoder) conditionCH0(index int) bool {
+	if index != 0 {
+		return false
+	}
+	if !e.contains(index+1, "HARAC", "HARIS") && !e.contains(index+1, "HOR", "HYM", "HIA", "HEM") {
+		return false
+	}
+	return !e.contains(0, "CHORE")
+}
+
+func (e *doubleMetaphoneEncoder) conditionCH1(index int) bool {
+	return e.contains(0, "VAN ", "VON ") || e.contains(0, "SCH") ||
+		e.contains(index-2, "ORCHES", "ARCHIT", "ORCHID") ||
+		e.contains(index+2, "T", "S") ||
+		((e.contains(index-1, "A", "O", "U", "E") || index == 0) &&
+			(e.contains(index+2, "L", "R", "N", "M", "B", "H", "F", "V", "W", " ") || index+1 == e.last()))
+}
+
+func (e *doubleMetaphoneEncoder) handleD(index int) int {
+	switch {
+	case e.contains(index, "DG"):
+		if e.contains(index+2, "I", "E", "Y") {
+			// edge
+			e.add("J")
+			return index + 3
+		}
+		// edgar
+		e.add("TK")
+		return index + 2
+	case e.contains(index, "DT", "DD"):
+		e.add("T")
+		return index + 2
+	}
+	e.add("T")
+	return index + 1
+}
+
+func (e *doubleMetaphoneEncoder) handleG(index int) int {
+	switch {
+	case e.at(index+1) == 'H':
+		return e.handleGH(index)
+	case e.at(index+1) == 'N':
+		if index == 1 && e.isVowel(0) && !e.slavoGermanic {
+			e.addBoth("KN", "N")
+		} else if !e.contains(index+2, "EY") && e.at(index+1) != 'Y' && !e.slavoGermanic {
+			e.addBoth("N", "KN")
+		} else {
+			e.add("KN")
+		}
+		return index + 2
+	case e.contains(index+1, "LI") && !e.slavoGermanic:
+		e.addBoth("KL", "L")
+		return index + 2
+	case index == 0 && (e.at(index+1) == 'Y' ||
+		e.contains(index+1, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")):
+		// -ges-, -gep-, -gel-, -gie- at the beginning
+		e.addBoth("K", "J")
+		return index + 2
+	case (e.contains(index+1, "ER") || e.at(index+1) == 'Y') &&
+		!e.contains(0, "DANGER", "RANGER", "MANGER") &&
+		!e.contains(index-1, "E", "I") && !e.contains(index-1, "RGY", "OGY"):
+		// -ger-, -gy-
+		e.addBoth("K", "J")
+		return index + 2
+	case e.contains(index+1, "E", "I", "Y") || e.contains(index-1, "AGGI", "OGGI"):
+		// Italian biaggi
+		if e.contains(0, "VAN ", "VON ") || e.contains(0, "SCH") || e.contains(index+1, "ET") {
+			// obviously Germanic
+			e.add("K")
+		} else if e.contains(index+1, "IER") {
+			e.add("J")
+		} else {
+			e.addBoth("J", "K")
+		}
+		return index + 2
+	case e.at(index+1) == 'G':
+		e.add("K")
+		return index + 2
+	}
+	e.add("K")
+	return index + 1
+}
+
+func (e *doubleMetaphoneEncoder) handleGH(index int) int {
+	switch {
+	case index > 0 && !e.isVowel(index-1):
+		e.add("K")
+	case index == 0:
+		if e.at(index+2) == 'I' {
+			e.add("J")
+		} else {
+			e.add("K")
+		}
+	case (index > 1 && e.contains(index-2, "B", "H", "D")) ||
+		(index > 2 && e.contains(index-3, "B", "H", "D")) ||
+		(index > 3 && e.contains(index-4, "B", "H")):
+		// Parker's rule, as in hugh
+	default:
+		if index > 2 && e.at(index-1) == 'U' && e.contains(index-3, "C", "G", "L", "R", "T") {
+			// laugh, McLaughlin, cough, gough, rough, tough
+			e.add("F")
+		} else if index > 0 && e.at(index-1) != 'I' {
+			e.add("K")
+		}
+	}
+	return index + 2
+}
+
+func (e *doubleMetaphoneEncoder) handleH(index int) int {
+	// only kept when first or between vowels
+	if (index == 0 || e.isVowel(index-1)) && e.isVowel(index+1) {
+		e.add("H")
+		return index + 2
+	}
+	return index + 1
+}
+
+func (e *doubleMetaphoneEncoder) handleJ(index int) int {
+	if e.contains(index, "JOSE") || e.contains(0, "SAN ") {
+		// obviously Spanish, Jose, San Jacinto
+		if (index == 0 && e.at(index+4) == ' ') || len(e.value) == 4 || e.contains(0, "SAN ") {
+			e.add("H")
+		} else {
+			e.addBoth("J", "H")
+		}
+		return index + 1
+	}
+	switch {
+	case index == 0:
+		e.addBoth("J", "A")
+	case e.isVowel(index-1) && !e.slavoGermanic && (e.at(index+1) == 'A' || e.at(index+1) == 'O'):
+		e.addBoth("J", "H")
+	case index == e.last():
+		e.addPrimary("J")
+	case !e.contains(index+1, "L", "T", "K", "S", "N", "M", "B", "Z") && !e.contains(index-1, "S", "K", "L"):
+		e.add("J")
+	}
+	return e.skip(index, 'J')
+}
+
+func (e *doubleMetaphoneEncoder) handleL(index int) int {
+	if e.at(index+1) != 'L' {
+		e.add("L")
+		return index + 1
+	}
+	if e.conditionL0(index) {
+		// Spanish, as in cabrillo, gallegos
+		e.addPrimary("L")
+	} else {
+		e.add("L")
+	}
+	return index + 2
+}
+
+func (e *doubleMetaphoneEncoder) conditionL0(index int) bool {
+	if index == len(e.value)-3 && e.contains(index-1, "ILLO", "ILLA", "ALLE") {
+		return true
+	}
+	return (e.contains(len(e.value)-2, "AS", "OS") || e.contains(len(e.value)-1, "A", "O")) &&
+		e.contains(index-1, "ALLE")
+}
+
+func (e *doubleMetaphoneEncoder) conditionM0(index int) bool {
+	if e.at(index+1) == 'M' {
+		return true
+	}
+	// dumb, thumb, thumbelina
+	return e.contains(index-1, "UMB") && (index+1 == e.last() || e.contains(index+2, "ER"))
+}
+
+func (e *doubleMetaphoneEncoder) handleP(index int) int {
+	if e.at(index+1) == 'H' {
+		e.add("F")
+		return index + 2
+	}
+	e.add("P")
+	if e.contains(index+1, "P", "B") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (e *doubleMetaphoneEncoder) handleR(index int) int {
+	if index == e.last() && !e.slavoGermanic && e.contains(index-2, "IE") &&
+		!e.contains(index-4, "ME", "MA") {
+		// French, as in rogier, but not hochmeier
+		e.addAlternate("R")
+	} else {
+		e.add("R")
+	}
+	return e.skip(index, 'R')
+}
+
+func (e *doubleMetaphoneEncoder) handleS(index int) int {
+	switch {
+	case e.contains(index-1, "ISL", "YSL"):
+		// island, isle, carlisle, carlysle
+		return index + 1
+	case index == 0 && e.contains(index, "SUGAR"):
+		e.addBoth("X", "S")
+		return index + 1
+	case e.contains(index, "SH"):
+		if e.contains(index+1, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			// Germanic
+			e.add("S")
+		} else {
+			e.add("X")
+		}
+		return index + 2
+	case e.contains(index, "SIO", "SIA") || e.contains(index, "SIAN"):
+		// Italian and Armenian
+		if e.slavoGermanic {
+			e.add("S")
+		} else {
+			e.addBoth("S", "X")
+		}
+		return index + 3
+	case (index == 0 && e.contains(index+1, "M", "N", "L", "W")) || e.contains(index+1, "Z"):
+		// German and anglicisations, such as smith matching
+		// schmidt, and -sz- in Slavic languages
+		e.addBoth("S", "X")
+		if e.contains(index+1, "Z") {
+			return index + 2
+		}
+		return index + 1
+	case e.contains(index, "SC"):
+		return e.handleSC(index)
+	}
+	if index == e.last() && e.contains(index-2, "AI", "OI") {
+		// French, as in resnais, artois
+		e.addAlternate("S")
+	} else {
+		e.add("S")
+	}
+	if e.contains(index+1, "S", "Z") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (e *doubleMetaphoneEncoder) handleSC(index int) int {
+	switch {
+	case e.at(index+2) == 'H':
+		// Schlesinger's rule
+		if e.contains(index+3, "OO", "ER", "EN", "UY", "ED", "EM") {
+			// Dutch, as in school, schooner
+			if e.contains(index+3, "ER", "EN") {
+				// schermerhorn, schenker
+				e.addBoth("X", "SK")
+			} else {
+				e.add("SK")
+			}
+		} else if index == 0 && !e.isVowel(3) && e.at(3) != 'W' {
+			e.addBoth("X", "S")
+		} else {
+			e.add("X")
+		}
+	case e.contains(index+2, "I", "E", "Y"):
+		e.add("S")
+	default:
+		e.add("SK")
+	}
+	return index + 3
+}
+
+func (e *doubleMetaphoneEncoder) handleT(index int) int {
+	switch {
+	case e.contains(index, "TION"), e.contains(index, "TIA", "TCH"):
+		e.add("X")
+		return index + 3
+	case e.contains(index, "TH") || e.contains(index, "TTH"):
+		if e.contains(index+2, "OM", "AM") ||
+			// thomas, thames or Germanic
+			e.contains(0, "VAN ", "VON ") || e.contains(0, "SCH") {
+			e.add("T")
+		} else {
+			e.addBoth("0", "T")
+		}
+		return index + 2
+	}
+	e.add("T")
+	if e.contains(index+1, "T", "D") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (e *doubleMetaphoneEncoder) handleW(index int) int {
+	switch {
+	case e.contains(index, "WR"):
+		// also in the middle of a word
+		e.add("R")
+		return index + 2
+	case index == 0 && (e.isVowel(index+1) || e.contains(index, "WH")):
+		if e.isVowel(index + 1) {
+			// Wasserman matching Vasserman
+			e.addBoth("A", "F")
+		} else {
+			// Uomo matching Womo
+			e.add("A")
+		}
+	case (index == e.last() && e.isVowel(index-1)) ||
+		e.contains(index-1, "EWSKI", "EWSKY", "OWSKI", "OWSKY") || e.contains(0, "SCH"):
+		// Arnow matching Arnoff
+		e.addAlternate("F")
+	case e.contains(index, "WICZ", "WITZ"):
+		// Polish, as in filipowicz
+		e.addBoth("TS", "FX")
+		return index + 4
+	}
+	return index + 1
+}
+
+func (e *doubleMetaphoneEncoder) handleX(index int) int {
+	if index == 0 {
+		e.add("S")
+		return index + 1
+	}
+	if !(index == e.last() && (e.contains(index-3, "IAU", "EAU") || e.contains(index-2, "AU", "OU"))) {
+		// but not French, as in breaux
+		e.add("KS")
+	}
+	if e.contains(index+1, "C", "X") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (e *doubleMetaphoneEncoder) handleZ(index int) int {
+	if e.at(index+1) == 'H' {
+		// Chinese pinyin, as in zhao
+		e.add("J")
+		return index + 2
+	}
+	if e.contains(index+1, "ZO", "ZI", "ZA") || (e.slavoGermanic && index > 0 && e.at(index-1) != 'T') {
+		e.addBoth("S", "TS")
+	} else {
+		e.add("S")
+	}
+	return e.skip(index, 'Z')
+}
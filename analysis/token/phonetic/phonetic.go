@@ -0,0 +1,168 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package phonetic provides token filters replacing terms by codes of
+// the way they sound, so that names spelled differently but sounding
+// alike, such as Smith and Smyth, match.
+package phonetic
+
+import (
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+const (
+	DoubleMetaphoneName = "double_metaphone"
+	RefinedSoundexName  = "refined_soundex"
+	CaverphoneName      = "caverphone"
+	BeiderMorseName     = "beider_morse"
+)
+
+// Encoder returns the phonetic codes of a term, of which there may be
+// several for terms sounding more than one way, or none for terms which
+// cannot be encoded, such as numbers.
+type Encoder func(term string) []string
+
+// PhoneticFilter replaces each token by tokens for the phonetic codes of
+// its term, at the same position, or, unless replace is set, adds them
+// after the token, so that exact matches of the original score higher
+// than sound-alike matches.  Tokens which cannot be encoded, as well as
+// keywords, are kept as they are.
+type PhoneticFilter struct {
+	encoder Encoder
+	replace bool
+}
+
+func NewPhoneticFilter(encoder Encoder, replace bool) *PhoneticFilter {
+	return &PhoneticFilter{
+		encoder: encoder,
+		replace: replace,
+	}
+}
+
+func (f *PhoneticFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	rv := make(analysis.TokenStream, 0, len(input))
+	for _, token := range input {
+		if token.KeyWord {
+			rv = append(rv, token)
+			continue
+		}
+		codes := f.encoder(string(token.Term))
+		if !f.replace || len(codes) == 0 {
+			rv = append(rv, token)
+		}
+		for _, code := range codes {
+			if !f.replace && code == string(token.Term) {
+				continue
+			}
+			rv = append(rv, &analysis.Token{
+				Term:     []byte(code),
+				Start:    token.Start,
+				End:      token.End,
+				Position: token.Position,
+				Type:     token.Type,
+			})
+		}
+	}
+	return rv
+}
+
+// replaceOption returns the replace option of the filter config,
+// which replaces the original tokens unless set to false.
+func replaceOption(config map[string]interface{}) (bool, error) {
+	replace := true
+	if replaceVal, ok := config["replace"]; ok {
+		if replace, ok = replaceVal.(bool); !ok {
+			return false, fmt.Errorf("replace must be a boolean")
+		}
+	}
+	return replace, nil
+}
+
+func DoubleMetaphoneFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	replace, err := replaceOption(config)
+	if err != nil {
+		return nil, err
+	}
+	maxCodeLen := DefaultDoubleMetaphoneMaxCodeLen
+	if maxVal, ok := config["max_code_len"].(float64); ok {
+		maxCodeLen = int(maxVal)
+	}
+	if maxCodeLen < 1 {
+		return nil, fmt.Errorf("max_code_len must be positive")
+	}
+	return NewPhoneticFilter(func(term string) []string {
+		return DoubleMetaphone(term, maxCodeLen)
+	}, replace), nil
+}
+
+func RefinedSoundexFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	replace, err := replaceOption(config)
+	if err != nil {
+		return nil, err
+	}
+	return NewPhoneticFilter(singleCode(RefinedSoundex), replace), nil
+}
+
+func CaverphoneFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	replace, err := replaceOption(config)
+	if err != nil {
+		return nil, err
+	}
+	return NewPhoneticFilter(singleCode(Caverphone), replace), nil
+}
+
+func BeiderMorseFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	replace, err := replaceOption(config)
+	if err != nil {
+		return nil, err
+	}
+	maxVariants := DefaultBeiderMorseMaxVariants
+	if maxVal, ok := config["max_variants"].(float64); ok {
+		maxVariants = int(maxVal)
+	}
+	if maxVariants < 1 {
+		return nil, fmt.Errorf("max_variants must be positive")
+	}
+	return NewPhoneticFilter(func(term string) []string {
+		return BeiderMorse(term, maxVariants)
+	}, replace), nil
+}
+
+// singleCode adapts an encoding with a single code, which
+// is empty for terms which cannot be encoded, to an Encoder.
+func singleCode(encode func(string) string) Encoder {
+	return func(term string) []string {
+		if code := encode(term); code != "" {
+			return []string{code}
+		}
+		return nil
+	}
+}
+
+func init() {
+	for name, constructor := range map[string]registry.TokenFilterConstructor{
+		DoubleMetaphoneName: DoubleMetaphoneFilterConstructor,
+		RefinedSoundexName:  RefinedSoundexFilterConstructor,
+		CaverphoneName:      CaverphoneFilterConstructor,
+		BeiderMorseName:     BeiderMorseFilterConstructor,
+	} {
+		err := registry.RegisterTokenFilter(name, constructor)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package phonetic
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+func TestDoubleMetaphone(t *testing.T) {
+	tests := map[string][]string{
+		"Smith":      {"SM0", "XMT"},
+		"Schmidt":    {"XMT", "SMT"},
+		"Jose":       {"HS"},
+		"Arnow":      {"ARN", "ARNF"},
+		"Katherine":  {"K0RN", "KTRN"},
+		"Michael":    {"MKL", "MXL"},
+		"Xavier":     {"SF", "SFR"},
+		"Caesar":     {"SSR"},
+		"Gallegos":   {"KLKS", "KKS"},
+		"Filipowicz": {"FLPT", "FLPF"},
+		"Wasserman":  {"ASRM", "FSRM"},
+		"Edge":       {"AJ"},
+		"Edgar":      {"ATKR"},
+		"Breaux":     {"PR"},
+		"accident":   {"AKST"},
+		"Laugh":      {"LF"},
+		"Hugh":       {"H"},
+		"Czerny":     {"SRN", "XRN"},
+		"focaccia":   {"FKX"},
+		"Sugar":      {"XKR", "SKR"},
+		"School":     {"SKL"},
+		"island":     {"ALNT"},
+		"42":         nil,
+		"":           nil,
+	}
+	for term, want := range tests {
+		got := DoubleMetaphone(term, DefaultDoubleMetaphoneMaxCodeLen)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", term, want, got)
+		}
+	}
+
+	got := DoubleMetaphone("Filipowicz", 8)
+	if want := []string{"FLPTS", "FLPFX"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRefinedSoundex(t *testing.T) {
+	tests := map[string]string{
+		"testing": "T6036084",
+		"TESTING": "T6036084",
+		"The":     "T60",
+		"quick":   "Q503",
+		"brown":   "B1908",
+		"fox":     "F205",
+		"jumped":  "J408106",
+		"over":    "O0209",
+		"lazy":    "L7050",
+		"dogs":    "D6043",
+		"O'Hara":  "O090",
+		"42":      "",
+	}
+	for term, want := range tests {
+		if got := RefinedSoundex(term); got != want {
+			t.Errorf("%s: expected %s, got %s", term, want, got)
+		}
+	}
+}
+
+func TestCaverphone(t *testing.T) {
+	tests := map[string]string{
+		"Peter":      "PTA1111111",
+		"ready":      "RTA1111111",
+		"social":     "SSA1111111",
+		"able":       "APA1111111",
+		"Tedder":     "TTA1111111",
+		"Karleen":    "KLN1111111",
+		"Dyun":       "TN11111111",
+		"Stevenson":  "STFNSN1111",
+		"Stephenson": "STFNSN1111",
+		"Smyth":      "SMT1111111",
+		"42":         "",
+	}
+	for term, want := range tests {
+		if got := Caverphone(term); got != want {
+			t.Errorf("%s: expected %s, got %s", term, want, got)
+		}
+	}
+}
+
+func TestBeiderMorse(t *testing.T) {
+	tests := map[string][]string{
+		"Schwarz": {"Svars", "Svarts", "SvaZ", "skvars", "skvarts", "skvaZ"},
+		"Shwartz": {"Svarts"},
+		"Meyer":   {"majir", "mir"},
+		"Müller":  {"mulir", "milir"},
+		"Mueller": {"mulir", "milir"},
+		"Jakob":   {"jakub", "Zakub", "dZakub"},
+		"Szabo":   {"sabu", "Sabu"},
+		"42":      nil,
+	}
+	for term, want := range tests {
+		got := BeiderMorse(term, DefaultBeiderMorseMaxVariants)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", term, want, got)
+		}
+	}
+
+	if got := BeiderMorse("Schwarz", 2); !reflect.DeepEqual(got, []string{"Svars", "Svarts"}) {
+		t.Errorf("expected variants to be limited, got %v", got)
+	}
+}
+
+func TestPhoneticFilter(t *testing.T) {
+	input := func() analysis.TokenStream {
+		return analysis.TokenStream{
+			&analysis.Token{Term: []byte("smith"), Start: 0, End: 5, Position: 1, Type: analysis.AlphaNumeric},
+			&analysis.Token{Term: []byte("42"), Start: 6, End: 8, Position: 2, Type: analysis.Numeric},
+			&analysis.Token{Term: []byte("jones"), Start: 9, End: 14, Position: 3, Type: analysis.AlphaNumeric, KeyWord: true},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		output []string
+		pos    []int
+	}{
+		{
+			name:   DoubleMetaphoneName,
+			config: map[string]interface{}{},
+			output: []string{"SM0", "XMT", "42", "jones"},
+			pos:    []int{1, 1, 2, 3},
+		},
+		{
+			name:   DoubleMetaphoneName,
+			config: map[string]interface{}{"replace": false, "max_code_len": 2.0},
+			output: []string{"smith", "SM", "XM", "42", "jones"},
+			pos:    []int{1, 1, 1, 2, 3},
+		},
+		{
+			name:   RefinedSoundexName,
+			config: map[string]interface{}{"replace": false},
+			output: []string{"smith", "S38060", "42", "jones"},
+			pos:    []int{1, 1, 2, 3},
+		},
+		{
+			name:   CaverphoneName,
+			config: map[string]interface{}{},
+			output: []string{"SMT1111111", "42", "jones"},
+			pos:    []int{1, 2, 3},
+		},
+		{
+			name:   BeiderMorseName,
+			config: map[string]interface{}{"max_variants": 1.0},
+			output: []string{"smit", "42", "jones"},
+			pos:    []int{1, 2, 3},
+		},
+	}
+
+	cache := registry.NewCache()
+	for i, test := range tests {
+		config := map[string]interface{}{"type": test.name}
+		for k, v := range test.config {
+			config[k] = v
+		}
+		filter, err := cache.DefineTokenFilter(fmt.Sprintf("phonetic%d", i), config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		output := filter.Filter(input())
+		var terms []string
+		var positions []int
+		for _, token := range output {
+			terms = append(terms, string(token.Term))
+			positions = append(positions, token.Position)
+		}
+		if !reflect.DeepEqual(terms, test.output) || !reflect.DeepEqual(positions, test.pos) {
+			t.Errorf("%s %v: expected %v at %v, got %v at %v", test.name, test.config,
+				test.output, test.pos, terms, positions)
+		}
+	}
+
+	_, err := DoubleMetaphoneFilterConstructor(map[string]interface{}{"replace": "no"}, nil)
+	if err == nil {
+		t.Errorf("expected error for invalid replace option")
+	}
+}
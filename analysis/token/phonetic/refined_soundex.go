@@ -0,0 +1,50 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package phonetic
+
+import (
+	"unicode/utf8"
+)
+
+// refinedSoundexCodes are the codes of the letters A to Z,
+// vowels and the letters H, W and Y all being 0.
+const refinedSoundexCodes = "01360240043788015936020505"
+
+// RefinedSoundex returns the Refined Soundex code of the term, its
+// first letter followed by a digit for each run of letters sounding
+// alike, or the empty string when the term has no letters A to Z.
+// Unlike Soundex, codes are not cut to a fixed length, and vowels
+// are kept apart from the consonants around them.
+func RefinedSoundex(term string) string {
+	rv := make([]byte, 0, utf8.RuneCountInString(term)+1)
+	var last byte
+	for _, r := range term {
+		if r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		if r < 'A' || r > 'Z' {
+			continue
+		}
+		if len(rv) == 0 {
+			rv = append(rv, byte(r))
+		}
+		code := refinedSoundexCodes[r-'A']
+		if code != last {
+			rv = append(rv, code)
+			last = code
+		}
+	}
+	return string(rv)
+}
@@ -0,0 +1,137 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stemmeroverride implements a TokenFilter which stems terms
+// from a dictionary, marking them as keywords so that the stemmers
+// following it, such as porter or snowball, leave them as they are.
+package stemmeroverride
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+const Name = "stemmer_override"
+
+type StemmerOverrideFilter struct {
+	stems map[string][]byte
+}
+
+// NewStemmerOverrideFilter returns a filter replacing the terms
+// which are keys of stems with their values.
+func NewStemmerOverrideFilter(stems map[string]string) *StemmerOverrideFilter {
+	rv := &StemmerOverrideFilter{
+		stems: make(map[string][]byte, len(stems)),
+	}
+	for term, stem := range stems {
+		rv.stems[term] = []byte(stem)
+	}
+	return rv
+}
+
+func (f *StemmerOverrideFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		if token.KeyWord {
+			continue
+		}
+		if stem, ok := f.stems[string(token.Term)]; ok {
+			token.Term = append([]byte(nil), stem...)
+			token.KeyWord = true
+		}
+	}
+	return input
+}
+
+// ParseRules reads rules such as "running, ran => run", where the terms
+// on the left are stemmed as the term on the right, one per line.  Empty
+// lines and lines starting with # are skipped.
+func ParseRules(r io.Reader) (map[string]string, error) {
+	var rules []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		rules = append(rules, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return parseRules(rules)
+}
+
+func parseRules(rules []string) (map[string]string, error) {
+	rv := make(map[string]string, len(rules))
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		if rule == "" || strings.HasPrefix(rule, "#") {
+			continue
+		}
+		terms, stem, ok := strings.Cut(rule, "=>")
+		stem = strings.TrimSpace(stem)
+		if !ok || stem == "" {
+			return nil, fmt.Errorf("invalid stemmer override rule '%s'", rule)
+		}
+		for _, term := range strings.Split(terms, ",") {
+			term = strings.TrimSpace(term)
+			if term == "" {
+				return nil, fmt.Errorf("invalid stemmer override rule '%s'", rule)
+			}
+			rv[term] = stem
+		}
+	}
+	return rv, nil
+}
+
+func StemmerOverrideFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	var stems map[string]string
+	var err error
+	if path, ok := config["rules_path"].(string); ok {
+		var file *os.File
+		file, err = os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error opening stemmer override rules file: %v", err)
+		}
+		stems, err = ParseRules(file)
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	} else if rulesVal, ok := config["rules"].([]interface{}); ok {
+		rules := make([]string, 0, len(rulesVal))
+		for _, ruleVal := range rulesVal {
+			rule, ok := ruleVal.(string)
+			if !ok {
+				return nil, fmt.Errorf("rules must be strings")
+			}
+			rules = append(rules, rule)
+		}
+		stems, err = parseRules(rules)
+	} else {
+		return nil, fmt.Errorf("must specify rules or rules_path")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error building stemmer override filter: %v", err)
+	}
+	return NewStemmerOverrideFilter(stems), nil
+}
+
+func init() {
+	err := registry.RegisterTokenFilter(Name, StemmerOverrideFilterConstructor)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,106 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stemmeroverride
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/analysis/token/porter"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+func TestParseRules(t *testing.T) {
+	stems, err := ParseRules(strings.NewReader(`
+# irregular verbs
+ran, running => run
+mice=>mouse
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"ran": "run", "running": "run", "mice": "mouse"}
+	if !reflect.DeepEqual(stems, expected) {
+		t.Errorf("expected %v, got %v", expected, stems)
+	}
+
+	for _, rule := range []string{"mice", "mice =>", "=> mouse", "ran,, running => run"} {
+		if _, err := parseRules([]string{rule}); err == nil {
+			t.Errorf("%s: expected error", rule)
+		}
+	}
+}
+
+func TestStemmerOverrideFilter(t *testing.T) {
+	cache := registry.NewCache()
+	filter, err := cache.DefineTokenFilter("irregular", map[string]interface{}{
+		"type":  Name,
+		"rules": []interface{}{"ran, running => run", "mice => mouse"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stemmer, err := cache.TokenFilterNamed(porter.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := analysis.TokenStream{
+		&analysis.Token{
+			Term: []byte("mice"),
+		},
+		&analysis.Token{
+			Term: []byte("ran"),
+		},
+		&analysis.Token{
+			Term: []byte("walking"),
+		},
+		&analysis.Token{
+			Term:    []byte("running"),
+			KeyWord: true,
+		},
+	}
+	output := stemmer.Filter(filter.Filter(input))
+
+	expected := analysis.TokenStream{
+		&analysis.Token{
+			Term:    []byte("mouse"),
+			KeyWord: true,
+		},
+		&analysis.Token{
+			Term:    []byte("run"),
+			KeyWord: true,
+		},
+		&analysis.Token{
+			Term: []byte("walk"),
+		},
+		&analysis.Token{
+			Term:    []byte("running"),
+			KeyWord: true,
+		},
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("expected %v, got %v", expected, output)
+	}
+
+	_, err = cache.DefineTokenFilter("missing", map[string]interface{}{
+		"type": Name,
+	})
+	if err == nil {
+		t.Errorf("expected error for missing rules")
+	}
+}
@@ -0,0 +1,171 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synonym
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+const Name = "synonym_graph"
+
+// SynonymGraphFilter adds the synonyms of the words and phrases of a
+// token stream, or replaces them by their synonyms for explicit mappings,
+// without needing a synonym source, so that it works with any index.
+//
+// Synonyms longer than the phrase they stand for move the tokens after
+// them along, and the last token of each of the shorter phrases spans
+// the positions up to the end of the longest, as set by its position
+// length.  Indexing the tokens keeps all the words at their positions,
+// such as new york at the position of ny, while queries analyzing text
+// with the filter match the phrases of each path through the tokens,
+// such as both ny city and new york city.
+type SynonymGraphFilter struct {
+	synonyms *SynonymMap
+}
+
+func NewSynonymGraphFilter(synonyms *SynonymMap) *SynonymGraphFilter {
+	return &SynonymGraphFilter{
+		synonyms: synonyms,
+	}
+}
+
+func (f *SynonymGraphFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	rv := make(analysis.TokenStream, 0, len(input))
+	shift := 0
+	for i := 0; i < len(input); {
+		rule := f.match(input, i)
+		if rule == nil {
+			input[i].Position += shift
+			rv = append(rv, input[i])
+			i++
+			continue
+		}
+
+		matched := input[i : i+len(rule.input)]
+		start := matched[0].Position + shift
+		length := len(matched)
+		for _, output := range rule.outputs {
+			if len(output) > length {
+				length = len(output)
+			}
+		}
+		for _, output := range rule.outputs {
+			for j, word := range output {
+				var token *analysis.Token
+				if equalWords(output, rule.input) {
+					// the original tokens, keeping their offsets
+					token = matched[j]
+				} else {
+					token = &analysis.Token{
+						Term:  []byte(word),
+						Start: matched[0].Start,
+						End:   matched[len(matched)-1].End,
+						Type:  matched[0].Type,
+					}
+				}
+				token.Position = start + j
+				token.PositionLength = 0
+				if j == len(output)-1 && length-j > 1 {
+					token.PositionLength = length - j
+				}
+				rv = append(rv, token)
+			}
+		}
+		shift += length - len(matched)
+		i += len(matched)
+	}
+	return rv
+}
+
+// match returns the rule for the longest phrase starting with
+// the token at the index, taking consecutive positions.
+func (f *SynonymGraphFilter) match(input analysis.TokenStream, index int) *synonymRule {
+	rules := f.synonyms.rules[f.word(input[index])]
+rules:
+	for _, rule := range rules {
+		if index+len(rule.input) > len(input) {
+			continue
+		}
+		for j := 1; j < len(rule.input); j++ {
+			token := input[index+j]
+			if token.Position != input[index].Position+j || f.word(token) != rule.input[j] {
+				continue rules
+			}
+		}
+		return rule
+	}
+	return nil
+}
+
+// word returns the term of the token, as the words of the rules are.
+func (f *SynonymGraphFilter) word(token *analysis.Token) string {
+	if f.synonyms.ignoreCase {
+		return string(bytes.ToLower(token.Term))
+	}
+	return string(token.Term)
+}
+
+func SynonymGraphFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	expand := true
+	if expandVal, ok := config["expand"].(bool); ok {
+		expand = expandVal
+	}
+	ignoreCase := false
+	if ignoreCaseVal, ok := config["ignore_case"].(bool); ok {
+		ignoreCase = ignoreCaseVal
+	}
+
+	var synonyms *SynonymMap
+	var err error
+	if path, ok := config["synonyms_path"].(string); ok {
+		var file *os.File
+		file, err = os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error opening synonyms file: %v", err)
+		}
+		synonyms, err = ParseSynonyms(file, expand, ignoreCase)
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	} else if rulesVal, ok := config["synonyms"].([]interface{}); ok {
+		rules := make([]string, 0, len(rulesVal))
+		for _, ruleVal := range rulesVal {
+			rule, ok := ruleVal.(string)
+			if !ok {
+				return nil, fmt.Errorf("synonyms must be strings")
+			}
+			rules = append(rules, rule)
+		}
+		synonyms, err = NewSynonymMap(rules, expand, ignoreCase)
+	} else {
+		return nil, fmt.Errorf("must specify synonyms or synonyms_path")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error building synonym graph filter: %v", err)
+	}
+	return NewSynonymGraphFilter(synonyms), nil
+}
+
+func init() {
+	err := registry.RegisterTokenFilter(Name, SynonymGraphFilterConstructor)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,212 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synonym
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// SynonymMap maps phrases, one or more words long, to the phrases
+// standing for them, parsed from rules in the Solr synonyms format:
+//
+//	# equivalent phrases, each standing for all of them
+//	ny, new york, big apple
+//	# explicit mappings, replacing the phrases on the left
+//	i-pod, i pod => ipod
+//
+// With expand unset, equivalent phrases all stand for the first of
+// them instead.  Commas, the # of comments and the => of mappings are
+// escaped with a backslash.  Phrases are split into words at white space,
+// so rules must be written as the tokens they match are, such as in
+// lower case, unless case is ignored.
+type SynonymMap struct {
+	ignoreCase bool
+	// the rules by the first word of the phrases they match,
+	// those matching the longest phrases first
+	rules map[string][]*synonymRule
+}
+
+// synonymRule is the phrases standing for the phrase of the input words.
+type synonymRule struct {
+	input   []string
+	outputs [][]string
+}
+
+// NewSynonymMap parses the rules, each in the Solr synonyms format.
+func NewSynonymMap(rules []string, expand, ignoreCase bool) (*SynonymMap, error) {
+	rv := &SynonymMap{
+		ignoreCase: ignoreCase,
+		rules:      make(map[string][]*synonymRule),
+	}
+	for _, rule := range rules {
+		if err := rv.parseRule(rule, expand); err != nil {
+			return nil, err
+		}
+	}
+	for _, rules := range rv.rules {
+		sort.SliceStable(rules, func(i, j int) bool {
+			return len(rules[i].input) > len(rules[j].input)
+		})
+	}
+	return rv, nil
+}
+
+// ParseSynonyms reads rules in the Solr synonyms format, one per line.
+func ParseSynonyms(r io.Reader, expand, ignoreCase bool) (*SynonymMap, error) {
+	var rules []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		rules = append(rules, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewSynonymMap(rules, expand, ignoreCase)
+}
+
+func (s *SynonymMap) parseRule(rule string, expand bool) error {
+	rule = strings.TrimSpace(unescapedCut(rule, "#")[0])
+	if rule == "" {
+		return nil
+	}
+	sides := unescapedCut(rule, "=>")
+	if len(sides) == 1 {
+		phrases, err := s.parsePhrases(rule)
+		if err != nil {
+			return err
+		}
+		for _, phrase := range phrases {
+			if expand {
+				s.add(phrase, phrases)
+			} else {
+				s.add(phrase, phrases[:1])
+			}
+		}
+		return nil
+	}
+	inputs, err := s.parsePhrases(sides[0])
+	if err != nil {
+		return err
+	}
+	outputs, err := s.parsePhrases(sides[1])
+	if err != nil {
+		return err
+	}
+	for _, input := range inputs {
+		s.add(input, outputs)
+	}
+	return nil
+}
+
+// parsePhrases splits the comma separated phrases into their words.
+func (s *SynonymMap) parsePhrases(text string) ([][]string, error) {
+	var rv [][]string
+	for _, phrase := range unescapedSplit(text, ",") {
+		words := strings.Fields(unescape(phrase))
+		if len(words) == 0 {
+			return nil, fmt.Errorf("invalid synonym rule '%s': empty phrase", text)
+		}
+		if s.ignoreCase {
+			for i, word := range words {
+				words[i] = strings.ToLower(word)
+			}
+		}
+		rv = append(rv, words)
+	}
+	return rv, nil
+}
+
+// add adds the outputs to those of the rule for the input phrase.
+func (s *SynonymMap) add(input []string, outputs [][]string) {
+	var rule *synonymRule
+	for _, r := range s.rules[input[0]] {
+		if equalWords(r.input, input) {
+			rule = r
+			break
+		}
+	}
+	if rule == nil {
+		rule = &synonymRule{input: input}
+		s.rules[input[0]] = append(s.rules[input[0]], rule)
+	}
+outputs:
+	for _, output := range outputs {
+		for _, existing := range rule.outputs {
+			if equalWords(existing, output) {
+				continue outputs
+			}
+		}
+		rule.outputs = append(rule.outputs, output)
+	}
+}
+
+func equalWords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// unescapedSplit splits the text at the separators not escaped by a backslash.
+func unescapedSplit(text, sep string) []string {
+	var rv []string
+	for {
+		parts := unescapedCut(text, sep)
+		rv = append(rv, parts[0])
+		if len(parts) == 1 {
+			return rv
+		}
+		text = parts[1]
+	}
+}
+
+// unescapedCut cuts the text around the first separator not escaped
+// by a backslash, returning the text alone when there is none.
+func unescapedCut(text, sep string) []string {
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(text[i:], sep) {
+			return []string{text[:i], text[i+len(sep):]}
+		}
+	}
+	return []string{text}
+}
+
+// unescape removes the backslashes escaping characters.
+func unescape(text string) string {
+	if !strings.Contains(text, "\\") {
+		return text
+	}
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) {
+			i++
+		}
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,203 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synonym
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+// tokenStream returns tokens for the space separated
+// terms, in consecutive positions.
+func tokenStream(text string) analysis.TokenStream {
+	var rv analysis.TokenStream
+	for i, term := range strings.Fields(text) {
+		rv = append(rv, &analysis.Token{
+			Term:     []byte(term),
+			Position: i + 1,
+		})
+	}
+	return rv
+}
+
+// describe describes tokens as their terms, positions and,
+// for tokens spanning several positions, position lengths.
+func describe(tokens analysis.TokenStream) []string {
+	var rv []string
+	for _, token := range tokens {
+		s := string(token.Term) + "/" + strconv.Itoa(token.Position)
+		if token.PositionLength > 1 {
+			s += "-" + strconv.Itoa(token.PositionLength)
+		}
+		rv = append(rv, s)
+	}
+	return rv
+}
+
+func TestSynonymMap(t *testing.T) {
+	synonyms, err := NewSynonymMap([]string{
+		"# a comment",
+		"",
+		"ny, new york # the city",
+		"i-pod, i pod => ipod",
+		"a\\,b, c\\#d => e\\=>f",
+		"tv => tv, television",
+	}, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]*synonymRule{
+		"ny":    {{input: []string{"ny"}, outputs: [][]string{{"ny"}, {"new", "york"}}}},
+		"new":   {{input: []string{"new", "york"}, outputs: [][]string{{"ny"}, {"new", "york"}}}},
+		"i-pod": {{input: []string{"i-pod"}, outputs: [][]string{{"ipod"}}}},
+		"i":     {{input: []string{"i", "pod"}, outputs: [][]string{{"ipod"}}}},
+		"a,b":   {{input: []string{"a,b"}, outputs: [][]string{{"e=>f"}}}},
+		"c#d":   {{input: []string{"c#d"}, outputs: [][]string{{"e=>f"}}}},
+		"tv":    {{input: []string{"tv"}, outputs: [][]string{{"tv"}, {"television"}}}},
+	}
+	if !reflect.DeepEqual(synonyms.rules, want) {
+		t.Errorf("unexpected rules %v", synonyms.rules)
+	}
+
+	synonyms, err = ParseSynonyms(strings.NewReader("NY, New York\nnyc => NY"), false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = map[string][]*synonymRule{
+		"ny":  {{input: []string{"ny"}, outputs: [][]string{{"ny"}}}},
+		"new": {{input: []string{"new", "york"}, outputs: [][]string{{"ny"}}}},
+		"nyc": {{input: []string{"nyc"}, outputs: [][]string{{"ny"}}}},
+	}
+	if !reflect.DeepEqual(synonyms.rules, want) {
+		t.Errorf("unexpected rules %v", synonyms.rules)
+	}
+
+	for _, rule := range []string{"a, , b", "=> b", "a =>"} {
+		if _, err = NewSynonymMap([]string{rule}, true, false); err == nil {
+			t.Errorf("%s: expected error", rule)
+		}
+	}
+}
+
+func TestSynonymGraphFilter(t *testing.T) {
+	synonyms, err := NewSynonymMap([]string{
+		"ny, new york",
+		"usa, united states, united states of america",
+		"i-pod => ipod",
+		"big apple => new york city",
+	}, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := NewSynonymGraphFilter(synonyms)
+
+	tests := []struct {
+		input  string
+		output []string
+	}{
+		{
+			input:  "ny city",
+			output: []string{"ny/1-2", "new/1", "york/2", "city/3"},
+		},
+		{
+			input:  "new york city",
+			output: []string{"ny/1-2", "new/1", "york/2", "city/3"},
+		},
+		{
+			input:  "new city",
+			output: []string{"new/1", "city/2"},
+		},
+		{
+			input: "in united states now",
+			output: []string{"in/1",
+				"usa/2-4", "united/2", "states/3-3", "united/2", "states/3", "of/4", "america/5",
+				"now/6"},
+		},
+		{
+			input:  "my i-pod",
+			output: []string{"my/1", "ipod/2"},
+		},
+		{
+			input:  "the big apple",
+			output: []string{"the/1", "new/2", "york/3", "city/4"},
+		},
+	}
+	for _, test := range tests {
+		got := describe(filter.Filter(tokenStream(test.input)))
+		if !reflect.DeepEqual(got, test.output) {
+			t.Errorf("%s: expected %v, got %v", test.input, test.output, got)
+		}
+	}
+
+	// a gap, as of a stop word, breaks phrases
+	input := tokenStream("new york")
+	input[1].Position = 3
+	got := describe(filter.Filter(input))
+	if want := []string{"new/1", "york/3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestSynonymGraphFilterConstructor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "synonyms.txt")
+	err := os.WriteFile(path, []byte("# synonyms\nNY, New York\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache := registry.NewCache()
+	filter, err := cache.DefineTokenFilter("from_file", map[string]interface{}{
+		"type":          Name,
+		"synonyms_path": path,
+		"ignore_case":   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := describe(filter.Filter(tokenStream("ny")))
+	if want := []string{"ny/1-2", "new/1", "york/2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	filter, err = cache.DefineTokenFilter("inline", map[string]interface{}{
+		"type":     Name,
+		"synonyms": []interface{}{"ny, new york"},
+		"expand":   false,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = describe(filter.Filter(tokenStream("new york")))
+	if want := []string{"ny/1-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	for name, config := range map[string]map[string]interface{}{
+		"missing": {"type": Name},
+		"invalid": {"type": Name, "synonyms": []interface{}{"a, , b"}},
+		"no_file": {"type": Name, "synonyms_path": filepath.Join(t.TempDir(), "missing.txt")},
+	} {
+		if _, err = cache.DefineTokenFilter(name, config); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trim implements a TokenFilter which removes the leading
+// and trailing white space of tokens, such as those of the keyword
+// tokenizer.
+package trim
+
+import (
+	"bytes"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+const Name = "trim"
+
+type TrimFilter struct {
+}
+
+func NewTrimFilter() *TrimFilter {
+	return &TrimFilter{}
+}
+
+func (f *TrimFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		trimmed := bytes.TrimSpace(token.Term)
+		if trimmed == nil {
+			// terms of only white space are left empty
+			trimmed = token.Term[:0]
+		}
+		token.Term = trimmed
+	}
+	return input
+}
+
+func TrimFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	return NewTrimFilter(), nil
+}
+
+func init() {
+	err := registry.RegisterTokenFilter(Name, TrimFilterConstructor)
+	if err != nil {
+		panic(err)
+	}
+}
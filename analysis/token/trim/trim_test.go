@@ -0,0 +1,67 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trim
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+)
+
+func TestTrimFilter(t *testing.T) {
+
+	inputTokenStream := analysis.TokenStream{
+		&analysis.Token{
+			Term: []byte("  padded  "),
+		},
+		&analysis.Token{
+			Term: []byte("\tnew york\n"),
+		},
+		&analysis.Token{
+			Term: []byte(" wide　"),
+		},
+		&analysis.Token{
+			Term: []byte("   "),
+		},
+		&analysis.Token{
+			Term: []byte("tight"),
+		},
+	}
+
+	expectedTokenStream := analysis.TokenStream{
+		&analysis.Token{
+			Term: []byte("padded"),
+		},
+		&analysis.Token{
+			Term: []byte("new york"),
+		},
+		&analysis.Token{
+			Term: []byte("wide"),
+		},
+		&analysis.Token{
+			Term: []byte(""),
+		},
+		&analysis.Token{
+			Term: []byte("tight"),
+		},
+	}
+
+	filter := NewTrimFilter()
+	ouputTokenStream := filter.Filter(inputTokenStream)
+	if !reflect.DeepEqual(ouputTokenStream, expectedTokenStream) {
+		t.Errorf("expected %#v got %#v", expectedTokenStream, ouputTokenStream)
+	}
+}
@@ -0,0 +1,52 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package uppercase implements a TokenFilter which converts
+// tokens to upper case according to unicode rules.
+package uppercase
+
+import (
+	"bytes"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+// Name is the name used to register UpperCaseFilter in the bleve registry
+const Name = "uppercase"
+
+type UpperCaseFilter struct {
+}
+
+func NewUpperCaseFilter() *UpperCaseFilter {
+	return &UpperCaseFilter{}
+}
+
+func (f *UpperCaseFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		token.Term = bytes.ToUpper(token.Term)
+	}
+	return input
+}
+
+func UpperCaseFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	return NewUpperCaseFilter(), nil
+}
+
+func init() {
+	err := registry.RegisterTokenFilter(Name, UpperCaseFilterConstructor)
+	if err != nil {
+		panic(err)
+	}
+}
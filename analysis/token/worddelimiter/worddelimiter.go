@@ -0,0 +1,323 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worddelimiter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+const Name = "word_delimiter"
+
+// Flags controlling how the WordDelimiterFilter splits tokens
+// and which tokens it generates from the parts.
+const (
+	// GenerateWordParts generates a token for each run of letters.
+	GenerateWordParts = 1 << iota
+	// GenerateNumberParts generates a token for each run of digits.
+	GenerateNumberParts
+	// CatenateWords generates a token joining consecutive word parts,
+	// such as wifi for wi-fi.
+	CatenateWords
+	// CatenateNumbers generates a token joining consecutive number
+	// parts, such as 5551234 for 555-1234.
+	CatenateNumbers
+	// CatenateAll generates a token joining all the parts,
+	// such as xps13 for xps-13.
+	CatenateAll
+	// PreserveOriginal keeps the original token as well as its parts.
+	PreserveOriginal
+	// SplitOnCaseChange splits where the case of letters changes, such
+	// as PowerShot into Power and Shot, and XMLParser into XML and Parser.
+	SplitOnCaseChange
+	// SplitOnNumerics splits where letters and digits meet,
+	// such as SD500 into SD and 500.
+	SplitOnNumerics
+	// StemEnglishPossessive removes the trailing 's of words.
+	StemEnglishPossessive
+)
+
+// DefaultFlags generate the word and number parts of tokens, splitting
+// on case changes, letter and digit transitions and punctuation.
+const DefaultFlags = GenerateWordParts | GenerateNumberParts |
+	SplitOnCaseChange | SplitOnNumerics | StemEnglishPossessive
+
+// WordDelimiterFilter splits tokens into parts at the characters other
+// than letters and digits, such as wi-fi into wi and fi, and, depending
+// on its flags, where the case of letters changes and where letters and
+// digits meet, such as PowerShot SD500 into Power, Shot, SD and 500.
+//
+// Each part takes a position of its own, so that phrases of the parts
+// match, while tokens catenating parts, and the original token when
+// preserved, take the position of the first part they cover.  Tokens
+// following a split token are moved along to make room for its parts.
+// Keywords and protected words are kept as they are.
+type WordDelimiterFilter struct {
+	flags     int
+	protected analysis.TokenMap
+}
+
+func NewWordDelimiterFilter(flags int, protected analysis.TokenMap) *WordDelimiterFilter {
+	return &WordDelimiterFilter{
+		flags:     flags,
+		protected: protected,
+	}
+}
+
+func (f *WordDelimiterFilter) has(flag int) bool {
+	return f.flags&flag != 0
+}
+
+// wordPart is a part of a token, from start to end of its term.
+type wordPart struct {
+	start  int
+	end    int
+	number bool
+}
+
+func (f *WordDelimiterFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	rv := make(analysis.TokenStream, 0, len(input))
+
+	// the position of the last input token and of the first and last
+	// output tokens for it, to shift the positions of the tokens after
+	lastInput, firstOutput, lastOutput := 0, 0, 0
+	for _, token := range input {
+		position := firstOutput
+		if token.Position != lastInput {
+			position = lastOutput + token.Position - lastInput
+			lastInput, firstOutput = token.Position, position
+		}
+		if position > lastOutput {
+			lastOutput = position
+		}
+
+		keep := token.KeyWord || f.isProtected(token.Term)
+		var parts []wordPart
+		if !keep {
+			parts = f.split(token.Term)
+			keep = len(parts) == 1 && parts[0].start == 0 && parts[0].end == len(token.Term)
+		}
+		if keep {
+			token.Position = position
+			rv = append(rv, token)
+			continue
+		}
+
+		if f.has(PreserveOriginal) {
+			token.Position = position
+			rv = append(rv, token)
+		}
+		if f.has(CatenateAll) && len(parts) > 1 {
+			rv = append(rv, newPartToken(token, parts, position))
+		}
+		next := position
+		for i := 0; i < len(parts); {
+			// runs of parts of the same kind
+			j := i + 1
+			for j < len(parts) && parts[j].number == parts[i].number {
+				j++
+			}
+			generate, catenate := f.has(GenerateWordParts), f.has(CatenateWords)
+			if parts[i].number {
+				generate, catenate = f.has(GenerateNumberParts), f.has(CatenateNumbers)
+			}
+			catenated := catenate && j-i > 1
+			if catenated {
+				rv = append(rv, newPartToken(token, parts[i:j], next))
+			}
+			if generate {
+				for k := i; k < j; k++ {
+					rv = append(rv, newPartToken(token, parts[k:k+1], next))
+					next++
+				}
+			} else if catenated {
+				next++
+			}
+			i = j
+		}
+		if next-1 > lastOutput {
+			lastOutput = next - 1
+		}
+	}
+	return rv
+}
+
+func (f *WordDelimiterFilter) isProtected(term []byte) bool {
+	if f.protected == nil {
+		return false
+	}
+	_, ok := f.protected[string(term)]
+	return ok
+}
+
+// newPartToken returns a token joining the parts of the
+// token, at the position, with offsets within the token
+// when its term is as long as the text it was found in.
+func newPartToken(token *analysis.Token, parts []wordPart, position int) *analysis.Token {
+	var term []byte
+	for _, part := range parts {
+		term = append(term, token.Term[part.start:part.end]...)
+	}
+	rv := &analysis.Token{
+		Term:     term,
+		Start:    token.Start,
+		End:      token.End,
+		Position: position,
+		Type:     token.Type,
+	}
+	if token.End-token.Start == len(token.Term) {
+		rv.Start = token.Start + parts[0].start
+		rv.End = token.Start + parts[len(parts)-1].end
+	}
+	return rv
+}
+
+type charType int
+
+const (
+	delimiter charType = iota
+	lower
+	upper
+	digit
+)
+
+func typeOf(r rune) charType {
+	switch {
+	case unicode.IsUpper(r), unicode.IsTitle(r):
+		return upper
+	case unicode.IsLetter(r):
+		return lower
+	case unicode.IsDigit(r), unicode.IsNumber(r):
+		return digit
+	}
+	return delimiter
+}
+
+// split returns the parts of the term, between the delimiters and,
+// depending on the flags, where case changes and letters and digits
+// meet.
+func (f *WordDelimiterFilter) split(term []byte) []wordPart {
+	end := len(term)
+	if f.has(StemEnglishPossessive) {
+		for _, suffix := range []string{"'s", "'S", "’s", "’S"} {
+			if len(term) > len(suffix) && strings.HasSuffix(string(term), suffix) {
+				end -= len(suffix)
+				break
+			}
+		}
+	}
+
+	var rv []wordPart
+	start := -1
+	number := true
+	var prevType, prevPrevType charType
+	prevStart := 0
+	closePart := func(at int) {
+		if start >= 0 {
+			rv = append(rv, wordPart{start: start, end: at, number: number})
+		}
+		start = -1
+		number = true
+	}
+	for i := 0; i < end; {
+		r, size := utf8.DecodeRune(term[i:end])
+		typ := typeOf(r)
+		switch {
+		case typ == delimiter:
+			closePart(i)
+		case start < 0:
+			start = i
+		case f.has(SplitOnNumerics) && (typ == digit) != (prevType == digit):
+			closePart(i)
+			start = i
+		case f.has(SplitOnCaseChange) && prevType == lower && typ == upper:
+			closePart(i)
+			start = i
+		case f.has(SplitOnCaseChange) && prevPrevType == upper && prevType == upper &&
+			typ == lower && prevStart > start:
+			// the last of a run of upper case letters starts a word
+			closePart(prevStart)
+			start = prevStart
+			number = false
+		}
+		if typ != delimiter && typ != digit {
+			number = false
+		}
+		if start == i {
+			prevPrevType = delimiter
+		} else {
+			prevPrevType = prevType
+		}
+		prevType, prevStart = typ, i
+		i += size
+	}
+	closePart(end)
+	return rv
+}
+
+func WordDelimiterFilterConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+	flags := DefaultFlags
+	options := []struct {
+		name string
+		flag int
+	}{
+		{"generate_word_parts", GenerateWordParts},
+		{"generate_number_parts", GenerateNumberParts},
+		{"catenate_words", CatenateWords},
+		{"catenate_numbers", CatenateNumbers},
+		{"catenate_all", CatenateAll},
+		{"preserve_original", PreserveOriginal},
+		{"split_on_case_change", SplitOnCaseChange},
+		{"split_on_numerics", SplitOnNumerics},
+		{"stem_english_possessive", StemEnglishPossessive},
+	}
+	for _, option := range options {
+		val, ok := config[option.name]
+		if !ok {
+			continue
+		}
+		set, ok := val.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a boolean", option.name)
+		}
+		if set {
+			flags |= option.flag
+		} else {
+			flags &^= option.flag
+		}
+	}
+
+	var protected analysis.TokenMap
+	if protectedTokenMapName, ok := config["protected_token_map"].(string); ok {
+		var err error
+		protected, err = cache.TokenMapNamed(protectedTokenMapName)
+		if err != nil {
+			return nil, fmt.Errorf("error building word delimiter filter: %v", err)
+		}
+	}
+	return NewWordDelimiterFilter(flags, protected), nil
+}
+
+func init() {
+	err := registry.RegisterTokenFilter(Name, WordDelimiterFilterConstructor)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,169 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package worddelimiter
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/analysis/tokenmap"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+// tokenStream returns tokens for the space separated
+// terms, in consecutive positions.
+func tokenStream(text string) analysis.TokenStream {
+	var rv analysis.TokenStream
+	start := 0
+	for i, term := range strings.Fields(text) {
+		start = strings.Index(text[start:], term) + start
+		rv = append(rv, &analysis.Token{
+			Term:     []byte(term),
+			Start:    start,
+			End:      start + len(term),
+			Position: i + 1,
+			Type:     analysis.AlphaNumeric,
+		})
+		start += len(term)
+	}
+	return rv
+}
+
+// termPositions describes tokens as their terms and positions.
+func termPositions(tokens analysis.TokenStream) []string {
+	var rv []string
+	for _, token := range tokens {
+		rv = append(rv, string(token.Term)+"/"+strconv.Itoa(token.Position))
+	}
+	return rv
+}
+
+func TestWordDelimiterFilter(t *testing.T) {
+	tests := []struct {
+		flags  int
+		input  string
+		output []string
+	}{
+		{
+			flags:  DefaultFlags,
+			input:  "PowerShot SD500",
+			output: []string{"Power/1", "Shot/2", "SD/3", "500/4"},
+		},
+		{
+			flags:  DefaultFlags,
+			input:  "XPS-13 laptop",
+			output: []string{"XPS/1", "13/2", "laptop/3"},
+		},
+		{
+			flags:  DefaultFlags,
+			input:  "XMLParser O'Neil's",
+			output: []string{"XML/1", "Parser/2", "O/3", "Neil/4"},
+		},
+		{
+			flags:  DefaultFlags,
+			input:  "-- ok",
+			output: []string{"ok/2"},
+		},
+		{
+			flags:  DefaultFlags &^ (SplitOnCaseChange | SplitOnNumerics),
+			input:  "PowerShot SD500 wi-fi",
+			output: []string{"PowerShot/1", "SD500/2", "wi/3", "fi/4"},
+		},
+		{
+			flags:  DefaultFlags | CatenateWords | PreserveOriginal,
+			input:  "wi-fi router",
+			output: []string{"wi-fi/1", "wifi/1", "wi/1", "fi/2", "router/3"},
+		},
+		{
+			flags:  DefaultFlags&^GenerateWordParts | CatenateWords,
+			input:  "wi-fi router",
+			output: []string{"wifi/1", "router/2"},
+		},
+		{
+			flags:  DefaultFlags | CatenateAll,
+			input:  "xps-13",
+			output: []string{"xps13/1", "xps/1", "13/2"},
+		},
+		{
+			flags:  DefaultFlags | CatenateNumbers,
+			input:  "555-1234 ext-9",
+			output: []string{"5551234/1", "555/1", "1234/2", "ext/3", "9/4"},
+		},
+	}
+
+	for _, test := range tests {
+		filter := NewWordDelimiterFilter(test.flags, nil)
+		got := termPositions(filter.Filter(tokenStream(test.input)))
+		if !reflect.DeepEqual(got, test.output) {
+			t.Errorf("%q with flags %b: expected %v, got %v", test.input, test.flags, test.output, got)
+		}
+	}
+}
+
+func TestWordDelimiterFilterPositions(t *testing.T) {
+	// a token at the same position as the one split, and after a gap
+	input := tokenStream("wi-fi wireless router")
+	input[1].Position = 1
+	input[2].Position = 3
+	filter := NewWordDelimiterFilter(DefaultFlags, nil)
+	output := filter.Filter(input)
+	got := termPositions(output)
+	want := []string{"wi/1", "fi/2", "wireless/1", "router/4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if output[1].Start != 3 || output[1].End != 5 {
+		t.Errorf("expected offsets 3 to 5, got %d to %d", output[1].Start, output[1].End)
+	}
+}
+
+func TestWordDelimiterFilterConstructor(t *testing.T) {
+	cache := registry.NewCache()
+	_, err := cache.DefineTokenMap("protected", map[string]interface{}{
+		"type":   tokenmap.Name,
+		"tokens": []interface{}{"wi-fi"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter, err := cache.DefineTokenFilter("delimit", map[string]interface{}{
+		"type":                 Name,
+		"split_on_case_change": false,
+		"catenate_all":         true,
+		"protected_token_map":  "protected",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := tokenStream("wi-fi PowerShot-5")
+	input = append(input, &analysis.Token{Term: []byte("a-b"), Position: 3, KeyWord: true})
+	got := termPositions(filter.Filter(input))
+	want := []string{"wi-fi/1", "PowerShot5/2", "PowerShot/2", "5/3", "a-b/4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	_, err = cache.DefineTokenFilter("invalid", map[string]interface{}{
+		"type":         Name,
+		"catenate_all": "yes",
+	})
+	if err == nil {
+		t.Errorf("expected error for invalid option")
+	}
+}
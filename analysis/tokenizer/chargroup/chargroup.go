@@ -0,0 +1,105 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package chargroup implements a Tokenizer splitting text on a
+// configured group of characters and classes of characters.
+package chargroup
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/character"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+const Name = "char_group"
+
+// classes are the classes of characters named by their use,
+// beside the unicode categories and scripts named as in package
+// unicode, such as Lu or Han.
+var classes = map[string]*unicode.RangeTable{
+	"whitespace":  unicode.White_Space,
+	"letter":      unicode.Letter,
+	"digit":       unicode.Nd,
+	"punctuation": unicode.Punct,
+	"symbol":      unicode.Symbol,
+}
+
+// NewCharGroupTokenizer returns a tokenizer splitting on the chars,
+// as well as on the characters of the tables, which are not part of
+// the tokens.
+func NewCharGroupTokenizer(chars []rune, tables []*unicode.RangeTable) *character.CharacterTokenizer {
+	split := make(map[rune]struct{}, len(chars))
+	for _, r := range chars {
+		split[r] = struct{}{}
+	}
+	return character.NewCharacterTokenizer(func(r rune) bool {
+		if _, ok := split[r]; ok {
+			return false
+		}
+		return !unicode.IsOneOf(tables, r)
+	})
+}
+
+// parseGroup parses each entry of the group, which is either a single
+// character or the name of a class of characters.
+func parseGroup(group []interface{}) ([]rune, []*unicode.RangeTable, error) {
+	var chars []rune
+	var tables []*unicode.RangeTable
+	for _, entryVal := range group {
+		entry, ok := entryVal.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("tokenize_on_chars must be strings")
+		}
+		if utf8.RuneCountInString(entry) == 1 {
+			r, _ := utf8.DecodeRuneInString(entry)
+			chars = append(chars, r)
+			continue
+		}
+		table, ok := classes[entry]
+		if !ok {
+			table, ok = unicode.Categories[entry]
+		}
+		if !ok {
+			table, ok = unicode.Scripts[entry]
+		}
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown character or class '%s'", entry)
+		}
+		tables = append(tables, table)
+	}
+	return chars, tables, nil
+}
+
+func TokenizerConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.Tokenizer, error) {
+	group, ok := config["tokenize_on_chars"].([]interface{})
+	if !ok || len(group) == 0 {
+		return nil, fmt.Errorf("must specify tokenize_on_chars")
+	}
+	chars, tables, err := parseGroup(group)
+	if err != nil {
+		return nil, fmt.Errorf("error building char group tokenizer: %v", err)
+	}
+	return NewCharGroupTokenizer(chars, tables), nil
+}
+
+func init() {
+	err := registry.RegisterTokenizer(Name, TokenizerConstructor)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,90 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chargroup
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+func TestCharGroupTokenizer(t *testing.T) {
+	tests := []struct {
+		group  []interface{}
+		input  string
+		output []string
+	}{
+		{
+			group:  []interface{}{"whitespace", "-", "\n"},
+			input:  "the quick-brown\tfox\njumps",
+			output: []string{"the", "quick", "brown", "fox", "jumps"},
+		},
+		{
+			group:  []interface{}{"punctuation", "symbol"},
+			input:  "a+b=c, (d) ¶e",
+			output: []string{"a", "b", "c", " ", "d", " ", "e"},
+		},
+		{
+			group:  []interface{}{"digit"},
+			input:  "abc123def٤٥ghi",
+			output: []string{"abc", "def", "ghi"},
+		},
+		{
+			group:  []interface{}{"Han", "Zs"},
+			input:  "東京 tokyo京都kyoto",
+			output: []string{"tokyo", "kyoto"},
+		},
+		{
+			group:  []interface{}{"letter"},
+			input:  "r2d2",
+			output: []string{"2", "2"},
+		},
+	}
+
+	for _, test := range tests {
+		cache := registry.NewCache()
+		tokenizer, err := TokenizerConstructor(map[string]interface{}{
+			"tokenize_on_chars": test.group,
+		}, cache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var output []string
+		for i, token := range tokenizer.Tokenize([]byte(test.input)) {
+			if token.Position != i+1 || string(token.Term) != test.input[token.Start:token.End] {
+				t.Errorf("%v: unexpected token %v", test.group, token)
+			}
+			output = append(output, string(token.Term))
+		}
+		if !reflect.DeepEqual(output, test.output) {
+			t.Errorf("%v: expected %q, got %q", test.group, test.output, output)
+		}
+	}
+}
+
+func TestCharGroupTokenizerConstructor(t *testing.T) {
+	cache := registry.NewCache()
+	for _, config := range []map[string]interface{}{
+		{},
+		{"tokenize_on_chars": []interface{}{}},
+		{"tokenize_on_chars": []interface{}{"no-such-class"}},
+		{"tokenize_on_chars": []interface{}{1.0}},
+	} {
+		if _, err := TokenizerConstructor(config, cache); err == nil {
+			t.Errorf("%v: expected error", config)
+		}
+	}
+}
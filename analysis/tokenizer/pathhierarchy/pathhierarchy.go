@@ -0,0 +1,144 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pathhierarchy implements a Tokenizer producing a token
+// for each level of a path, such as /a, /a/b and /a/b/c for /a/b/c,
+// or, reversed, a token for each level of a domain name, such as
+// www.example.com, example.com and com for www.example.com.
+package pathhierarchy
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+const Name = "path_hierarchy"
+
+type PathHierarchyTokenizer struct {
+	delimiter   []byte
+	replacement []byte
+	skip        int
+	reverse     bool
+}
+
+// NewPathHierarchyTokenizer returns a tokenizer of paths with levels
+// separated by the delimiter, which is replaced in the tokens by the
+// replacement.  Tokens start at the root of the path, or, reversed,
+// end at its last level, and the first skip levels from there are left
+// out of the tokens.
+func NewPathHierarchyTokenizer(delimiter, replacement []byte, skip int, reverse bool) *PathHierarchyTokenizer {
+	return &PathHierarchyTokenizer{
+		delimiter:   delimiter,
+		replacement: replacement,
+		skip:        skip,
+		reverse:     reverse,
+	}
+}
+
+func (t *PathHierarchyTokenizer) Tokenize(input []byte) analysis.TokenStream {
+	levels := t.levels(input)
+	if len(levels) <= t.skip {
+		return nil
+	}
+
+	rv := make(analysis.TokenStream, 0, len(levels)-t.skip)
+	if t.reverse {
+		end := levels[len(levels)-1-t.skip][1]
+		for _, level := range levels[:len(levels)-t.skip] {
+			rv = append(rv, t.token(input, level[0], end))
+		}
+	} else {
+		start := levels[t.skip][0]
+		for _, level := range levels[t.skip:] {
+			rv = append(rv, t.token(input, start, level[1]))
+		}
+	}
+	return rv
+}
+
+// levels returns the start and end of each level of the path.  Levels
+// include the delimiter before them, or, reversed, the delimiter after
+// them, so that /a/b/c has the levels /a, /b and /c, while reversed it
+// has /, a/, b/ and c.
+func (t *PathHierarchyTokenizer) levels(input []byte) [][2]int {
+	var rv [][2]int
+	start := 0
+	for start < len(input) {
+		from := start
+		if !t.reverse && bytes.HasPrefix(input[start:], t.delimiter) {
+			from += len(t.delimiter)
+		}
+		end := len(input)
+		if i := bytes.Index(input[from:], t.delimiter); i >= 0 {
+			end = from + i
+			if t.reverse {
+				end += len(t.delimiter)
+			}
+		}
+		rv = append(rv, [2]int{start, end})
+		start = end
+	}
+	return rv
+}
+
+func (t *PathHierarchyTokenizer) token(input []byte, start, end int) *analysis.Token {
+	term := input[start:end]
+	if !bytes.Equal(t.delimiter, t.replacement) {
+		term = bytes.ReplaceAll(term, t.delimiter, t.replacement)
+	}
+	// the levels are all at the same position, as for synonyms
+	return &analysis.Token{
+		Term:     term,
+		Start:    start,
+		End:      end,
+		Position: 1,
+		Type:     analysis.AlphaNumeric,
+	}
+}
+
+func TokenizerConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.Tokenizer, error) {
+	delimiter := "/"
+	if delimiterVal, ok := config["delimiter"].(string); ok {
+		delimiter = delimiterVal
+	}
+	if delimiter == "" {
+		return nil, fmt.Errorf("delimiter must not be empty")
+	}
+	replacement := delimiter
+	if replacementVal, ok := config["replacement"].(string); ok {
+		replacement = replacementVal
+	}
+	skip := 0
+	if skipVal, ok := config["skip"].(float64); ok {
+		if skipVal < 0 {
+			return nil, fmt.Errorf("skip must not be negative")
+		}
+		skip = int(skipVal)
+	}
+	reverse := false
+	if reverseVal, ok := config["reverse"].(bool); ok {
+		reverse = reverseVal
+	}
+	return NewPathHierarchyTokenizer([]byte(delimiter), []byte(replacement), skip, reverse), nil
+}
+
+func init() {
+	err := registry.RegisterTokenizer(Name, TokenizerConstructor)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,127 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathhierarchy
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+func TestPathHierarchyTokenizer(t *testing.T) {
+	tests := []struct {
+		config map[string]interface{}
+		input  string
+		output []string
+	}{
+		{
+			config: map[string]interface{}{},
+			input:  "/a/b/c",
+			output: []string{"/a[0:2]", "/a/b[0:4]", "/a/b/c[0:6]"},
+		},
+		{
+			config: map[string]interface{}{},
+			input:  "a/b/",
+			output: []string{"a[0:1]", "a/b[0:3]", "a/b/[0:4]"},
+		},
+		{
+			config: map[string]interface{}{},
+			input:  "a//b",
+			output: []string{"a[0:1]", "a/[0:2]", "a//b[0:4]"},
+		},
+		{
+			config: map[string]interface{}{
+				"delimiter":   "\\",
+				"replacement": "/",
+			},
+			input:  "c:\\users\\me",
+			output: []string{"c:[0:2]", "c:/users[0:8]", "c:/users/me[0:11]"},
+		},
+		{
+			config: map[string]interface{}{
+				"skip": 1.0,
+			},
+			input:  "/a/b/c",
+			output: []string{"/b[2:4]", "/b/c[2:6]"},
+		},
+		{
+			config: map[string]interface{}{
+				"delimiter": ".",
+				"reverse":   true,
+			},
+			input:  "www.example.com",
+			output: []string{"www.example.com[0:15]", "example.com[4:15]", "com[12:15]"},
+		},
+		{
+			config: map[string]interface{}{
+				"delimiter": ".",
+				"reverse":   true,
+				"skip":      1.0,
+			},
+			input:  "www.example.com",
+			output: []string{"www.example.[0:12]", "example.[4:12]"},
+		},
+		{
+			config: map[string]interface{}{
+				"reverse": true,
+			},
+			input:  "/a/b",
+			output: []string{"/a/b[0:4]", "a/b[1:4]", "b[3:4]"},
+		},
+		{
+			config: map[string]interface{}{
+				"skip": 3.0,
+			},
+			input: "/a/b",
+		},
+		{
+			config: map[string]interface{}{},
+			input:  "",
+		},
+	}
+
+	for _, test := range tests {
+		cache := registry.NewCache()
+		tokenizer, err := TokenizerConstructor(test.config, cache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var output []string
+		for _, token := range tokenizer.Tokenize([]byte(test.input)) {
+			if token.Position != 1 || token.Type != analysis.AlphaNumeric {
+				t.Errorf("%s: unexpected token %v", test.input, token)
+			}
+			output = append(output, fmt.Sprintf("%s[%d:%d]", token.Term, token.Start, token.End))
+		}
+		if !reflect.DeepEqual(output, test.output) {
+			t.Errorf("%s %v: expected %v, got %v", test.input, test.config, test.output, output)
+		}
+	}
+}
+
+func TestPathHierarchyTokenizerConstructor(t *testing.T) {
+	cache := registry.NewCache()
+	for _, config := range []map[string]interface{}{
+		{"delimiter": ""},
+		{"skip": -1.0},
+	} {
+		if _, err := TokenizerConstructor(config, cache); err == nil {
+			t.Errorf("%v: expected error", config)
+		}
+	}
+}
@@ -53,6 +53,13 @@ type Token struct {
 	Position int       `json:"position"`
 	Type     TokenType `json:"type"`
 	KeyWord  bool      `json:"keyword"`
+
+	// PositionLength specifies the number of positions spanned by a token
+	// standing for several others, such as a one word synonym of a phrase,
+	// where 0 is the same as 1.  Indexes only keep the position a token
+	// starts at, while queries follow the lengths to find the phrases of
+	// the different paths through the tokens.
+	PositionLength int `json:"position_length,omitempty"`
 }
 
 func (t *Token) String() string {
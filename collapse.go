@@ -0,0 +1,189 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"context"
+	"time"
+
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/numeric"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+type searchFunc func(ctx context.Context, req *SearchRequest) (*SearchResult, error)
+
+// searchWithInnerHits executes the search using searchFn, and when the
+// request collapses hits with inner hits, expands the collapsed hits
+// with the top hits sharing their collapse keys, found by follow up
+// searches against idx.  The expansion happens once the outer search
+// has returned, so that an alias expands its merged hits across all of
+// its indexes rather than each index expanding its own.
+func searchWithInnerHits(ctx context.Context, req *SearchRequest, idx Index,
+	searchFn searchFunc) (*SearchResult, error) {
+	if req.Collapse == nil || req.Collapse.InnerHits == 0 {
+		return searchFn(ctx, req)
+	}
+	if _, ok := ctx.Value(search.PreSearchKey).(bool); ok {
+		return searchFn(ctx, req)
+	}
+
+	outerReq := *req
+	outerReq.Collapse = req.Collapse.withoutInnerHits()
+	sr, err := searchFn(ctx, &outerReq)
+	if err != nil {
+		return nil, err
+	}
+	if sr.Request != nil {
+		sr.Request = req
+	}
+
+	// hits without a value for the collapse
+	// field have no term to expand on
+	var keys []string
+	for _, hit := range sr.Hits {
+		if len(hit.CollapseKey) > 0 {
+			keys = append(keys, string(hit.CollapseKey))
+		}
+	}
+	innerHits, err := searchInnerHits(ctx, req, idx, keys)
+	if err != nil {
+		return nil, err
+	}
+	for _, hit := range sr.Hits {
+		if len(hit.CollapseKey) > 0 {
+			hit.InnerHits = innerHits[string(hit.CollapseKey)]
+		}
+	}
+
+	return sr, nil
+}
+
+// searchInnerHits returns the top hits for each of the collapse keys,
+// searching for the top hits of all the keys together, sorted by the
+// collapse field after the requested sort so that the key of each hit
+// is known.  When that leaves some keys with fewer hits than requested,
+// while more hits matched, they are searched for again, which at least
+// one of them needs no more of, as the hits all have one of the keys.
+func searchInnerHits(ctx context.Context, req *SearchRequest, idx Index,
+	keys []string) (map[string]search.DocumentMatchCollection, error) {
+	size := req.Collapse.InnerHits
+	sort := req.Sort.Copy()
+	if len(sort) == 0 {
+		sort = search.SortOrder{&search.SortScore{Desc: true}}
+	}
+	sort = append(sort, &search.SortField{Field: req.Collapse.Field})
+
+	rv := make(map[string]search.DocumentMatchCollection, len(keys))
+	for len(keys) > 0 {
+		keyQueries := make([]query.Query, len(keys))
+		for i, key := range keys {
+			keyQuery := query.NewTermQuery(key)
+			keyQuery.SetField(req.Collapse.Field)
+			keyQueries[i] = keyQuery
+		}
+		// the keys filter the hits, without
+		// changing their scores
+		innerQuery := query.NewBooleanQuery([]query.Query{req.Query}, nil, nil)
+		innerQuery.AddFilter(query.NewDisjunctionQuery(keyQueries))
+		innerReq := &SearchRequest{
+			Query:            innerQuery,
+			Size:             size * len(keys),
+			Highlight:        req.Highlight,
+			Fields:           req.Fields,
+			Source:           req.Source,
+			RuntimeFields:    req.RuntimeFields,
+			Explain:          req.Explain,
+			Sort:             sort.Copy(),
+			IncludeLocations: req.IncludeLocations,
+			Score:            req.Score,
+			PointInTimeID:    req.PointInTimeID,
+		}
+		innerSR, err := idx.SearchInContext(ctx, innerReq)
+		if err != nil {
+			return nil, err
+		}
+		for _, hit := range innerSR.Hits {
+			n := len(hit.Sort) - 1
+			if n < 0 {
+				continue
+			}
+			key := hit.Sort[n]
+			hit.Sort = hit.Sort[:n]
+			if len(hit.DecodedSort) > n {
+				hit.DecodedSort = hit.DecodedSort[:n]
+			}
+			if len(rv[key]) < size {
+				rv[key] = append(rv[key], hit)
+			}
+		}
+		if innerSR.Total <= uint64(len(innerSR.Hits)) {
+			break
+		}
+		remaining := keys[:0]
+		for _, key := range keys {
+			if len(rv[key]) < size {
+				delete(rv, key)
+				remaining = append(remaining, key)
+			}
+		}
+		keys = remaining
+	}
+	return rv, nil
+}
+
+// collapseValue decodes the collapse key of a hit according to the
+// type of the collapse field, as text when not mapped, unless a full
+// precision numeric term.
+func collapseValue(m mapping.IndexMapping, field string, key []byte) interface{} {
+	if len(key) == 0 {
+		return nil
+	}
+	typ := m.FieldMappingForPath(field).Type
+	switch typ {
+	case "number", "datetime", "":
+		valid, shift := numeric.ValidPrefixCodedTermBytes(key)
+		if !valid || shift != 0 {
+			break
+		}
+		i64, err := numeric.PrefixCoded(key).Int64()
+		if err != nil {
+			break
+		}
+		if typ == "datetime" {
+			return time.Unix(0, i64).UTC().Format(time.RFC3339Nano)
+		}
+		return numeric.Int64ToFloat64(i64)
+	case "boolean":
+		return string(key) == "T"
+	}
+	return string(key)
+}
+
+// collapseHits keeps only the first hit for each collapse key,
+// the hits must already be sorted in the requested order.
+func collapseHits(hits search.DocumentMatchCollection) search.DocumentMatchCollection {
+	seen := make(map[string]struct{}, len(hits))
+	rv := hits[:0]
+	for _, hit := range hits {
+		if _, ok := seen[string(hit.CollapseKey)]; ok {
+			continue
+		}
+		seen[string(hit.CollapseKey)] = struct{}{}
+		rv = append(rv, hit)
+	}
+	return rv
+}
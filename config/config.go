@@ -47,21 +47,31 @@ import (
 	_ "github.com/blevesearch/bleve/v2/analysis/token/apostrophe"
 	_ "github.com/blevesearch/bleve/v2/analysis/token/camelcase"
 	_ "github.com/blevesearch/bleve/v2/analysis/token/compound"
+	_ "github.com/blevesearch/bleve/v2/analysis/token/decimaldigit"
 	_ "github.com/blevesearch/bleve/v2/analysis/token/edgengram"
 	_ "github.com/blevesearch/bleve/v2/analysis/token/elision"
 	_ "github.com/blevesearch/bleve/v2/analysis/token/keyword"
 	_ "github.com/blevesearch/bleve/v2/analysis/token/length"
 	_ "github.com/blevesearch/bleve/v2/analysis/token/lowercase"
 	_ "github.com/blevesearch/bleve/v2/analysis/token/ngram"
+	_ "github.com/blevesearch/bleve/v2/analysis/token/patternreplace"
+	_ "github.com/blevesearch/bleve/v2/analysis/token/phonetic"
 	_ "github.com/blevesearch/bleve/v2/analysis/token/reverse"
 	_ "github.com/blevesearch/bleve/v2/analysis/token/shingle"
+	_ "github.com/blevesearch/bleve/v2/analysis/token/stemmeroverride"
 	_ "github.com/blevesearch/bleve/v2/analysis/token/stop"
+	_ "github.com/blevesearch/bleve/v2/analysis/token/synonym"
+	_ "github.com/blevesearch/bleve/v2/analysis/token/trim"
 	_ "github.com/blevesearch/bleve/v2/analysis/token/truncate"
 	_ "github.com/blevesearch/bleve/v2/analysis/token/unicodenorm"
 	_ "github.com/blevesearch/bleve/v2/analysis/token/unique"
+	_ "github.com/blevesearch/bleve/v2/analysis/token/uppercase"
+	_ "github.com/blevesearch/bleve/v2/analysis/token/worddelimiter"
 
 	// tokenizers
+	_ "github.com/blevesearch/bleve/v2/analysis/tokenizer/chargroup"
 	_ "github.com/blevesearch/bleve/v2/analysis/tokenizer/exception"
+	_ "github.com/blevesearch/bleve/v2/analysis/tokenizer/pathhierarchy"
 	_ "github.com/blevesearch/bleve/v2/analysis/tokenizer/regexp"
 	_ "github.com/blevesearch/bleve/v2/analysis/tokenizer/single"
 	_ "github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
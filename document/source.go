@@ -0,0 +1,36 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	index "github.com/blevesearch/bleve_index_api"
+	"github.com/golang/snappy"
+)
+
+// SourceFieldName is the name of the stored field holding the
+// original JSON source of a document.
+const SourceFieldName = "_source"
+
+// NewSourceField returns a stored only field holding the compressed
+// JSON source of a document.
+func NewSourceField(source []byte) *TextField {
+	return NewTextFieldCustom(SourceFieldName, nil, snappy.Encode(nil, source),
+		index.StoreField, nil)
+}
+
+// DecodeSource returns the JSON source held by a source field.
+func DecodeSource(field index.Field) ([]byte, error) {
+	return snappy.Decode(nil, field.Value())
+}
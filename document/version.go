@@ -0,0 +1,96 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package document
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// VersionFieldName is the name of the stored field holding the
+// version of a document, for indexes which track versions.
+const VersionFieldName = "_version"
+
+// NewVersionField returns a stored only field holding the version
+// of a document, as its 8 big endian bytes rather than a number, as
+// a float64 cannot hold every version above 2^53 exactly.
+func NewVersionField(version uint64) *TextField {
+	return NewTextFieldWithIndexingOptions(VersionFieldName, nil,
+		binary.BigEndian.AppendUint64(nil, version), index.StoreField)
+}
+
+// DecodeVersion returns the version held by the
+// stored value of a version field.
+func DecodeVersion(value []byte) (uint64, error) {
+	if len(value) != 8 {
+		return 0, fmt.Errorf("invalid version of %d bytes", len(value))
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+// ErrVersionsNotTracked is returned for a batch with version
+// preconditions against an index which does not track versions.
+var ErrVersionsNotTracked = fmt.Errorf("index does not track document versions")
+
+// Precondition describes the state a document must be in for
+// a batch operation on it to be applied.
+type Precondition struct {
+	// Version, when non zero, is the version the live
+	// document is required to have.
+	Version uint64
+	// Absent requires that there is no live document.
+	Absent bool
+}
+
+func (p Precondition) String() string {
+	if p.Absent {
+		return "absent"
+	}
+	return fmt.Sprintf("version %d", p.Version)
+}
+
+// VersionConflict describes a document failing its precondition,
+// Version holds its live version if Exists.
+type VersionConflict struct {
+	ID           string
+	Precondition Precondition
+	Version      uint64
+	Exists       bool
+}
+
+func (c VersionConflict) String() string {
+	if !c.Exists {
+		return fmt.Sprintf("document %s expected %s, but it does not exist",
+			c.ID, c.Precondition)
+	}
+	return fmt.Sprintf("document %s expected %s, but it has version %d",
+		c.ID, c.Precondition, c.Version)
+}
+
+// VersionConflictError is returned for a batch with operations whose
+// preconditions do not hold, in which case none of the batch is applied.
+type VersionConflictError struct {
+	Conflicts []VersionConflict
+}
+
+func (e *VersionConflictError) Error() string {
+	if len(e.Conflicts) == 1 {
+		return fmt.Sprintf("version conflict: %s", e.Conflicts[0])
+	}
+	return fmt.Sprintf("version conflict for %d documents, including: %s",
+		len(e.Conflicts), e.Conflicts[0])
+}
@@ -29,6 +29,14 @@ const (
 	ErrorTwoPhaseSearchInconsistency
 	ErrorSynonymSearchNotSupported
 	ErrorTrainingNotSupported
+	ErrorPointInTimeNotFound
+	ErrorPointInTimeNotSupported
+	ErrorStreamingNotSupported
+	ErrorByQueryNotSupported
+	ErrorBatchUpdateConflict
+	ErrorSourceNotStored
+	ErrorVersionsNotSupported
+	ErrorPreconditionsNotSupported
 )
 
 // Error represents a more strongly typed bleve error for detecting
@@ -52,4 +60,12 @@ var errorMessages = map[Error]string{
 	ErrorIndexReadInconsistency:      "index read inconsistency detected",
 	ErrorTwoPhaseSearchInconsistency: "2-phase search failed, likely due to an overlapping topology change",
 	ErrorSynonymSearchNotSupported:   "synonym search not supported",
+	ErrorPointInTimeNotFound:         "point in time not found, it may have expired or been released",
+	ErrorPointInTimeNotSupported:     "point in time not supported",
+	ErrorStreamingNotSupported:       "streaming search not supported",
+	ErrorByQueryNotSupported:         "delete and update by query not supported",
+	ErrorBatchUpdateConflict:         "cannot update a document indexed or deleted in the same batch",
+	ErrorSourceNotStored:             "document source not stored",
+	ErrorVersionsNotSupported:        "document versions not supported",
+	ErrorPreconditionsNotSupported:   "batch preconditions not supported, they require a scorch index",
 }
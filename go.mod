@@ -27,6 +27,7 @@ require (
 	github.com/blevesearch/zapx/v16 v16.3.4
 	github.com/blevesearch/zapx/v17 v17.2.0
 	github.com/couchbase/moss v0.2.0
+	github.com/golang/snappy v1.0.0
 	github.com/spf13/cobra v1.10.2
 	go.etcd.io/bbolt v1.4.0
 	golang.org/x/text v0.37.0
@@ -36,7 +37,6 @@ require (
 require (
 	github.com/blevesearch/mmap-go v1.2.0 // indirect
 	github.com/couchbase/ghistogram v0.1.0 // indirect
-	github.com/golang/snappy v1.0.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/json-iterator/go v0.0.0-20171115153421-f7279a603ede // indirect
 	github.com/mschoch/smat v0.2.0 // indirect
@@ -17,6 +17,7 @@ package bleve
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/blevesearch/bleve/v2/index/upsidedown"
 
@@ -38,6 +39,13 @@ type Batch struct {
 
 	lastDocSize uint64
 	totalSize   uint64
+
+	// merge patches to be applied to the stored documents,
+	// in the order they were added
+	patches map[string][]map[string]interface{}
+
+	// preconditions the documents operated on must satisfy
+	preconditions map[string]document.Precondition
 }
 
 // Index adds the specified index operation to the
@@ -56,6 +64,8 @@ func (b *Batch) Index(id string, data interface{}) error {
 		return err
 	}
 	b.internal.Update(doc)
+	delete(b.patches, id)
+	delete(b.preconditions, id)
 
 	b.lastDocSize = uint64(doc.Size() +
 		len(id) + size.SizeOfString) // overhead from internal
@@ -64,6 +74,71 @@ func (b *Batch) Index(id string, data interface{}) error {
 	return nil
 }
 
+// IndexIfVersion adds the specified index operation to the batch,
+// which is only executed if the document currently has the version.
+// The whole batch fails with a *VersionConflictError otherwise.  The
+// index must track document versions, see DocumentVersion.
+// NOTE: the bleve Index is not updated until the batch is executed.
+func (b *Batch) IndexIfVersion(id string, data interface{}, version uint64) error {
+	err := b.Index(id, data)
+	if err != nil {
+		return err
+	}
+	b.setPrecondition(id, document.Precondition{Version: version})
+	return nil
+}
+
+// IndexIfAbsent adds the specified index operation to the batch,
+// which is only executed if the document does not currently exist.
+// The whole batch fails with a *VersionConflictError otherwise, and
+// with ErrorPreconditionsNotSupported for indexes other than scorch.
+// NOTE: the bleve Index is not updated until the batch is executed.
+func (b *Batch) IndexIfAbsent(id string, data interface{}) error {
+	err := b.Index(id, data)
+	if err != nil {
+		return err
+	}
+	b.setPrecondition(id, document.Precondition{Absent: true})
+	return nil
+}
+
+func (b *Batch) setPrecondition(id string, p document.Precondition) {
+	if b.preconditions == nil {
+		b.preconditions = make(map[string]document.Precondition)
+	}
+	b.preconditions[id] = p
+}
+
+// Update adds the specified partial update operation to the
+// batch.  The patch, either a value marshalling to a JSON object
+// or the JSON itself, is applied with JSON merge patch semantics to
+// the document's stored source when the batch is executed, and the
+// result is reindexed.  Members set to null are removed, and an
+// object holding only PatchAppendKey appends to an array rather than
+// replacing it.  A document which does not exist is created from the
+// patch.  Existing documents must have their source stored, as enabled
+// by the StoreSource option of the mapping, otherwise executing the
+// batch fails with ErrorSourceNotStored.  A document indexed or deleted
+// earlier in the same batch cannot be updated.
+// NOTE: the bleve Index is not updated until the batch is executed.
+func (b *Batch) Update(id string, patch interface{}) error {
+	if id == "" {
+		return ErrorEmptyID
+	}
+	if _, ok := b.internal.IndexOps[id]; ok {
+		return ErrorBatchUpdateConflict
+	}
+	p, err := normalizePatch(patch)
+	if err != nil {
+		return err
+	}
+	if b.patches == nil {
+		b.patches = make(map[string][]map[string]interface{})
+	}
+	b.patches[id] = append(b.patches[id], p)
+	return nil
+}
+
 func (b *Batch) IndexSynonym(id string, collection string, definition *SynonymDefinition) error {
 	if id == "" {
 		return ErrorEmptyID
@@ -110,15 +185,30 @@ func (b *Batch) IndexAdvanced(doc *document.Document) (err error) {
 		return ErrorEmptyID
 	}
 	b.internal.Update(doc)
+	delete(b.preconditions, doc.ID())
 	return nil
 }
 
 // Delete adds the specified delete operation to the
-// batch.  NOTE: the bleve Index is not updated until
-// the batch is executed.
+// batch, which deletes the document whatever its version,
+// see DeleteIfVersion.  NOTE: the bleve Index is not updated
+// until the batch is executed.
 func (b *Batch) Delete(id string) {
 	if id != "" {
 		b.internal.Delete(id)
+		delete(b.patches, id)
+		delete(b.preconditions, id)
+	}
+}
+
+// DeleteIfVersion adds the specified delete operation to the batch,
+// which is only executed if the document currently has the version.
+// The whole batch fails with a *VersionConflictError otherwise.
+// NOTE: the bleve Index is not updated until the batch is executed.
+func (b *Batch) DeleteIfVersion(id string, version uint64) {
+	if id != "" {
+		b.Delete(id)
+		b.setPrecondition(id, document.Precondition{Version: version})
 	}
 }
 
@@ -139,7 +229,7 @@ func (b *Batch) DeleteInternal(key []byte) {
 // Size returns the total number of operations inside the batch
 // including normal index operations and internal operations.
 func (b *Batch) Size() int {
-	return len(b.internal.IndexOps) + len(b.internal.InternalOps)
+	return len(b.internal.IndexOps) + len(b.internal.InternalOps) + len(b.patches)
 }
 
 // String prints a user friendly string representation of what
@@ -154,16 +244,41 @@ func (b *Batch) Reset() {
 	b.internal.Reset()
 	b.lastDocSize = 0
 	b.totalSize = 0
+	b.patches = nil
+	b.preconditions = nil
 }
 
-func (b *Batch) Merge(o *Batch) {
+// Merge adds the operations of the other batch to this batch, as
+// if added after its own operations.  It returns ErrorBatchUpdateConflict,
+// leaving this batch unchanged, when the other batch updates a document
+// indexed or deleted by this batch, as Update does.
+func (b *Batch) Merge(o *Batch) error {
 	if o != nil && o.internal != nil {
+		for id := range o.patches {
+			if _, ok := b.internal.IndexOps[id]; ok {
+				return ErrorBatchUpdateConflict
+			}
+		}
+		for id := range o.internal.IndexOps {
+			delete(b.patches, id)
+			delete(b.preconditions, id)
+		}
+		for id, p := range o.preconditions {
+			b.setPrecondition(id, p)
+		}
+		for id, patches := range o.patches {
+			if b.patches == nil {
+				b.patches = make(map[string][]map[string]interface{})
+			}
+			b.patches[id] = append(b.patches[id], patches...)
+		}
 		b.internal.Merge(o.internal)
 		if o.LastDocSize() > 0 {
 			b.lastDocSize = o.LastDocSize()
 		}
 		b.totalSize = uint64(b.internal.TotalDocSize())
 	}
+	return nil
 }
 
 func (b *Batch) SetPersistedCallback(f index.BatchCallback) {
@@ -402,6 +517,22 @@ type InsightsIndex interface {
 	CentroidCardinalities(field string, limit int, desceding bool) ([]index.CentroidCardinality, error)
 }
 
+// PointInTimeIndex supports pinning the current state of the index, so that
+// a sequence of searches, such as pages requested using SearchAfter, sees a
+// consistent view while the index continues to be updated.  Searches target
+// the point in time by setting SearchRequest.PointInTimeID.
+type PointInTimeIndex interface {
+	Index
+	// OpenPointInTime pins the current state of the index and returns its
+	// id.  The point in time is released once it goes unused for the
+	// provided time to live, every search using it extends its expiry.
+	// A time to live <= 0 uses DefaultPointInTimeTTL.
+	OpenPointInTime(ttl time.Duration) (string, error)
+	// ReleasePointInTime releases the point in time with the provided id,
+	// searches already using it are allowed to complete.
+	ReleasePointInTime(id string) error
+}
+
 type TrainableIndex interface {
 	Index
 	Train(*Batch) error
@@ -20,6 +20,7 @@ import (
 	"sync/atomic"
 
 	"github.com/RoaringBitmap/roaring/v2"
+	"github.com/blevesearch/bleve/v2/document"
 	index "github.com/blevesearch/bleve_index_api"
 	segment "github.com/blevesearch/scorch_segment_api/v2"
 )
@@ -33,6 +34,11 @@ type segmentIntroduction struct {
 	ids       []string
 	internal  map[string][]byte
 
+	// version assigned to the documents of the segment, if tracking
+	// versions, and preconditions which must hold to introduce it
+	version       uint64
+	preconditions map[string]document.Precondition
+
 	applied           chan error
 	persisted         chan error
 	persistedCallback index.BatchCallback
@@ -116,6 +122,28 @@ func (s *Scorch) introduceSegment(next *segmentIntroduction) error {
 
 	defer func() { _ = root.DecRef() }()
 
+	stale, err := s.checkVersions(root, next)
+	if err != nil {
+		next.applied <- err
+		close(next.applied)
+		return err
+	}
+	var staleDocs *roaring.Bitmap
+	if len(stale) > 0 {
+		// the stale documents neither replace their later versions,
+		// nor are they live in the new segment
+		next.ids = subtractStrings(next.ids, stale)
+		next.obsoletes = nil
+		if next.data != nil {
+			staleDocs, err = staleDocNumbers(next.data, stale)
+			if err != nil {
+				next.applied <- fmt.Errorf("error computing doc numbers: %v", err)
+				close(next.applied)
+				return err
+			}
+		}
+	}
+
 	nsegs := len(root.segment)
 
 	// prepare new index snapshot
@@ -202,6 +230,7 @@ func (s *Scorch) introduceSegment(next *segmentIntroduction) error {
 		newSegmentSnapshot := &SegmentSnapshot{
 			id:         next.id,
 			segment:    next.data, // take ownership of next.data's ref-count
+			deleted:    staleDocs,
 			stats:      stats,
 			cachedDocs: &cachedDocs{cache: nil},
 			cachedMeta: newCachedMeta(),
@@ -227,6 +256,10 @@ func (s *Scorch) introduceSegment(next *segmentIntroduction) error {
 			delete(newSnapshot.internal, key)
 		}
 	}
+	if next.version > s.introducedVersion {
+		s.introducedVersion = next.version
+		newSnapshot.internal[string(versionKey)] = encodeVersion(next.version)
+	}
 
 	newSnapshot.updateSize()
 	s.rootLock.Lock()
@@ -455,6 +488,11 @@ func (s *Scorch) introduceMerge(nextMerge *segmentMerge) {
 				fsr.UpdateFieldStats(stats)
 			}
 
+			meta := newCachedMeta()
+			if nextMerge.newFieldDocs[i] != nil {
+				meta.store(fieldDocsCacheKey, nextMerge.newFieldDocs[i])
+			}
+
 			// put the merged segment at the end of newSnapshot
 			newSnapshot.segment = append(newSnapshot.segment, &SegmentSnapshot{
 				id:         nextMerge.newSegmentIDs[i],
@@ -462,7 +500,7 @@ func (s *Scorch) introduceMerge(nextMerge *segmentMerge) {
 				deleted:    newSegmentsDeleted[i],
 				stats:      stats,
 				cachedDocs: &cachedDocs{cache: nil},
-				cachedMeta: newCachedMeta(),
+				cachedMeta: meta,
 				creator:    "introduceMerge",
 				mmaped:     nextMerge.mmaped,
 			})
@@ -477,6 +477,7 @@ func (s *Scorch) planMergeAtSnapshot(ctrlMsg *mergerCtrl, ourSnapshot *IndexSnap
 		newSegmentIDs:    newSegmentIDs,
 		newSegments:      newSegments,
 		mergedSegHistory: mergedSegHistory,
+		newFieldDocs:     mergedFieldDocs(numBatches, mergedSegHistory),
 		notifyCh:         make(chan *mergeTaskIntroStatus),
 		mmaped:           1,
 		fileMerge:        true,
@@ -537,6 +538,7 @@ type segmentMerge struct {
 	newSegmentIDs    []uint64
 	newSegments      []segment.Segment
 	mergedSegHistory map[uint64]*mergedSegmentHistory
+	newFieldDocs     []*fieldDocsCache
 	notifyCh         chan *mergeTaskIntroStatus
 	mmaped           uint32
 	fileMerge        bool
@@ -679,6 +681,7 @@ func (s *Scorch) mergeAndPersistInMemorySegments(flushes []*flushable, po *persi
 		newSegmentIDs:    newSegmentIDs,
 		newSegments:      newSegments,
 		mergedSegHistory: mergedSegHistory,
+		newFieldDocs:     mergedFieldDocs(numBatches, mergedSegHistory),
 		notifyCh:         make(chan *mergeTaskIntroStatus),
 		mmaped:           1,
 		fileMerge:        false,
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/RoaringBitmap/roaring/v2"
+	"github.com/blevesearch/bleve/v2/document"
 	"github.com/blevesearch/bleve/v2/index/scorch/mergeplan"
 	"github.com/blevesearch/bleve/v2/registry"
 	"github.com/blevesearch/bleve/v2/util"
@@ -55,6 +56,13 @@ type Scorch struct {
 
 	unsafeBatch bool
 
+	// versions enables tracking a version for each document, the last
+	// version assigned to a batch, and the last version introduced,
+	// which is only accessed by the introducer
+	versions          bool
+	nextVersion       uint64
+	introducedVersion uint64
+
 	rootLock sync.RWMutex
 
 	root                 *IndexSnapshot // holds 1 ref-count on the root
@@ -229,6 +237,10 @@ func NewScorch(storeName string,
 	if ok {
 		rv.unsafeBatch = ub
 	}
+	versions, ok := config["versions"].(bool)
+	if ok {
+		rv.versions = versions
+	}
 	ecbName, ok := config["eventCallbackName"].(string)
 	if ok {
 		rv.onEvent = RegistryEventCallbacks[ecbName]
@@ -417,6 +429,9 @@ func (s *Scorch) openBolt() error {
 
 	atomic.StoreUint64(&s.stats.TotFileSegmentsAtRoot, uint64(len(s.root.segment)))
 
+	s.nextVersion = snapshotVersion(s.root)
+	s.introducedVersion = s.nextVersion
+
 	s.introductions = make(chan *segmentIntroduction)
 	s.persists = make(chan *persistIntroduction)
 	s.merges = make(chan *segmentMerge)
@@ -498,6 +513,10 @@ func (s *Scorch) isTrained(batch *index.Batch) (bool, error) {
 
 // Batch applices a batch of changes to the index atomically
 func (s *Scorch) Batch(batch *index.Batch) (err error) {
+	return s.batch(batch, nil)
+}
+
+func (s *Scorch) batch(batch *index.Batch, preconditions map[string]document.Precondition) (err error) {
 	start := time.Now()
 
 	// notify handlers that we're about to index a batch of data
@@ -515,6 +534,14 @@ func (s *Scorch) Batch(batch *index.Batch) (err error) {
 		return fmt.Errorf("index is not trained yet")
 	}
 
+	var version uint64
+	if s.versions {
+		version, err = s.addVersionFields(batch)
+		if err != nil {
+			return err
+		}
+	}
+
 	resultChan := make(chan index.Document, len(batch.IndexOps))
 
 	var numUpdates uint64
@@ -590,7 +617,8 @@ func (s *Scorch) Batch(batch *index.Batch) (err error) {
 		atomic.AddUint64(&s.stats.TotBatchesEmpty, 1)
 	}
 
-	err = s.prepareSegment(newSegment, ids, batch.InternalOps, batch.PersistedCallback())
+	err = s.prepareSegment(newSegment, ids, batch.InternalOps, batch.PersistedCallback(),
+		version, preconditions)
 	if err != nil {
 		if newSegment != nil {
 			_ = newSegment.Close()
@@ -633,13 +661,16 @@ func (s *Scorch) Train(batch *index.Batch) error {
 }
 
 func (s *Scorch) prepareSegment(newSegment segment.Segment, ids []string,
-	internalOps map[string][]byte, persistedCallback index.BatchCallback) error {
+	internalOps map[string][]byte, persistedCallback index.BatchCallback,
+	version uint64, preconditions map[string]document.Precondition) error {
 	// new introduction
 	introduction := &segmentIntroduction{
 		id:                atomic.AddUint64(&s.nextSegmentID, 1),
 		data:              newSegment,
 		ids:               ids,
 		internal:          internalOps,
+		version:           version,
+		preconditions:     preconditions,
 		applied:           make(chan error),
 		persistedCallback: persistedCallback,
 	}
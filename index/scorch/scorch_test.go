@@ -26,6 +26,7 @@ import (
 	"reflect"
 	"regexp"
 	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -3961,3 +3962,252 @@ func checkUint64Stat(t *testing.T, m map[string]interface{}, key string, want ui
 		t.Errorf("stat %q: got %d, want %d", key, got, want)
 	}
 }
+
+func TestIndexVersions(t *testing.T) {
+	cfg := CreateConfig("TestIndexVersions")
+	cfg["versions"] = true
+	err := InitTest(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := DestroyTest(cfg)
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	analysisQueue := index.NewAnalysisQueue(1)
+	idx, err := NewScorch(Name, cfg, analysisQueue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx.Open()
+	if err != nil {
+		t.Fatalf("error opening index: %v", err)
+	}
+	s := idx.(*Scorch)
+
+	newDoc := func(id, name string) *document.Document {
+		doc := document.NewDocument(id)
+		doc.AddField(document.NewTextFieldWithIndexingOptions("name", nil, []byte(name), index.IndexField|index.StoreField))
+		return doc
+	}
+	batchWith := func(p map[string]document.Precondition, docs ...*document.Document) error {
+		b := index.NewBatch()
+		for _, doc := range docs {
+			b.Update(doc)
+		}
+		return s.BatchWithPreconditions(b, p)
+	}
+	checkVersion := func(id string, expected uint64, expectedName string) {
+		t.Helper()
+		reader, err := s.Reader()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			_ = reader.Close()
+		}()
+		is := reader.(*IndexSnapshot)
+		version, exists, err := is.liveVersion(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !exists || version != expected {
+			t.Errorf("expected %s to have version %d, got %d (exists %t)", id, expected, version, exists)
+		}
+		internalID, err := is.InternalID(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		version, ok, err := is.DocumentVersion(internalID)
+		if err != nil || !ok || version != expected {
+			t.Errorf("expected document version %d, got %d, %t, %v", expected, version, ok, err)
+		}
+		doc, err := is.Document(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var name string
+		doc.VisitFields(func(field index.Field) {
+			if field.Name() == "name" {
+				name = string(field.Value())
+			}
+		})
+		if name != expectedName {
+			t.Errorf("expected %s to be named %s, got %s", id, expectedName, name)
+		}
+	}
+
+	err = idx.Update(newDoc("a", "one"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkVersion("a", 1, "one")
+
+	// matching preconditions are applied
+	err = batchWith(map[string]document.Precondition{"a": {Version: 1}, "b": {Absent: true}},
+		newDoc("a", "two"), newDoc("b", "one"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkVersion("a", 2, "two")
+	checkVersion("b", 2, "one")
+
+	// a failed precondition fails the whole batch
+	err = batchWith(map[string]document.Precondition{"a": {Version: 1}, "c": {Absent: true}},
+		newDoc("a", "three"), newDoc("c", "one"))
+	conflictErr, ok := err.(*document.VersionConflictError)
+	if !ok {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+	if len(conflictErr.Conflicts) != 1 || conflictErr.Conflicts[0].ID != "a" ||
+		conflictErr.Conflicts[0].Version != 2 {
+		t.Errorf("unexpected conflicts %v", conflictErr.Conflicts)
+	}
+	checkVersion("a", 2, "two")
+	err = batchWith(map[string]document.Precondition{"b": {Absent: true}}, newDoc("b", "two"))
+	if _, ok := err.(*document.VersionConflictError); !ok {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+
+	// a batch introduced after a later version does not replace it
+	s.nextVersion = 10
+	err = idx.Update(newDoc("a", "eleven"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.nextVersion = 4
+	err = batchWith(nil, newDoc("a", "five"), newDoc("d", "five"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkVersion("a", 11, "eleven")
+	checkVersion("d", 5, "five")
+
+	// versions continue from the last introduced once reopened
+	err = idx.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx, err = NewScorch(Name, cfg, analysisQueue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx.Open()
+	if err != nil {
+		t.Fatalf("error opening index: %v", err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+	s = idx.(*Scorch)
+	err = idx.Update(newDoc("b", "twelve"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkVersion("b", 12, "twelve")
+}
+
+func TestIndexSnapshotExistsReader(t *testing.T) {
+	cfg := CreateConfig("TestIndexSnapshotExistsReader")
+	err := InitTest(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := DestroyTest(cfg)
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	analysisQueue := index.NewAnalysisQueue(1)
+	idx, err := NewScorch(Name, cfg, analysisQueue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx.Open()
+	if err != nil {
+		t.Fatalf("error opening index: %v", err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	newDoc := func(id string, fields ...string) *document.Document {
+		doc := document.NewDocument(id)
+		for _, field := range fields {
+			doc.AddField(document.NewTextField(field, nil, []byte("value "+id)))
+		}
+		return doc
+	}
+	// each in a segment of its own
+	for _, doc := range []*document.Document{
+		newDoc("a", "name", "desc"),
+		newDoc("b", "desc"),
+		newDoc("c", "name"),
+		newDoc("d", "name"),
+	} {
+		err = idx.Update(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = idx.Delete("d")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := idx.Reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := reader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+	for field, expected := range map[string][]string{
+		"name":    {"a", "c"},
+		"desc":    {"a", "b"},
+		"missing": nil,
+	} {
+		// twice, the second time using the cached bitmaps
+		for i := 0; i < 2; i++ {
+			tfr, err := reader.(*IndexSnapshot).ExistsReader(field)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			tfd, err := tfr.Next(nil)
+			for err == nil && tfd != nil {
+				id, err := reader.ExternalID(tfd.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ids = append(ids, id)
+				tfd, err = tfr.Next(nil)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Strings(ids)
+			if !reflect.DeepEqual(ids, expected) {
+				t.Errorf("expected %s to exist in %v, got %v", field, expected, ids)
+			}
+			err = tfr.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,250 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorch
+
+import (
+	"math"
+	"sync"
+
+	"github.com/RoaringBitmap/roaring/v2"
+	index "github.com/blevesearch/bleve_index_api"
+	segment "github.com/blevesearch/scorch_segment_api/v2"
+)
+
+// ExistsReader returns a reader of the documents having any value for
+// the field, from the field presence bitmap of each segment.  As the
+// reader is unadorned, its postings have no frequencies, norms or term
+// vectors.
+func (is *IndexSnapshot) ExistsReader(field string) (index.TermFieldReader, error) {
+	rv := is.unadornedTermFieldReader(nil, field)
+	for i, s := range is.segment {
+		bm, err := s.fieldDocs(field)
+		if err != nil {
+			return nil, err
+		}
+		if s.deleted != nil && !s.deleted.IsEmpty() {
+			bm = roaring.AndNot(bm, s.deleted)
+		}
+		if bm.IsEmpty() {
+			rv.iterators[i] = anEmptyPostingsIterator
+			continue
+		}
+		rv.iterators[i] = newUnadornedPostingsIteratorFromBitmap(bm)
+	}
+	return rv, nil
+}
+
+// FieldDocsCacheMaxBytes bounds the memory held by the field presence
+// bitmaps cached for each segment, the bitmaps of the fields least
+// recently used are dropped to make room for others.
+var FieldDocsCacheMaxBytes = uint64(4 << 20)
+
+// fieldDocsCacheKey is the key of the field presence
+// bitmaps among the cached metadata of a segment.
+const fieldDocsCacheKey = "\x00exists"
+
+// fieldDocsCache holds the field presence bitmaps of a segment,
+// within FieldDocsCacheMaxBytes.
+type fieldDocsCache struct {
+	m       sync.Mutex
+	bitmaps map[string]*roaring.Bitmap
+	fields  []string // least recently used first
+	bytes   uint64
+}
+
+func (c *fieldDocsCache) load(field string) (*roaring.Bitmap, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	bm, ok := c.bitmaps[field]
+	if ok {
+		c.touch(field)
+	}
+	return bm, ok
+}
+
+func (c *fieldDocsCache) store(field string, bm *roaring.Bitmap) {
+	size := bm.GetSizeInBytes()
+	if size > FieldDocsCacheMaxBytes {
+		return
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	if _, ok := c.bitmaps[field]; ok {
+		c.touch(field)
+		return
+	}
+	for len(c.fields) > 0 && c.bytes+size > FieldDocsCacheMaxBytes {
+		evicted := c.fields[0]
+		c.fields = c.fields[1:]
+		c.bytes -= c.bitmaps[evicted].GetSizeInBytes()
+		delete(c.bitmaps, evicted)
+	}
+	c.bitmaps[field] = bm
+	c.fields = append(c.fields, field)
+	c.bytes += size
+}
+
+// snapshot returns the bitmaps cached, by field.
+func (c *fieldDocsCache) snapshot() map[string]*roaring.Bitmap {
+	c.m.Lock()
+	defer c.m.Unlock()
+	rv := make(map[string]*roaring.Bitmap, len(c.bitmaps))
+	for field, bm := range c.bitmaps {
+		rv[field] = bm
+	}
+	return rv
+}
+
+// touch moves the field to the end of the fields, as the most recently used.
+func (c *fieldDocsCache) touch(field string) {
+	for i, f := range c.fields {
+		if f == field {
+			copy(c.fields[i:], c.fields[i+1:])
+			c.fields[len(c.fields)-1] = field
+			return
+		}
+	}
+}
+
+// fieldDocs returns the bitmap of the documents of the segment having
+// any term for the field, deleted or not.  The bitmap is not persisted
+// with the segment, so it is worked out from the postings of every term
+// of the field the first time it is needed, which takes time in the
+// number of terms.  It is then cached with the segment, which never
+// changes, within the bounds of FieldDocsCacheMaxBytes, and carried over
+// to the segment the segment is merged into, see mergedFieldDocs.  The
+// bitmap is shared, so is not to be modified.
+func (s *SegmentSnapshot) fieldDocs(field string) (*roaring.Bitmap, error) {
+	cached, ok := s.cachedMeta.load(fieldDocsCacheKey)
+	if !ok {
+		cached, _ = s.cachedMeta.loadOrStore(fieldDocsCacheKey, &fieldDocsCache{
+			bitmaps: make(map[string]*roaring.Bitmap),
+		})
+	}
+	cache := cached.(*fieldDocsCache)
+	if bm, ok := cache.load(field); ok {
+		return bm, nil
+	}
+
+	dict, err := s.segment.Dictionary(field)
+	if err != nil {
+		return nil, err
+	}
+	rv := roaring.New()
+	var postings segment.PostingsList
+	var itr segment.PostingsIterator
+	dictItr := dict.AutomatonIterator(nil, nil, nil)
+	next, err := dictItr.Next()
+	for err == nil && next != nil {
+		postings, err = dict.PostingsList([]byte(next.Term), nil, postings)
+		if err != nil {
+			return nil, err
+		}
+		itr = postings.Iterator(false, false, false, itr)
+		if oitr, ok := itr.(segment.OptimizablePostingsIterator); ok {
+			if docNum, ok := oitr.DocNum1Hit(); ok {
+				rv.Add(uint32(docNum))
+			} else if bm := oitr.ActualBitmap(); bm != nil {
+				rv.Or(bm)
+			}
+		} else {
+			var p segment.Posting
+			p, err = itr.Next()
+			for err == nil && p != nil {
+				rv.Add(uint32(p.Number()))
+				p, err = itr.Next()
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		next, err = dictItr.Next()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rv.RunOptimize()
+	cache.store(field, rv)
+	return rv, nil
+}
+
+// mergedFieldDocs returns the field presence bitmaps of each segment
+// merged from the segments of the histories, for the fields whose
+// bitmaps are cached for every one of them, renumbering their documents
+// rather than working the bitmaps out again from the terms of the
+// merged segment.
+func mergedFieldDocs(numMerged int, histories map[uint64]*mergedSegmentHistory) []*fieldDocsCache {
+	merged := make([][]*mergedSegmentHistory, numMerged)
+	for _, history := range histories {
+		merged[history.batchID] = append(merged[history.batchID], history)
+	}
+	rv := make([]*fieldDocsCache, numMerged)
+	for i := range merged {
+		rv[i] = mergeFieldDocs(merged[i])
+	}
+	return rv
+}
+
+func mergeFieldDocs(histories []*mergedSegmentHistory) *fieldDocsCache {
+	if len(histories) == 0 {
+		return nil
+	}
+	var fields map[string][]*roaring.Bitmap
+	for i, history := range histories {
+		cached, ok := history.oldSegment.cachedMeta.load(fieldDocsCacheKey)
+		if !ok {
+			return nil
+		}
+		bitmaps := cached.(*fieldDocsCache).snapshot()
+		if i == 0 {
+			fields = make(map[string][]*roaring.Bitmap, len(bitmaps))
+			for field, bm := range bitmaps {
+				fields[field] = []*roaring.Bitmap{bm}
+			}
+			continue
+		}
+		for field := range fields {
+			bm, ok := bitmaps[field]
+			if !ok {
+				delete(fields, field)
+				continue
+			}
+			fields[field] = append(fields[field], bm)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	rv := &fieldDocsCache{bitmaps: make(map[string]*roaring.Bitmap, len(fields))}
+	for field, bitmaps := range fields {
+		merged := roaring.New()
+		for i, bm := range bitmaps {
+			newDocNums := histories[i].oldNewDocIDs
+			itr := bm.Iterator()
+			for itr.HasNext() {
+				oldDocNum := itr.Next()
+				// documents deleted before the merge are dropped
+				if int(oldDocNum) < len(newDocNums) && newDocNums[oldDocNum] <= math.MaxUint32 {
+					merged.Add(uint32(newDocNums[oldDocNum]))
+				}
+			}
+		}
+		merged.RunOptimize()
+		rv.store(field, merged)
+	}
+	return rv
+}
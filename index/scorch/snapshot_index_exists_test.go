@@ -0,0 +1,114 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorch
+
+import (
+	"math"
+	"testing"
+
+	"github.com/RoaringBitmap/roaring/v2"
+)
+
+func TestFieldDocsCacheBounded(t *testing.T) {
+	bitmap := func(docNums ...uint32) *roaring.Bitmap {
+		bm := roaring.BitmapOf(docNums...)
+		bm.RunOptimize()
+		return bm
+	}
+	a, b, c := bitmap(1, 3), bitmap(2, 5), bitmap(4, 6)
+
+	defer func(maxBytes uint64) {
+		FieldDocsCacheMaxBytes = maxBytes
+	}(FieldDocsCacheMaxBytes)
+	FieldDocsCacheMaxBytes = a.GetSizeInBytes() + b.GetSizeInBytes()
+
+	cache := &fieldDocsCache{bitmaps: make(map[string]*roaring.Bitmap)}
+	cache.store("a", a)
+	cache.store("b", b)
+	if _, ok := cache.load("a"); !ok {
+		t.Fatalf("expected a to be cached")
+	}
+
+	// b is the least recently used
+	cache.store("c", c)
+	if _, ok := cache.load("b"); ok {
+		t.Errorf("expected b to be dropped")
+	}
+	for _, field := range []string{"a", "c"} {
+		if _, ok := cache.load(field); !ok {
+			t.Errorf("expected %s to be cached", field)
+		}
+	}
+	if cache.bytes > FieldDocsCacheMaxBytes {
+		t.Errorf("expected at most %d bytes cached, got %d", FieldDocsCacheMaxBytes, cache.bytes)
+	}
+
+	// a bitmap beyond the bounds is never cached
+	FieldDocsCacheMaxBytes = 1
+	cache.store("d", bitmap(7))
+	if _, ok := cache.load("d"); ok {
+		t.Errorf("expected d not to be cached")
+	}
+}
+
+func TestMergedFieldDocs(t *testing.T) {
+	segmentSnapshot := func(fields map[string]*roaring.Bitmap) *SegmentSnapshot {
+		cache := &fieldDocsCache{bitmaps: make(map[string]*roaring.Bitmap)}
+		for field, bm := range fields {
+			cache.store(field, bm)
+		}
+		meta := newCachedMeta()
+		meta.store(fieldDocsCacheKey, cache)
+		return &SegmentSnapshot{cachedMeta: meta}
+	}
+	histories := map[uint64]*mergedSegmentHistory{
+		1: {
+			batchID:      0,
+			oldNewDocIDs: []uint64{0, math.MaxUint64, 1},
+			oldSegment: segmentSnapshot(map[string]*roaring.Bitmap{
+				"a": roaring.BitmapOf(0, 1),
+				"b": roaring.BitmapOf(2),
+			}),
+		},
+		2: {
+			batchID:      0,
+			oldNewDocIDs: []uint64{2, 3},
+			oldSegment: segmentSnapshot(map[string]*roaring.Bitmap{
+				"a": roaring.BitmapOf(1),
+			}),
+		},
+		3: {
+			batchID:      1,
+			oldNewDocIDs: []uint64{0},
+			oldSegment:   &SegmentSnapshot{cachedMeta: newCachedMeta()},
+		},
+	}
+
+	merged := mergedFieldDocs(2, histories)
+	if len(merged) != 2 {
+		t.Fatalf("expected field docs for 2 merged segments, got %d", len(merged))
+	}
+	// the deleted document is dropped, and the field
+	// not cached for every segment is not carried over
+	if bm, ok := merged[0].load("a"); !ok || !bm.Equals(roaring.BitmapOf(0, 3)) {
+		t.Errorf("expected a in documents 0 and 3, got %v", bm)
+	}
+	if _, ok := merged[0].load("b"); ok {
+		t.Errorf("expected b not to be carried over")
+	}
+	if merged[1] != nil {
+		t.Errorf("expected nothing carried over without cached bitmaps")
+	}
+}
@@ -393,6 +393,12 @@ func (c *cachedMeta) load(field string) (rv interface{}, ok bool) {
 	return c.meta.Load(field)
 }
 
+// loadOrStore the value for a field, returning the value already present
+// in the cache if any, along with a boolean indicating whether it was.
+func (c *cachedMeta) loadOrStore(field string, val interface{}) (rv interface{}, loaded bool) {
+	return c.meta.LoadOrStore(field, val)
+}
+
 // contains reports whether the cache has an entry for the given field.
 func (c *cachedMeta) contains(field string) bool {
 	_, ok := c.meta.Load(field)
@@ -0,0 +1,210 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/RoaringBitmap/roaring/v2"
+	"github.com/blevesearch/bleve/v2/document"
+	index "github.com/blevesearch/bleve_index_api"
+	segment "github.com/blevesearch/scorch_segment_api/v2"
+)
+
+// versionKey is the internal key holding the last version introduced
+// into an index tracking versions, so that versions keep increasing
+// once the index is reopened.
+var versionKey = []byte("_$version")
+
+// Versioned reports whether the index tracks a version for each
+// document, as enabled by the "versions" config option.  Every batch
+// is then assigned the next version in sequence, held by the
+// document.VersionFieldName stored field of the documents it indexes.
+// A batch which is rejected still uses up its version, so versions
+// increase, but are not necessarily consecutive.
+func (s *Scorch) Versioned() bool {
+	return s.versions
+}
+
+// BatchWithPreconditions executes the batch only if the documents it
+// operates on satisfy their preconditions, as of the moment the batch
+// is introduced.  It returns a *document.VersionConflictError otherwise.
+func (s *Scorch) BatchWithPreconditions(batch *index.Batch,
+	preconditions map[string]document.Precondition) error {
+	if !s.versions {
+		for _, p := range preconditions {
+			if p.Version != 0 {
+				return document.ErrVersionsNotTracked
+			}
+		}
+	}
+	return s.batch(batch, preconditions)
+}
+
+// addVersionFields assigns the next version to the
+// documents indexed by the batch.
+func (s *Scorch) addVersionFields(batch *index.Batch) (uint64, error) {
+	for _, doc := range batch.IndexOps {
+		if _, ok := doc.(*document.Document); doc != nil && !ok {
+			return 0, fmt.Errorf("cannot version document of type %T", doc)
+		}
+	}
+	version := atomic.AddUint64(&s.nextVersion, 1)
+	for _, doc := range batch.IndexOps {
+		if doc != nil {
+			setVersionField(doc.(*document.Document), version)
+		}
+	}
+	return version, nil
+}
+
+// setVersionField sets the version field of the document, replacing
+// the one added when the same batch was executed before, such as
+// when retrying it after a version conflict.
+func setVersionField(doc *document.Document, version uint64) {
+	field := document.NewVersionField(version)
+	for i, f := range doc.Fields {
+		if f.Name() == document.VersionFieldName {
+			doc.Fields[i] = field
+			return
+		}
+	}
+	doc.AddField(field)
+}
+
+// checkVersions returns a *document.VersionConflictError when any
+// preconditions of the introduction do not hold in the root.
+// Otherwise, when a later version was already introduced, it
+// returns the ids of the introduction already overwritten by a
+// later version, which must not replace them.  The ids include
+// those the introduction deletes, so a stale delete does not
+// remove a later version either.  However, a delete leaves no
+// version behind, so a stale update of a document deleted by a
+// later version is not detected, and brings the document back.
+func (s *Scorch) checkVersions(root *IndexSnapshot,
+	next *segmentIntroduction) ([]string, error) {
+	var conflicts []document.VersionConflict
+	for id, p := range next.preconditions {
+		version, exists, err := root.liveVersion(id)
+		if err != nil {
+			return nil, err
+		}
+		if (p.Absent && exists) ||
+			(p.Version != 0 && (!exists || version != p.Version)) {
+			conflicts = append(conflicts, document.VersionConflict{
+				ID:           id,
+				Precondition: p,
+				Version:      version,
+				Exists:       exists,
+			})
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, &document.VersionConflictError{Conflicts: conflicts}
+	}
+
+	// batches are assigned versions as they start, so one may be
+	// introduced after a later one which finished sooner
+	if next.version == 0 || next.version > s.introducedVersion {
+		return nil, nil
+	}
+	var stale []string
+	for _, id := range next.ids {
+		version, _, err := root.liveVersion(id)
+		if err != nil {
+			return nil, err
+		}
+		if version > next.version {
+			stale = append(stale, id)
+		}
+	}
+	return stale, nil
+}
+
+// liveVersion returns the version of the live document with the id,
+// which is zero for documents indexed before versions were tracked.
+func (is *IndexSnapshot) liveVersion(id string) (version uint64, exists bool, err error) {
+	ids := []string{id}
+	for i := len(is.segment) - 1; i >= 0; i-- {
+		docNums, err := is.segment[i].DocNumbers(ids)
+		if err != nil {
+			return 0, false, err
+		}
+		if docNums.IsEmpty() {
+			continue
+		}
+		version, err = is.segment[i].version(uint64(docNums.Minimum()))
+		return version, true, err
+	}
+	return 0, false, nil
+}
+
+// DocumentVersion returns the version of the document, and whether
+// the index tracks versions.
+func (is *IndexSnapshot) DocumentVersion(id index.IndexInternalID) (uint64, bool, error) {
+	if is.parent == nil || !is.parent.versions {
+		return 0, false, nil
+	}
+	segmentIndex, localDocNum, err := is.segmentIndexAndLocalDocNum(id)
+	if err != nil {
+		return 0, false, err
+	}
+	version, err := is.segment[segmentIndex].version(localDocNum)
+	if err != nil {
+		return 0, false, err
+	}
+	return version, true, nil
+}
+
+func (s *SegmentSnapshot) version(docNum uint64) (version uint64, err error) {
+	verr := s.VisitDocument(docNum, func(field string, typ byte, value []byte, pos []uint64) bool {
+		if field == document.VersionFieldName {
+			version, err = document.DecodeVersion(value)
+			return false
+		}
+		return true
+	})
+	if verr != nil {
+		return 0, verr
+	}
+	return version, err
+}
+
+// staleDocNumbers returns the doc numbers in the segment of the
+// ids, along with their nested documents.
+func staleDocNumbers(seg segment.Segment, ids []string) (*roaring.Bitmap, error) {
+	rv, err := seg.DocNumbers(ids)
+	if err != nil {
+		return nil, err
+	}
+	if ns, ok := seg.(segment.NestedSegment); ok {
+		rv = ns.AddNestedDocuments(rv)
+	}
+	return rv, nil
+}
+
+func snapshotVersion(is *IndexSnapshot) uint64 {
+	if v, ok := is.internal[string(versionKey)]; ok {
+		version, _ := binary.Uvarint(v)
+		return version
+	}
+	return 0
+}
+
+func encodeVersion(version uint64) []byte {
+	return binary.AppendUvarint(nil, version)
+}
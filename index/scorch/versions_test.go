@@ -0,0 +1,105 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorch
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/document"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+func TestVersions(t *testing.T) {
+	cfg := CreateConfig("TestVersions")
+	cfg["versions"] = true
+	err := InitTest(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := DestroyTest(cfg)
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+
+	analysisQueue := index.NewAnalysisQueue(1)
+	idx, err := NewScorch(Name, cfg, analysisQueue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = idx.Open()
+	if err != nil {
+		t.Fatalf("error opening index: %v", err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+	s := idx.(*Scorch)
+
+	liveVersion := func(id string) uint64 {
+		t.Helper()
+		reader, err := idx.Reader()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = reader.Close() }()
+		version, _, err := reader.(*IndexSnapshot).liveVersion(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return version
+	}
+	update := func(id string) {
+		t.Helper()
+		doc := document.NewDocument(id)
+		doc.AddField(document.NewTextField("name", []uint64{}, []byte("test")))
+		batch := index.NewBatch()
+		batch.Update(doc)
+		if err := idx.Batch(batch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// versions above 2^53 are kept exactly
+	s.nextVersion = 1<<60 + 1
+	update("a")
+	if version := liveVersion("a"); version != 1<<60+2 {
+		t.Fatalf("expected version %d, got %d", uint64(1<<60+2), version)
+	}
+	update("a")
+	if version := liveVersion("a"); version != 1<<60+3 {
+		t.Fatalf("expected version %d, got %d", uint64(1<<60+3), version)
+	}
+
+	// a delete introduced after a later version of the
+	// document does not remove it
+	root := s.currentSnapshot()
+	defer func() { _ = root.DecRef() }()
+	stale, err := s.checkVersions(root, &segmentIntroduction{
+		ids:     []string{"a", "b"},
+		version: 1<<60 + 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(stale, []string{"a"}) {
+		t.Errorf("expected the delete of a to be stale, got %v", stale)
+	}
+}
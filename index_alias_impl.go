@@ -37,6 +37,7 @@ type indexAliasImpl struct {
 	// then the user can set the mapping here to avoid
 	// checking the mapping of each index in the alias
 	mapping mapping.IndexMapping
+	pits    pointInTimes
 }
 
 // NewIndexAlias creates a new IndexAlias over the provided
@@ -195,6 +196,10 @@ func (i *indexAliasImpl) Search(req *SearchRequest) (*SearchResult, error) {
 }
 
 func (i *indexAliasImpl) SearchInContext(ctx context.Context, req *SearchRequest) (*SearchResult, error) {
+	return searchWithInnerHits(ctx, req, i, i.searchInContext)
+}
+
+func (i *indexAliasImpl) searchInContext(ctx context.Context, req *SearchRequest) (*SearchResult, error) {
 	i.mutex.RLock()
 	defer i.mutex.RUnlock()
 
@@ -202,7 +207,21 @@ func (i *indexAliasImpl) SearchInContext(ctx context.Context, req *SearchRequest
 		return nil, ErrorIndexClosed
 	}
 
-	if len(i.indexes) < 1 {
+	// a point in time searches the indexes pinned when it was
+	// opened, each targeting its own point in time
+	indexes := i.indexes
+	if req.PointInTimeID != "" {
+		pit, err := i.pits.acquire(req.PointInTimeID)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = i.pits.done(pit)
+		}()
+		indexes = pit.indexes
+	}
+
+	if len(indexes) < 1 {
 		return nil, ErrorAliasEmpty
 	}
 
@@ -218,7 +237,7 @@ func (i *indexAliasImpl) SearchInContext(ctx context.Context, req *SearchRequest
 			synonyms: !isMatchNoneQuery(req.Query),
 			bm25:     bm25PreSearch,
 		}
-		return preSearchDataSearch(ctx, req, flags, i.indexes...)
+		return preSearchDataSearch(ctx, req, flags, indexes...)
 	}
 
 	// at this point we know we are doing a real search
@@ -233,18 +252,18 @@ func (i *indexAliasImpl) SearchInContext(ctx context.Context, req *SearchRequest
 	var preSearchData map[string]map[string]interface{}
 	if req.PreSearchData != nil {
 		var err error
-		preSearchData, err = redistributePreSearchData(req, i.indexes)
+		preSearchData, err = redistributePreSearchData(req, indexes)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	// short circuit the simple case
-	if len(i.indexes) == 1 {
+	if len(indexes) == 1 {
 		if preSearchData != nil {
-			req.PreSearchData = preSearchData[i.indexes[0].Name()]
+			req.PreSearchData = preSearchData[indexes[0].Name()]
 		}
-		return i.indexes[0].SearchInContext(ctx, req)
+		return indexes[0].SearchInContext(ctx, req)
 	}
 
 	// rescorer will be set if score fusion is supposed to happen
@@ -285,7 +304,7 @@ func (i *indexAliasImpl) SearchInContext(ctx context.Context, req *SearchRequest
 	}
 	if req.PreSearchData == nil && flags != nil {
 		searchStart := time.Now()
-		preSearchResult, err := preSearch(ctx, req, flags, i.indexes...)
+		preSearchResult, err := preSearch(ctx, req, flags, indexes...)
 		if err != nil {
 			return nil, err
 		}
@@ -307,7 +326,7 @@ func (i *indexAliasImpl) SearchInContext(ctx context.Context, req *SearchRequest
 			sr = finalizeSearchResult(ctx, req, preSearchResult, rescorer)
 			// no need to run the 2nd phase MultiSearch(..)
 		} else {
-			preSearchData, fusionKnnHits, err = constructPreSearchDataAndFusionKnnHits(req, flags, preSearchResult, rescorer, i.indexes)
+			preSearchData, fusionKnnHits, err = constructPreSearchDataAndFusionKnnHits(req, flags, preSearchResult, rescorer, indexes)
 			if err != nil {
 				return nil, err
 			}
@@ -318,7 +337,7 @@ func (i *indexAliasImpl) SearchInContext(ctx context.Context, req *SearchRequest
 	// check if search result was generated as part of preSearch itself
 	if sr == nil {
 		multiSearchParams := &multiSearchParams{preSearchData, rescorer, fusionKnnHits}
-		sr, err = MultiSearch(ctx, req, multiSearchParams, i.indexes...)
+		sr, err = MultiSearch(ctx, req, multiSearchParams, indexes...)
 		if err != nil {
 			return nil, err
 		}
@@ -426,7 +445,46 @@ func (i *indexAliasImpl) Close() error {
 	defer i.mutex.Unlock()
 
 	i.open = false
-	return nil
+	return i.pits.releaseAll()
+}
+
+// OpenPointInTime opens a point in time on each index in the alias,
+// returning the id to be set as SearchRequest.PointInTimeID to search
+// them.  Every index in the alias must support points in time, and
+// later changes to the indexes in the alias do not affect the indexes
+// searched by the point in time.
+func (i *indexAliasImpl) OpenPointInTime(ttl time.Duration) (string, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	if !i.open {
+		return "", ErrorIndexClosed
+	}
+
+	pit := &pointInTime{indexes: make([]Index, 0, len(i.indexes))}
+	for _, in := range i.indexes {
+		pitIndex, ok := in.(PointInTimeIndex)
+		if !ok {
+			_ = pit.close()
+			return "", ErrorPointInTimeNotSupported
+		}
+		id, err := pitIndex.OpenPointInTime(ttl)
+		if err != nil {
+			_ = pit.close()
+			return "", err
+		}
+		pit.indexes = append(pit.indexes, &pointInTimeIndex{
+			PointInTimeIndex: pitIndex,
+			id:               id,
+		})
+	}
+	return i.pits.open(pit, ttl), nil
+}
+
+// ReleasePointInTime releases the point in time with the provided id,
+// along with the points in time it holds on the indexes in the alias.
+func (i *indexAliasImpl) ReleasePointInTime(id string) error {
+	return i.pits.release(id)
 }
 
 // SetIndexMapping sets the mapping for the alias and must be used
@@ -760,6 +818,11 @@ func requestSatisfiedByPreSearch(req *SearchRequest, flags *preSearchFlags) bool
 	if flags.synonyms {
 		return false
 	}
+	// collapse keys are gathered by the collector, which the
+	// preSearch does not run
+	if req.Collapse != nil {
+		return false
+	}
 	if flags.knn && isKNNrequestSatisfiedByPreSearch(req) {
 		return true
 	}
@@ -841,9 +904,11 @@ func preSearchDataSearch(ctx context.Context, req *SearchRequest, flags *preSear
 	asyncResults := make(chan *asyncSearchResult, len(indexes))
 	// run search on each index in separate go routine
 	var waitGroup sync.WaitGroup
+	// the histograms of the children are filled in once merged
+	childCtx := context.WithValue(ctx, search.MergeFacetsKey, true)
 	searchChildIndex := func(in Index, childReq *SearchRequest) {
 		rv := asyncSearchResult{Name: in.Name()}
-		rv.Result, rv.Err = in.SearchInContext(ctx, childReq)
+		rv.Result, rv.Err = in.SearchInContext(childCtx, childReq)
 		asyncResults <- &rv
 		waitGroup.Done()
 	}
@@ -977,6 +1042,11 @@ func hitsInCurrentPage(req *SearchRequest, hits []*search.DocumentMatch) []*sear
 		sorter := newSearchHitSorter(req.Sort, hits)
 		sortFunc(sorter)
 	}
+	// keep only the top hit for each collapse key, as
+	// each index collapses its own hits independently
+	if req.Collapse != nil {
+		hits = collapseHits(hits)
+	}
 	// now skip over the correct From
 	if req.From > 0 && len(hits) > req.From {
 		hits = hits[req.From:]
@@ -1023,9 +1093,11 @@ func MultiSearch(ctx context.Context, req *SearchRequest, params *multiSearchPar
 	// run search on each index in separate go routine
 	var waitGroup sync.WaitGroup
 
+	// the histograms of the children are filled in once merged
+	childCtx := context.WithValue(ctx, search.MergeFacetsKey, true)
 	searchChildIndex := func(in Index, childReq *SearchRequest) {
 		rv := asyncSearchResult{Name: in.Name()}
-		rv.Result, rv.Err = in.SearchInContext(ctx, childReq)
+		rv.Result, rv.Err = in.SearchInContext(childCtx, childReq)
 		asyncResults <- &rv
 		waitGroup.Done()
 	}
@@ -1082,9 +1154,10 @@ func MultiSearch(ctx context.Context, req *SearchRequest, params *multiSearchPar
 	sr.Hits = hitsInCurrentPage(req, sr.Hits)
 
 	// fix up facets
-	for name, fr := range req.Facets {
-		sr.Facets.Fixup(name, fr.Size)
+	if _, ok := ctx.Value(search.MergeFacetsKey).(bool); !ok {
+		req.Facets.fillHistograms(sr.Facets)
 	}
+	req.Facets.fixup(sr.Facets)
 
 	if reverseQueryExecution {
 		// reverse the sort back to the original
@@ -0,0 +1,275 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/blevesearch/bleve/v2/util"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// ByQueryBatchSize is the maximum number of documents applied
+// in each batch by DeleteByQuery and UpdateByQuery.
+var ByQueryBatchSize = 1000
+
+// UpdateByQueryFunc is invoked by UpdateByQuery with the stored fields
+// of each matching document, as of the snapshot being updated.  It
+// returns the replacement data to be indexed for the document, and
+// whether the document should be updated.
+type UpdateByQueryFunc func(doc index.Document) (interface{}, bool)
+
+// ByQueryIndex supports deleting and updating all the documents
+// matching a query.
+type ByQueryIndex interface {
+	Index
+	// DeleteByQuery deletes the documents matching the query.
+	DeleteByQuery(ctx context.Context, q query.Query) (*ByQueryResult, error)
+	// UpdateByQuery reindexes the documents matching the query with the
+	// data returned by fn.
+	UpdateByQuery(ctx context.Context, q query.Query, fn UpdateByQueryFunc) (*ByQueryResult, error)
+}
+
+// ByQueryFailure describes a document that could not be
+// deleted or updated.
+type ByQueryFailure struct {
+	ID  string
+	Err error
+}
+
+func (f *ByQueryFailure) Error() string {
+	return fmt.Sprintf("document %s: %v", f.ID, f.Err)
+}
+
+func (f *ByQueryFailure) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(struct {
+		ID  string `json:"id"`
+		Err string `json:"error"`
+	}{
+		ID:  f.ID,
+		Err: f.Err.Error(),
+	})
+}
+
+// ByQueryResult reports the outcome of a DeleteByQuery or UpdateByQuery.
+// Matched counts the documents matching the query, Applied those deleted
+// or updated, and Noops those the update function chose not to update.
+type ByQueryResult struct {
+	Matched  uint64            `json:"matched"`
+	Applied  uint64            `json:"applied"`
+	Noops    uint64            `json:"noops"`
+	Failures []*ByQueryFailure `json:"failures,omitempty"`
+	Took     time.Duration     `json:"took"`
+}
+
+func (r *ByQueryResult) merge(other *ByQueryResult) {
+	r.Matched += other.Matched
+	r.Applied += other.Applied
+	r.Noops += other.Noops
+	r.Failures = append(r.Failures, other.Failures...)
+}
+
+// DeleteByQuery deletes the documents matching the query in a snapshot
+// of the index taken when it is invoked, applying the deletes in batches
+// of at most ByQueryBatchSize.  Cancelling the context stops any further
+// batches from being applied, and returns the result so far along with
+// the context error.
+func (i *indexImpl) DeleteByQuery(ctx context.Context, q query.Query) (*ByQueryResult, error) {
+	return i.byQuery(ctx, q, func(b *Batch, _ index.IndexReader, id string) (bool, error) {
+		b.Delete(id)
+		return true, nil
+	})
+}
+
+// UpdateByQuery reindexes the documents matching the query in a snapshot
+// of the index taken when it is invoked, with the data returned by fn for
+// each of them.  Updates are applied in batches of at most
+// ByQueryBatchSize.  Cancelling the context stops any further batches from
+// being applied, and returns the result so far along with the context error.
+func (i *indexImpl) UpdateByQuery(ctx context.Context, q query.Query, fn UpdateByQueryFunc) (*ByQueryResult, error) {
+	return i.byQuery(ctx, q, func(b *Batch, r index.IndexReader, id string) (bool, error) {
+		doc, err := r.Document(id)
+		if err != nil {
+			return false, err
+		}
+		if doc == nil {
+			return false, nil
+		}
+		data, ok := fn(doc)
+		if !ok {
+			return false, nil
+		}
+		return true, b.Index(id, data)
+	})
+}
+
+// byQuery invokes apply to add the change for each document matching the
+// query to the current batch, which is executed once full.  Failures of
+// individual documents and batches are recorded in the result, while
+// other errors abort the operation.
+func (i *indexImpl) byQuery(ctx context.Context, q query.Query,
+	apply func(b *Batch, r index.IndexReader, id string) (bool, error)) (*ByQueryResult, error) {
+	start := time.Now()
+
+	i.mutex.RLock()
+	if !i.open {
+		i.mutex.RUnlock()
+		return nil, ErrorIndexClosed
+	}
+	indexReader, err := i.i.Reader()
+	i.mutex.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("error opening index reader %v", err)
+	}
+	defer func() {
+		_ = indexReader.Close()
+	}()
+
+	rv := &ByQueryResult{}
+	batch := newByQueryBatch(i, rv)
+
+	req := NewSearchRequest(q)
+	err = i.searchStreamReader(ctx, req, indexReader, func(hit *search.DocumentMatch) error {
+		rv.Matched++
+		ok, err := apply(batch.batch, indexReader, hit.ID)
+		if err != nil {
+			rv.Failures = append(rv.Failures, &ByQueryFailure{ID: hit.ID, Err: err})
+			return nil
+		}
+		if !ok {
+			rv.Noops++
+			return nil
+		}
+		return batch.added(hit.ID)
+	})
+	if err == nil {
+		err = batch.finish(ctx)
+	}
+	rv.Took = time.Since(start)
+	return rv, err
+}
+
+// byQueryBatch accumulates the changes of a by query operation,
+// executing them against the index in batches of ByQueryBatchSize.
+type byQueryBatch struct {
+	index  Index
+	batch  *Batch
+	ids    []string
+	result *ByQueryResult
+}
+
+func newByQueryBatch(idx Index, result *ByQueryResult) *byQueryBatch {
+	return &byQueryBatch{
+		index:  idx,
+		batch:  idx.NewBatch(),
+		result: result,
+	}
+}
+
+// added records that a change for the document was added to the
+// batch, executing the batch once full.
+func (b *byQueryBatch) added(id string) error {
+	b.ids = append(b.ids, id)
+	if len(b.ids) >= ByQueryBatchSize {
+		return b.flush()
+	}
+	return nil
+}
+
+// finish executes the final batch, unless the context was cancelled.
+func (b *byQueryBatch) finish(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return b.flush()
+	}
+}
+
+// flush executes the batch, recording a failure for each of its
+// documents if it fails, only a closed index aborts the operation.
+func (b *byQueryBatch) flush() error {
+	if len(b.ids) == 0 {
+		return nil
+	}
+	err := b.index.Batch(b.batch)
+	if err == ErrorIndexClosed {
+		return err
+	}
+	if err != nil {
+		for _, id := range b.ids {
+			b.result.Failures = append(b.result.Failures, &ByQueryFailure{ID: id, Err: err})
+		}
+	} else {
+		b.result.Applied += uint64(len(b.ids))
+	}
+	b.batch.Reset()
+	b.ids = b.ids[:0]
+	return nil
+}
+
+// DeleteByQuery deletes the documents matching the query in each index
+// of the alias.  Every index in the alias must support it.
+func (i *indexAliasImpl) DeleteByQuery(ctx context.Context, q query.Query) (*ByQueryResult, error) {
+	return i.byQuery(func(in ByQueryIndex) (*ByQueryResult, error) {
+		return in.DeleteByQuery(ctx, q)
+	})
+}
+
+// UpdateByQuery updates the documents matching the query in each index
+// of the alias.  Every index in the alias must support it.
+func (i *indexAliasImpl) UpdateByQuery(ctx context.Context, q query.Query, fn UpdateByQueryFunc) (*ByQueryResult, error) {
+	return i.byQuery(func(in ByQueryIndex) (*ByQueryResult, error) {
+		return in.UpdateByQuery(ctx, q, fn)
+	})
+}
+
+func (i *indexAliasImpl) byQuery(apply func(in ByQueryIndex) (*ByQueryResult, error)) (*ByQueryResult, error) {
+	start := time.Now()
+
+	i.mutex.RLock()
+	if !i.open {
+		i.mutex.RUnlock()
+		return nil, ErrorIndexClosed
+	}
+	indexes := make([]ByQueryIndex, 0, len(i.indexes))
+	for _, in := range i.indexes {
+		byQueryIndex, ok := in.(ByQueryIndex)
+		if !ok {
+			i.mutex.RUnlock()
+			return nil, ErrorByQueryNotSupported
+		}
+		indexes = append(indexes, byQueryIndex)
+	}
+	i.mutex.RUnlock()
+
+	rv := &ByQueryResult{}
+	for _, in := range indexes {
+		res, err := apply(in)
+		if res != nil {
+			rv.merge(res)
+		}
+		if err != nil {
+			rv.Took = time.Since(start)
+			return rv, err
+		}
+	}
+	rv.Took = time.Since(start)
+	return rv, nil
+}
@@ -16,6 +16,7 @@ package bleve
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -54,6 +55,12 @@ type indexImpl struct {
 	mutex sync.RWMutex
 	open  bool
 	stats *IndexStat
+	pits  pointInTimes
+
+	// held shared by the writers of documents, and exclusively by
+	// batches with patches, which read and then update documents,
+	// so that no other write lands in between
+	writeMutex sync.RWMutex
 }
 
 const storePath = "store"
@@ -327,6 +334,8 @@ func (i *indexImpl) Index(id string, data interface{}) (err error) {
 	if err != nil {
 		return
 	}
+	i.writeMutex.RLock()
+	defer i.writeMutex.RUnlock()
 	err = i.i.Update(doc)
 	return
 }
@@ -361,6 +370,8 @@ func (i *indexImpl) IndexSynonym(id string, collection string, definition *Synon
 	if err != nil {
 		return err
 	}
+	i.writeMutex.RLock()
+	defer i.writeMutex.RUnlock()
 	err = i.i.Update(doc)
 	return err
 }
@@ -393,6 +404,8 @@ func (i *indexImpl) IndexAdvanced(doc *document.Document) (err error) {
 		return ErrorIndexClosed
 	}
 
+	i.writeMutex.RLock()
+	defer i.writeMutex.RUnlock()
 	err = i.i.Update(doc)
 	return
 }
@@ -411,6 +424,8 @@ func (i *indexImpl) Delete(id string) (err error) {
 		return ErrorIndexClosed
 	}
 
+	i.writeMutex.RLock()
+	defer i.writeMutex.RUnlock()
 	err = i.i.Delete(id)
 	return
 }
@@ -427,7 +442,77 @@ func (i *indexImpl) Batch(b *Batch) error {
 		return ErrorIndexClosed
 	}
 
-	return i.i.Batch(b.internal)
+	internal := b.internal
+	if len(b.patches) > 0 {
+		// patches are applied to the latest stored documents,
+		// which no other writer may change until the batch is done
+		i.writeMutex.Lock()
+		defer i.writeMutex.Unlock()
+		var err error
+		internal, err = i.applyPatches(b)
+		if err != nil {
+			return err
+		}
+	} else {
+		i.writeMutex.RLock()
+		defer i.writeMutex.RUnlock()
+	}
+
+	if len(b.preconditions) > 0 {
+		versioned, ok := i.i.(versionedIndex)
+		if !ok {
+			return ErrorPreconditionsNotSupported
+		}
+		err := versioned.BatchWithPreconditions(internal, b.preconditions)
+		if err == document.ErrVersionsNotTracked {
+			return ErrorVersionsNotSupported
+		}
+		return err
+	}
+
+	return i.i.Batch(internal)
+}
+
+// applyPatches returns a copy of the batch in which the patches are
+// replaced with updates of the patched documents, leaving the batch
+// as it is should it fail.
+func (i *indexImpl) applyPatches(b *Batch) (rv *index.Batch, err error) {
+	indexReader, err := i.i.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := indexReader.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+
+	rv = index.NewBatch()
+	rv.Merge(b.internal)
+	rv.SetPersistedCallback(b.internal.PersistedCallback())
+	for id, patches := range b.patches {
+		doc, err := indexReader.Document(id)
+		if err != nil {
+			return nil, err
+		}
+		var source interface{} = map[string]interface{}{}
+		if doc != nil {
+			source, err = patchSource(doc)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, patch := range patches {
+			source = mergePatch(source, patch)
+		}
+		patched := document.NewDocument(id)
+		err = i.m.MapDocument(patched, source)
+		if err != nil {
+			return nil, err
+		}
+		rv.Update(patched)
+	}
+	return rv, nil
 }
 
 // Document is used to find the values of all the
@@ -660,9 +745,90 @@ func (i *indexImpl) preSearch(ctx context.Context, req *SearchRequest, reader in
 	}, nil
 }
 
+// searchReader opens a reader for the search request, or uses the one
+// pinned by the point in time targeted by the request.  The returned
+// function must be called once the reader is no longer in use.
+func (i *indexImpl) searchReader(req *SearchRequest) (index.IndexReader, func() error, error) {
+	if req.PointInTimeID != "" {
+		pit, err := i.pits.acquire(req.PointInTimeID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return pit.reader, func() error {
+			return i.pits.done(pit)
+		}, nil
+	}
+	indexReader, err := i.i.Reader()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening index reader %v", err)
+	}
+	return indexReader, indexReader.Close, nil
+}
+
+// searchContext sets up the context values required by the searchers and
+// collectors of any search operation against the index.
+func (i *indexImpl) searchContext(ctx context.Context) context.Context {
+	// Scoring model callback to be used to get scoring model
+	scoringModelCallback := func() string {
+		if isBM25Enabled(i.m) {
+			return index.BM25Scoring
+		}
+		return index.DefaultScoringModel
+	}
+	ctx = context.WithValue(ctx, search.GetScoringModelCallbackKey,
+		search.GetScoringModelCallbackFn(scoringModelCallback))
+
+	// Geo buffer pool callback to be used for getting geo buffer pool
+	var bufPool *s2.GeoBufferPool
+	getBufferPool := func() *s2.GeoBufferPool {
+		if bufPool == nil {
+			bufPool = s2.NewGeoBufferPool(search.MaxGeoBufPoolSize, search.MinGeoBufPoolSize)
+		}
+
+		return bufPool
+	}
+
+	ctx = context.WithValue(ctx, search.GeoBufferPoolCallbackKey, search.GeoBufferPoolCallbackFunc(getBufferPool))
+	// check if the index mapping has any nested fields, which should force
+	// all collectors and searchers to be run in nested mode
+	if nm, ok := i.m.(mapping.NestedMapping); ok {
+		if nm.CountNested() > 0 {
+			ctx = context.WithValue(ctx, search.NestedSearchKey, true)
+		}
+	}
+	return ctx
+}
+
+// requestHighlighter returns the highlighter for the request,
+// or nil if no highlighting was requested.
+func requestHighlighter(req *SearchRequest) (highlight.Highlighter, error) {
+	if req.Highlight == nil {
+		return nil, nil
+	}
+	// get the right highlighter
+	highlighter, err := Config.Cache.HighlighterNamed(Config.DefaultHighlighter)
+	if err != nil {
+		return nil, err
+	}
+	if req.Highlight.Style != nil {
+		highlighter, err = Config.Cache.HighlighterNamed(*req.Highlight.Style)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if highlighter == nil {
+		return nil, fmt.Errorf("no highlighter named `%s` registered", *req.Highlight.Style)
+	}
+	return highlighter, nil
+}
+
 // SearchInContext executes a search request operation within the provided
 // Context. Returns a SearchResult object or an error.
-func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr *SearchResult, err error) {
+func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (*SearchResult, error) {
+	return searchWithInnerHits(ctx, req, i, i.searchInContext)
+}
+
+func (i *indexImpl) searchInContext(ctx context.Context, req *SearchRequest) (sr *SearchResult, err error) {
 	i.mutex.RLock()
 	defer i.mutex.RUnlock()
 
@@ -673,12 +839,12 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 	}
 
 	// open a reader for this search
-	indexReader, err := i.i.Reader()
+	indexReader, closeReader, err := i.searchReader(req)
 	if err != nil {
-		return nil, fmt.Errorf("error opening index reader %v", err)
+		return nil, err
 	}
 	defer func() {
-		if cerr := indexReader.Close(); err == nil && cerr != nil {
+		if cerr := closeReader(); err == nil && cerr != nil {
 			err = cerr
 		}
 	}()
@@ -701,16 +867,7 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 	// ------------------------------------------------------------------------------------------
 	// set up additional contexts for any search operation that will proceed from
 	// here, such as presearch, knn collector, topn collector etc.
-
-	// Scoring model callback to be used to get scoring model
-	scoringModelCallback := func() string {
-		if isBM25Enabled(i.m) {
-			return index.BM25Scoring
-		}
-		return index.DefaultScoringModel
-	}
-	ctx = context.WithValue(ctx, search.GetScoringModelCallbackKey,
-		search.GetScoringModelCallbackFn(scoringModelCallback))
+	ctx = i.searchContext(ctx)
 
 	// This callback and variable handles the tracking of bytes read
 	//  1. as part of creation of tfr and its Next() calls which is
@@ -733,25 +890,6 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 	}()
 
 	ctx = context.WithValue(ctx, search.SearchIOStatsCallbackKey, search.SearchIOStatsCallbackFunc(sendBytesRead))
-
-	// Geo buffer pool callback to be used for getting geo buffer pool
-	var bufPool *s2.GeoBufferPool
-	getBufferPool := func() *s2.GeoBufferPool {
-		if bufPool == nil {
-			bufPool = s2.NewGeoBufferPool(search.MaxGeoBufPoolSize, search.MinGeoBufPoolSize)
-		}
-
-		return bufPool
-	}
-
-	ctx = context.WithValue(ctx, search.GeoBufferPoolCallbackKey, search.GeoBufferPoolCallbackFunc(getBufferPool))
-	// check if the index mapping has any nested fields, which should force
-	// all collectors and searchers to be run in nested mode
-	if nm, ok := i.m.(mapping.NestedMapping); ok {
-		if nm.CountNested() > 0 {
-			ctx = context.WithValue(ctx, search.NestedSearchKey, true)
-		}
-	}
 	// ------------------------------------------------------------------------------------------
 
 	if _, ok := ctx.Value(search.PreSearchKey).(bool); ok {
@@ -785,6 +923,21 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 	if err != nil {
 		return nil, err
 	}
+	if req.Collapse != nil {
+		coll.SetCollapse(req.Collapse.Field)
+	}
+
+	var runtimeFields *search.RuntimeFields
+	facetsMapping := i.m
+	if len(req.RuntimeFields) > 0 {
+		runtimeFields, err = req.RuntimeFields.build(i.m)
+		if err != nil {
+			return nil, err
+		}
+		coll.SetRuntimeFields(runtimeFields)
+		// facets on runtime fields decode their values by their types
+		facetsMapping = &runtimeFieldsMapping{IndexMapping: i.m, runtimeFields: runtimeFields}
+	}
 
 	var knnHits []*search.DocumentMatch
 	var skipKNNCollector bool
@@ -889,62 +1042,9 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 	}()
 
 	if req.Facets != nil {
-		facetsBuilder := search.NewFacetsBuilder(indexReader)
-		for facetName, facetRequest := range req.Facets {
-			if facetRequest.NumericRanges != nil {
-				// build numeric range facet
-				facetBuilder := facet.NewNumericFacetBuilder(facetRequest.Field, facetRequest.Size)
-				for _, nr := range facetRequest.NumericRanges {
-					facetBuilder.AddRange(nr.Name, nr.Min, nr.Max)
-				}
-				facetsBuilder.Add(facetName, facetBuilder)
-			} else if facetRequest.DateTimeRanges != nil {
-				// build date range facet
-				facetBuilder := facet.NewDateTimeFacetBuilder(facetRequest.Field, facetRequest.Size)
-				for _, dr := range facetRequest.DateTimeRanges {
-					dateTimeParserName := defaultDateTimeParser
-					if dr.DateTimeParser != "" {
-						dateTimeParserName = dr.DateTimeParser
-					}
-					dateTimeParser := i.m.DateTimeParserNamed(dateTimeParserName)
-					if dateTimeParser == nil {
-						return nil, fmt.Errorf("no date time parser named `%s` registered", dateTimeParserName)
-					}
-					start, end, err := dr.ParseDates(dateTimeParser)
-					if err != nil {
-						return nil, fmt.Errorf("ParseDates err: %v, using date time parser named %s", err, dateTimeParserName)
-					}
-					if start.IsZero() && end.IsZero() {
-						return nil, fmt.Errorf("date range query must specify either start, end or both for date range name '%s'", dr.Name)
-					}
-					facetBuilder.AddRange(dr.Name, start, end)
-				}
-				facetsBuilder.Add(facetName, facetBuilder)
-			} else {
-				// build terms facet
-				facetBuilder := facet.NewTermsFacetBuilder(facetRequest.Field, facetRequest.Size)
-
-				// Set prefix filter if provided
-				if facetRequest.TermPrefix != "" {
-					facetBuilder.SetPrefixFilter(facetRequest.TermPrefix)
-				}
-
-				// Set regex filter if provided
-				if facetRequest.TermPattern != "" {
-					// Use cached compiled pattern if available, otherwise compile it now
-					if facetRequest.compiledPattern != nil {
-						facetBuilder.SetRegexFilter(facetRequest.compiledPattern)
-					} else {
-						regex, err := regexp.Compile(facetRequest.TermPattern)
-						if err != nil {
-							return nil, fmt.Errorf("error compiling regex pattern for facet '%s': %v", facetName, err)
-						}
-						facetBuilder.SetRegexFilter(regex)
-					}
-				}
-
-				facetsBuilder.Add(facetName, facetBuilder)
-			}
+		facetsBuilder, err := newFacetsBuilder(facetsMapping, indexReader, req.Facets)
+		if err != nil {
+			return nil, err
 		}
 		coll.SetFacetsBuilder(facetsBuilder)
 	}
@@ -974,23 +1074,9 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 
 	hits := coll.Results()
 
-	var highlighter highlight.Highlighter
-
-	if req.Highlight != nil {
-		// get the right highlighter
-		highlighter, err = Config.Cache.HighlighterNamed(Config.DefaultHighlighter)
-		if err != nil {
-			return nil, err
-		}
-		if req.Highlight.Style != nil {
-			highlighter, err = Config.Cache.HighlighterNamed(*req.Highlight.Style)
-			if err != nil {
-				return nil, err
-			}
-		}
-		if highlighter == nil {
-			return nil, fmt.Errorf("no highlighter named `%s` registered", *req.Highlight.Style)
-		}
+	highlighter, err := requestHighlighter(req)
+	if err != nil {
+		return nil, err
 	}
 
 	var storedFieldsCost uint64
@@ -1006,6 +1092,18 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 		}
 		storedFieldsCost += storedFieldsBytes
 	}
+	if runtimeFields != nil {
+		runtimeFieldsBytes, err := loadRuntimeFields(hits, req, runtimeFields, indexReader)
+		if err != nil {
+			return nil, err
+		}
+		storedFieldsCost += runtimeFieldsBytes
+	}
+	if req.Collapse != nil {
+		for _, hit := range hits {
+			hit.CollapseValue = collapseValue(facetsMapping, req.Collapse.Field, hit.CollapseKey)
+		}
+	}
 
 	totalSearchCost += storedFieldsCost
 	search.RecordSearchCost(ctx, search.AddM, storedFieldsCost)
@@ -1048,6 +1146,11 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 		Facets:   coll.FacetResults(),
 	}
 
+	// histograms merged with those of other indexes are filled in once merged
+	if _, ok := ctx.Value(search.MergeFacetsKey).(bool); !ok {
+		req.Facets.fillHistograms(rv.Facets)
+	}
+
 	// rescore if fusion flag is set
 	if rescorer != nil {
 		rv.Hits, rv.Total, rv.MaxScore = rescorer.rescore(rv.Hits, knnHits)
@@ -1062,20 +1165,158 @@ func (i *indexImpl) SearchInContext(ctx context.Context, req *SearchRequest) (sr
 	return rv, nil
 }
 
+// newFacetsBuilder builds the facets builder for the requested facets,
+// including the child facets nested within each of their buckets.
+func newFacetsBuilder(m mapping.IndexMapping, indexReader index.IndexReader,
+	facets FacetsRequest) (*search.FacetsBuilder, error) {
+	facetsBuilder := search.NewFacetsBuilder(indexReader)
+	for facetName, facetRequest := range facets {
+		facetBuilder, err := newFacetBuilder(m, facetName, facetRequest)
+		if err != nil {
+			return nil, err
+		}
+		if len(facetRequest.Facets) > 0 {
+			nestedBuilder, ok := facetBuilder.(search.NestedFacetBuilder)
+			if !ok {
+				return nil, fmt.Errorf("facet '%s' does not support child facets", facetName)
+			}
+			// build the child facets once up front, so that any error is
+			// reported here rather than when the first bucket is seen
+			subFacetsBuilder, err := newFacetsBuilder(m, indexReader, facetRequest.Facets)
+			if err != nil {
+				return nil, err
+			}
+			subFacets := facetRequest.Facets
+			nestedBuilder.SetSubFacets(search.NewSubFacetsBuilder(subFacetsBuilder.RequiredFields(),
+				func() *search.FacetsBuilder {
+					rv, _ := newFacetsBuilder(m, indexReader, subFacets)
+					return rv
+				}))
+		}
+		facetsBuilder.Add(facetName, facetBuilder)
+	}
+	return facetsBuilder, nil
+}
+
+func newFacetBuilder(m mapping.IndexMapping, facetName string,
+	facetRequest *FacetRequest) (search.FacetBuilder, error) {
+	if facetRequest.Metric != "" {
+		// build metric facet, date time values must be decoded differently
+		dateTime := m.FieldMappingForPath(facetRequest.Field).Type == "datetime"
+		facetBuilder, err := facet.NewMetricFacetBuilder(facetRequest.Field, facetRequest.Metric, dateTime)
+		if err != nil {
+			return nil, fmt.Errorf("error building metric facet '%s': %v", facetName, err)
+		}
+		facetBuilder.SetPercents(facetRequest.Percents)
+		return facetBuilder, nil
+	} else if facetRequest.Interval != 0 {
+		// build histogram facet
+		facetBuilder, err := facet.NewHistogramFacetBuilder(facetRequest.Field,
+			facetRequest.Size, facetRequest.Interval, facetRequest.MinDocCount)
+		if err != nil {
+			return nil, fmt.Errorf("error building histogram facet '%s': %v", facetName, err)
+		}
+		return facetBuilder, nil
+	} else if facetRequest.CalendarInterval != "" {
+		// build date histogram facet
+		location := time.UTC
+		if facetRequest.TimeZone != "" {
+			var err error
+			location, err = time.LoadLocation(facetRequest.TimeZone)
+			if err != nil {
+				return nil, fmt.Errorf("invalid time zone '%s' for facet '%s': %v",
+					facetRequest.TimeZone, facetName, err)
+			}
+		}
+		facetBuilder, err := facet.NewDateHistogramFacetBuilder(facetRequest.Field,
+			facetRequest.Size, facetRequest.CalendarInterval, location, facetRequest.MinDocCount)
+		if err != nil {
+			return nil, fmt.Errorf("error building date histogram facet '%s': %v", facetName, err)
+		}
+		return facetBuilder, nil
+	} else if facetRequest.NumericRanges != nil {
+		// build numeric range facet
+		facetBuilder := facet.NewNumericFacetBuilder(facetRequest.Field, facetRequest.Size)
+		for _, nr := range facetRequest.NumericRanges {
+			facetBuilder.AddRange(nr.Name, nr.Min, nr.Max)
+		}
+		return facetBuilder, nil
+	} else if facetRequest.DateTimeRanges != nil {
+		// build date range facet
+		facetBuilder := facet.NewDateTimeFacetBuilder(facetRequest.Field, facetRequest.Size)
+		for _, dr := range facetRequest.DateTimeRanges {
+			dateTimeParserName := defaultDateTimeParser
+			if dr.DateTimeParser != "" {
+				dateTimeParserName = dr.DateTimeParser
+			}
+			dateTimeParser := m.DateTimeParserNamed(dateTimeParserName)
+			if dateTimeParser == nil {
+				return nil, fmt.Errorf("no date time parser named `%s` registered", dateTimeParserName)
+			}
+			start, end, err := dr.ParseDates(dateTimeParser)
+			if err != nil {
+				return nil, fmt.Errorf("ParseDates err: %v, using date time parser named %s", err, dateTimeParserName)
+			}
+			if start.IsZero() && end.IsZero() {
+				return nil, fmt.Errorf("date range query must specify either start, end or both for date range name '%s'", dr.Name)
+			}
+			facetBuilder.AddRange(dr.Name, start, end)
+		}
+		return facetBuilder, nil
+	}
+
+	// build terms facet
+	facetBuilder := facet.NewTermsFacetBuilder(facetRequest.Field, facetRequest.Size)
+
+	// Set prefix filter if provided
+	if facetRequest.TermPrefix != "" {
+		facetBuilder.SetPrefixFilter(facetRequest.TermPrefix)
+	}
+
+	// Set regex filter if provided
+	if facetRequest.TermPattern != "" {
+		// Use cached compiled pattern if available, otherwise compile it now
+		if facetRequest.compiledPattern != nil {
+			facetBuilder.SetRegexFilter(facetRequest.compiledPattern)
+		} else {
+			regex, err := regexp.Compile(facetRequest.TermPattern)
+			if err != nil {
+				return nil, fmt.Errorf("error compiling regex pattern for facet '%s': %v", facetName, err)
+			}
+			facetBuilder.SetRegexFilter(regex)
+		}
+	}
+
+	return facetBuilder, nil
+}
+
 func LoadAndHighlightFields(hit *search.DocumentMatch, req *SearchRequest,
 	indexName string, r index.IndexReader,
 	highlighter highlight.Highlighter,
 ) (error, uint64) {
 	var totalStoredFieldsBytes uint64
-	if len(req.Fields) > 0 || highlighter != nil {
+	if vr, ok := r.(documentVersionReader); ok && hit.Version == 0 {
+		var err error
+		hit.Version, _, err = vr.DocumentVersion(hit.IndexInternalID)
+		if err != nil {
+			return err, totalStoredFieldsBytes
+		}
+	}
+	if len(req.Fields) > 0 || highlighter != nil || req.Source != nil {
 		doc, err := r.Document(hit.ID)
 		if err == nil && doc != nil {
+			if req.Source != nil && hit.Source == nil {
+				hit.Source, err = DocumentSource(doc, req.Source)
+				if err != nil {
+					return err, totalStoredFieldsBytes
+				}
+			}
 			if len(req.Fields) > 0 && hit.Fields == nil {
 				totalStoredFieldsBytes = doc.StoredFieldsBytes()
 				fieldsToLoad := deDuplicate(req.Fields)
 				for _, f := range fieldsToLoad {
 					doc.VisitFields(func(docF index.Field) {
-						if f == "*" || docF.Name() == f {
+						if (f == "*" && !isMetadataField(docF.Name())) || docF.Name() == f {
 							var value interface{}
 							switch docF := docF.(type) {
 							case index.TextField:
@@ -1088,30 +1329,7 @@ func LoadAndHighlightFields(hit *search.DocumentMatch, req *SearchRequest,
 							case index.DateTimeField:
 								datetime, layout, err := docF.DateTime()
 								if err == nil {
-									if layout == "" {
-										// missing layout means we fallback to
-										// the default layout which is RFC3339
-										value = datetime.Format(time.RFC3339)
-									} else {
-										// the layout here can now either be representative
-										// of an actual datetime layout or a timestamp
-										switch layout {
-										case seconds.Name:
-											value = strconv.FormatInt(datetime.Unix(), 10)
-										case milliseconds.Name:
-											value = strconv.FormatInt(datetime.UnixMilli(), 10)
-										case microseconds.Name:
-											value = strconv.FormatInt(datetime.UnixMicro(), 10)
-										case nanoseconds.Name:
-											value = strconv.FormatInt(datetime.UnixNano(), 10)
-										default:
-											// the layout for formatting the date to a string
-											// is provided by a datetime parser which is not
-											// handling the timestamp case, hence the layout
-											// can be directly used to format the date
-											value = datetime.Format(layout)
-										}
-									}
+									value = formatDateTime(datetime, layout)
 								}
 							case index.BooleanField:
 								boolean, err := docF.Boolean()
@@ -1170,6 +1388,34 @@ func LoadAndHighlightFields(hit *search.DocumentMatch, req *SearchRequest,
 
 const NestedDocumentKey = "_$nested"
 
+// formatDateTime formats a stored datetime value using the
+// layout of the datetime parser it was indexed with.
+func formatDateTime(datetime time.Time, layout string) string {
+	if layout == "" {
+		// missing layout means we fallback to
+		// the default layout which is RFC3339
+		return datetime.Format(time.RFC3339)
+	}
+	// the layout here can now either be representative
+	// of an actual datetime layout or a timestamp
+	switch layout {
+	case seconds.Name:
+		return strconv.FormatInt(datetime.Unix(), 10)
+	case milliseconds.Name:
+		return strconv.FormatInt(datetime.UnixMilli(), 10)
+	case microseconds.Name:
+		return strconv.FormatInt(datetime.UnixMicro(), 10)
+	case nanoseconds.Name:
+		return strconv.FormatInt(datetime.UnixNano(), 10)
+	default:
+		// the layout for formatting the date to a string
+		// is provided by a datetime parser which is not
+		// handling the timestamp case, hence the layout
+		// can be directly used to format the date
+		return datetime.Format(layout)
+	}
+}
+
 // LoadAndHighlightAllFields loads stored fields + highlights for root and its descendants.
 // All descendant documents are collected into a _$nested array in the root DocumentMatch.
 func LoadAndHighlightAllFields(
@@ -1333,7 +1579,31 @@ func (i *indexImpl) Close() error {
 	indexStats.UnRegister(i)
 
 	i.open = false
-	return i.i.Close()
+	// the index is closed even when releasing its points
+	// in time fails, as it can no longer be closed later
+	return errors.Join(i.pits.releaseAll(), i.i.Close())
+}
+
+// OpenPointInTime pins the current state of the index, returning the id
+// to be set as SearchRequest.PointInTimeID to search it.
+func (i *indexImpl) OpenPointInTime(ttl time.Duration) (string, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	if !i.open {
+		return "", ErrorIndexClosed
+	}
+
+	reader, err := i.i.Reader()
+	if err != nil {
+		return "", fmt.Errorf("error opening index reader %v", err)
+	}
+	return i.pits.open(&pointInTime{reader: reader}, ttl), nil
+}
+
+// ReleasePointInTime releases the point in time with the provided id.
+func (i *indexImpl) ReleasePointInTime(id string) error {
+	return i.pits.release(id)
 }
 
 func (i *indexImpl) Stats() *IndexStat {
@@ -17,6 +17,7 @@ package bleve
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -2576,13 +2577,17 @@ func TestBatchMerge(t *testing.T) {
 	batchC.Delete("a")
 	batchC.DeleteInternal([]byte("batchkA"))
 
-	batchA.Merge(batchB)
+	if err = batchA.Merge(batchB); err != nil {
+		t.Fatal(err)
+	}
 
 	if batchA.Size() != 4 {
 		t.Errorf("expected batch size 4, got %d", batchA.Size())
 	}
 
-	batchA.Merge(batchC)
+	if err = batchA.Merge(batchC); err != nil {
+		t.Fatal(err)
+	}
 
 	if batchA.Size() != 6 {
 		t.Errorf("expected batch size 6, got %d", batchA.Size())
@@ -3245,3 +3250,455 @@ func TestFuzzyScoring(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestDeleteAndUpdateByQuery(t *testing.T) {
+	defer func() {
+		ByQueryBatchSize = 1000
+	}()
+	ByQueryBatchSize = 7
+
+	indexes := make([]Index, 2)
+	for i := range indexes {
+		tmpIndexPath := createTmpIndexPath(t)
+		defer cleanupTmpIndexPath(t, tmpIndexPath)
+
+		idx, err := New(tmpIndexPath, NewIndexMapping())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			err := idx.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}()
+		indexes[i] = idx
+	}
+	batches := []*Batch{indexes[0].NewBatch(), indexes[1].NewBatch()}
+	for i := 0; i < 100; i++ {
+		tenant := "acme"
+		if i%4 == 0 {
+			tenant = "globex"
+		}
+		err := batches[i%2].Index(strconv.Itoa(i), map[string]interface{}{
+			"tenant": tenant,
+			"n":      float64(i),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, batch := range batches {
+		if err := indexes[i].Batch(batch); err != nil {
+			t.Fatal(err)
+		}
+	}
+	alias := NewIndexAlias(indexes...)
+
+	tenantQuery := func(tenant string) query.Query {
+		q := NewTermQuery(tenant)
+		q.SetField("tenant")
+		return q
+	}
+	count := func(q query.Query) uint64 {
+		res, err := alias.Search(NewSearchRequest(q))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Total
+	}
+
+	res, err := alias.UpdateByQuery(context.Background(), tenantQuery("globex"), func(doc index.Document) (interface{}, bool) {
+		var n float64
+		doc.VisitFields(func(f index.Field) {
+			if nf, ok := f.(index.NumericField); ok && f.Name() == "n" {
+				n, _ = nf.Number()
+			}
+		})
+		// leave the even tens alone
+		if int(n)%20 == 0 {
+			return nil, false
+		}
+		return map[string]interface{}{"tenant": "initech", "n": n}, true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Matched != 25 || res.Applied != 20 || res.Noops != 5 || len(res.Failures) != 0 {
+		t.Errorf("unexpected update result %+v", res)
+	}
+	if c := count(tenantQuery("initech")); c != 20 {
+		t.Errorf("expected 20 updated documents, got %d", c)
+	}
+
+	res, err = alias.DeleteByQuery(context.Background(), tenantQuery("acme"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Matched != 75 || res.Applied != 75 || len(res.Failures) != 0 {
+		t.Errorf("unexpected delete result %+v", res)
+	}
+	if c := count(NewMatchAllQuery()); c != 25 {
+		t.Errorf("expected 25 remaining documents, got %d", c)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = indexes[0].(ByQueryIndex).DeleteByQuery(ctx, NewMatchAllQuery())
+	if err != context.Canceled {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+	if c := count(NewMatchAllQuery()); c != 25 {
+		t.Errorf("expected cancelled delete to leave 25 documents, got %d", c)
+	}
+}
+
+func TestBatchUpdate(t *testing.T) {
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+
+	indexMapping := NewIndexMapping()
+	indexMapping.StoreSource = true
+	idx, err := New(tmpIndexPath, indexMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	err = idx.Index("a", map[string]interface{}{
+		"title":      "hello",
+		"view_count": 1.0,
+		"tags":       []interface{}{"x", "y"},
+		"meta":       map[string]interface{}{"author": "marty", "draft": true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch := idx.NewBatch()
+	err = batch.Update("a", map[string]interface{}{
+		"view_count": 2.0,
+		"tags":       map[string]interface{}{PatchAppendKey: []interface{}{"z"}},
+		"meta":       map[string]interface{}{"draft": nil},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// patches apply in order
+	if err = batch.Update("a", []byte(`{"view_count": 3}`)); err != nil {
+		t.Fatal(err)
+	}
+	// missing documents are created
+	if err = batch.Update("b", `{"title": "created"}`); err != nil {
+		t.Fatal(err)
+	}
+	if batch.Size() != 2 {
+		t.Errorf("expected batch size 2, got %d", batch.Size())
+	}
+	if err = idx.Batch(batch); err != nil {
+		t.Fatal(err)
+	}
+	documentSource := func(id string) map[string]interface{} {
+		t.Helper()
+		doc, err := idx.Document(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		source, err := DocumentSource(doc, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rv map[string]interface{}
+		if err = json.Unmarshal(source, &rv); err != nil {
+			t.Fatal(err)
+		}
+		return rv
+	}
+	// the batch executed is left as it was
+	if batch.Size() != 2 {
+		t.Errorf("expected executed batch size 2, got %d", batch.Size())
+	}
+
+	expected := map[string]interface{}{
+		"title":      "hello",
+		"view_count": 3.0,
+		"tags":       []interface{}{"x", "y", "z"},
+		"meta":       map[string]interface{}{"author": "marty"},
+	}
+	if source := documentSource("a"); !reflect.DeepEqual(source, expected) {
+		t.Errorf("expected %v, got %v", expected, source)
+	}
+
+	q := NewTermQuery("z")
+	q.SetField("tags")
+	res, err := idx.Search(NewSearchRequest(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 1 {
+		t.Errorf("expected the appended tag to be searchable, got %d hits", res.Total)
+	}
+	res, err = idx.Search(NewSearchRequest(NewQueryStringQuery("title:created")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 1 || res.Hits[0].ID != "b" {
+		t.Errorf("expected the created document to be searchable, got %d hits", res.Total)
+	}
+
+	batch = idx.NewBatch()
+	if err = batch.Index("c", map[string]interface{}{"title": "c"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = batch.Update("c", `{"title": "d"}`); err != ErrorBatchUpdateConflict {
+		t.Errorf("expected update conflict, got %v", err)
+	}
+	if err = batch.Update("a", `[]`); err == nil {
+		t.Errorf("expected error for a patch which is not an object")
+	}
+	// as does merging a batch updating a document indexed by the batch
+	other := idx.NewBatch()
+	if err = other.Update("c", `{"title": "d"}`); err != nil {
+		t.Fatal(err)
+	}
+	if err = batch.Merge(other); err != ErrorBatchUpdateConflict {
+		t.Errorf("expected merge conflict, got %v", err)
+	}
+	if batch.Size() != 1 {
+		t.Errorf("expected the conflicting merge to leave batch size 1, got %d", batch.Size())
+	}
+
+	// a batch failing is left as it was, as are the documents
+	batch = idx.NewBatch()
+	if err = batch.Update("a", `{"view_count": 4}`); err != nil {
+		t.Fatal(err)
+	}
+	if err = batch.IndexIfAbsent("b", map[string]interface{}{"title": "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = idx.Batch(batch); err == nil {
+		t.Errorf("expected version conflict")
+	} else if _, ok := err.(*VersionConflictError); !ok {
+		t.Errorf("expected version conflict, got %v", err)
+	}
+	if batch.Size() != 2 {
+		t.Errorf("expected failed batch size 2, got %d", batch.Size())
+	}
+	if source := documentSource("a"); source["view_count"] != 3.0 {
+		t.Errorf("expected view count 3 after failed batch, got %v", source["view_count"])
+	}
+
+	// documents without their source stored cannot be updated
+	unstoredPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, unstoredPath)
+	unstored, err := New(unstoredPath, NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := unstored.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err = unstored.Index("a", map[string]interface{}{"title": "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	batch = unstored.NewBatch()
+	if err = batch.Update("a", `{"view_count": 1}`); err != nil {
+		t.Fatal(err)
+	}
+	if err = unstored.Batch(batch); err != ErrorSourceNotStored {
+		t.Errorf("expected source not stored, got %v", err)
+	}
+}
+
+func TestDocumentVersions(t *testing.T) {
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+
+	idx, err := NewUsing(tmpIndexPath, NewIndexMapping(), scorch.Name, Config.DefaultKVStore,
+		map[string]interface{}{"versions": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	documentVersion := func(id string) uint64 {
+		t.Helper()
+		doc, err := idx.Document(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc == nil {
+			return 0
+		}
+		version, ok := DocumentVersion(doc)
+		if !ok {
+			t.Fatalf("expected document %s to have a version", id)
+		}
+		return version
+	}
+
+	if err = idx.Index("a", map[string]interface{}{"name": "one"}); err != nil {
+		t.Fatal(err)
+	}
+	version := documentVersion("a")
+
+	req := NewSearchRequest(NewMatchQuery("one"))
+	req.Fields = []string{"*"}
+	res, err := idx.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hits) != 1 || res.Hits[0].Version != version {
+		t.Fatalf("expected hit with version %d, got %v", version, res.Hits)
+	}
+	if _, ok := res.Hits[0].Fields["_version"]; ok {
+		t.Errorf("expected version to be excluded from stored fields")
+	}
+
+	batch := idx.NewBatch()
+	if err = batch.IndexIfVersion("a", map[string]interface{}{"name": "two"}, version); err != nil {
+		t.Fatal(err)
+	}
+	if err = batch.IndexIfAbsent("b", map[string]interface{}{"name": "one"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = idx.Batch(batch); err != nil {
+		t.Fatal(err)
+	}
+	if documentVersion("a") <= version {
+		t.Errorf("expected version of a to increase from %d", version)
+	}
+
+	// a stale version conflicts, and nothing in the batch is applied
+	batch = idx.NewBatch()
+	if err = batch.IndexIfVersion("a", map[string]interface{}{"name": "three"}, version); err != nil {
+		t.Fatal(err)
+	}
+	batch.DeleteIfVersion("b", documentVersion("b"))
+	err = idx.Batch(batch)
+	var conflictErr *VersionConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+	if len(conflictErr.Conflicts) != 1 || conflictErr.Conflicts[0].ID != "a" {
+		t.Errorf("unexpected conflicts %v", conflictErr.Conflicts)
+	}
+	if documentVersion("b") == 0 {
+		t.Errorf("expected b not to be deleted")
+	}
+
+	batch = idx.NewBatch()
+	if err = batch.IndexIfAbsent("a", map[string]interface{}{"name": "four"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = idx.Batch(batch); !errors.As(err, &conflictErr) {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+
+	// an unconditional operation replaces an earlier precondition
+	batch = idx.NewBatch()
+	batch.DeleteIfVersion("b", version)
+	batch.Delete("b")
+	if err = idx.Batch(batch); err != nil {
+		t.Fatal(err)
+	}
+	if documentVersion("b") != 0 {
+		t.Errorf("expected b to be deleted")
+	}
+
+	// a batch retried after a conflict has the version of the retry
+	batch = idx.NewBatch()
+	if err = batch.IndexIfAbsent("a", map[string]interface{}{"name": "five"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = idx.Batch(batch); !errors.As(err, &conflictErr) {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+	if err = idx.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err = idx.Index("c", map[string]interface{}{"name": "six"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = idx.Batch(batch); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := idx.Document("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	versionFields := 0
+	doc.VisitFields(func(field index.Field) {
+		if field.Name() == "_version" {
+			versionFields++
+		}
+	})
+	if versionFields != 1 {
+		t.Errorf("expected a single version field, got %d", versionFields)
+	}
+	if retried, earlier := documentVersion("a"), documentVersion("c"); retried <= earlier {
+		t.Errorf("expected the version of the retried batch to follow %d, got %d", earlier, retried)
+	}
+
+	// versions must be tracked to use them as preconditions
+	unversionedPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, unversionedPath)
+	unversioned, err := New(unversionedPath, NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := unversioned.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+	batch = unversioned.NewBatch()
+	if err = batch.IndexIfVersion("a", map[string]interface{}{"name": "one"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err = unversioned.Batch(batch); err != ErrorVersionsNotSupported {
+		t.Errorf("expected versions not supported, got %v", err)
+	}
+	batch = unversioned.NewBatch()
+	if err = batch.IndexIfAbsent("a", map[string]interface{}{"name": "one"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = unversioned.Batch(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	// other index types do not support preconditions
+	upsidedownPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, upsidedownPath)
+	other, err := NewUsing(upsidedownPath, NewIndexMapping(), upsidedown.Name, boltdb.Name, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := other.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+	batch = other.NewBatch()
+	if err = batch.IndexIfAbsent("a", map[string]interface{}{"name": "one"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = other.Batch(batch); err != ErrorPreconditionsNotSupported {
+		t.Errorf("expected preconditions not supported, got %v", err)
+	}
+}
@@ -91,3 +91,9 @@ func NewGeoShapeFieldMapping() *mapping.FieldMapping {
 func NewIPFieldMapping() *mapping.FieldMapping {
 	return mapping.NewIPFieldMapping()
 }
+
+// NewPercolatorFieldMapping returns a default field mapping
+// for queries to be matched against documents by Percolate.
+func NewPercolatorFieldMapping() *mapping.FieldMapping {
+	return mapping.NewPercolatorFieldMapping()
+}
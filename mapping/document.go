@@ -104,7 +104,7 @@ func (dm *DocumentMapping) Validate(cache *registry.Cache,
 
 func validateFieldType(field *FieldMapping) error {
 	switch field.Type {
-	case "text", "datetime", "number", "boolean", "geopoint", "geoshape", "IP":
+	case "text", "datetime", "number", "boolean", "geopoint", "geoshape", "IP", "percolator":
 		return nil
 	default:
 		return fmt.Errorf("field: '%s', unknown field type: '%s'",
@@ -557,6 +557,8 @@ func (dm *DocumentMapping) processProperty(property interface{}, path []string,
 					fieldMapping.processGeoPoint(property, pathString, path, indexes, context)
 				case "vector_base64":
 					fieldMapping.processVectorBase64(property, pathString, path, indexes, context)
+				case "percolator":
+					fieldMapping.processPercolator(property, pathString, path, indexes, context)
 				default:
 					fieldMapping.processString(propertyValueString, pathString, path, indexes, context)
 				}
@@ -666,6 +668,13 @@ func (dm *DocumentMapping) processProperty(property interface{}, path []string,
 				case "geoshape":
 					fieldMapping.processGeoShape(property, pathString, path, indexes, context)
 					walkDocument = true
+				case "percolator":
+					// a slice holds several queries
+					if propertyType.Kind() == reflect.Map {
+						fieldMapping.processPercolator(property, pathString, path, indexes, context)
+					} else {
+						walkDocument = true
+					}
 				default:
 					walkDocument = true
 				}
@@ -678,6 +687,19 @@ func (dm *DocumentMapping) processProperty(property interface{}, path []string,
 		}
 	case reflect.Ptr:
 		if !propertyValue.IsNil() {
+			// queries are held by pointers, such as *query.TermQuery
+			if subDocMapping != nil {
+				percolated := false
+				for _, fieldMapping := range subDocMapping.Fields {
+					if fieldMapping.Type == "percolator" {
+						fieldMapping.processPercolator(property, pathString, path, indexes, context)
+						percolated = true
+					}
+				}
+				if percolated {
+					return
+				}
+			}
 			switch property := property.(type) {
 			case encoding.TextMarshaler:
 				// ONLY process TextMarshaler if there is an explicit mapping
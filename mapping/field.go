@@ -211,6 +211,22 @@ func NewIPFieldMapping() *FieldMapping {
 	}
 }
 
+// NewPercolatorFieldMapping returns a default field mapping for
+// percolator queries, which are stored to be matched against
+// documents, as by bleve.Percolate
+func NewPercolatorFieldMapping() *FieldMapping {
+	return &FieldMapping{
+		Type:  "percolator",
+		Store: true,
+		Index: true,
+	}
+}
+
+// PercolatorQueryTerms returns the terms to index for a percolator query,
+// given as JSON, which are used to find the queries possibly matching a
+// document.  It is registered by the search/query package.
+var PercolatorQueryTerms func(query []byte, m IndexMapping) ([]string, error)
+
 // Options returns the indexing options for this field.
 func (fm *FieldMapping) Options() index.FieldIndexingOptions {
 	var rv index.FieldIndexingOptions
@@ -381,6 +397,39 @@ func (fm *FieldMapping) processGeoShape(propertyMightBeGeoShape interface{},
 	}
 }
 
+// processPercolator stores the query as JSON, and indexes its terms
+// untokenized.  Percolator fields are never included in the _all field.
+func (fm *FieldMapping) processPercolator(property interface{}, pathString string, path []string, indexes []uint64, context *walkContext) {
+	if PercolatorQueryTerms == nil {
+		logger.Printf("no percolator query terms function registered")
+		return
+	}
+	query, err := util.MarshalJSON(property)
+	if err != nil {
+		logger.Printf("could not marshal percolator query %v", err)
+		return
+	}
+	terms, err := PercolatorQueryTerms(query, context.im)
+	if err != nil {
+		logger.Printf("could not index percolator query %v", err)
+		return
+	}
+
+	fieldName := getFieldName(pathString, path, fm)
+	if fm.Store {
+		field := document.NewTextFieldWithIndexingOptions(fieldName, indexes, query, index.StoreField)
+		context.doc.AddField(field)
+	}
+	if fm.Index {
+		analyzer := context.im.AnalyzerNamed(keyword.Name)
+		for _, term := range terms {
+			field := document.NewTextFieldCustom(fieldName, indexes, []byte(term), index.IndexField, analyzer)
+			context.doc.AddField(field)
+		}
+	}
+	context.excludedFromAll = append(context.excludedFromAll, fieldName)
+}
+
 func (fm *FieldMapping) analyzerForField(path []string, context *walkContext) analysis.Analyzer {
 	analyzerName := fm.Analyzer
 	if analyzerName == "" {
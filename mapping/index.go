@@ -57,6 +57,7 @@ type IndexMappingImpl struct {
 	StoreDynamic          bool                        `json:"store_dynamic"`
 	IndexDynamic          bool                        `json:"index_dynamic"`
 	DocValuesDynamic      bool                        `json:"docvalues_dynamic"`
+	StoreSource           bool                        `json:"store_source,omitempty"`
 	CustomAnalysis        *customAnalysis             `json:"analysis,omitempty"`
 	cache                 *registry.Cache
 }
@@ -327,6 +328,11 @@ func (im *IndexMappingImpl) UnmarshalJSON(data []byte) error {
 			if err != nil {
 				return err
 			}
+		case "store_source":
+			err := util.UnmarshalJSON(v, &im.StoreSource)
+			if err != nil {
+				return err
+			}
 
 		default:
 			invalidKeys = append(invalidKeys, k)
@@ -382,6 +388,14 @@ func (im *IndexMappingImpl) MapDocument(doc *document.Document, data interface{}
 		doc.SetIndexed()
 	}
 
+	if im.StoreSource {
+		source, err := util.MarshalJSON(data)
+		if err != nil {
+			return err
+		}
+		doc.AddField(document.NewSourceField(source))
+	}
+
 	return nil
 }
 
@@ -0,0 +1,112 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2/util"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// PatchAppendKey is the key of a patch object whose value lists values
+// to be appended to the array being patched, rather than replacing it.
+// For example {"tags": {"$append": ["new"]}} adds "new" to the tags.
+const PatchAppendKey = "$append"
+
+// normalizePatch converts a patch into generic JSON values, it
+// must describe an object.
+func normalizePatch(patch interface{}) (map[string]interface{}, error) {
+	var buf []byte
+	switch p := patch.(type) {
+	case []byte:
+		buf = p
+	case string:
+		buf = []byte(p)
+	default:
+		var err error
+		buf, err = util.MarshalJSON(patch)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var rv interface{}
+	err := util.UnmarshalJSON(buf, &rv)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := rv.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("patch must be an object, got %T", rv)
+	}
+	return obj, nil
+}
+
+// mergePatch applies the patch to the target following JSON merge patch
+// (RFC 7396) semantics: objects are merged recursively, null values remove
+// the corresponding member, and any other value replaces the target.  An
+// object holding only PatchAppendKey appends its values to the target.
+func mergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	if values, ok := patchObj[PatchAppendKey]; ok && len(patchObj) == 1 {
+		var rv []interface{}
+		switch t := target.(type) {
+		case nil:
+		case []interface{}:
+			rv = t
+		default:
+			rv = []interface{}{t}
+		}
+		if vs, ok := values.([]interface{}); ok {
+			return append(rv, vs...)
+		}
+		return append(rv, values)
+	}
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = make(map[string]interface{}, len(patchObj))
+	}
+	for k, v := range patchObj {
+		if v == nil {
+			delete(targetObj, k)
+			continue
+		}
+		targetObj[k] = mergePatch(targetObj[k], v)
+	}
+	return targetObj
+}
+
+// patchSource returns the stored source of a document for patching,
+// or ErrorSourceNotStored when the source of the document is not stored,
+// as it cannot be rebuilt from the fields of the document without losing
+// those which were not stored.
+func patchSource(doc index.Document) (interface{}, error) {
+	source, err := DocumentSource(doc, nil)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, ErrorSourceNotStored
+	}
+	var rv interface{}
+	err = util.UnmarshalJSON(source, &rv)
+	if err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
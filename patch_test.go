@@ -0,0 +1,69 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/util"
+)
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		target   string
+		patch    string
+		expected string
+	}{
+		// examples from RFC 7396
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		// appending
+		{`{"a":["b"]}`, `{"a":{"$append":["c","d"]}}`, `{"a":["b","c","d"]}`},
+		{`{"a":"b"}`, `{"a":{"$append":"c"}}`, `{"a":["b","c"]}`},
+		{`{}`, `{"a":{"$append":["c"]}}`, `{"a":["c"]}`},
+		{`{"a":["b"]}`, `{"a":{"$append":["c"],"x":1}}`, `{"a":{"$append":["c"],"x":1}}`},
+	}
+
+	for _, test := range tests {
+		var target, expected interface{}
+		if err := util.UnmarshalJSON([]byte(test.target), &target); err != nil {
+			t.Fatal(err)
+		}
+		if err := util.UnmarshalJSON([]byte(test.expected), &expected); err != nil {
+			t.Fatal(err)
+		}
+		patch, err := normalizePatch(test.patch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := mergePatch(target, patch)
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("patching %s with %s: expected %v, got %v", test.target, test.patch, expected, actual)
+		}
+	}
+
+	if _, err := normalizePatch(`["a"]`); err == nil {
+		t.Errorf("expected error for a patch which is not an object")
+	}
+}
@@ -0,0 +1,191 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// PercolateBatchSize is the number of candidate queries loaded at
+// a time by Percolate, as well as the number of terms of the document
+// for which the candidate queries are looked up at a time.
+var PercolateBatchSize = 1000
+
+// percolateDocID is the id of the document percolated,
+// alone in its in-memory index.
+const percolateDocID = "_percolate"
+
+// Percolate returns the ids of the documents of the index whose queries,
+// held by the percolator field, match the document, which is analyzed
+// by the mapping of the index.  A document holding several queries in
+// the field is returned when any of them matches.  The queries that
+// could match are found by the terms indexed for them, before each is
+// run against the document, alone in an in-memory index.
+func Percolate(i Index, field string, doc interface{}) ([]string, error) {
+	return PercolateInContext(context.Background(), i, field, doc)
+}
+
+// PercolateInContext is Percolate, within the context.
+func PercolateInContext(ctx context.Context, i Index, field string, doc interface{}) ([]string, error) {
+	docIndex, err := NewMemOnly(i.Mapping())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = docIndex.Close()
+	}()
+	err = docIndex.Index(percolateDocID, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	terms, err := percolateTerms(docIndex, i.Mapping().DefaultSearchField())
+	if err != nil {
+		return nil, err
+	}
+
+	// queries indexed for several of the terms are
+	// candidates of several batches, but run once
+	seen := make(map[string]struct{})
+	var rv []string
+	for len(terms) > 0 {
+		batch := terms
+		if len(batch) > PercolateBatchSize {
+			batch = batch[:PercolateBatchSize]
+		}
+		terms = terms[len(batch):]
+
+		err = percolateCandidates(ctx, i, field, batch, func(hit *search.DocumentMatch) error {
+			if _, ok := seen[hit.ID]; ok {
+				return nil
+			}
+			seen[hit.ID] = struct{}{}
+			matched, err := percolateHit(ctx, docIndex, hit, field)
+			if err != nil {
+				return fmt.Errorf("percolator query of document %s: %v", hit.ID, err)
+			}
+			if matched {
+				rv = append(rv, hit.ID)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(rv)
+	return rv, nil
+}
+
+// percolateTerms returns the terms for which percolator queries are
+// indexed which could match the document, PercolatorAnyTerm first.
+// The internal fields are skipped, other than the default search field,
+// for which the queries without a field are indexed.
+func percolateTerms(docIndex Index, defaultSearchField string) ([]string, error) {
+	rv := []string{query.PercolatorAnyTerm}
+	fields, err := docIndex.Fields()
+	if err != nil {
+		return nil, err
+	}
+	for _, docField := range fields {
+		if strings.HasPrefix(docField, "_") && docField != defaultSearchField {
+			continue
+		}
+		dict, err := docIndex.FieldDict(docField)
+		if err != nil {
+			return nil, err
+		}
+		entry, err := dict.Next()
+		for err == nil && entry != nil {
+			rv = append(rv, query.PercolatorTerm(docField, entry.Term))
+			entry, err = dict.Next()
+		}
+		if cerr := dict.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rv, nil
+}
+
+// percolateCandidates visits the documents of the index with
+// percolator queries indexed for any of the terms, a page at a time.
+func percolateCandidates(ctx context.Context, i Index, field string, terms []string,
+	visitor func(hit *search.DocumentMatch) error) error {
+	disjuncts := make([]query.Query, 0, len(terms))
+	for _, term := range terms {
+		q := query.NewTermQuery(term)
+		q.SetField(field)
+		disjuncts = append(disjuncts, q)
+	}
+
+	req := NewSearchRequestOptions(query.NewDisjunctionQuery(disjuncts), PercolateBatchSize, 0, false)
+	req.Fields = []string{field}
+	req.SortBy([]string{"_id"})
+	for {
+		res, err := i.SearchInContext(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, hit := range res.Hits {
+			if err := visitor(hit); err != nil {
+				return err
+			}
+		}
+		if len(res.Hits) < req.Size {
+			return nil
+		}
+		req.SetSearchAfter([]string{res.Hits[len(res.Hits)-1].ID})
+	}
+}
+
+// percolateHit reports whether any of the
+// queries of the hit match the document.
+func percolateHit(ctx context.Context, docIndex Index, hit *search.DocumentMatch, field string) (bool, error) {
+	var queries []string
+	switch v := hit.Fields[field].(type) {
+	case string:
+		queries = []string{v}
+	case []interface{}:
+		for _, q := range v {
+			if s, ok := q.(string); ok {
+				queries = append(queries, s)
+			}
+		}
+	}
+	for _, data := range queries {
+		q, err := query.ParsePercolatorQuery([]byte(data))
+		if err != nil {
+			return false, err
+		}
+		req := NewSearchRequestOptions(q, 0, 0, false)
+		res, err := docIndex.SearchInContext(ctx, req)
+		if err != nil {
+			return false, err
+		}
+		if res.Total > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,206 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+	"time"
+
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// DefaultPointInTimeTTL is the time to live used for a point in time
+// opened without an explicit time to live.
+var DefaultPointInTimeTTL = time.Minute
+
+// pointInTime is an open point in time, holding either the pinned
+// reader of an index or the pinned children of an alias.
+type pointInTime struct {
+	id      string
+	ttl     time.Duration
+	expires time.Time
+	timer   *time.Timer
+
+	// number of searches currently using the point in time, it is
+	// only closed once released and no longer in use
+	refs     int
+	released bool
+
+	reader  index.IndexReader
+	indexes []Index
+}
+
+// close releases the resources pinned by the point in time.
+func (p *pointInTime) close() error {
+	if p.reader != nil {
+		return p.reader.Close()
+	}
+	var rv error
+	for _, in := range p.indexes {
+		if pin, ok := in.(*pointInTimeIndex); ok {
+			// the child may have expired on its own already
+			err := pin.release()
+			if err != nil && err != ErrorPointInTimeNotFound && rv == nil {
+				rv = err
+			}
+		}
+	}
+	return rv
+}
+
+// pointInTimes tracks the open points in time of an index or alias,
+// the zero value is ready to use.
+type pointInTimes struct {
+	m    sync.Mutex
+	pits map[string]*pointInTime
+}
+
+// open registers the point in time, expiring it after ttl
+// passes without it being used.
+func (p *pointInTimes) open(pit *pointInTime, ttl time.Duration) string {
+	if ttl <= 0 {
+		ttl = DefaultPointInTimeTTL
+	}
+	pit.id = rand.Text()
+	pit.ttl = ttl
+	pit.expires = time.Now().Add(ttl)
+
+	p.m.Lock()
+	if p.pits == nil {
+		p.pits = make(map[string]*pointInTime)
+	}
+	p.pits[pit.id] = pit
+	pit.timer = time.AfterFunc(ttl, func() {
+		p.expire(pit)
+	})
+	p.m.Unlock()
+
+	return pit.id
+}
+
+// expire releases the point in time if it has not been used within
+// its time to live, or waits for the remainder otherwise.
+func (p *pointInTimes) expire(pit *pointInTime) {
+	p.m.Lock()
+	if pit.released {
+		p.m.Unlock()
+		return
+	}
+	if remaining := time.Until(pit.expires); remaining > 0 {
+		pit.timer.Reset(remaining)
+		p.m.Unlock()
+		return
+	}
+	closeNow := p.releaseLOCKED(pit)
+	p.m.Unlock()
+	if closeNow {
+		_ = pit.close()
+	}
+}
+
+// acquire returns the point in time for the id, extending its
+// expiry.  It must be returned using done once no longer in use.
+func (p *pointInTimes) acquire(id string) (*pointInTime, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	pit, ok := p.pits[id]
+	if !ok {
+		return nil, ErrorPointInTimeNotFound
+	}
+	pit.refs++
+	pit.expires = time.Now().Add(pit.ttl)
+	return pit, nil
+}
+
+func (p *pointInTimes) done(pit *pointInTime) error {
+	p.m.Lock()
+	pit.refs--
+	closeNow := pit.released && pit.refs == 0
+	p.m.Unlock()
+	if closeNow {
+		return pit.close()
+	}
+	return nil
+}
+
+// release drops the point in time for the id, it is closed
+// once any searches using it complete.
+func (p *pointInTimes) release(id string) error {
+	p.m.Lock()
+	pit, ok := p.pits[id]
+	if !ok {
+		p.m.Unlock()
+		return ErrorPointInTimeNotFound
+	}
+	closeNow := p.releaseLOCKED(pit)
+	p.m.Unlock()
+	if closeNow {
+		return pit.close()
+	}
+	return nil
+}
+
+func (p *pointInTimes) releaseAll() error {
+	p.m.Lock()
+	var toClose []*pointInTime
+	for _, pit := range p.pits {
+		if p.releaseLOCKED(pit) {
+			toClose = append(toClose, pit)
+		}
+	}
+	p.m.Unlock()
+	var rv error
+	for _, pit := range toClose {
+		err := pit.close()
+		if err != nil && rv == nil {
+			rv = err
+		}
+	}
+	return rv
+}
+
+// releaseLOCKED removes the point in time, returning whether it
+// should be closed now as no searches are using it.
+func (p *pointInTimes) releaseLOCKED(pit *pointInTime) bool {
+	delete(p.pits, pit.id)
+	pit.released = true
+	pit.timer.Stop()
+	return pit.refs == 0
+}
+
+// pointInTimeIndex is a child of an alias pinned by a point in time
+// opened on the alias, searches against it target the child's own
+// point in time.
+type pointInTimeIndex struct {
+	PointInTimeIndex
+	id string
+}
+
+func (p *pointInTimeIndex) Search(req *SearchRequest) (*SearchResult, error) {
+	return p.SearchInContext(context.Background(), req)
+}
+
+func (p *pointInTimeIndex) SearchInContext(ctx context.Context, req *SearchRequest) (*SearchResult, error) {
+	pitReq := *req
+	pitReq.PointInTimeID = p.id
+	return p.PointInTimeIndex.SearchInContext(ctx, &pitReq)
+}
+
+func (p *pointInTimeIndex) release() error {
+	return p.ReleasePointInTime(p.id)
+}
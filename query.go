@@ -288,3 +288,113 @@ func NewGeoShapeCircleQuery(coordinates []float64, radius, relation string) (*qu
 func NewGeometryCollectionQuery(coordinates [][][][][]float64, types []string, relation string) (*query.GeoShapeQuery, error) {
 	return query.NewGeometryCollectionQuery(coordinates, types, relation)
 }
+
+// NewSpanTermQuery creates a new span query matching
+// each occurrence of the exact term.
+func NewSpanTermQuery(term string) *query.SpanTermQuery {
+	return query.NewSpanTermQuery(term)
+}
+
+// NewSpanNearQuery creates a new span query matching a span of each
+// clause, which do not overlap, with at most slop positions between
+// them, in any order unless SetInOrder is used.  Searches fail when
+// finding the spans of a document takes more than
+// searcher.SpanNearMaxSteps steps, as can many repeated terms.
+func NewSpanNearQuery(clauses []query.SpanQuery, slop int) *query.SpanNearQuery {
+	return query.NewSpanNearQuery(clauses, slop)
+}
+
+// NewSpanOrQuery creates a new span query
+// matching the spans of any of the clauses.
+func NewSpanOrQuery(clauses []query.SpanQuery) *query.SpanOrQuery {
+	return query.NewSpanOrQuery(clauses)
+}
+
+// NewSpanNotQuery creates a new span query matching the
+// spans of include which do not overlap a span of exclude.
+func NewSpanNotQuery(include, exclude query.SpanQuery) *query.SpanNotQuery {
+	return query.NewSpanNotQuery(include, exclude)
+}
+
+// NewSpanFirstQuery creates a new span query matching the spans
+// of match which end within the first end positions of the field.
+func NewSpanFirstQuery(match query.SpanQuery, end int) *query.SpanFirstQuery {
+	return query.NewSpanFirstQuery(match, end)
+}
+
+// NewSpanContainingQuery creates a new span query matching
+// the spans of big which contain a span of little.
+func NewSpanContainingQuery(big, little query.SpanQuery) *query.SpanContainingQuery {
+	return query.NewSpanContainingQuery(big, little)
+}
+
+// NewIntervalsQuery creates a new Query for finding documents with
+// intervals of token positions matching the rule, such as terms
+// following one another within a few positions.
+func NewIntervalsQuery(rule *query.IntervalsRule) *query.IntervalsQuery {
+	return query.NewIntervalsQuery(rule)
+}
+
+// NewMoreLikeThisQuery creates a new Query for finding
+// documents similar to the text, searching for its
+// most significant terms.
+func NewMoreLikeThisQuery(like string) *query.MoreLikeThisQuery {
+	return query.NewMoreLikeThisQuery(like)
+}
+
+// NewMoreLikeThisDocumentsQuery creates a new Query for
+// finding documents similar to the stored fields of the
+// documents, other than the documents themselves.
+func NewMoreLikeThisDocumentsQuery(ids []string) *query.MoreLikeThisQuery {
+	return query.NewMoreLikeThisDocumentsQuery(ids)
+}
+
+// NewFunctionScoreQuery creates a new Query scoring the
+// matches of the query by combining their score with the
+// results of the score functions, such as decay functions
+// over the values of numeric, datetime or geopoint fields.
+func NewFunctionScoreQuery(q query.Query, functions ...*query.ScoreFunction) *query.FunctionScoreQuery {
+	return query.NewFunctionScoreQuery(q, functions...)
+}
+
+// NewTermsLookupQuery creates a new Query for finding
+// documents having any of the terms stored at the path
+// of the document with the id, such as the authors a
+// user follows.
+func NewTermsLookupQuery(id, path string) *query.TermsLookupQuery {
+	return query.NewTermsLookupQuery(id, path)
+}
+
+// NewExistsQuery creates a new Query for finding
+// documents having any value for the field, or,
+// when excluded, those lacking any value for it.
+func NewExistsQuery(field string) *query.ExistsQuery {
+	return query.NewExistsQuery(field)
+}
+
+// NewNumericTermsQuery creates a new Query for finding
+// documents having any of the numeric values exactly.
+func NewNumericTermsQuery(numbers []float64) *query.NumericTermsQuery {
+	return query.NewNumericTermsQuery(numbers)
+}
+
+// NewBoolTermsQuery creates a new Query for finding
+// documents having any of the boolean values.
+func NewBoolTermsQuery(bools []bool) *query.BoolTermsQuery {
+	return query.NewBoolTermsQuery(bools)
+}
+
+// NewDateTermsQuery creates a new Query for finding
+// documents having any of the date values exactly.
+// Date strings are parsed using the DateTimeParser set using
+//
+//	the DateTermsQuery.SetDateTimeParser() method.
+//
+// If no DateTimeParser is set, then the
+//
+//	top-level config.QueryDateTimeParser
+//
+// is used.
+func NewDateTermsQuery(dates []string) *query.DateTermsQuery {
+	return query.NewDateTermsQuery(dates)
+}
@@ -0,0 +1,133 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// A RuntimeFieldRequest describes a field computed at search time
+// from the doc values of indexed fields, without reindexing, using
+// the expression language documented by search.RuntimeFields.
+// Runtime fields can be returned like stored fields by naming them
+// in the Fields of the request, and sorted and faceted on like
+// indexed fields.
+type RuntimeFieldRequest struct {
+	Expression string `json:"expression"`
+}
+
+// RuntimeFieldsRequest groups together all the
+// RuntimeFieldRequest objects for a single query, by name.
+type RuntimeFieldsRequest map[string]*RuntimeFieldRequest
+
+func (r RuntimeFieldsRequest) Validate() error {
+	for name, rf := range r {
+		if name == "" {
+			return fmt.Errorf("runtime fields must have a name")
+		}
+		if rf == nil || rf.Expression == "" {
+			return fmt.Errorf("runtime field '%s' must have an expression", name)
+		}
+	}
+	return nil
+}
+
+// build parses the expressions of the runtime fields,
+// using the types of the fields of the mapping.
+func (r RuntimeFieldsRequest) build(m mapping.IndexMapping) (*search.RuntimeFields, error) {
+	expressions := make(map[string]string, len(r))
+	for name, rf := range r {
+		if rf != nil {
+			expressions[name] = rf.Expression
+		}
+	}
+	return search.NewRuntimeFields(expressions, func(field string) string {
+		return m.FieldMappingForPath(field).Type
+	})
+}
+
+// AddRuntimeField adds a field computed at search time to this SearchRequest
+func (r *SearchRequest) AddRuntimeField(name, expression string) {
+	if r.RuntimeFields == nil {
+		r.RuntimeFields = make(RuntimeFieldsRequest, 1)
+	}
+	r.RuntimeFields[name] = &RuntimeFieldRequest{Expression: expression}
+}
+
+// runtimeFieldsMapping describes the runtime fields of a
+// search, as well as the fields of the index mapping.
+type runtimeFieldsMapping struct {
+	mapping.IndexMapping
+	runtimeFields *search.RuntimeFields
+}
+
+func (m *runtimeFieldsMapping) FieldMappingForPath(path string) mapping.FieldMapping {
+	if typ := m.runtimeFields.Type(path); typ != "" {
+		return mapping.FieldMapping{Name: path, Type: typ}
+	}
+	return m.IndexMapping.FieldMappingForPath(path)
+}
+
+// loadRuntimeFields adds the values of the runtime fields
+// named by the fields of the request to the hits, in place
+// of any stored fields of the same names.
+func loadRuntimeFields(hits search.DocumentMatchCollection, req *SearchRequest,
+	runtimeFields *search.RuntimeFields, r index.IndexReader) (uint64, error) {
+	var names []string
+	for _, f := range deDuplicate(req.Fields) {
+		if f == "*" {
+			names = runtimeFields.Fields()
+			break
+		}
+		if runtimeFields.Type(f) != "" {
+			names = append(names, f)
+		}
+	}
+	if len(names) == 0 || len(hits) == 0 {
+		return 0, nil
+	}
+
+	dvReader, err := r.DocValueReader(runtimeFields.RequiredFields(names))
+	if err != nil {
+		return 0, err
+	}
+	for _, hit := range hits {
+		id := hit.IndexInternalID
+		if id == nil {
+			id, err = r.InternalID(hit.ID)
+			if err != nil {
+				return 0, err
+			}
+		}
+		runtimeFields.StartDoc(nil)
+		err = dvReader.VisitDocValues(id, runtimeFields.UpdateVisitor)
+		if err != nil {
+			return 0, err
+		}
+		for _, name := range names {
+			if value := runtimeFields.Value(name); value != nil {
+				if hit.Fields == nil {
+					hit.Fields = make(map[string]interface{})
+				}
+				hit.Fields[name] = value
+			}
+		}
+	}
+	return dvReader.BytesRead(), nil
+}
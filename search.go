@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"slices"
@@ -32,6 +33,7 @@ import (
 	"github.com/blevesearch/bleve/v2/registry"
 	"github.com/blevesearch/bleve/v2/search"
 	"github.com/blevesearch/bleve/v2/search/collector"
+	"github.com/blevesearch/bleve/v2/search/facet"
 	"github.com/blevesearch/bleve/v2/search/query"
 	"github.com/blevesearch/bleve/v2/size"
 	"github.com/blevesearch/bleve/v2/util"
@@ -157,6 +159,28 @@ type FacetRequest struct {
 	NumericRanges  []*numericRange  `json:"numeric_ranges,omitempty"`
 	DateTimeRanges []*dateTimeRange `json:"date_ranges,omitempty"`
 
+	// Interval builds a histogram, with buckets of this width generated
+	// from the numeric values of the field. CalendarInterval builds a date
+	// histogram instead, with buckets of one minute, hour, day, week, month,
+	// quarter or year in TimeZone (UTC by default). MinDocCount omits the
+	// buckets with fewer documents, when 0 the empty buckets between the
+	// lowest and highest values are included. Histogram buckets are
+	// ordered by key and limited to Size buckets.
+	Interval         float64 `json:"interval,omitempty"`
+	CalendarInterval string  `json:"calendar_interval,omitempty"`
+	TimeZone         string  `json:"time_zone,omitempty"`
+	MinDocCount      int     `json:"min_doc_count,omitempty"`
+
+	// Metric computes one of the search.Metric* statistics over the
+	// numeric or date time values of the field, instead of bucketing
+	// documents. Percents applies only to the percentiles metric.
+	Metric   string    `json:"metric,omitempty"`
+	Percents []float64 `json:"percents,omitempty"`
+
+	// Facets are computed separately for the documents
+	// in each of the buckets of this facet.
+	Facets FacetsRequest `json:"facets,omitempty"`
+
 	// Compiled regex pattern (cached during validation)
 	compiledPattern *regexp.Regexp
 }
@@ -171,6 +195,35 @@ func NewFacetRequest(field string, size int) *FacetRequest {
 	}
 }
 
+// NewHistogramFacetRequest creates a histogram facet on the
+// specified numeric field, with buckets of width interval.
+func NewHistogramFacetRequest(field string, size int, interval float64) *FacetRequest {
+	return &FacetRequest{
+		Field:    field,
+		Size:     size,
+		Interval: interval,
+	}
+}
+
+// NewDateHistogramFacetRequest creates a date histogram facet on the
+// specified date time field, with buckets of one calendar interval.
+func NewDateHistogramFacetRequest(field string, size int, calendarInterval string) *FacetRequest {
+	return &FacetRequest{
+		Field:            field,
+		Size:             size,
+		CalendarInterval: calendarInterval,
+	}
+}
+
+// NewMetricFacetRequest creates a facet computing the specified
+// metric over the numeric or date time values of field.
+func NewMetricFacetRequest(field string, metric string) *FacetRequest {
+	return &FacetRequest{
+		Field:  field,
+		Metric: metric,
+	}
+}
+
 // SetPrefixFilter sets the prefix filter for term facets.
 func (fr *FacetRequest) SetPrefixFilter(prefix string) {
 	fr.TermPrefix = prefix
@@ -197,6 +250,55 @@ func (fr *FacetRequest) Validate() error {
 		return fmt.Errorf("facet can only contain numeric ranges or date ranges, not both")
 	}
 
+	if fr.Interval != 0 || fr.CalendarInterval != "" {
+		if fr.Interval != 0 && fr.CalendarInterval != "" {
+			return fmt.Errorf("facet can only contain an interval or a calendar interval, not both")
+		}
+		if nrCount > 0 || drCount > 0 || fr.Metric != "" || fr.TermPrefix != "" || fr.TermPattern != "" {
+			return fmt.Errorf("histogram facet cannot contain ranges, metrics or term filters")
+		}
+		if fr.Interval < 0 || math.IsInf(fr.Interval, 0) || math.IsNaN(fr.Interval) {
+			return fmt.Errorf("histogram interval must be positive, got %v", fr.Interval)
+		}
+		if fr.CalendarInterval != "" && !facet.IsValidCalendarInterval(fr.CalendarInterval) {
+			return fmt.Errorf("unknown calendar interval '%s'", fr.CalendarInterval)
+		}
+		if fr.TimeZone != "" {
+			if _, err := time.LoadLocation(fr.TimeZone); err != nil {
+				return fmt.Errorf("invalid time zone '%s': %v", fr.TimeZone, err)
+			}
+		}
+		if fr.MinDocCount < 0 {
+			return fmt.Errorf("min doc count cannot be negative")
+		}
+		return fr.Facets.Validate()
+	} else if fr.TimeZone != "" || fr.MinDocCount != 0 {
+		return fmt.Errorf("time zone and min doc count can only be specified for histogram facets")
+	}
+
+	if fr.Metric != "" {
+		if !search.IsValidMetric(fr.Metric) {
+			return fmt.Errorf("unknown metric '%s'", fr.Metric)
+		}
+		if nrCount > 0 || drCount > 0 || fr.TermPrefix != "" || fr.TermPattern != "" {
+			return fmt.Errorf("metric facet cannot contain ranges or term filters")
+		}
+		if len(fr.Facets) > 0 {
+			return fmt.Errorf("metric facet cannot contain child facets")
+		}
+		if len(fr.Percents) > 0 && fr.Metric != search.MetricPercentiles {
+			return fmt.Errorf("percents can only be specified for a percentiles metric")
+		}
+		for _, percent := range fr.Percents {
+			if percent < 0 || percent > 100 {
+				return fmt.Errorf("percentile must be between 0 and 100, got %v", percent)
+			}
+		}
+		return nil
+	} else if len(fr.Percents) > 0 {
+		return fmt.Errorf("percents can only be specified for a percentiles metric")
+	}
+
 	if nrCount > 0 {
 		nrNames := map[string]interface{}{}
 		for _, nr := range fr.NumericRanges {
@@ -234,7 +336,7 @@ func (fr *FacetRequest) Validate() error {
 		}
 	}
 
-	return nil
+	return fr.Facets.Validate()
 }
 
 // AddDateTimeRange adds a bucket to a field
@@ -280,6 +382,15 @@ func (fr *FacetRequest) AddNumericRange(name string, min, max *float64) {
 	fr.NumericRanges = append(fr.NumericRanges, &numericRange{Name: name, Min: min, Max: max})
 }
 
+// AddFacet adds a child FacetRequest, which will be
+// computed for the documents in each bucket of this facet.
+func (fr *FacetRequest) AddFacet(facetName string, f *FacetRequest) {
+	if fr.Facets == nil {
+		fr.Facets = make(FacetsRequest, 1)
+	}
+	fr.Facets[facetName] = f
+}
+
 // FacetsRequest groups together all the
 // FacetRequest objects for a single query.
 type FacetsRequest map[string]*FacetRequest
@@ -293,6 +404,50 @@ func (fr FacetsRequest) Validate() error {
 	return nil
 }
 
+// fillHistograms fills in the empty buckets of the histogram facet
+// results, or omits those with too few documents, including in the child
+// facets of every bucket. This is done once the results are merged, as
+// the buckets of each index differ.
+func (fr FacetsRequest) fillHistograms(results search.FacetResults) {
+	for name, facetRequest := range fr {
+		facetResult, ok := results[name]
+		if !ok {
+			continue
+		}
+		if facetRequest.Interval != 0 {
+			facet.FillHistogram(facetResult, facetRequest.Size,
+				facetRequest.Interval, facetRequest.MinDocCount)
+		} else if facetRequest.CalendarInterval != "" {
+			// the time zone was validated with the request
+			location, _ := time.LoadLocation(facetRequest.TimeZone)
+			facet.FillDateHistogram(facetResult, facetRequest.Size,
+				facetRequest.CalendarInterval, location, facetRequest.MinDocCount)
+		}
+		if len(facetRequest.Facets) == 0 {
+			continue
+		}
+		for _, subFacets := range facetResult.SubFacets() {
+			facetRequest.Facets.fillHistograms(subFacets)
+		}
+	}
+}
+
+// fixup trims the merged facet results, including the child
+// facets of every bucket, to the sizes requested.
+func (fr FacetsRequest) fixup(results search.FacetResults) {
+	for name, facetRequest := range fr {
+		results.Fixup(name, facetRequest.Size)
+		if len(facetRequest.Facets) == 0 {
+			continue
+		}
+		if facetResult, ok := results[name]; ok {
+			for _, subFacets := range facetResult.SubFacets() {
+				facetRequest.Facets.fixup(subFacets)
+			}
+		}
+	}
+}
+
 // HighlightRequest describes how field matches
 // should be highlighted.
 type HighlightRequest struct {
@@ -321,6 +476,55 @@ func (h *HighlightRequest) AddField(field string) {
 	h.Fields = append(h.Fields, field)
 }
 
+// CollapseRequest describes how search hits should be
+// collapsed, so that only the top hit for each distinct
+// value of Field is returned.  The field must have doc
+// values, and is expected to hold a single value per
+// document.  Size, From and SearchAfter page over the
+// collapsed hits, while the total still counts every
+// matching document.  When paging an alias with
+// SearchAfter, documents sharing a value are expected
+// to be held by the same index.  SearchAfter keeps
+// every group sorting after the key while collecting,
+// so it fails on fields with more distinct values than
+// collector.CollapseSearchAfterMaxGroups.
+// InnerHits optionally requests the top hits sharing
+// each returned hit's value, in the request sort order.
+type CollapseRequest struct {
+	Field     string `json:"field"`
+	InnerHits int    `json:"inner_hits,omitempty"`
+}
+
+// NewCollapseRequest creates a CollapseRequest
+// for the provided field.
+func NewCollapseRequest(field string) *CollapseRequest {
+	return &CollapseRequest{
+		Field: field,
+	}
+}
+
+func (c *CollapseRequest) Validate() error {
+	if c.Field == "" {
+		return fmt.Errorf("collapse field must be specified")
+	}
+	if c.InnerHits < 0 {
+		return fmt.Errorf("collapse inner hits must be >= 0")
+	}
+	return nil
+}
+
+// withoutInnerHits returns the request to be passed to
+// the indexes searched on behalf of an alias, inner hits
+// are expanded once the hits have been merged.
+func (c *CollapseRequest) withoutInnerHits() *CollapseRequest {
+	if c == nil || c.InnerHits == 0 {
+		return c
+	}
+	return &CollapseRequest{
+		Field: c.Field,
+	}
+}
+
 func (r *SearchRequest) Validate() error {
 	if srq, ok := r.Query.(query.ValidatableQuery); ok {
 		err := srq.Validate()
@@ -367,6 +571,24 @@ func (r *SearchRequest) Validate() error {
 		}
 	}
 
+	if r.Collapse != nil {
+		err = r.Collapse.Validate()
+		if err != nil {
+			return err
+		}
+		if r.SearchBefore != nil {
+			return fmt.Errorf("cannot use search before with collapse")
+		}
+		if IsScoreFusionRequested(r) {
+			return fmt.Errorf("cannot use collapse with score fusion")
+		}
+	}
+
+	err = r.RuntimeFields.Validate()
+	if err != nil {
+		return err
+	}
+
 	err = validateKNN(r)
 	if err != nil {
 		return err
@@ -609,6 +831,21 @@ func (sr *SearchResult) String() string {
 			for _, d := range f.DateRanges {
 				fmt.Fprintf(rv, "\t%s(%d)\n", d.Name, d.Count)
 			}
+			for _, h := range f.Histogram {
+				if h.KeyAsString != "" {
+					fmt.Fprintf(rv, "\t%s(%d)\n", h.KeyAsString, h.Count)
+				} else {
+					fmt.Fprintf(rv, "\t%v(%d)\n", h.Key, h.Count)
+				}
+			}
+			if f.Metric != nil {
+				if f.Metric.Value != nil {
+					fmt.Fprintf(rv, "\t%s(%v)\n", f.Metric.Metric, *f.Metric.Value)
+				}
+				for _, p := range f.Metric.Percentiles {
+					fmt.Fprintf(rv, "\tp%v(%v)\n", p.Percent, p.Value)
+				}
+			}
 			if f.Other != 0 {
 				fmt.Fprintf(rv, "\tOther(%d)\n", f.Other)
 			}
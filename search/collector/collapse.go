@@ -0,0 +1,223 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+
+	"github.com/blevesearch/bleve/v2/search"
+)
+
+// CollapseSearchAfterMaxGroups limits the number of groups held while
+// collapsing with search after, which can not evict groups during
+// collection.  Searches collapsing on fields with more distinct values
+// than this fail, and should page with From instead.
+var CollapseSearchAfterMaxGroups = 10000
+
+type collapseGroup struct {
+	key   string
+	doc   *search.DocumentMatch
+	index int
+}
+
+// collectStoreCollapse keeps only the best hit for each distinct
+// collapse key, retaining the top groups by the sort order of their
+// best hit.  The worst group sits at the root of the heap so that it
+// can be evicted cheaply.
+type collectStoreCollapse struct {
+	heap    []*collapseGroup
+	groups  map[string]*collapseGroup
+	compare collectorCompare
+
+	// the number of groups retained by Final, the store may temporarily
+	// hold more when documents are added with a larger size
+	size int
+
+	// keys of groups that must not be returned, because their best hit
+	// sorted at or before the search after key and so appeared on an
+	// earlier page
+	excluded map[string]struct{}
+}
+
+func newStoreCollapse(size int, compare collectorCompare) *collectStoreCollapse {
+	capacity := size + 1
+	if size > PreAllocSizeSkipCap {
+		capacity = PreAllocSizeSkipCap + 1
+	}
+	rv := &collectStoreCollapse{
+		size:    size,
+		heap:    make([]*collapseGroup, 0, capacity),
+		groups:  make(map[string]*collapseGroup, capacity),
+		compare: compare,
+	}
+	heap.Init(rv)
+	return rv
+}
+
+// AddNotExceedingSize adds the document to its group.  Only one document
+// can be displaced per call: either the document itself (its group already
+// has a better hit), the group's previous best hit, or the best hit of the
+// worst group when the number of groups exceeds size.
+func (c *collectStoreCollapse) AddNotExceedingSize(doc *search.DocumentMatch,
+	size int) *search.DocumentMatch {
+	if _, ok := c.excluded[string(doc.CollapseKey)]; ok {
+		return doc
+	}
+	if g, ok := c.groups[string(doc.CollapseKey)]; ok {
+		if c.compare(doc, g.doc) >= 0 {
+			return doc
+		}
+		rv := g.doc
+		g.doc = doc
+		heap.Fix(c, g.index)
+		return rv
+	}
+	g := &collapseGroup{key: string(doc.CollapseKey), doc: doc}
+	c.groups[g.key] = g
+	heap.Push(c, g)
+	if c.Len() > size {
+		return c.removeLast()
+	}
+	return nil
+}
+
+// Exclude drops the group for the provided key, and ensures no further
+// documents are accepted for it.  The best hit of the dropped group is
+// returned, if there was one.
+func (c *collectStoreCollapse) Exclude(key []byte) *search.DocumentMatch {
+	if _, ok := c.excluded[string(key)]; ok {
+		return nil
+	}
+	if c.excluded == nil {
+		c.excluded = make(map[string]struct{})
+	}
+	c.excluded[string(key)] = struct{}{}
+	if g, ok := c.groups[string(key)]; ok {
+		heap.Remove(c, g.index)
+		delete(c.groups, g.key)
+		return g.doc
+	}
+	return nil
+}
+
+func (c *collectStoreCollapse) removeLast() *search.DocumentMatch {
+	g := heap.Pop(c).(*collapseGroup)
+	delete(c.groups, g.key)
+	return g.doc
+}
+
+// Final trims the store down to its size before skipping and
+// returning the best hit of each remaining group.
+func (c *collectStoreCollapse) Final(skip int, fixup collectorFixup) (search.DocumentMatchCollection, error) {
+	for c.Len() > c.size {
+		c.removeLast()
+	}
+	count := c.Len() - skip
+	if count <= 0 {
+		return make(search.DocumentMatchCollection, 0), nil
+	}
+	rv := make(search.DocumentMatchCollection, count)
+	for i := count - 1; i >= 0; i-- {
+		doc := c.removeLast()
+		rv[i] = doc
+		err := fixup(doc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rv, nil
+}
+
+func (c *collectStoreCollapse) Internal() search.DocumentMatchCollection {
+	rv := make(search.DocumentMatchCollection, len(c.heap))
+	for i, g := range c.heap {
+		rv[i] = g.doc
+	}
+	return rv
+}
+
+// heap interface implementation
+
+func (c *collectStoreCollapse) Len() int {
+	return len(c.heap)
+}
+
+func (c *collectStoreCollapse) Less(i, j int) bool {
+	so := c.compare(c.heap[i].doc, c.heap[j].doc)
+	return -so < 0
+}
+
+func (c *collectStoreCollapse) Swap(i, j int) {
+	c.heap[i], c.heap[j] = c.heap[j], c.heap[i]
+	c.heap[i].index = i
+	c.heap[j].index = j
+}
+
+func (c *collectStoreCollapse) Push(x interface{}) {
+	g := x.(*collapseGroup)
+	g.index = len(c.heap)
+	c.heap = append(c.heap, g)
+}
+
+func (c *collectStoreCollapse) Pop() interface{} {
+	var rv *collapseGroup
+	rv, c.heap = c.heap[len(c.heap)-1], c.heap[:len(c.heap)-1]
+	return rv
+}
+
+// makeCollapseDocumentMatchHandler builds the document match handler used
+// when collapsing.  Without search after, the store holds at most size+skip
+// groups.  With search after, any group with a hit at or before the search
+// after key was already returned on an earlier page and has to be dropped,
+// which can make room for groups evicted earlier; so the store is left
+// unbounded during collection, retaining one hit per distinct key, and is
+// trimmed to size in Final.  The search is rejected once more than
+// CollapseSearchAfterMaxGroups groups are held.
+func makeCollapseDocumentMatchHandler(ctx *search.SearchContext,
+	hc *TopNCollector) search.DocumentMatchHandler {
+	storeSize := hc.size + hc.skip
+	if hc.searchAfter != nil {
+		storeSize = math.MaxInt
+	}
+	return func(d *search.DocumentMatch) error {
+		if d == nil {
+			return nil
+		}
+
+		if hc.searchAfter != nil {
+			hc.searchAfter.HitNumber = d.HitNumber
+			if hc.cmp(d, hc.searchAfter) <= 0 {
+				removed := hc.collapseStore.Exclude(d.CollapseKey)
+				if removed != nil {
+					ctx.DocumentMatchPool.Put(removed)
+				}
+				ctx.DocumentMatchPool.Put(d)
+				return nil
+			}
+		}
+
+		removed := hc.collapseStore.AddNotExceedingSize(d, storeSize)
+		if removed != nil {
+			ctx.DocumentMatchPool.Put(removed)
+		}
+		if hc.searchAfter != nil && hc.collapseStore.Len() > CollapseSearchAfterMaxGroups {
+			return fmt.Errorf("collapse with search after exceeded %d groups, "+
+				"use from to page over field %s", CollapseSearchAfterMaxGroups, hc.collapseField)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,206 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/document"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/searcher"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+func TestCollapse(t *testing.T) {
+	idx := setupIndex(t)
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := []struct {
+		id    string
+		group string
+		data  int64
+	}{
+		{"a", "g1", 10},
+		{"b", "g2", 9},
+		{"c", "g1", 8},
+		{"d", "g3", 7},
+		{"e", "g2", 6},
+		{"f", "g4", 5},
+		{"g", "g3", 4},
+		{"h", "", 3},
+		{"i", "g5", 2},
+		{"j", "", 1},
+	}
+
+	batch := index.NewBatch()
+	for _, d := range docs {
+		doc := document.NewDocument(d.id)
+		doc.AddField(document.NewNumericFieldWithIndexingOptions("data", []uint64{},
+			float64(d.data), index.IndexField|index.DocValues))
+		if d.group != "" {
+			doc.AddField(document.NewTextFieldWithIndexingOptions("group", []uint64{},
+				[]byte(d.group), index.IndexField|index.DocValues))
+		}
+		batch.Update(doc)
+	}
+	err := idx.Batch(batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := idx.Reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := reader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	sortOrder := search.SortOrder{&search.SortField{Field: "data", Type: search.SortFieldAsNumber, Desc: true}}
+
+	tests := []struct {
+		name     string
+		coll     func() *TopNCollector
+		expected []string
+	}{
+		{
+			name:     "all groups",
+			coll:     func() *TopNCollector { return NewTopNCollector(10, 0, sortOrder) },
+			expected: []string{"a", "b", "d", "f", "h", "i"},
+		},
+		{
+			name:     "top groups",
+			coll:     func() *TopNCollector { return NewTopNCollector(3, 0, sortOrder) },
+			expected: []string{"a", "b", "d"},
+		},
+		{
+			name:     "skip",
+			coll:     func() *TopNCollector { return NewTopNCollector(2, 1, sortOrder) },
+			expected: []string{"b", "d"},
+		},
+		{
+			name:     "search after",
+			coll:     func() *TopNCollector { return NewTopNCollectorAfter(2, sortOrder, []string{"7"}) },
+			expected: []string{"f", "h"},
+		},
+		{
+			name:     "search after skips groups seen on earlier pages",
+			coll:     func() *TopNCollector { return NewTopNCollectorAfter(10, sortOrder, []string{"5"}) },
+			expected: []string{"h", "i"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := searcher.NewMatchAllSearcher(context.Background(), reader, 1.0, search.SearcherOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			coll := test.coll()
+			coll.SetCollapse("group")
+			err = coll.Collect(context.Background(), s, reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if coll.Total() != uint64(len(docs)) {
+				t.Errorf("expected total %d, got %d", len(docs), coll.Total())
+			}
+			var ids []string
+			for _, hit := range coll.Results() {
+				ids = append(ids, hit.ID)
+			}
+			if !reflect.DeepEqual(ids, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, ids)
+			}
+		})
+	}
+}
+
+func TestCollapseSearchAfterMaxGroups(t *testing.T) {
+	idx := setupIndex(t)
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	batch := index.NewBatch()
+	for i, group := range []string{"g1", "g2", "g3", "g4"} {
+		doc := document.NewDocument(group)
+		doc.AddField(document.NewNumericFieldWithIndexingOptions("data", []uint64{},
+			float64(i), index.IndexField|index.DocValues))
+		doc.AddField(document.NewTextFieldWithIndexingOptions("group", []uint64{},
+			[]byte(group), index.IndexField|index.DocValues))
+		batch.Update(doc)
+	}
+	err := idx.Batch(batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := idx.Reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := reader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	defer func(max int) {
+		CollapseSearchAfterMaxGroups = max
+	}(CollapseSearchAfterMaxGroups)
+	CollapseSearchAfterMaxGroups = 2
+
+	sortOrder := search.SortOrder{&search.SortField{Field: "data", Type: search.SortFieldAsNumber}}
+	collect := func(coll *TopNCollector) error {
+		s, err := searcher.NewMatchAllSearcher(context.Background(), reader, 1.0, search.SearcherOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		coll.SetCollapse("group")
+		return coll.Collect(context.Background(), s, reader)
+	}
+
+	// groups at or before the search after key are not held
+	err = collect(NewTopNCollectorAfter(1, sortOrder, []string{"1"}))
+	if err != nil {
+		t.Fatalf("expected groups after the key to fit, got %v", err)
+	}
+
+	err = collect(NewTopNCollectorAfter(1, sortOrder, []string{"0"}))
+	if err == nil {
+		t.Fatal("expected error collapsing too many groups with search after")
+	}
+
+	// paging with from still evicts groups
+	err = collect(NewTopNCollector(1, 1, sortOrder))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -83,6 +83,14 @@ type TopNCollector struct {
 
 	nestedStore *collectStoreNested
 
+	collapseField  string
+	collapseStore  *collectStoreCollapse
+	collapseDoc    *search.DocumentMatch
+	sawCollapseKey bool
+
+	runtimeFields  *search.RuntimeFields
+	runtimeVisitor index.DocValueVisitor
+
 	fastPrepare bool
 }
 
@@ -314,7 +322,12 @@ func (hc *TopNCollector) Collect(ctx context.Context, searcher search.Searcher,
 		IndexReader:       reader,
 	}
 
-	hc.dvReader, err = reader.DocValueReader(hc.neededFields)
+	docValueFields := hc.neededFields
+	if hc.runtimeFields != nil {
+		// runtime fields are computed from the doc values of other fields
+		docValueFields = hc.runtimeFields.RequiredFields(docValueFields)
+	}
+	hc.dvReader, err = reader.DocValueReader(docValueFields)
 	if err != nil {
 		return err
 	}
@@ -323,6 +336,9 @@ func (hc *TopNCollector) Collect(ctx context.Context, searcher search.Searcher,
 		if hc.facetsBuilder != nil {
 			hc.facetsBuilder.UpdateVisitor(field, term)
 		}
+		if hc.collapseDoc != nil {
+			hc.updateCollapseKey(field, term)
+		}
 		hc.sort.UpdateVisitor(field, term)
 	}
 
@@ -542,9 +558,14 @@ func (hc *TopNCollector) prepareDocumentMatch(ctx *search.SearchContext,
 func (hc *TopNCollector) prepareKNNDocumentMatch(ctx *search.SearchContext,
 	reader index.IndexReader, d *search.DocumentMatch) (err error) {
 
-	if hc.facetsBuilder != nil {
+	if hc.facetsBuilder != nil || hc.collapseStore != nil {
 		err = hc.visitFieldTerms(reader, d, func(field string, term []byte) {
-			hc.facetsBuilder.UpdateVisitor(field, term)
+			if hc.facetsBuilder != nil {
+				hc.facetsBuilder.UpdateVisitor(field, term)
+			}
+			if hc.collapseDoc != nil {
+				hc.updateCollapseKey(field, term)
+			}
 		})
 		if err != nil {
 			return err
@@ -560,6 +581,9 @@ func MakeTopNDocumentMatchHandler(
 	var hc *TopNCollector
 	var ok bool
 	if hc, ok = ctx.Collector.(*TopNCollector); ok {
+		if hc.collapseStore != nil {
+			return makeCollapseDocumentMatchHandler(ctx, hc), false, nil
+		}
 		return func(d *search.DocumentMatch) error {
 			if d == nil {
 				return nil
@@ -614,6 +638,11 @@ func (hc *TopNCollector) visitFieldTerms(reader index.IndexReader, d *search.Doc
 	if hc.facetsBuilder != nil {
 		hc.facetsBuilder.StartDoc()
 	}
+	if hc.collapseStore != nil {
+		d.CollapseKey = d.CollapseKey[:0]
+		hc.collapseDoc = d
+		hc.sawCollapseKey = false
+	}
 	if d.ID != "" && d.IndexInternalID == nil {
 		// this document may have been sent over as preSearchData and
 		// we need to look up the internal id to visit the doc values for it
@@ -624,6 +653,13 @@ func (hc *TopNCollector) visitFieldTerms(reader index.IndexReader, d *search.Doc
 		}
 	}
 
+	if hc.runtimeFields != nil {
+		// the doc values go through the runtime fields, which
+		// pass them on along with the values they compute
+		hc.runtimeFields.StartDoc(v)
+		v = hc.runtimeVisitor
+	}
+
 	// first visit descendants if any
 	for _, descID := range d.Descendants {
 		err := hc.dvReader.VisitDocValues(descID, v)
@@ -633,9 +669,13 @@ func (hc *TopNCollector) visitFieldTerms(reader index.IndexReader, d *search.Doc
 	}
 	// now visit the doc values for this document
 	err := hc.dvReader.VisitDocValues(d.IndexInternalID, v)
+	if hc.runtimeFields != nil && err == nil {
+		hc.runtimeFields.EndDoc()
+	}
 	if hc.facetsBuilder != nil {
 		hc.facetsBuilder.EndDoc()
 	}
+	hc.collapseDoc = nil
 
 	hc.bytesRead += hc.dvReader.BytesRead()
 
@@ -661,6 +701,38 @@ func (hc *TopNCollector) SetFacetsBuilder(facetsBuilder *search.FacetsBuilder) {
 	}
 }
 
+// SetCollapse configures the collector to keep only the best hit for
+// each distinct value of the provided field, with pagination applying to
+// the collapsed hits.  Only the lowest sorting term of the field's doc
+// values is used, so the field is expected to hold a single value per
+// document, and hits without a value for the field are collapsed together.
+func (hc *TopNCollector) SetCollapse(field string) {
+	hc.collapseField = field
+	hc.collapseStore = newStoreCollapse(hc.size+hc.skip, hc.cmp)
+	hc.store = hc.collapseStore
+	for _, neededField := range hc.neededFields {
+		if neededField == field {
+			return
+		}
+	}
+	hc.neededFields = append(hc.neededFields, field)
+}
+
+// SetRuntimeFields registers the fields computed at search time, which
+// can then be sorted, faceted and collapsed on as any indexed field.
+func (hc *TopNCollector) SetRuntimeFields(runtimeFields *search.RuntimeFields) {
+	hc.runtimeFields = runtimeFields
+	hc.runtimeVisitor = runtimeFields.UpdateVisitor
+}
+
+func (hc *TopNCollector) updateCollapseKey(field string, term []byte) {
+	if field != hc.collapseField || hc.sawCollapseKey {
+		return
+	}
+	hc.collapseDoc.CollapseKey = append(hc.collapseDoc.CollapseKey, term...)
+	hc.sawCollapseKey = true
+}
+
 // finalizeResults starts with the heap containing the final top size+skip
 // it now throws away the results to be skipped
 // and does final doc id lookup (if necessary)
@@ -0,0 +1,270 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facet
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/blevesearch/bleve/v2/numeric"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/size"
+)
+
+var reflectStaticSizeDateHistogramFacetBuilder int
+
+func init() {
+	var dhfb DateHistogramFacetBuilder
+	reflectStaticSizeDateHistogramFacetBuilder = int(reflect.TypeOf(dhfb).Size())
+}
+
+// The calendar intervals supported by date histogram facets.
+const (
+	CalendarIntervalMinute  = "minute"
+	CalendarIntervalHour    = "hour"
+	CalendarIntervalDay     = "day"
+	CalendarIntervalWeek    = "week"
+	CalendarIntervalMonth   = "month"
+	CalendarIntervalQuarter = "quarter"
+	CalendarIntervalYear    = "year"
+)
+
+// IsValidCalendarInterval returns true if interval is one of
+// the supported calendar intervals.
+func IsValidCalendarInterval(interval string) bool {
+	switch interval {
+	case CalendarIntervalMinute, CalendarIntervalHour, CalendarIntervalDay,
+		CalendarIntervalWeek, CalendarIntervalMonth, CalendarIntervalQuarter,
+		CalendarIntervalYear:
+		return true
+	}
+	return false
+}
+
+// DateHistogramFacetBuilder counts the date time values of a field in
+// calendar aware buckets, the buckets are generated from the values seen.
+// Its result holds only the buckets with values, FillDateHistogram
+// completes the result once merged with those of other indexes.
+type DateHistogramFacetBuilder struct {
+	size        int
+	field       string
+	interval    string
+	location    *time.Location
+	minDocCount int
+	termsCount  map[int64]int
+	total       int
+	missing     int
+	sawValue    bool
+	subFacets   *search.SubFacetsBuilder
+}
+
+// NewDateHistogramFacetBuilder returns a builder with buckets of one
+// calendar interval, as observed in location (UTC if nil). The result
+// is limited to the earliest size buckets, unless minDocCount is more
+// than 1 and the buckets are yet to be filtered by FillDateHistogram.
+func NewDateHistogramFacetBuilder(field string, size int, interval string,
+	location *time.Location, minDocCount int) (*DateHistogramFacetBuilder, error) {
+	if !IsValidCalendarInterval(interval) {
+		return nil, fmt.Errorf("unknown calendar interval '%s'", interval)
+	}
+	if location == nil {
+		location = time.UTC
+	}
+	return &DateHistogramFacetBuilder{
+		size:        size,
+		field:       field,
+		interval:    interval,
+		location:    location,
+		minDocCount: minDocCount,
+		termsCount:  make(map[int64]int),
+	}, nil
+}
+
+func (fb *DateHistogramFacetBuilder) Size() int {
+	sizeInBytes := reflectStaticSizeDateHistogramFacetBuilder + size.SizeOfPtr +
+		len(fb.field) + len(fb.interval) +
+		len(fb.termsCount)*(size.SizeOfUint64+size.SizeOfInt)
+
+	if fb.subFacets != nil {
+		sizeInBytes += fb.subFacets.Size()
+	}
+
+	return sizeInBytes
+}
+
+func (fb *DateHistogramFacetBuilder) Field() string {
+	return fb.field
+}
+
+// SetSubFacets sets the child facets computed within each bucket.
+func (fb *DateHistogramFacetBuilder) SetSubFacets(subFacets *search.SubFacetsBuilder) {
+	fb.subFacets = subFacets
+}
+
+func (fb *DateHistogramFacetBuilder) SubFacets() *search.SubFacetsBuilder {
+	return fb.subFacets
+}
+
+// calendarBucketStart truncates t to the start of its calendar
+// interval, as observed in location.
+func calendarBucketStart(t time.Time, interval string, location *time.Location) time.Time {
+	t = t.In(location)
+	year, month, day := t.Date()
+	switch interval {
+	case CalendarIntervalMinute:
+		return time.Date(year, month, day, t.Hour(), t.Minute(), 0, 0, location)
+	case CalendarIntervalHour:
+		return time.Date(year, month, day, t.Hour(), 0, 0, 0, location)
+	case CalendarIntervalDay:
+		return time.Date(year, month, day, 0, 0, 0, 0, location)
+	case CalendarIntervalWeek:
+		// weeks start on monday
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, location)
+	case CalendarIntervalMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, location)
+	case CalendarIntervalQuarter:
+		return time.Date(year, ((month-1)/3)*3+1, 1, 0, 0, 0, 0, location)
+	}
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, location)
+}
+
+// nextCalendarBucketStart returns the start of the interval following
+// start. It uses the same calendar arithmetic as calendarBucketStart,
+// rather than adding durations, so that both agree on the starts of
+// the intervals across daylight saving time changes.
+func nextCalendarBucketStart(start time.Time, interval string, location *time.Location) time.Time {
+	start = start.In(location)
+	year, month, day := start.Date()
+	switch interval {
+	case CalendarIntervalMinute:
+		return time.Date(year, month, day, start.Hour(), start.Minute()+1, 0, 0, location)
+	case CalendarIntervalHour:
+		return time.Date(year, month, day, start.Hour()+1, 0, 0, 0, location)
+	case CalendarIntervalDay:
+		return time.Date(year, month, day+1, 0, 0, 0, 0, location)
+	case CalendarIntervalWeek:
+		return time.Date(year, month, day+7, 0, 0, 0, 0, location)
+	case CalendarIntervalMonth:
+		return time.Date(year, month+1, 1, 0, 0, 0, 0, location)
+	case CalendarIntervalQuarter:
+		return time.Date(year, month+3, 1, 0, 0, 0, 0, location)
+	}
+	return time.Date(year+1, time.January, 1, 0, 0, 0, 0, location)
+}
+
+func (fb *DateHistogramFacetBuilder) UpdateVisitor(term []byte) {
+	fb.sawValue = true
+	// only consider the values which are shifted 0
+	prefixCoded := numeric.PrefixCoded(term)
+	shift, err := prefixCoded.Shift()
+	if err == nil && shift == 0 {
+		i64, err := prefixCoded.Int64()
+		if err == nil {
+			bucket := calendarBucketStart(time.Unix(0, i64), fb.interval, fb.location).UnixNano()
+			fb.termsCount[bucket] = fb.termsCount[bucket] + 1
+			fb.total++
+			if fb.subFacets != nil {
+				fb.subFacets.AddBucket(strconv.FormatInt(bucket, 10))
+			}
+		}
+	}
+}
+
+func (fb *DateHistogramFacetBuilder) StartDoc() {
+	fb.sawValue = false
+	if fb.subFacets != nil {
+		fb.subFacets.StartDoc()
+	}
+}
+
+func (fb *DateHistogramFacetBuilder) EndDoc() {
+	if !fb.sawValue {
+		fb.missing++
+	}
+	if fb.subFacets != nil {
+		fb.subFacets.EndDoc()
+	}
+}
+
+func (fb *DateHistogramFacetBuilder) Result() *search.FacetResult {
+	rv := search.FacetResult{
+		Field:   fb.field,
+		Total:   fb.total,
+		Missing: fb.missing,
+	}
+
+	buckets := make([]int64, 0, len(fb.termsCount))
+	for bucket := range fb.termsCount {
+		buckets = append(buckets, bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i] < buckets[j]
+	})
+
+	rv.Histogram = make(search.HistogramFacets, 0, len(buckets))
+	for _, bucket := range buckets {
+		start := time.Unix(0, bucket).In(fb.location)
+		hf := &search.HistogramFacet{
+			Key:         float64(start.UnixMilli()),
+			KeyAsString: start.Format(time.RFC3339Nano),
+			Count:       fb.termsCount[bucket],
+		}
+		if fb.subFacets != nil {
+			hf.Facets = fb.subFacets.Results(strconv.FormatInt(bucket, 10))
+		}
+		rv.Histogram = append(rv.Histogram, hf)
+	}
+
+	// we now have the list of the earliest N buckets, which include
+	// the earliest N buckets of the results merged from other indexes,
+	// unless the buckets are yet to be filtered by their counts
+	if fb.minDocCount <= 1 && fb.size < len(rv.Histogram) {
+		rv.Histogram = rv.Histogram[:fb.size]
+	}
+
+	notOther := 0
+	for _, hf := range rv.Histogram {
+		notOther += hf.Count
+	}
+	rv.Other = fb.total - notOther
+
+	return &rv
+}
+
+// FillDateHistogram completes a date histogram facet result, once the
+// results of all the indexes are merged. With a minDocCount of 0 the
+// empty buckets between the earliest and latest buckets are filled in,
+// otherwise the buckets with fewer than minDocCount values are omitted.
+// The result is limited to the earliest size buckets.
+func FillDateHistogram(fr *search.FacetResult, size int, interval string,
+	location *time.Location, minDocCount int) {
+	if location == nil {
+		location = time.UTC
+	}
+	fillHistogram(fr, size, minDocCount, func(key float64) float64 {
+		start := time.UnixMilli(int64(key))
+		return float64(nextCalendarBucketStart(start, interval, location).UnixMilli())
+	}, func(key float64) *search.HistogramFacet {
+		start := time.UnixMilli(int64(key)).In(location)
+		return &search.HistogramFacet{
+			Key:         key,
+			KeyAsString: start.Format(time.RFC3339Nano),
+		}
+	})
+}
@@ -49,6 +49,7 @@ type DateTimeFacetBuilder struct {
 	missing    int
 	ranges     map[string]*dateTimeRange
 	sawValue   bool
+	subFacets  *search.SubFacetsBuilder
 }
 
 func NewDateTimeFacetBuilder(field string, size int) *DateTimeFacetBuilder {
@@ -74,6 +75,10 @@ func (fb *DateTimeFacetBuilder) Size() int {
 			size.SizeOfPtr + reflectStaticSizedateTimeRange
 	}
 
+	if fb.subFacets != nil {
+		sizeInBytes += fb.subFacets.Size()
+	}
+
 	return sizeInBytes
 }
 
@@ -89,6 +94,15 @@ func (fb *DateTimeFacetBuilder) Field() string {
 	return fb.field
 }
 
+// SetSubFacets sets the child facets computed within each bucket.
+func (fb *DateTimeFacetBuilder) SetSubFacets(subFacets *search.SubFacetsBuilder) {
+	fb.subFacets = subFacets
+}
+
+func (fb *DateTimeFacetBuilder) SubFacets() *search.SubFacetsBuilder {
+	return fb.subFacets
+}
+
 func (fb *DateTimeFacetBuilder) UpdateVisitor(term []byte) {
 	fb.sawValue = true
 	// only consider the values which are shifted 0
@@ -104,6 +118,9 @@ func (fb *DateTimeFacetBuilder) UpdateVisitor(term []byte) {
 				if (r.start.IsZero() || t.After(r.start) || t.Equal(r.start)) && (r.end.IsZero() || t.Before(r.end)) {
 					fb.termsCount[rangeName] = fb.termsCount[rangeName] + 1
 					fb.total++
+					if fb.subFacets != nil {
+						fb.subFacets.AddBucket(rangeName)
+					}
 				}
 			}
 		}
@@ -112,12 +129,18 @@ func (fb *DateTimeFacetBuilder) UpdateVisitor(term []byte) {
 
 func (fb *DateTimeFacetBuilder) StartDoc() {
 	fb.sawValue = false
+	if fb.subFacets != nil {
+		fb.subFacets.StartDoc()
+	}
 }
 
 func (fb *DateTimeFacetBuilder) EndDoc() {
 	if !fb.sawValue {
 		fb.missing++
 	}
+	if fb.subFacets != nil {
+		fb.subFacets.EndDoc()
+	}
 }
 
 func (fb *DateTimeFacetBuilder) Result() *search.FacetResult {
@@ -159,5 +182,11 @@ func (fb *DateTimeFacetBuilder) Result() *search.FacetResult {
 	}
 	rv.Other = fb.total - notOther
 
+	if fb.subFacets != nil {
+		for _, r := range rv.DateRanges {
+			r.Facets = fb.subFacets.Results(r.Name)
+		}
+	}
+
 	return &rv
 }
@@ -0,0 +1,236 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facet
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/blevesearch/bleve/v2/numeric"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/size"
+)
+
+var reflectStaticSizeHistogramFacetBuilder int
+
+func init() {
+	var hfb HistogramFacetBuilder
+	reflectStaticSizeHistogramFacetBuilder = int(reflect.TypeOf(hfb).Size())
+}
+
+// HistogramFacetBuilder counts the numeric values of a field in buckets
+// of a fixed width, the buckets are generated from the values seen.
+// Its result holds only the buckets with values, FillHistogram
+// completes the result once merged with those of other indexes.
+type HistogramFacetBuilder struct {
+	size        int
+	field       string
+	interval    float64
+	minDocCount int
+	termsCount  map[int64]int
+	total       int
+	missing     int
+	sawValue    bool
+	subFacets   *search.SubFacetsBuilder
+}
+
+// NewHistogramFacetBuilder returns a builder with buckets of width
+// interval. The result is limited to the lowest size buckets, unless
+// minDocCount is more than 1 and the buckets are yet to be filtered
+// by FillHistogram.
+func NewHistogramFacetBuilder(field string, size int, interval float64, minDocCount int) (*HistogramFacetBuilder, error) {
+	if interval <= 0 || math.IsInf(interval, 0) || math.IsNaN(interval) {
+		return nil, fmt.Errorf("histogram interval must be positive, got %v", interval)
+	}
+	return &HistogramFacetBuilder{
+		size:        size,
+		field:       field,
+		interval:    interval,
+		minDocCount: minDocCount,
+		termsCount:  make(map[int64]int),
+	}, nil
+}
+
+func (fb *HistogramFacetBuilder) Size() int {
+	sizeInBytes := reflectStaticSizeHistogramFacetBuilder + size.SizeOfPtr +
+		len(fb.field) +
+		len(fb.termsCount)*(size.SizeOfUint64+size.SizeOfInt)
+
+	if fb.subFacets != nil {
+		sizeInBytes += fb.subFacets.Size()
+	}
+
+	return sizeInBytes
+}
+
+func (fb *HistogramFacetBuilder) Field() string {
+	return fb.field
+}
+
+// SetSubFacets sets the child facets computed within each bucket.
+func (fb *HistogramFacetBuilder) SetSubFacets(subFacets *search.SubFacetsBuilder) {
+	fb.subFacets = subFacets
+}
+
+func (fb *HistogramFacetBuilder) SubFacets() *search.SubFacetsBuilder {
+	return fb.subFacets
+}
+
+func (fb *HistogramFacetBuilder) bucketKey(bucket int64) float64 {
+	return float64(bucket) * fb.interval
+}
+
+func (fb *HistogramFacetBuilder) UpdateVisitor(term []byte) {
+	fb.sawValue = true
+	// only consider the values which are shifted 0
+	prefixCoded := numeric.PrefixCoded(term)
+	shift, err := prefixCoded.Shift()
+	if err == nil && shift == 0 {
+		i64, err := prefixCoded.Int64()
+		if err == nil {
+			f64 := numeric.Int64ToFloat64(i64)
+			if math.IsNaN(f64) || math.IsInf(f64, 0) {
+				return
+			}
+
+			bucket := int64(math.Floor(f64 / fb.interval))
+			fb.termsCount[bucket] = fb.termsCount[bucket] + 1
+			fb.total++
+			if fb.subFacets != nil {
+				fb.subFacets.AddBucket(strconv.FormatInt(bucket, 10))
+			}
+		}
+	}
+}
+
+func (fb *HistogramFacetBuilder) StartDoc() {
+	fb.sawValue = false
+	if fb.subFacets != nil {
+		fb.subFacets.StartDoc()
+	}
+}
+
+func (fb *HistogramFacetBuilder) EndDoc() {
+	if !fb.sawValue {
+		fb.missing++
+	}
+	if fb.subFacets != nil {
+		fb.subFacets.EndDoc()
+	}
+}
+
+func (fb *HistogramFacetBuilder) Result() *search.FacetResult {
+	rv := search.FacetResult{
+		Field:   fb.field,
+		Total:   fb.total,
+		Missing: fb.missing,
+	}
+
+	buckets := make([]int64, 0, len(fb.termsCount))
+	for bucket := range fb.termsCount {
+		buckets = append(buckets, bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i] < buckets[j]
+	})
+
+	rv.Histogram = make(search.HistogramFacets, 0, len(buckets))
+	for _, bucket := range buckets {
+		hf := &search.HistogramFacet{
+			Key:   fb.bucketKey(bucket),
+			Count: fb.termsCount[bucket],
+		}
+		if fb.subFacets != nil {
+			hf.Facets = fb.subFacets.Results(strconv.FormatInt(bucket, 10))
+		}
+		rv.Histogram = append(rv.Histogram, hf)
+	}
+
+	// we now have the list of the lowest N buckets, which include
+	// the lowest N buckets of the results merged from other indexes,
+	// unless the buckets are yet to be filtered by their counts
+	if fb.minDocCount <= 1 && fb.size < len(rv.Histogram) {
+		rv.Histogram = rv.Histogram[:fb.size]
+	}
+
+	notOther := 0
+	for _, hf := range rv.Histogram {
+		notOther += hf.Count
+	}
+	rv.Other = fb.total - notOther
+
+	return &rv
+}
+
+// FillHistogram completes a histogram facet result with buckets of
+// width interval, once the results of all the indexes are merged. With
+// a minDocCount of 0 the empty buckets between the lowest and highest
+// buckets are filled in, otherwise the buckets with fewer than
+// minDocCount values are omitted. The result is limited to the lowest
+// size buckets.
+func FillHistogram(fr *search.FacetResult, size int, interval float64, minDocCount int) {
+	fillHistogram(fr, size, minDocCount, func(key float64) float64 {
+		return (math.Round(key/interval) + 1) * interval
+	}, func(key float64) *search.HistogramFacet {
+		return &search.HistogramFacet{Key: key}
+	})
+}
+
+// fillHistogram fills in or filters the buckets of fr, next returns
+// the key of the bucket following key and empty a bucket without values.
+func fillHistogram(fr *search.FacetResult, size int, minDocCount int,
+	next func(key float64) float64, empty func(key float64) *search.HistogramFacet) {
+	if fr == nil || fr.Histogram == nil {
+		return
+	}
+	sort.Sort(fr.Histogram)
+
+	var rv search.HistogramFacets
+	if minDocCount == 0 && len(fr.Histogram) > 0 {
+		// there is no point going past size
+		rv = make(search.HistogramFacets, 0, len(fr.Histogram))
+		key := fr.Histogram[0].Key
+		for i := 0; i < len(fr.Histogram) && len(rv) < size; {
+			hf := fr.Histogram[i]
+			if hf.Key <= key {
+				rv = append(rv, hf)
+				i++
+				if hf.Key == key {
+					key = next(key)
+				}
+				continue
+			}
+			rv = append(rv, empty(key))
+			key = next(key)
+		}
+	} else {
+		rv = make(search.HistogramFacets, 0, len(fr.Histogram))
+		for _, hf := range fr.Histogram {
+			if hf.Count >= minDocCount && len(rv) < size {
+				rv = append(rv, hf)
+			}
+		}
+	}
+	fr.Histogram = rv
+
+	notOther := 0
+	for _, hf := range fr.Histogram {
+		notOther += hf.Count
+	}
+	fr.Other = fr.Total - notOther
+}
@@ -0,0 +1,280 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facet
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/blevesearch/bleve/v2/numeric"
+	"github.com/blevesearch/bleve/v2/search"
+)
+
+type histogramBucket struct {
+	key   string
+	count int
+}
+
+func histogramBuckets(fr *search.FacetResult) []histogramBucket {
+	rv := make([]histogramBucket, 0, len(fr.Histogram))
+	for _, h := range fr.Histogram {
+		key := h.KeyAsString
+		if key == "" {
+			key = strconv.FormatFloat(h.Key, 'f', -1, 64)
+		}
+		rv = append(rv, histogramBucket{key: key, count: h.Count})
+	}
+	return rv
+}
+
+func TestHistogramFacetBuilder(t *testing.T) {
+	values := []float64{-3, 1, 4, 9.5, 22, 24}
+
+	tests := []struct {
+		size        int
+		minDocCount int
+		expected    []histogramBucket
+		other       int
+	}{
+		{
+			size:        100,
+			minDocCount: 1,
+			expected: []histogramBucket{
+				{key: "-5", count: 1}, {key: "0", count: 2},
+				{key: "5", count: 1}, {key: "20", count: 2},
+			},
+		},
+		{
+			size:        100,
+			minDocCount: 0,
+			expected: []histogramBucket{
+				{key: "-5", count: 1}, {key: "0", count: 2},
+				{key: "5", count: 1}, {key: "10", count: 0},
+				{key: "15", count: 0}, {key: "20", count: 2},
+			},
+		},
+		{
+			size:        100,
+			minDocCount: 2,
+			expected: []histogramBucket{
+				{key: "0", count: 2}, {key: "20", count: 2},
+			},
+			other: 2,
+		},
+		{
+			size:        2,
+			minDocCount: 0,
+			expected: []histogramBucket{
+				{key: "-5", count: 1}, {key: "0", count: 2},
+			},
+			other: 3,
+		},
+	}
+
+	for _, test := range tests {
+		hfb, err := NewHistogramFacetBuilder("price", test.size, 5, test.minDocCount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range values {
+			hfb.StartDoc()
+			hfb.UpdateVisitor(numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(v), 0))
+			hfb.EndDoc()
+		}
+		fr := hfb.Result()
+		FillHistogram(fr, test.size, 5, test.minDocCount)
+		if got := histogramBuckets(fr); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("min doc count %d size %d: expected %v, got %v",
+				test.minDocCount, test.size, test.expected, got)
+		}
+		if fr.Other != test.other {
+			t.Errorf("expected other %d, got %d", test.other, fr.Other)
+		}
+	}
+
+	if _, err := NewHistogramFacetBuilder("price", 10, 0, 0); err == nil {
+		t.Errorf("expected error for zero interval")
+	}
+}
+
+func TestDateHistogramFacetBuilder(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	values := []time.Time{
+		time.Date(2024, 1, 31, 23, 30, 0, 0, time.UTC),
+		time.Date(2024, 2, 1, 3, 0, 0, 0, time.UTC),
+		time.Date(2024, 2, 14, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		interval    string
+		location    *time.Location
+		minDocCount int
+		expected    []histogramBucket
+	}{
+		{
+			interval:    CalendarIntervalMonth,
+			location:    time.UTC,
+			minDocCount: 1,
+			expected: []histogramBucket{
+				{key: "2024-01-01T00:00:00Z", count: 1},
+				{key: "2024-02-01T00:00:00Z", count: 2},
+				{key: "2024-05-01T00:00:00Z", count: 1},
+			},
+		},
+		{
+			// in new york the first two values both fall on january 31st
+			interval:    CalendarIntervalMonth,
+			location:    newYork,
+			minDocCount: 0,
+			expected: []histogramBucket{
+				{key: "2024-01-01T00:00:00-05:00", count: 2},
+				{key: "2024-02-01T00:00:00-05:00", count: 1},
+				{key: "2024-03-01T00:00:00-05:00", count: 0},
+				{key: "2024-04-01T00:00:00-04:00", count: 0},
+				{key: "2024-05-01T00:00:00-04:00", count: 1},
+			},
+		},
+		{
+			interval:    CalendarIntervalQuarter,
+			location:    time.UTC,
+			minDocCount: 1,
+			expected: []histogramBucket{
+				{key: "2024-01-01T00:00:00Z", count: 3},
+				{key: "2024-04-01T00:00:00Z", count: 1},
+			},
+		},
+		{
+			// weeks start on monday
+			interval:    CalendarIntervalWeek,
+			location:    time.UTC,
+			minDocCount: 1,
+			expected: []histogramBucket{
+				{key: "2024-01-29T00:00:00Z", count: 2},
+				{key: "2024-02-12T00:00:00Z", count: 1},
+				{key: "2024-04-29T00:00:00Z", count: 1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		dhfb, err := NewDateHistogramFacetBuilder("created", 100, test.interval,
+			test.location, test.minDocCount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range values {
+			dhfb.StartDoc()
+			dhfb.UpdateVisitor(numeric.MustNewPrefixCodedInt64(v.UnixNano(), 0))
+			dhfb.EndDoc()
+		}
+		fr := dhfb.Result()
+		FillDateHistogram(fr, 100, test.interval, test.location, test.minDocCount)
+		if got := histogramBuckets(fr); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s in %s: expected %v, got %v", test.interval, test.location,
+				test.expected, got)
+		}
+	}
+
+	if _, err := NewDateHistogramFacetBuilder("created", 10, "fortnight", nil, 0); err == nil {
+		t.Errorf("expected error for unknown calendar interval")
+	}
+}
+
+func TestHistogramFacetMerge(t *testing.T) {
+	histogram := func(minDocCount int, values ...float64) *search.FacetResult {
+		hfb, err := NewHistogramFacetBuilder("price", 100, 5, minDocCount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range values {
+			hfb.StartDoc()
+			hfb.UpdateVisitor(numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(v), 0))
+			hfb.EndDoc()
+		}
+		return hfb.Result()
+	}
+
+	// the gaps between the buckets of different indexes are filled in
+	fr := histogram(0, 1, 6)
+	fr.Merge(histogram(0, 16, 22))
+	FillHistogram(fr, 100, 5, 0)
+	expected := []histogramBucket{
+		{key: "0", count: 1}, {key: "5", count: 1}, {key: "10", count: 0},
+		{key: "15", count: 1}, {key: "20", count: 1},
+	}
+	if got := histogramBuckets(fr); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// the buckets are counted across the indexes
+	fr = histogram(2, 1, 6, 7)
+	fr.Merge(histogram(2, 2, 16))
+	FillHistogram(fr, 100, 5, 2)
+	expected = []histogramBucket{
+		{key: "0", count: 2}, {key: "5", count: 2},
+	}
+	if got := histogramBuckets(fr); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if fr.Other != 1 {
+		t.Errorf("expected other 1, got %d", fr.Other)
+	}
+}
+
+func TestDateHistogramFacetDaylightSaving(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	// the clocks go back from 2am to 1am on november 3rd 2024
+	dhfb, err := NewDateHistogramFacetBuilder("created", 100, CalendarIntervalHour, newYork, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []time.Time{
+		time.Date(2024, 11, 3, 4, 30, 0, 0, time.UTC),
+		time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC),
+		time.Date(2024, 11, 3, 6, 30, 0, 0, time.UTC),
+		time.Date(2024, 11, 3, 7, 30, 0, 0, time.UTC),
+	} {
+		dhfb.StartDoc()
+		dhfb.UpdateVisitor(numeric.MustNewPrefixCodedInt64(v.UnixNano(), 0))
+		dhfb.EndDoc()
+	}
+	fr := dhfb.Result()
+	FillDateHistogram(fr, 100, CalendarIntervalHour, newYork, 0)
+
+	// filling in the gaps adds no bucket which values cannot fall in
+	for _, hf := range fr.Histogram {
+		if hf.Count == 0 {
+			t.Errorf("unexpected empty bucket %s", hf.KeyAsString)
+		}
+	}
+	total := 0
+	for _, hf := range fr.Histogram {
+		total += hf.Count
+	}
+	if total != 4 {
+		t.Errorf("expected 4 values in the buckets, got %d: %v", total, histogramBuckets(fr))
+	}
+}
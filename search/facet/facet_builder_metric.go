@@ -0,0 +1,188 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facet
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/blevesearch/bleve/v2/numeric"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/sketch"
+	"github.com/blevesearch/bleve/v2/size"
+)
+
+var reflectStaticSizeMetricFacetBuilder int
+
+func init() {
+	var mfb MetricFacetBuilder
+	reflectStaticSizeMetricFacetBuilder = int(reflect.TypeOf(mfb).Size())
+}
+
+// MetricFacetBuilder computes a single metric over the numeric or
+// date time doc values of a field, rather than counting documents.
+type MetricFacetBuilder struct {
+	field    string
+	metric   string
+	dateTime bool
+	percents []float64
+
+	total    int
+	missing  int
+	count    int64
+	sum      float64
+	min      float64
+	max      float64
+	hll      *sketch.HyperLogLog
+	digest   *sketch.TDigest
+	sawValue bool
+}
+
+// NewMetricFacetBuilder returns a builder computing metric over field,
+// date time fields hold nanoseconds since the epoch rather than
+// float64 values and must be flagged with dateTime.
+func NewMetricFacetBuilder(field string, metric string, dateTime bool) (*MetricFacetBuilder, error) {
+	if !search.IsValidMetric(metric) {
+		return nil, fmt.Errorf("unknown metric '%s'", metric)
+	}
+	rv := &MetricFacetBuilder{
+		field:    field,
+		metric:   metric,
+		dateTime: dateTime,
+	}
+	switch metric {
+	case search.MetricCardinality:
+		var err error
+		rv.hll, err = sketch.NewHyperLogLog(sketch.DefaultHyperLogLogPrecision)
+		if err != nil {
+			return nil, err
+		}
+	case search.MetricPercentiles:
+		rv.digest = sketch.NewTDigest(sketch.DefaultTDigestCompression)
+		rv.percents = search.DefaultPercents
+	}
+	return rv, nil
+}
+
+func (fb *MetricFacetBuilder) Size() int {
+	sizeInBytes := reflectStaticSizeMetricFacetBuilder + size.SizeOfPtr +
+		len(fb.field) + len(fb.metric) +
+		len(fb.percents)*size.SizeOfFloat64
+
+	if fb.hll != nil {
+		sizeInBytes += fb.hll.Size()
+	}
+	if fb.digest != nil {
+		sizeInBytes += fb.digest.Size()
+	}
+
+	return sizeInBytes
+}
+
+func (fb *MetricFacetBuilder) Field() string {
+	return fb.field
+}
+
+// SetPercents sets the percentiles (between 0 and 100) reported
+// by a percentiles metric.
+func (fb *MetricFacetBuilder) SetPercents(percents []float64) {
+	if len(percents) > 0 {
+		fb.percents = percents
+	}
+}
+
+func (fb *MetricFacetBuilder) UpdateVisitor(term []byte) {
+	fb.sawValue = true
+	// only consider the values which are shifted 0
+	prefixCoded := numeric.PrefixCoded(term)
+	shift, err := prefixCoded.Shift()
+	if err != nil || shift != 0 {
+		return
+	}
+	i64, err := prefixCoded.Int64()
+	if err != nil {
+		return
+	}
+
+	var f64 float64
+	if fb.dateTime {
+		f64 = float64(i64)
+	} else {
+		f64 = numeric.Int64ToFloat64(i64)
+	}
+
+	if fb.count == 0 || f64 < fb.min {
+		fb.min = f64
+	}
+	if fb.count == 0 || f64 > fb.max {
+		fb.max = f64
+	}
+	fb.count++
+	fb.sum += f64
+	fb.total++
+
+	if fb.hll != nil {
+		fb.hll.Add(sketch.Hash64(uint64(i64)))
+	}
+	if fb.digest != nil {
+		fb.digest.Add(f64)
+	}
+}
+
+func (fb *MetricFacetBuilder) StartDoc() {
+	fb.sawValue = false
+}
+
+func (fb *MetricFacetBuilder) EndDoc() {
+	if !fb.sawValue {
+		fb.missing++
+	}
+}
+
+func (fb *MetricFacetBuilder) Result() *search.FacetResult {
+	rv := search.FacetResult{
+		Field:   fb.field,
+		Total:   fb.total,
+		Missing: fb.missing,
+	}
+
+	mf := &search.MetricFacet{
+		Metric:      fb.metric,
+		DateTime:    fb.dateTime,
+		Count:       fb.count,
+		Sum:         fb.sum,
+		Cardinality: fb.hll,
+	}
+	if fb.count > 0 {
+		min, max := fb.min, fb.max
+		mf.Min = &min
+		mf.Max = &max
+	}
+	if fb.digest != nil {
+		if fb.count > 0 {
+			// an empty digest has infinite bounds, which json cannot encode
+			fb.digest.Compress()
+			mf.Digest = fb.digest
+		}
+		mf.Percentiles = make([]*search.Percentile, 0, len(fb.percents))
+		for _, percent := range fb.percents {
+			mf.Percentiles = append(mf.Percentiles, &search.Percentile{Percent: percent})
+		}
+	}
+	mf.Finalize()
+	rv.Metric = mf
+
+	return &rv
+}
@@ -0,0 +1,194 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facet
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/blevesearch/bleve/v2/numeric"
+	"github.com/blevesearch/bleve/v2/search"
+)
+
+func metricFacetResult(t *testing.T, metric string, values []float64) *search.FacetResult {
+	mfb, err := NewMetricFacetBuilder("price", metric, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range values {
+		mfb.StartDoc()
+		mfb.UpdateVisitor(numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(v), 0))
+		// lower precision terms must be ignored
+		mfb.UpdateVisitor(numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(v), 4))
+		mfb.EndDoc()
+	}
+	// a document without a value
+	mfb.StartDoc()
+	mfb.EndDoc()
+	return mfb.Result()
+}
+
+func TestMetricFacetBuilder(t *testing.T) {
+	values := []float64{4, -2, 10, 4, 9}
+
+	tests := []struct {
+		metric string
+		value  float64
+	}{
+		{metric: search.MetricMin, value: -2},
+		{metric: search.MetricMax, value: 10},
+		{metric: search.MetricSum, value: 25},
+		{metric: search.MetricAvg, value: 5},
+		{metric: search.MetricValueCount, value: 5},
+		{metric: search.MetricCardinality, value: 4},
+	}
+
+	for _, test := range tests {
+		fr := metricFacetResult(t, test.metric, values)
+		if fr.Total != 5 || fr.Missing != 1 {
+			t.Errorf("%s: expected total 5 missing 1, got %d %d", test.metric, fr.Total, fr.Missing)
+		}
+		if fr.Metric == nil || fr.Metric.Value == nil {
+			t.Fatalf("%s: expected a metric value", test.metric)
+		}
+		if *fr.Metric.Value != test.value {
+			t.Errorf("%s: expected %f, got %f", test.metric, test.value, *fr.Metric.Value)
+		}
+	}
+
+	if _, err := NewMetricFacetBuilder("price", "median", false); err == nil {
+		t.Errorf("expected error for unknown metric")
+	}
+}
+
+func TestMetricFacetPercentiles(t *testing.T) {
+	values := make([]float64, 0, 101)
+	for i := 0; i <= 100; i++ {
+		values = append(values, float64(i))
+	}
+	fr := metricFacetResult(t, search.MetricPercentiles, values)
+	if len(fr.Metric.Percentiles) != len(search.DefaultPercents) {
+		t.Fatalf("expected %d percentiles, got %d",
+			len(search.DefaultPercents), len(fr.Metric.Percentiles))
+	}
+	for _, p := range fr.Metric.Percentiles {
+		if math.Abs(p.Value-p.Percent) > 1 {
+			t.Errorf("expected percentile %f to be approximately %f, got %f",
+				p.Percent, p.Percent, p.Value)
+		}
+	}
+}
+
+func TestMetricFacetMerge(t *testing.T) {
+	for _, metric := range []string{search.MetricAvg, search.MetricCardinality,
+		search.MetricPercentiles} {
+		fr := metricFacetResult(t, metric, []float64{1, 2, 3})
+		other := metricFacetResult(t, metric, []float64{3, 4, 5, 6, 7})
+		fr.Merge(other)
+
+		if fr.Metric.Error != "" {
+			t.Fatalf("unexpected merge error for %s: %s", metric, fr.Metric.Error)
+		}
+		switch metric {
+		case search.MetricAvg:
+			if *fr.Metric.Value != 31.0/8 {
+				t.Errorf("expected avg %f, got %f", 31.0/8, *fr.Metric.Value)
+			}
+		case search.MetricCardinality:
+			if *fr.Metric.Value != 7 {
+				t.Errorf("expected cardinality 7, got %f", *fr.Metric.Value)
+			}
+		case search.MetricPercentiles:
+			if fr.Metric.Digest.Count != 8 {
+				t.Errorf("expected digest count 8, got %f", fr.Metric.Digest.Count)
+			}
+			for _, p := range fr.Metric.Percentiles {
+				if p.Percent == 50 && (p.Value < 3 || p.Value > 4) {
+					t.Errorf("expected median between 3 and 4, got %f", p.Value)
+				}
+			}
+		}
+	}
+}
+
+func TestMetricFacetMergeJSON(t *testing.T) {
+	roundTrip := func(fr *search.FacetResult) *search.FacetResult {
+		buf, err := json.Marshal(fr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(buf) > 512 {
+			t.Errorf("expected the sketches to be left out of the json, got %d bytes", len(buf))
+		}
+		var rv search.FacetResult
+		if err := json.Unmarshal(buf, &rv); err != nil {
+			t.Fatal(err)
+		}
+		return &rv
+	}
+
+	// the plain metrics merge after a round trip through json
+	fr := metricFacetResult(t, search.MetricAvg, []float64{1, 2, 3})
+	fr.Merge(roundTrip(metricFacetResult(t, search.MetricAvg, []float64{3, 4, 5, 6, 7})))
+	if fr.Metric.Error != "" || *fr.Metric.Value != 31.0/8 {
+		t.Errorf("expected avg %f, got %v (%s)", 31.0/8, fr.Metric.Value, fr.Metric.Error)
+	}
+
+	// those needing a sketch report they cannot be merged
+	for _, metric := range []string{search.MetricCardinality, search.MetricPercentiles} {
+		fr := metricFacetResult(t, metric, []float64{1, 2, 3})
+		fr.Merge(roundTrip(metricFacetResult(t, metric, []float64{3, 4, 5, 6, 7})))
+		if fr.Metric.Error == "" {
+			t.Errorf("expected merge error for %s", metric)
+		}
+		if fr.Metric.Value != nil {
+			t.Errorf("expected no value for %s, got %f", metric, *fr.Metric.Value)
+		}
+
+		// unless the result is empty
+		fr = metricFacetResult(t, metric, []float64{1, 2, 3})
+		fr.Merge(roundTrip(metricFacetResult(t, metric, nil)))
+		if fr.Metric.Error != "" {
+			t.Errorf("unexpected merge error for empty %s: %s", metric, fr.Metric.Error)
+		}
+	}
+}
+
+func TestMetricFacetDateTime(t *testing.T) {
+	mfb, err := NewMetricFacetBuilder("created", search.MetricMax, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, d := range []time.Time{last, first} {
+		mfb.StartDoc()
+		mfb.UpdateVisitor(numeric.MustNewPrefixCodedInt64(d.UnixNano(), 0))
+		mfb.EndDoc()
+	}
+	fr := mfb.Result()
+	if fr.Metric.ValueAsString != last.Format(time.RFC3339Nano) {
+		t.Errorf("expected %s, got %s", last.Format(time.RFC3339Nano), fr.Metric.ValueAsString)
+	}
+}
+
+func TestMetricFacetEmptyPercentiles(t *testing.T) {
+	fr := metricFacetResult(t, search.MetricPercentiles, nil)
+	if _, err := json.Marshal(fr); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -48,6 +48,7 @@ type NumericFacetBuilder struct {
 	missing    int
 	ranges     map[string]*numericRange
 	sawValue   bool
+	subFacets  *search.SubFacetsBuilder
 }
 
 func NewNumericFacetBuilder(field string, size int) *NumericFacetBuilder {
@@ -73,6 +74,10 @@ func (fb *NumericFacetBuilder) Size() int {
 			size.SizeOfPtr + reflectStaticSizenumericRange
 	}
 
+	if fb.subFacets != nil {
+		sizeInBytes += fb.subFacets.Size()
+	}
+
 	return sizeInBytes
 }
 
@@ -88,6 +93,15 @@ func (fb *NumericFacetBuilder) Field() string {
 	return fb.field
 }
 
+// SetSubFacets sets the child facets computed within each bucket.
+func (fb *NumericFacetBuilder) SetSubFacets(subFacets *search.SubFacetsBuilder) {
+	fb.subFacets = subFacets
+}
+
+func (fb *NumericFacetBuilder) SubFacets() *search.SubFacetsBuilder {
+	return fb.subFacets
+}
+
 func (fb *NumericFacetBuilder) UpdateVisitor(term []byte) {
 	fb.sawValue = true
 	// only consider the values which are shifted 0
@@ -103,6 +117,9 @@ func (fb *NumericFacetBuilder) UpdateVisitor(term []byte) {
 				if (r.min == nil || f64 >= *r.min) && (r.max == nil || f64 < *r.max) {
 					fb.termsCount[rangeName] = fb.termsCount[rangeName] + 1
 					fb.total++
+					if fb.subFacets != nil {
+						fb.subFacets.AddBucket(rangeName)
+					}
 				}
 			}
 		}
@@ -111,12 +128,18 @@ func (fb *NumericFacetBuilder) UpdateVisitor(term []byte) {
 
 func (fb *NumericFacetBuilder) StartDoc() {
 	fb.sawValue = false
+	if fb.subFacets != nil {
+		fb.subFacets.StartDoc()
+	}
 }
 
 func (fb *NumericFacetBuilder) EndDoc() {
 	if !fb.sawValue {
 		fb.missing++
 	}
+	if fb.subFacets != nil {
+		fb.subFacets.EndDoc()
+	}
 }
 
 func (fb *NumericFacetBuilder) Result() *search.FacetResult {
@@ -153,5 +176,11 @@ func (fb *NumericFacetBuilder) Result() *search.FacetResult {
 	}
 	rv.Other = fb.total - notOther
 
+	if fb.subFacets != nil {
+		for _, r := range rv.NumericRanges {
+			r.Facets = fb.subFacets.Results(r.Name)
+		}
+	}
+
 	return &rv
 }
@@ -40,6 +40,7 @@ type TermsFacetBuilder struct {
 	total       int
 	missing     int
 	sawValue    bool
+	subFacets   *search.SubFacetsBuilder
 }
 
 func NewTermsFacetBuilder(field string, size int) *TermsFacetBuilder {
@@ -68,6 +69,10 @@ func (fb *TermsFacetBuilder) Size() int {
 			size.SizeOfInt
 	}
 
+	if fb.subFacets != nil {
+		sizeInBytes += fb.subFacets.Size()
+	}
+
 	return sizeInBytes
 }
 
@@ -75,6 +80,15 @@ func (fb *TermsFacetBuilder) Field() string {
 	return fb.field
 }
 
+// SetSubFacets sets the child facets computed within each bucket.
+func (fb *TermsFacetBuilder) SetSubFacets(subFacets *search.SubFacetsBuilder) {
+	fb.subFacets = subFacets
+}
+
+func (fb *TermsFacetBuilder) SubFacets() *search.SubFacetsBuilder {
+	return fb.subFacets
+}
+
 // SetPrefixFilter sets the prefix filter for term facets.
 func (fb *TermsFacetBuilder) SetPrefixFilter(prefix string) {
 	if prefix != "" {
@@ -108,16 +122,25 @@ func (fb *TermsFacetBuilder) UpdateVisitor(term []byte) {
 	termStr := string(term)
 	fb.sawValue = true
 	fb.termsCount[termStr] = fb.termsCount[termStr] + 1
+	if fb.subFacets != nil {
+		fb.subFacets.AddBucket(termStr)
+	}
 }
 
 func (fb *TermsFacetBuilder) StartDoc() {
 	fb.sawValue = false
+	if fb.subFacets != nil {
+		fb.subFacets.StartDoc()
+	}
 }
 
 func (fb *TermsFacetBuilder) EndDoc() {
 	if !fb.sawValue {
 		fb.missing++
 	}
+	if fb.subFacets != nil {
+		fb.subFacets.EndDoc()
+	}
 }
 
 func (fb *TermsFacetBuilder) Result() *search.FacetResult {
@@ -153,5 +176,11 @@ func (fb *TermsFacetBuilder) Result() *search.FacetResult {
 	}
 	rv.Other = fb.total - notOther
 
+	if fb.subFacets != nil {
+		for _, tf := range rv.Terms.Terms() {
+			tf.Facets = fb.subFacets.Results(tf.Term)
+		}
+	}
+
 	return &rv
 }
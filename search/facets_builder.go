@@ -28,6 +28,8 @@ var reflectStaticSizeFacetResult int
 var reflectStaticSizeTermFacet int
 var reflectStaticSizeNumericRangeFacet int
 var reflectStaticSizeDateRangeFacet int
+var reflectStaticSizeSubFacetsBuilder int
+var reflectStaticSizeHistogramFacet int
 
 func init() {
 	var fb FacetsBuilder
@@ -40,6 +42,10 @@ func init() {
 	reflectStaticSizeNumericRangeFacet = int(reflect.TypeOf(nrf).Size())
 	var drf DateRangeFacet
 	reflectStaticSizeDateRangeFacet = int(reflect.TypeOf(drf).Size())
+	var sfb SubFacetsBuilder
+	reflectStaticSizeSubFacetsBuilder = int(reflect.TypeOf(sfb).Size())
+	var hf HistogramFacet
+	reflectStaticSizeHistogramFacet = int(reflect.TypeOf(hf).Size())
 }
 
 type FacetBuilder interface {
@@ -53,12 +59,22 @@ type FacetBuilder interface {
 	Size() int
 }
 
+// NestedFacetBuilder is implemented by FacetBuilders which group documents
+// into buckets and can compute child facets within each of those buckets.
+type NestedFacetBuilder interface {
+	FacetBuilder
+
+	SetSubFacets(subFacets *SubFacetsBuilder)
+	SubFacets() *SubFacetsBuilder
+}
+
 type FacetsBuilder struct {
-	indexReader   index.IndexReader
-	facetNames    []string
-	facets        []FacetBuilder
-	facetsByField map[string][]FacetBuilder
-	fields        []string
+	indexReader      index.IndexReader
+	facetNames       []string
+	facets           []FacetBuilder
+	facetsByField    map[string][]FacetBuilder
+	subFacetsByField map[string][]*SubFacetsBuilder
+	fields           []string
 }
 
 func NewFacetsBuilder(indexReader index.IndexReader) *FacetsBuilder {
@@ -90,6 +106,18 @@ func (fb *FacetsBuilder) Add(name string, facetBuilder FacetBuilder) {
 	fb.facets = append(fb.facets, facetBuilder)
 	fb.facetsByField[facetBuilder.Field()] = append(fb.facetsByField[facetBuilder.Field()], facetBuilder)
 	fb.fields = append(fb.fields, facetBuilder.Field())
+
+	// child facets need to see the doc values of their own fields as well
+	if nfb, ok := facetBuilder.(NestedFacetBuilder); ok && nfb.SubFacets() != nil {
+		if fb.subFacetsByField == nil {
+			fb.subFacetsByField = map[string][]*SubFacetsBuilder{}
+		}
+		subFacets := nfb.SubFacets()
+		for _, field := range subFacets.Fields() {
+			fb.subFacetsByField[field] = append(fb.subFacetsByField[field], subFacets)
+			fb.fields = append(fb.fields, field)
+		}
+	}
 }
 
 func (fb *FacetsBuilder) RequiredFields() []string {
@@ -114,11 +142,114 @@ func (fb *FacetsBuilder) UpdateVisitor(field string, term []byte) {
 			facetBuilder.UpdateVisitor(term)
 		}
 	}
+	if subFacets, ok := fb.subFacetsByField[field]; ok {
+		for _, subFacet := range subFacets {
+			subFacet.UpdateVisitor(field, term)
+		}
+	}
+}
+
+// SubFacetsBuilder computes the child facets of a NestedFacetBuilder.
+// The doc values of the child fields are buffered while a document is
+// being visited, and once the parent has decided which buckets the
+// document belongs to, they are replayed into a separate FacetsBuilder
+// for each of those buckets.
+type SubFacetsBuilder struct {
+	fields     []string
+	newBuilder func() *FacetsBuilder
+	buckets    map[string]*FacetsBuilder
+
+	docFields   []string
+	docTermEnds []int
+	docTerms    []byte
+	docBuckets  []string
+}
+
+// NewSubFacetsBuilder returns a SubFacetsBuilder for child facets over the
+// specified fields, newBuilder is invoked once for every distinct bucket.
+func NewSubFacetsBuilder(fields []string, newBuilder func() *FacetsBuilder) *SubFacetsBuilder {
+	return &SubFacetsBuilder{
+		fields:     fields,
+		newBuilder: newBuilder,
+		buckets:    make(map[string]*FacetsBuilder),
+	}
+}
+
+func (sb *SubFacetsBuilder) Size() int {
+	sizeInBytes := reflectStaticSizeSubFacetsBuilder + size.SizeOfPtr +
+		cap(sb.docTerms) + cap(sb.docTermEnds)*size.SizeOfInt
+
+	for _, entry := range sb.fields {
+		sizeInBytes += size.SizeOfString + len(entry)
+	}
+
+	for k, v := range sb.buckets {
+		sizeInBytes += size.SizeOfString + len(k) + v.Size()
+	}
+
+	return sizeInBytes
+}
+
+// Fields returns the fields required by the child facets.
+func (sb *SubFacetsBuilder) Fields() []string {
+	return sb.fields
+}
+
+func (sb *SubFacetsBuilder) StartDoc() {
+	sb.docFields = sb.docFields[:0]
+	sb.docTermEnds = sb.docTermEnds[:0]
+	sb.docTerms = sb.docTerms[:0]
+	sb.docBuckets = sb.docBuckets[:0]
+}
+
+func (sb *SubFacetsBuilder) UpdateVisitor(field string, term []byte) {
+	// the term bytes are only valid for the duration of the callback
+	sb.docFields = append(sb.docFields, field)
+	sb.docTerms = append(sb.docTerms, term...)
+	sb.docTermEnds = append(sb.docTermEnds, len(sb.docTerms))
+}
+
+// AddBucket records that the current document was counted in the
+// named bucket of the parent facet.
+func (sb *SubFacetsBuilder) AddBucket(name string) {
+	for _, bucket := range sb.docBuckets {
+		if bucket == name {
+			return
+		}
+	}
+	sb.docBuckets = append(sb.docBuckets, name)
+}
+
+func (sb *SubFacetsBuilder) EndDoc() {
+	for _, bucket := range sb.docBuckets {
+		builder, ok := sb.buckets[bucket]
+		if !ok {
+			builder = sb.newBuilder()
+			sb.buckets[bucket] = builder
+		}
+		builder.StartDoc()
+		start := 0
+		for i, field := range sb.docFields {
+			end := sb.docTermEnds[i]
+			builder.UpdateVisitor(field, sb.docTerms[start:end])
+			start = end
+		}
+		builder.EndDoc()
+	}
+}
+
+// Results returns the child facet results for the named bucket.
+func (sb *SubFacetsBuilder) Results(bucket string) FacetResults {
+	if builder, ok := sb.buckets[bucket]; ok {
+		return builder.Results()
+	}
+	return nil
 }
 
 type TermFacet struct {
-	Term  string `json:"term"`
-	Count int    `json:"count"`
+	Term   string       `json:"term"`
+	Count  int          `json:"count"`
+	Facets FacetResults `json:"facets,omitempty"`
 }
 
 type TermFacets struct {
@@ -145,6 +276,7 @@ func (tf *TermFacets) Add(termFacets ...*TermFacet) {
 
 		if term, ok := tf.termLookup[termFacet.Term]; ok {
 			term.Count += termFacet.Count
+			term.Facets = term.Facets.mergeInto(termFacet.Facets)
 			return
 		}
 
@@ -194,10 +326,11 @@ func (tf *TermFacets) UnmarshalJSON(b []byte) error {
 }
 
 type NumericRangeFacet struct {
-	Name  string   `json:"name"`
-	Min   *float64 `json:"min,omitempty"`
-	Max   *float64 `json:"max,omitempty"`
-	Count int      `json:"count"`
+	Name   string       `json:"name"`
+	Min    *float64     `json:"min,omitempty"`
+	Max    *float64     `json:"max,omitempty"`
+	Count  int          `json:"count"`
+	Facets FacetResults `json:"facets,omitempty"`
 }
 
 func (nrf *NumericRangeFacet) Same(other *NumericRangeFacet) bool {
@@ -229,6 +362,7 @@ func (nrf NumericRangeFacets) Add(numericRangeFacet *NumericRangeFacet) NumericR
 	for _, existingNr := range nrf {
 		if numericRangeFacet.Same(existingNr) {
 			existingNr.Count += numericRangeFacet.Count
+			existingNr.Facets = existingNr.Facets.mergeInto(numericRangeFacet.Facets)
 			return nrf
 		}
 	}
@@ -247,10 +381,11 @@ func (nrf NumericRangeFacets) Less(i, j int) bool {
 }
 
 type DateRangeFacet struct {
-	Name  string  `json:"name"`
-	Start *string `json:"start,omitempty"`
-	End   *string `json:"end,omitempty"`
-	Count int     `json:"count"`
+	Name   string       `json:"name"`
+	Start  *string      `json:"start,omitempty"`
+	End    *string      `json:"end,omitempty"`
+	Count  int          `json:"count"`
+	Facets FacetResults `json:"facets,omitempty"`
 }
 
 func (drf *DateRangeFacet) Same(other *DateRangeFacet) bool {
@@ -282,6 +417,7 @@ func (drf DateRangeFacets) Add(dateRangeFacet *DateRangeFacet) DateRangeFacets {
 	for _, existingDr := range drf {
 		if dateRangeFacet.Same(existingDr) {
 			existingDr.Count += dateRangeFacet.Count
+			existingDr.Facets = existingDr.Facets.mergeInto(dateRangeFacet.Facets)
 			return drf
 		}
 	}
@@ -299,6 +435,36 @@ func (drf DateRangeFacets) Less(i, j int) bool {
 	return drf[i].Count > drf[j].Count
 }
 
+// HistogramFacet is a bucket of a histogram or date histogram facet.
+// For date histograms Key is the start of the bucket in milliseconds
+// since the epoch, and KeyAsString is that start in the requested time zone.
+type HistogramFacet struct {
+	Key         float64      `json:"key"`
+	KeyAsString string       `json:"key_as_string,omitempty"`
+	Count       int          `json:"count"`
+	Facets      FacetResults `json:"facets,omitempty"`
+}
+
+type HistogramFacets []*HistogramFacet
+
+func (hf HistogramFacets) Add(histogramFacet *HistogramFacet) HistogramFacets {
+	for _, existingH := range hf {
+		if histogramFacet.Key == existingH.Key {
+			existingH.Count += histogramFacet.Count
+			existingH.Facets = existingH.Facets.mergeInto(histogramFacet.Facets)
+			return hf
+		}
+	}
+	// if we got here it wasn't already in the existing buckets
+	hf = append(hf, histogramFacet)
+	return hf
+}
+
+// histogram buckets are ordered by key, not by count
+func (hf HistogramFacets) Len() int           { return len(hf) }
+func (hf HistogramFacets) Swap(i, j int)      { hf[i], hf[j] = hf[j], hf[i] }
+func (hf HistogramFacets) Less(i, j int) bool { return hf[i].Key < hf[j].Key }
+
 type FacetResult struct {
 	Field         string             `json:"field"`
 	Total         int                `json:"total"`
@@ -307,20 +473,71 @@ type FacetResult struct {
 	Terms         *TermFacets        `json:"terms,omitempty"`
 	NumericRanges NumericRangeFacets `json:"numeric_ranges,omitempty"`
 	DateRanges    DateRangeFacets    `json:"date_ranges,omitempty"`
+	Histogram     HistogramFacets    `json:"histogram,omitempty"`
+	Metric        *MetricFacet       `json:"metric,omitempty"`
 }
 
 func (fr *FacetResult) Size() int {
-	return reflectStaticSizeFacetResult + size.SizeOfPtr +
+	sizeInBytes := reflectStaticSizeFacetResult + size.SizeOfPtr +
 		len(fr.Field) +
 		fr.Terms.Len()*(reflectStaticSizeTermFacet+size.SizeOfPtr) +
 		len(fr.NumericRanges)*(reflectStaticSizeNumericRangeFacet+size.SizeOfPtr) +
-		len(fr.DateRanges)*(reflectStaticSizeDateRangeFacet+size.SizeOfPtr)
+		len(fr.DateRanges)*(reflectStaticSizeDateRangeFacet+size.SizeOfPtr) +
+		len(fr.Histogram)*(reflectStaticSizeHistogramFacet+size.SizeOfPtr)
+
+	for _, h := range fr.Histogram {
+		sizeInBytes += len(h.KeyAsString)
+	}
+
+	for _, subFacets := range fr.SubFacets() {
+		sizeInBytes += subFacets.Size()
+	}
+
+	if fr.Metric != nil {
+		sizeInBytes += fr.Metric.Size()
+	}
+
+	return sizeInBytes
+}
+
+// SubFacets returns the child facet results of all the buckets
+// in this facet result which have them.
+func (fr *FacetResult) SubFacets() []FacetResults {
+	var rv []FacetResults
+	for _, tf := range fr.Terms.Terms() {
+		if tf.Facets != nil {
+			rv = append(rv, tf.Facets)
+		}
+	}
+	for _, nr := range fr.NumericRanges {
+		if nr.Facets != nil {
+			rv = append(rv, nr.Facets)
+		}
+	}
+	for _, dr := range fr.DateRanges {
+		if dr.Facets != nil {
+			rv = append(rv, dr.Facets)
+		}
+	}
+	for _, h := range fr.Histogram {
+		if h.Facets != nil {
+			rv = append(rv, h.Facets)
+		}
+	}
+	return rv
 }
 
 func (fr *FacetResult) Merge(other *FacetResult) {
 	fr.Total += other.Total
 	fr.Missing += other.Missing
 	fr.Other += other.Other
+	if other.Metric != nil {
+		if fr.Metric == nil {
+			fr.Metric = other.Metric
+		} else {
+			fr.Metric.Merge(other.Metric)
+		}
+	}
 	if other.Terms != nil {
 		if fr.Terms == nil {
 			fr.Terms = other.Terms
@@ -348,6 +565,15 @@ func (fr *FacetResult) Merge(other *FacetResult) {
 			fr.DateRanges = fr.DateRanges.Add(dr)
 		}
 	}
+	if other.Histogram != nil {
+		if fr.Histogram == nil {
+			fr.Histogram = other.Histogram
+			return
+		}
+		for _, h := range other.Histogram {
+			fr.Histogram = fr.Histogram.Add(h)
+		}
+	}
 }
 
 func (fr *FacetResult) Fixup(size int) {
@@ -378,6 +604,15 @@ func (fr *FacetResult) Fixup(size int) {
 			}
 			fr.DateRanges = fr.DateRanges[0:size]
 		}
+	} else if fr.Histogram != nil {
+		sort.Sort(fr.Histogram)
+		if len(fr.Histogram) > size {
+			moveToOther := fr.Histogram[size:]
+			for _, mto := range moveToOther {
+				fr.Other += mto.Count
+			}
+			fr.Histogram = fr.Histogram[0:size]
+		}
 	}
 }
 
@@ -394,6 +629,27 @@ func (fr FacetResults) Merge(other FacetResults) {
 	}
 }
 
+// mergeInto merges other into fr, allocating fr if necessary,
+// and returns the result.
+func (fr FacetResults) mergeInto(other FacetResults) FacetResults {
+	if len(other) == 0 {
+		return fr
+	}
+	if fr == nil {
+		return other
+	}
+	fr.Merge(other)
+	return fr
+}
+
+func (fr FacetResults) Size() int {
+	sizeInBytes := 0
+	for k, v := range fr {
+		sizeInBytes += size.SizeOfString + len(k) + v.Size()
+	}
+	return sizeInBytes
+}
+
 func (fr FacetResults) Fixup(name string, size int) {
 	facetResult, ok := fr[name]
 	if ok {
@@ -422,3 +422,100 @@ func TestDateFacetResultsMerge(t *testing.T) {
 		t.Errorf("expected %#v, got %#v", expectedFrs, frs1)
 	}
 }
+
+func TestNestedFacetResultsMerge(t *testing.T) {
+	newResults := func(brand string, count int, colors map[string]int) FacetResults {
+		colorFacets := &TermFacets{}
+		for color, colorCount := range colors {
+			colorFacets.Add(&TermFacet{Term: color, Count: colorCount})
+		}
+		brandFacets := &TermFacets{}
+		brandFacets.Add(&TermFacet{
+			Term:  brand,
+			Count: count,
+			Facets: FacetResults{
+				"colors": &FacetResult{
+					Field: "color",
+					Total: count,
+					Terms: colorFacets,
+				},
+			},
+		})
+		return FacetResults{
+			"brands": &FacetResult{
+				Field: "brand",
+				Total: count,
+				Terms: brandFacets,
+			},
+		}
+	}
+
+	frs := newResults("acme", 3, map[string]int{"red": 2, "blue": 1})
+	frs.Merge(newResults("acme", 2, map[string]int{"red": 1, "green": 1}))
+	frs.Merge(newResults("globex", 1, map[string]int{"red": 1}))
+
+	expected := map[string]map[string]int{
+		"acme":   {"red": 3, "blue": 1, "green": 1},
+		"globex": {"red": 1},
+	}
+
+	brands := frs["brands"]
+	if brands.Terms.Len() != 2 {
+		t.Fatalf("expected 2 brands, got %d", brands.Terms.Len())
+	}
+	if len(brands.SubFacets()) != 2 {
+		t.Fatalf("expected 2 sets of child facets, got %d", len(brands.SubFacets()))
+	}
+	for _, brand := range brands.Terms.Terms() {
+		colors := brand.Facets["colors"]
+		if colors.Terms.Len() != len(expected[brand.Term]) {
+			t.Fatalf("expected %d colors for %s, got %d",
+				len(expected[brand.Term]), brand.Term, colors.Terms.Len())
+		}
+		for _, color := range colors.Terms.Terms() {
+			if color.Count != expected[brand.Term][color.Term] {
+				t.Errorf("expected %d for %s/%s, got %d",
+					expected[brand.Term][color.Term], brand.Term, color.Term, color.Count)
+			}
+		}
+	}
+}
+
+func TestHistogramFacetResultsMerge(t *testing.T) {
+	frs := FacetResults{
+		"prices": &FacetResult{
+			Field: "price",
+			Total: 5,
+			Histogram: HistogramFacets{
+				{Key: 0, Count: 2},
+				{Key: 10, Count: 3},
+			},
+		},
+	}
+	frs.Merge(FacetResults{
+		"prices": &FacetResult{
+			Field: "price",
+			Total: 6,
+			Histogram: HistogramFacets{
+				{Key: 30, Count: 1},
+				{Key: 10, Count: 4},
+				{Key: -10, Count: 1},
+			},
+		},
+	})
+	frs.Fixup("prices", 3)
+
+	expected := &FacetResult{
+		Field: "price",
+		Total: 11,
+		Other: 1,
+		Histogram: HistogramFacets{
+			{Key: -10, Count: 1},
+			{Key: 0, Count: 2},
+			{Key: 10, Count: 7},
+		},
+	}
+	if !reflect.DeepEqual(frs["prices"], expected) {
+		t.Errorf("expected %v, got %v", expected, frs["prices"])
+	}
+}
@@ -0,0 +1,229 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/blevesearch/bleve/v2/search/sketch"
+	"github.com/blevesearch/bleve/v2/size"
+)
+
+var reflectStaticSizeMetricFacet int
+var reflectStaticSizePercentile int
+
+func init() {
+	var mf MetricFacet
+	reflectStaticSizeMetricFacet = int(reflect.TypeOf(mf).Size())
+	var p Percentile
+	reflectStaticSizePercentile = int(reflect.TypeOf(p).Size())
+}
+
+// The metrics which can be computed over the numeric
+// or date time values of a field.
+const (
+	MetricMin         = "min"
+	MetricMax         = "max"
+	MetricSum         = "sum"
+	MetricAvg         = "avg"
+	MetricValueCount  = "value_count"
+	MetricCardinality = "cardinality"
+	MetricPercentiles = "percentiles"
+)
+
+// DefaultPercents are the percentiles computed when none are requested.
+var DefaultPercents = []float64{1, 5, 25, 50, 75, 95, 99}
+
+// IsValidMetric returns true if metric is one of the supported metrics.
+func IsValidMetric(metric string) bool {
+	switch metric {
+	case MetricMin, MetricMax, MetricSum, MetricAvg,
+		MetricValueCount, MetricCardinality, MetricPercentiles:
+		return true
+	}
+	return false
+}
+
+type Percentile struct {
+	Percent       float64 `json:"percent"`
+	Value         float64 `json:"value"`
+	ValueAsString string  `json:"value_as_string,omitempty"`
+}
+
+// MetricFacet is the result of a metric facet. Value (or Percentiles)
+// holds the requested metric, the remaining fields carry the state
+// required to merge results from several indexes. The sketches behind
+// the cardinality and percentiles metrics are large, so they are not
+// part of the json, and such results can only be merged in process,
+// as by an index alias. Error records a merge which was not possible.
+type MetricFacet struct {
+	Metric        string        `json:"metric"`
+	DateTime      bool          `json:"datetime,omitempty"`
+	Value         *float64      `json:"value,omitempty"`
+	ValueAsString string        `json:"value_as_string,omitempty"`
+	Percentiles   []*Percentile `json:"percentiles,omitempty"`
+	Error         string        `json:"error,omitempty"`
+
+	Count       int64               `json:"count"`
+	Sum         float64             `json:"sum"`
+	Min         *float64            `json:"min,omitempty"`
+	Max         *float64            `json:"max,omitempty"`
+	Cardinality *sketch.HyperLogLog `json:"-"`
+	Digest      *sketch.TDigest     `json:"-"`
+}
+
+func (mf *MetricFacet) Size() int {
+	sizeInBytes := reflectStaticSizeMetricFacet + size.SizeOfPtr +
+		len(mf.Metric) + len(mf.ValueAsString) + len(mf.Error) +
+		len(mf.Percentiles)*(reflectStaticSizePercentile+size.SizeOfPtr)
+
+	for _, p := range mf.Percentiles {
+		sizeInBytes += len(p.ValueAsString)
+	}
+	if mf.Cardinality != nil {
+		sizeInBytes += mf.Cardinality.Size()
+	}
+	if mf.Digest != nil {
+		sizeInBytes += mf.Digest.Size()
+	}
+
+	return sizeInBytes
+}
+
+// Merge folds the state of other into this metric and
+// recomputes the resulting value.
+func (mf *MetricFacet) Merge(other *MetricFacet) {
+	if mf.Error == "" {
+		mf.Error = other.Error
+	}
+	if mf.Error == "" {
+		if err := mf.mergeSketches(other); err != nil {
+			mf.Error = err.Error()
+		}
+	}
+	mf.Count += other.Count
+	mf.Sum += other.Sum
+	if other.Min != nil && (mf.Min == nil || *other.Min < *mf.Min) {
+		mf.Min = other.Min
+	}
+	if other.Max != nil && (mf.Max == nil || *other.Max > *mf.Max) {
+		mf.Max = other.Max
+	}
+	if len(mf.Percentiles) == 0 {
+		mf.Percentiles = other.Percentiles
+	}
+	mf.Finalize()
+}
+
+// mergeSketches merges the sketch of the metric, which both
+// results must have unless they are empty.
+func (mf *MetricFacet) mergeSketches(other *MetricFacet) error {
+	switch mf.Metric {
+	case MetricCardinality:
+		if other.Count == 0 {
+			return nil
+		}
+		if other.Cardinality == nil || (mf.Cardinality == nil && mf.Count > 0) {
+			return fmt.Errorf("cannot merge cardinality without its sketch")
+		}
+		if mf.Cardinality == nil {
+			mf.Cardinality = other.Cardinality
+			return nil
+		}
+		return mf.Cardinality.Merge(other.Cardinality)
+	case MetricPercentiles:
+		if other.Count == 0 {
+			return nil
+		}
+		if other.Digest == nil || (mf.Digest == nil && mf.Count > 0) {
+			return fmt.Errorf("cannot merge percentiles without their sketch")
+		}
+		if mf.Digest == nil {
+			mf.Digest = other.Digest
+			return nil
+		}
+		mf.Digest.Merge(other.Digest)
+	}
+	return nil
+}
+
+// Finalize computes Value (or Percentiles) from the merge state.
+func (mf *MetricFacet) Finalize() {
+	mf.Value = nil
+	mf.ValueAsString = ""
+	if mf.Error != "" {
+		for _, p := range mf.Percentiles {
+			p.Value, p.ValueAsString = 0, ""
+		}
+		return
+	}
+
+	var value float64
+	switch mf.Metric {
+	case MetricMin:
+		if mf.Min == nil {
+			return
+		}
+		value = *mf.Min
+	case MetricMax:
+		if mf.Max == nil {
+			return
+		}
+		value = *mf.Max
+	case MetricSum:
+		value = mf.Sum
+	case MetricAvg:
+		if mf.Count == 0 {
+			return
+		}
+		value = mf.Sum / float64(mf.Count)
+	case MetricValueCount:
+		value = float64(mf.Count)
+	case MetricCardinality:
+		if mf.Cardinality != nil {
+			value = float64(mf.Cardinality.Count())
+		}
+	case MetricPercentiles:
+		for _, p := range mf.Percentiles {
+			p.Value, p.ValueAsString = 0, ""
+			if mf.Digest == nil || mf.Digest.Count == 0 {
+				continue
+			}
+			p.Value = mf.Digest.Quantile(p.Percent / 100)
+			if mf.DateTime {
+				p.ValueAsString = formatMetricDateTime(p.Value)
+			}
+		}
+		return
+	}
+
+	mf.Value = &value
+	if mf.DateTime {
+		switch mf.Metric {
+		case MetricMin, MetricMax, MetricAvg:
+			mf.ValueAsString = formatMetricDateTime(value)
+		}
+	}
+}
+
+func formatMetricDateTime(nanos float64) string {
+	if math.IsNaN(nanos) || math.IsInf(nanos, 0) {
+		return ""
+	}
+	return time.Unix(0, int64(nanos)).UTC().Format(time.RFC3339Nano)
+}
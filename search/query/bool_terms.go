@@ -0,0 +1,88 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/searcher"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// BoolTermsQuery matches documents having any of the boolean values,
+// looking up the term each value is indexed as.  Matches all score
+// the boost, as for a NumericTermsQuery.
+type BoolTermsQuery struct {
+	Bools    []bool `json:"bool_terms"`
+	FieldVal string `json:"field,omitempty"`
+	BoostVal *Boost `json:"boost,omitempty"`
+}
+
+// NewBoolTermsQuery creates a new Query for finding
+// documents having any of the boolean values.
+func NewBoolTermsQuery(bools []bool) *BoolTermsQuery {
+	return &BoolTermsQuery{
+		Bools: bools,
+	}
+}
+
+func (q *BoolTermsQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *BoolTermsQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *BoolTermsQuery) SetField(f string) {
+	q.FieldVal = f
+}
+
+func (q *BoolTermsQuery) Field() string {
+	return q.FieldVal
+}
+
+func (q *BoolTermsQuery) Validate() error {
+	if len(q.Bools) == 0 {
+		return fmt.Errorf("bool terms query must have at least one value")
+	}
+	return nil
+}
+
+func (q *BoolTermsQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	field := q.FieldVal
+	if q.FieldVal == "" {
+		field = m.DefaultSearchField()
+	}
+	var hasFalse, hasTrue bool
+	for _, b := range q.Bools {
+		hasFalse, hasTrue = hasFalse || !b, hasTrue || b
+	}
+	var terms []string
+	if hasFalse {
+		terms = append(terms, "F")
+	}
+	if hasTrue {
+		terms = append(terms, "T")
+	}
+	return searcher.NewConstantScoreMultiTermSearcher(ctx, i, terms, field, q.BoostVal.Value(), options)
+}
@@ -0,0 +1,122 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/numeric"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/searcher"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// DateTermsQuery matches documents having any of the date values
+// exactly, looking up the full precision term each date is indexed
+// as, rather than searching a range per date.  Matches all score the
+// boost, however many of the dates they have.
+// Dates are parsed using DateTimeParser, which is a custom date time
+// parser defined in the index mapping.  If DateTimeParser is not
+// specified, then the top-level config.QueryDateTimeParser is used.
+type DateTermsQuery struct {
+	Dates          []string `json:"date_terms"`
+	FieldVal       string   `json:"field,omitempty"`
+	BoostVal       *Boost   `json:"boost,omitempty"`
+	DateTimeParser string   `json:"datetime_parser,omitempty"`
+}
+
+// NewDateTermsQuery creates a new Query for finding
+// documents having any of the date values exactly.
+// Date strings are parsed using the DateTimeParser field of the query struct,
+// which is a custom date time parser defined in the index mapping.
+// if DateTimeParser is not specified, then the
+// top-level config.QueryDateTimeParser is used.
+func NewDateTermsQuery(dates []string) *DateTermsQuery {
+	return &DateTermsQuery{
+		Dates: dates,
+	}
+}
+
+func (q *DateTermsQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *DateTermsQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *DateTermsQuery) SetField(f string) {
+	q.FieldVal = f
+}
+
+func (q *DateTermsQuery) Field() string {
+	return q.FieldVal
+}
+
+func (q *DateTermsQuery) SetDateTimeParser(d string) {
+	q.DateTimeParser = d
+}
+
+func (q *DateTermsQuery) DateTimeParserName() string {
+	return q.DateTimeParser
+}
+
+func (q *DateTermsQuery) Validate() error {
+	if len(q.Dates) == 0 {
+		return fmt.Errorf("date terms query must have at least one date")
+	}
+	for _, date := range q.Dates {
+		if date == "" {
+			return fmt.Errorf("date terms query dates must not be empty")
+		}
+	}
+	return nil
+}
+
+func (q *DateTermsQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	field := q.FieldVal
+	if q.FieldVal == "" {
+		field = m.DefaultSearchField()
+	}
+
+	dateTimeParserName := QueryDateTimeParser
+	if q.DateTimeParser != "" {
+		dateTimeParserName = q.DateTimeParser
+	}
+	dateTimeParser := m.DateTimeParserNamed(dateTimeParserName)
+	if dateTimeParser == nil {
+		return nil, fmt.Errorf("no dateTimeParser named '%s' registered", dateTimeParserName)
+	}
+
+	terms := make([]string, 0, len(q.Dates))
+	for _, date := range q.Dates {
+		t, _, err := dateTimeParser.ParseDateTime(date)
+		if err != nil {
+			return nil, fmt.Errorf("%v, date time parser name: %s", err, dateTimeParserName)
+		}
+		if !isDateTimeWithinRange(t) {
+			// overflow
+			return nil, fmt.Errorf("invalid/unsupported date: %v", date)
+		}
+		terms = append(terms, string(numeric.MustNewPrefixCodedInt64(t.UnixNano(), 0)))
+	}
+	return searcher.NewConstantScoreMultiTermSearcher(ctx, i, terms, field, q.BoostVal.Value(), options)
+}
@@ -0,0 +1,74 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"context"
+
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/searcher"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// ExistsQuery matches documents having any value indexed for a field,
+// whatever its type, all scoring the boost.  Documents lacking a value
+// for the field are found by excluding its matches, as with a
+// BooleanQuery having the ExistsQuery as MustNot.
+//
+// No structure persisted with the index records which documents have a
+// field.  Scorch indexes work out a bitmap of the documents having the
+// field from the postings of all its terms, the first time each segment
+// is searched, taking time in the number of terms.  The bitmaps are then
+// cached with the segments, within scorch.FieldDocsCacheMaxBytes, and
+// carried over to the segments they are merged into.  Other indexes
+// search for every term of the field, as a disjunction.
+type ExistsQuery struct {
+	FieldVal string `json:"exists"`
+	BoostVal *Boost `json:"boost,omitempty"`
+}
+
+// NewExistsQuery creates a new Query for finding
+// documents having any value for the field.
+func NewExistsQuery(field string) *ExistsQuery {
+	return &ExistsQuery{
+		FieldVal: field,
+	}
+}
+
+func (q *ExistsQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *ExistsQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *ExistsQuery) SetField(f string) {
+	q.FieldVal = f
+}
+
+func (q *ExistsQuery) Field() string {
+	return q.FieldVal
+}
+
+func (q *ExistsQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	field := q.FieldVal
+	if q.FieldVal == "" {
+		field = m.DefaultSearchField()
+	}
+	return searcher.NewExistsSearcher(ctx, i, field, q.BoostVal.Value(), options)
+}
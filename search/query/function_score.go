@@ -0,0 +1,761 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blevesearch/bleve/v2/geo"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/numeric"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/searcher"
+	"github.com/blevesearch/bleve/v2/util"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// FunctionScoreQuery scores the matches of a query by combining their
+// score with the results of score functions.  The functions applying
+// to a document are combined by ScoreMode, one of "multiply" (the
+// default), "sum", "avg", "first", "max" or "min", and capped at
+// MaxBoost when it is positive.  The result is then combined with the
+// score of the query by BoostMode, which is any of the score modes but
+// "first", or "replace" to ignore the score of the query.  Documents
+// no function applies to have a function result of 1.  Documents
+// scoring less than MinScore are not matched.
+type FunctionScoreQuery struct {
+	Query     Query
+	Functions []*ScoreFunction
+	ScoreMode string
+	BoostMode string
+	MaxBoost  float64
+	MinScore  float64
+	BoostVal  *Boost
+}
+
+// NewFunctionScoreQuery creates a new Query scoring the
+// matches of the query by the score functions.  A nil
+// query matches every document.
+func NewFunctionScoreQuery(query Query, functions ...*ScoreFunction) *FunctionScoreQuery {
+	return &FunctionScoreQuery{
+		Query:     query,
+		Functions: functions,
+	}
+}
+
+func (q *FunctionScoreQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *FunctionScoreQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *FunctionScoreQuery) SetScoreMode(mode string) {
+	q.ScoreMode = mode
+}
+
+func (q *FunctionScoreQuery) SetBoostMode(mode string) {
+	q.BoostMode = mode
+}
+
+func (q *FunctionScoreQuery) SetMaxBoost(maxBoost float64) {
+	q.MaxBoost = maxBoost
+}
+
+func (q *FunctionScoreQuery) SetMinScore(minScore float64) {
+	q.MinScore = minScore
+}
+
+func (q *FunctionScoreQuery) scoreMode() string {
+	if q.ScoreMode == "" {
+		return searcher.ScoreModeMultiply
+	}
+	return q.ScoreMode
+}
+
+func (q *FunctionScoreQuery) boostMode() string {
+	if q.BoostMode == "" {
+		return searcher.ScoreModeMultiply
+	}
+	return q.BoostMode
+}
+
+func (q *FunctionScoreQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	query := q.Query
+	if query == nil {
+		query = NewMatchAllQuery()
+	}
+	child, err := query.Searcher(ctx, i, m, options)
+	if err != nil {
+		return nil, err
+	}
+
+	functions := make([]*searcher.ScoreFunction, 0, len(q.Functions))
+	closeAll := func() {
+		_ = child.Close()
+		for _, f := range functions {
+			if f.Filter != nil {
+				_ = f.Filter.Close()
+			}
+		}
+	}
+	for _, f := range q.Functions {
+		sf, err := f.scoreFunction(m)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		if f.Filter != nil {
+			sf.Filter, err = f.Filter.Searcher(ctx, i, m, search.SearcherOptions{})
+			if err != nil {
+				closeAll()
+				return nil, err
+			}
+		}
+		functions = append(functions, sf)
+	}
+
+	rv, err := searcher.NewFunctionScoreSearcher(ctx, child, functions, i,
+		searcher.FunctionScoreOptions{
+			ScoreMode: q.scoreMode(),
+			BoostMode: q.boostMode(),
+			MaxBoost:  q.MaxBoost,
+			MinScore:  q.MinScore,
+		}, options.Explain)
+	if err != nil {
+		closeAll()
+		return nil, err
+	}
+	return rv, nil
+}
+
+func (q *FunctionScoreQuery) Validate() error {
+	switch q.scoreMode() {
+	case searcher.ScoreModeMultiply, searcher.ScoreModeSum, searcher.ScoreModeAvg,
+		searcher.ScoreModeFirst, searcher.ScoreModeMax, searcher.ScoreModeMin:
+	default:
+		return fmt.Errorf("unknown function score mode '%s'", q.ScoreMode)
+	}
+	switch q.boostMode() {
+	case searcher.ScoreModeMultiply, searcher.ScoreModeSum, searcher.ScoreModeAvg,
+		searcher.ScoreModeMax, searcher.ScoreModeMin, searcher.BoostModeReplace:
+	default:
+		return fmt.Errorf("unknown function score boost mode '%s'", q.BoostMode)
+	}
+	if q.MaxBoost < 0 || q.MinScore < 0 {
+		return fmt.Errorf("function score max boost and min score must not be negative")
+	}
+	for _, f := range q.Functions {
+		if err := f.Validate(); err != nil {
+			return err
+		}
+	}
+	if vq, ok := q.Query.(ValidatableQuery); ok {
+		return vq.Validate()
+	}
+	return nil
+}
+
+type functionScoreQueryJSON struct {
+	Query     json.RawMessage  `json:"query,omitempty"`
+	Functions []*ScoreFunction `json:"functions,omitempty"`
+	ScoreMode string           `json:"score_mode,omitempty"`
+	BoostMode string           `json:"boost_mode,omitempty"`
+	MaxBoost  float64          `json:"max_boost,omitempty"`
+	MinScore  float64          `json:"min_score,omitempty"`
+	BoostVal  *Boost           `json:"boost,omitempty"`
+}
+
+func (q *FunctionScoreQuery) MarshalJSON() ([]byte, error) {
+	inner := functionScoreQueryJSON{
+		Functions: q.Functions,
+		ScoreMode: q.ScoreMode,
+		BoostMode: q.BoostMode,
+		MaxBoost:  q.MaxBoost,
+		MinScore:  q.MinScore,
+		BoostVal:  q.BoostVal,
+	}
+	if q.Query != nil {
+		var err error
+		inner.Query, err = json.Marshal(q.Query)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(map[string]interface{}{
+		"function_score": inner,
+	})
+}
+
+func (q *FunctionScoreQuery) UnmarshalJSON(data []byte) error {
+	var tmp struct {
+		Inner *functionScoreQueryJSON `json:"function_score"`
+	}
+	err := util.UnmarshalJSON(data, &tmp)
+	if err != nil {
+		return err
+	}
+	if tmp.Inner == nil {
+		return fmt.Errorf("function score query must be a JSON object")
+	}
+	q.Query = nil
+	if len(tmp.Inner.Query) > 0 {
+		q.Query, err = ParseQuery(tmp.Inner.Query)
+		if err != nil {
+			return err
+		}
+	}
+	q.Functions = tmp.Inner.Functions
+	q.ScoreMode = tmp.Inner.ScoreMode
+	q.BoostMode = tmp.Inner.BoostMode
+	q.MaxBoost = tmp.Inner.MaxBoost
+	q.MinScore = tmp.Inner.MinScore
+	q.BoostVal = tmp.Inner.BoostVal
+	return nil
+}
+
+// ScoreFunction computes a factor for the score of the documents
+// matching its Filter, or of every document when there is none.
+// At most one of the functions may be given, whose result is
+// multiplied by Weight, which defaults to 1.
+type ScoreFunction struct {
+	Filter           Query                     `json:"filter,omitempty"`
+	Weight           *float64                  `json:"weight,omitempty"`
+	Gauss            *DecayFunction            `json:"gauss,omitempty"`
+	Linear           *DecayFunction            `json:"linear,omitempty"`
+	Exp              *DecayFunction            `json:"exp,omitempty"`
+	FieldValueFactor *FieldValueFactorFunction `json:"field_value_factor,omitempty"`
+	RandomScore      *RandomScoreFunction      `json:"random_score,omitempty"`
+}
+
+// NewWeightFunction creates a score function
+// whose result is the weight alone.
+func NewWeightFunction(weight float64) *ScoreFunction {
+	return &ScoreFunction{
+		Weight: &weight,
+	}
+}
+
+// NewGaussFunction creates a score function decaying along a
+// normal curve, by the distance of the field from the origin.
+func NewGaussFunction(field string, origin, scale interface{}) *ScoreFunction {
+	return &ScoreFunction{
+		Gauss: NewDecayFunction(field, origin, scale),
+	}
+}
+
+// NewLinearFunction creates a score function decaying linearly,
+// by the distance of the field from the origin, down to 0.
+func NewLinearFunction(field string, origin, scale interface{}) *ScoreFunction {
+	return &ScoreFunction{
+		Linear: NewDecayFunction(field, origin, scale),
+	}
+}
+
+// NewExpFunction creates a score function decaying exponentially,
+// by the distance of the field from the origin.
+func NewExpFunction(field string, origin, scale interface{}) *ScoreFunction {
+	return &ScoreFunction{
+		Exp: NewDecayFunction(field, origin, scale),
+	}
+}
+
+// NewFieldValueFactorFunction creates a score
+// function of the numeric value of the field.
+func NewFieldValueFactorFunction(field string) *ScoreFunction {
+	return &ScoreFunction{
+		FieldValueFactor: &FieldValueFactorFunction{
+			Field: field,
+		},
+	}
+}
+
+// NewRandomScoreFunction creates a score function whose result is
+// between 0 and 1, the same for a document each time the seed is used.
+func NewRandomScoreFunction(seed int64) *ScoreFunction {
+	return &ScoreFunction{
+		RandomScore: &RandomScoreFunction{
+			Seed: seed,
+		},
+	}
+}
+
+func (f *ScoreFunction) SetFilter(filter Query) {
+	f.Filter = filter
+}
+
+func (f *ScoreFunction) SetWeight(weight float64) {
+	f.Weight = &weight
+}
+
+func (f *ScoreFunction) Validate() error {
+	if f == nil {
+		return fmt.Errorf("score function missing")
+	}
+	if f.Weight != nil && *f.Weight < 0 {
+		return fmt.Errorf("score function weight must not be negative")
+	}
+	kinds := 0
+	for _, decay := range []*DecayFunction{f.Gauss, f.Linear, f.Exp} {
+		if decay != nil {
+			kinds++
+			if err := decay.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	if f.FieldValueFactor != nil {
+		kinds++
+		if err := f.FieldValueFactor.Validate(); err != nil {
+			return err
+		}
+	}
+	if f.RandomScore != nil {
+		kinds++
+	}
+	if kinds > 1 {
+		return fmt.Errorf("score function must have at most one of gauss, linear, exp, field_value_factor or random_score")
+	}
+	if vq, ok := f.Filter.(ValidatableQuery); ok {
+		return vq.Validate()
+	}
+	return nil
+}
+
+func (f *ScoreFunction) UnmarshalJSON(data []byte) error {
+	type scoreFunction ScoreFunction
+	tmp := struct {
+		scoreFunction
+		Filter json.RawMessage `json:"filter,omitempty"`
+	}{}
+	err := util.UnmarshalJSON(data, &tmp)
+	if err != nil {
+		return err
+	}
+	*f = ScoreFunction(tmp.scoreFunction)
+	if len(tmp.Filter) > 0 {
+		f.Filter, err = ParseQuery(tmp.Filter)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scoreFunction returns the function computing the factor
+// for the documents, not yet restricted by the filter.
+func (f *ScoreFunction) scoreFunction(m mapping.IndexMapping) (*searcher.ScoreFunction, error) {
+	rv := &searcher.ScoreFunction{
+		Weight: 1,
+	}
+	if f.Weight != nil {
+		rv.Weight = *f.Weight
+	}
+	var err error
+	switch {
+	case f.Gauss != nil:
+		rv.Func, err = f.Gauss.docValueFunc(decayGauss, m)
+		rv.Fields = []string{f.Gauss.Field}
+	case f.Linear != nil:
+		rv.Func, err = f.Linear.docValueFunc(decayLinear, m)
+		rv.Fields = []string{f.Linear.Field}
+	case f.Exp != nil:
+		rv.Func, err = f.Exp.docValueFunc(decayExp, m)
+		rv.Fields = []string{f.Exp.Field}
+	case f.FieldValueFactor != nil:
+		rv.Func = f.FieldValueFactor.docValueFunc()
+		rv.Fields = []string{f.FieldValueFactor.Field}
+	case f.RandomScore != nil:
+		rv.Func = f.RandomScore.docValueFunc()
+		if f.RandomScore.Field != "" {
+			rv.Fields = []string{f.RandomScore.Field}
+		} else {
+			rv.NeedsID = true
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+// DecayFunction scores documents by the distance of the value of a
+// numeric, datetime or geopoint field from an origin, less the
+// offset.  Documents at the origin score 1, and those at a distance
+// of scale score decay, which defaults to 0.5.  For a numeric field
+// the origin, scale and offset are numbers.  For a datetime field
+// the origin is a date, which defaults to now, while the scale and
+// offset are durations, such as "12h" or "7d".  For a geopoint field
+// the origin is a point, while the scale and offset are distances,
+// such as "5km".  Documents having several values are scored by the
+// closest one, and those having none score 1.
+type DecayFunction struct {
+	Field  string      `json:"field"`
+	Origin interface{} `json:"origin,omitempty"`
+	Scale  interface{} `json:"scale"`
+	Offset interface{} `json:"offset,omitempty"`
+	Decay  float64     `json:"decay,omitempty"`
+}
+
+// NewDecayFunction creates a decay function for the field,
+// for use by any of the gauss, linear or exp score functions.
+func NewDecayFunction(field string, origin, scale interface{}) *DecayFunction {
+	return &DecayFunction{
+		Field:  field,
+		Origin: origin,
+		Scale:  scale,
+	}
+}
+
+func (d *DecayFunction) SetOffset(offset interface{}) {
+	d.Offset = offset
+}
+
+func (d *DecayFunction) SetDecay(decay float64) {
+	d.Decay = decay
+}
+
+func (d *DecayFunction) decay() float64 {
+	if d.Decay == 0 {
+		return 0.5
+	}
+	return d.Decay
+}
+
+func (d *DecayFunction) Validate() error {
+	if d.Field == "" {
+		return fmt.Errorf("decay function must have a field")
+	}
+	if d.Scale == nil {
+		return fmt.Errorf("decay function must have a scale")
+	}
+	if d.Decay < 0 || d.Decay >= 1 {
+		return fmt.Errorf("decay function decay must be between 0 and 1")
+	}
+	return nil
+}
+
+const (
+	decayGauss = iota
+	decayLinear
+	decayExp
+)
+
+// docValueFunc returns the function scoring documents by the distance
+// of their values, after working out the type of the field from the
+// mapping, or from the origin for fields mapped dynamically.
+func (d *DecayFunction) docValueFunc(kind int, m mapping.IndexMapping) (searcher.DocValueFunc, error) {
+	fieldType := m.FieldMappingForPath(d.Field).Type
+	if fieldType == "" {
+		switch origin := d.Origin.(type) {
+		case nil:
+			fieldType = "datetime"
+		case string:
+			fieldType = "geopoint"
+			if _, err := queryTimeFromString(origin); err == nil || origin == "now" {
+				fieldType = "datetime"
+			}
+		default:
+			fieldType = "geopoint"
+			if _, ok := decayNumber(origin); ok {
+				fieldType = "number"
+			}
+		}
+	}
+
+	var distance func(v int64) float64
+	var scale, offset float64
+	var err error
+	switch fieldType {
+	case "number":
+		origin, ok := decayNumber(d.Origin)
+		if !ok {
+			return nil, fmt.Errorf("decay function origin for field '%s' must be a number", d.Field)
+		}
+		if scale, ok = decayNumber(d.Scale); !ok {
+			return nil, fmt.Errorf("decay function scale for field '%s' must be a number", d.Field)
+		}
+		if d.Offset != nil {
+			if offset, ok = decayNumber(d.Offset); !ok {
+				return nil, fmt.Errorf("decay function offset for field '%s' must be a number", d.Field)
+			}
+		}
+		distance = func(v int64) float64 {
+			return math.Abs(numeric.Int64ToFloat64(v) - origin)
+		}
+	case "datetime":
+		origin := time.Now()
+		if s, ok := d.Origin.(string); ok && s != "now" && s != "" {
+			origin, err = queryTimeFromString(s)
+			if err != nil {
+				return nil, fmt.Errorf("decay function origin for field '%s': %v", d.Field, err)
+			}
+		} else if d.Origin != nil && !ok {
+			return nil, fmt.Errorf("decay function origin for field '%s' must be a date", d.Field)
+		}
+		if scale, err = decayDuration(d.Scale); err != nil {
+			return nil, fmt.Errorf("decay function scale for field '%s': %v", d.Field, err)
+		}
+		if d.Offset != nil {
+			if offset, err = decayDuration(d.Offset); err != nil {
+				return nil, fmt.Errorf("decay function offset for field '%s': %v", d.Field, err)
+			}
+		}
+		originNanos := float64(origin.UnixNano())
+		distance = func(v int64) float64 {
+			return math.Abs(float64(v) - originNanos)
+		}
+	case "geopoint":
+		lon, lat, ok := geo.ExtractGeoPoint(d.Origin)
+		if !ok {
+			return nil, fmt.Errorf("decay function origin for field '%s' must be a geopoint", d.Field)
+		}
+		if scale, err = decayDistance(d.Scale); err != nil {
+			return nil, fmt.Errorf("decay function scale for field '%s': %v", d.Field, err)
+		}
+		if d.Offset != nil {
+			if offset, err = decayDistance(d.Offset); err != nil {
+				return nil, fmt.Errorf("decay function offset for field '%s': %v", d.Field, err)
+			}
+		}
+		distance = func(v int64) float64 {
+			return 1000 * geo.Haversin(lon, lat,
+				geo.MortonUnhashLon(uint64(v)), geo.MortonUnhashLat(uint64(v)))
+		}
+	default:
+		return nil, fmt.Errorf("decay function field '%s' of type %s is not numeric, datetime or geopoint",
+			d.Field, fieldType)
+	}
+	if scale <= 0 || offset < 0 {
+		return nil, fmt.Errorf("decay function scale for field '%s' must be positive, and offset not negative", d.Field)
+	}
+
+	decay := d.decay()
+	var score func(dist float64) float64
+	switch kind {
+	case decayGauss:
+		variance := -scale * scale / (2 * math.Log(decay))
+		score = func(dist float64) float64 {
+			return math.Exp(-dist * dist / (2 * variance))
+		}
+	case decayLinear:
+		s := scale / (1 - decay)
+		score = func(dist float64) float64 {
+			return math.Max(0, (s-dist)/s)
+		}
+	default:
+		lambda := math.Log(decay) / scale
+		score = func(dist float64) float64 {
+			return math.Exp(lambda * dist)
+		}
+	}
+
+	field := d.Field
+	return func(_ *search.DocumentMatch, values map[string][][]byte) (float64, error) {
+		vs := docValueInt64s(values[field])
+		if len(vs) == 0 {
+			return 1, nil
+		}
+		closest := math.Inf(1)
+		for _, v := range vs {
+			closest = math.Min(closest, distance(v))
+		}
+		return score(math.Max(0, closest-offset)), nil
+	}, nil
+}
+
+func decayNumber(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// decayDuration returns the nanoseconds of a duration, which besides
+// the units of time.ParseDuration may be in days or weeks.
+func decayDuration(v interface{}) (float64, error) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, fmt.Errorf("duration must be a string, such as \"7d\"")
+	}
+	for suffix, unit := range map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	} {
+		if strings.HasSuffix(s, suffix) {
+			n, err := strconv.ParseFloat(strings.TrimSuffix(s, suffix), 64)
+			if err != nil {
+				return 0, err
+			}
+			return n * float64(unit), nil
+		}
+	}
+	dur, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	return float64(dur), nil
+}
+
+// decayDistance returns the meters of a distance,
+// given as a string with units, or a number of meters.
+func decayDistance(v interface{}) (float64, error) {
+	if s, ok := v.(string); ok {
+		return geo.ParseDistance(s)
+	}
+	if n, ok := decayNumber(v); ok {
+		return n, nil
+	}
+	return 0, fmt.Errorf("distance must be a string, such as \"5km\"")
+}
+
+// docValueInt64s decodes the numeric terms of the doc values.
+func docValueInt64s(terms [][]byte) []int64 {
+	var rv []int64
+	for _, term := range terms {
+		if valid, _ := numeric.ValidPrefixCodedTermBytes(term); !valid {
+			continue
+		}
+		i64, err := numeric.PrefixCoded(term).Int64()
+		if err == nil {
+			rv = append(rv, i64)
+		}
+	}
+	return rv
+}
+
+// FieldValueFactorFunction scores documents by the modified product
+// of the value of a numeric field and Factor, which defaults to 1.
+// Modifier is one of "none" (the default), "log", "log1p", "log2p",
+// "ln", "ln1p", "ln2p", "square", "sqrt" or "reciprocal".  Documents
+// having several values are scored by the smallest one, and those
+// having none by Missing, or otherwise score 1.
+type FieldValueFactorFunction struct {
+	Field    string   `json:"field"`
+	Factor   *float64 `json:"factor,omitempty"`
+	Modifier string   `json:"modifier,omitempty"`
+	Missing  *float64 `json:"missing,omitempty"`
+}
+
+func (f *FieldValueFactorFunction) SetFactor(factor float64) {
+	f.Factor = &factor
+}
+
+func (f *FieldValueFactorFunction) SetModifier(modifier string) {
+	f.Modifier = modifier
+}
+
+func (f *FieldValueFactorFunction) SetMissing(missing float64) {
+	f.Missing = &missing
+}
+
+var fieldValueFactorModifiers = map[string]func(float64) float64{
+	"":           func(v float64) float64 { return v },
+	"none":       func(v float64) float64 { return v },
+	"log":        math.Log10,
+	"log1p":      func(v float64) float64 { return math.Log10(v + 1) },
+	"log2p":      func(v float64) float64 { return math.Log10(v + 2) },
+	"ln":         math.Log,
+	"ln1p":       math.Log1p,
+	"ln2p":       func(v float64) float64 { return math.Log(v + 2) },
+	"square":     func(v float64) float64 { return v * v },
+	"sqrt":       math.Sqrt,
+	"reciprocal": func(v float64) float64 { return 1 / v },
+}
+
+func (f *FieldValueFactorFunction) Validate() error {
+	if f.Field == "" {
+		return fmt.Errorf("field value factor function must have a field")
+	}
+	if _, ok := fieldValueFactorModifiers[f.Modifier]; !ok {
+		return fmt.Errorf("unknown field value factor modifier '%s'", f.Modifier)
+	}
+	return nil
+}
+
+func (f *FieldValueFactorFunction) docValueFunc() searcher.DocValueFunc {
+	factor := 1.0
+	if f.Factor != nil {
+		factor = *f.Factor
+	}
+	modifier := fieldValueFactorModifiers[f.Modifier]
+	field, missing := f.Field, f.Missing
+	return func(_ *search.DocumentMatch, values map[string][][]byte) (float64, error) {
+		vs := docValueInt64s(values[field])
+		var v float64
+		switch {
+		case len(vs) > 0:
+			v = numeric.Int64ToFloat64(vs[0])
+		case missing != nil:
+			v = *missing
+		default:
+			return 1, nil
+		}
+		return modifier(factor * v), nil
+	}
+}
+
+// RandomScoreFunction scores documents between 0 and 1, at random, yet
+// the same for a document each time the seed is used.  The score is
+// derived from the first value of Field, when set, in which case the
+// documents without a value all score the same.  Otherwise it is
+// derived from the document id, which is then looked up for every
+// document scored.
+type RandomScoreFunction struct {
+	Seed  int64  `json:"seed"`
+	Field string `json:"field,omitempty"`
+}
+
+func (f *RandomScoreFunction) docValueFunc() searcher.DocValueFunc {
+	seed := binary.BigEndian.AppendUint64(nil, uint64(f.Seed))
+	field := f.Field
+	return func(d *search.DocumentMatch, values map[string][][]byte) (float64, error) {
+		h := fnv.New64a()
+		_, _ = h.Write(seed)
+		if field == "" {
+			_, _ = h.Write([]byte(d.ID))
+		} else if terms := values[field]; len(terms) > 0 {
+			_, _ = h.Write(terms[0])
+		}
+		return float64(h.Sum64()>>11) / (1 << 53), nil
+	}
+}
@@ -0,0 +1,279 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/searcher"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// IntervalsRule describes intervals of token positions within a field.
+// Exactly one of Match, AnyOf or AllOf must be given.  Ordered and
+// MaxGaps apply to the terms of Match and the rules of AllOf, whose
+// intervals may not overlap and are separated by at most MaxGaps
+// positions in total, or any number when MaxGaps is nil or negative.
+// Only the minimal intervals, which contain no other, are matched.
+type IntervalsRule struct {
+	// Match is analyzed text, whose terms are each an interval
+	Match    string           `json:"match,omitempty"`
+	Analyzer string           `json:"analyzer,omitempty"`
+	AnyOf    []*IntervalsRule `json:"any_of,omitempty"`
+	AllOf    []*IntervalsRule `json:"all_of,omitempty"`
+	Ordered  bool             `json:"ordered,omitempty"`
+	MaxGaps  *int             `json:"max_gaps,omitempty"`
+	Filter   *IntervalsFilter `json:"filter,omitempty"`
+}
+
+// IntervalsFilter restricts the intervals of a rule by their relation to
+// the intervals of other rules, keeping those satisfying every relation.
+type IntervalsFilter struct {
+	Containing     *IntervalsRule `json:"containing,omitempty"`
+	NotContaining  *IntervalsRule `json:"not_containing,omitempty"`
+	ContainedBy    *IntervalsRule `json:"contained_by,omitempty"`
+	NotContainedBy *IntervalsRule `json:"not_contained_by,omitempty"`
+	Before         *IntervalsRule `json:"before,omitempty"`
+	After          *IntervalsRule `json:"after,omitempty"`
+}
+
+// NewIntervalsMatchRule creates a rule matching the terms
+// of the analyzed text, in any order and with any gaps.
+func NewIntervalsMatchRule(text string) *IntervalsRule {
+	return &IntervalsRule{
+		Match: text,
+	}
+}
+
+// NewIntervalsAnyOfRule creates a rule
+// matching the intervals of any of the rules.
+func NewIntervalsAnyOfRule(rules ...*IntervalsRule) *IntervalsRule {
+	return &IntervalsRule{
+		AnyOf: rules,
+	}
+}
+
+// NewIntervalsAllOfRule creates a rule matching an interval of
+// each of the rules, in any order and with any gaps.
+func NewIntervalsAllOfRule(rules ...*IntervalsRule) *IntervalsRule {
+	return &IntervalsRule{
+		AllOf: rules,
+	}
+}
+
+func (r *IntervalsRule) SetOrdered(ordered bool) {
+	r.Ordered = ordered
+}
+
+func (r *IntervalsRule) SetMaxGaps(maxGaps int) {
+	r.MaxGaps = &maxGaps
+}
+
+// intervals returns the matcher of the minimal intervals made of an
+// interval of each clause, bounded by the gaps of the rule, if any.
+func (r *IntervalsRule) intervals(clauses []searcher.SpanMatcher) searcher.SpanMatcher {
+	if r.MaxGaps == nil || *r.MaxGaps < 0 {
+		return searcher.NewSpanIntervals(clauses, r.Ordered)
+	}
+	return searcher.NewSpanIntervalsMaxGaps(clauses, *r.MaxGaps, r.Ordered)
+}
+
+func (r *IntervalsRule) Validate() error {
+	if r == nil {
+		return fmt.Errorf("intervals rule missing")
+	}
+	kinds := 0
+	if r.Match != "" {
+		kinds++
+	}
+	if len(r.AnyOf) > 0 {
+		kinds++
+	}
+	if len(r.AllOf) > 0 {
+		kinds++
+	}
+	if kinds != 1 {
+		return fmt.Errorf("intervals rule must have exactly one of match, any_of or all_of")
+	}
+	for _, rules := range [][]*IntervalsRule{r.AnyOf, r.AllOf, r.Filter.rules()} {
+		for _, rule := range rules {
+			if err := rule.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *IntervalsFilter) rules() []*IntervalsRule {
+	if f == nil {
+		return nil
+	}
+	var rv []*IntervalsRule
+	for _, rule := range []*IntervalsRule{f.Containing, f.NotContaining,
+		f.ContainedBy, f.NotContainedBy, f.Before, f.After} {
+		if rule != nil {
+			rv = append(rv, rule)
+		}
+	}
+	return rv
+}
+
+// spanMatcher returns the matcher for the intervals of the rule, which
+// is nil when the rule cannot match, such as text analyzed to no terms.
+func (r *IntervalsRule) spanMatcher(field string, m mapping.IndexMapping) (searcher.SpanMatcher, error) {
+	var rv searcher.SpanMatcher
+	switch {
+	case r.Match != "":
+		analyzerName := r.Analyzer
+		if analyzerName == "" {
+			analyzerName = m.AnalyzerNameForPath(field)
+		}
+		analyzer := m.AnalyzerNamed(analyzerName)
+		if analyzer == nil {
+			return nil, fmt.Errorf("no analyzer named '%s' registered", analyzerName)
+		}
+		tokens := analyzer.Analyze([]byte(r.Match))
+		if len(tokens) == 0 {
+			return nil, nil
+		}
+		rv = r.intervals(phraseToSpanClauses(tokenStreamToPhrase(tokens)))
+	case len(r.AnyOf) > 0:
+		clauses := make([]searcher.SpanMatcher, 0, len(r.AnyOf))
+		for _, rule := range r.AnyOf {
+			matcher, err := rule.spanMatcher(field, m)
+			if err != nil {
+				return nil, err
+			}
+			if matcher != nil {
+				clauses = append(clauses, matcher)
+			}
+		}
+		if len(clauses) == 0 {
+			return nil, nil
+		}
+		rv = searcher.NewSpanOr(clauses)
+	case len(r.AllOf) > 0:
+		clauses := make([]searcher.SpanMatcher, 0, len(r.AllOf))
+		for _, rule := range r.AllOf {
+			matcher, err := rule.spanMatcher(field, m)
+			if err != nil || matcher == nil {
+				return nil, err
+			}
+			clauses = append(clauses, matcher)
+		}
+		rv = r.intervals(clauses)
+	default:
+		return nil, fmt.Errorf("intervals rule must have exactly one of match, any_of or all_of")
+	}
+	return r.Filter.apply(rv, field, m)
+}
+
+// apply filters the intervals of the matcher.  A filter rule which
+// cannot match excludes nothing when negated, and otherwise everything.
+func (f *IntervalsFilter) apply(matcher searcher.SpanMatcher, field string,
+	m mapping.IndexMapping) (searcher.SpanMatcher, error) {
+	if f == nil {
+		return matcher, nil
+	}
+	for _, filter := range []struct {
+		rule     *IntervalsRule
+		relation searcher.SpanRelation
+		negated  bool
+	}{
+		{f.Containing, searcher.SpanContaining, false},
+		{f.NotContaining, searcher.SpanNotContaining, true},
+		{f.ContainedBy, searcher.SpanContainedBy, false},
+		{f.NotContainedBy, searcher.SpanNotContainedBy, true},
+		{f.Before, searcher.SpanBefore, false},
+		{f.After, searcher.SpanAfter, false},
+	} {
+		if filter.rule == nil {
+			continue
+		}
+		filterMatcher, err := filter.rule.spanMatcher(field, m)
+		if err != nil {
+			return nil, err
+		}
+		if filterMatcher == nil {
+			if filter.negated {
+				continue
+			}
+			return nil, nil
+		}
+		matcher = searcher.NewSpanFilter(matcher, filterMatcher, filter.relation)
+	}
+	return matcher, nil
+}
+
+type IntervalsQuery struct {
+	Intervals *IntervalsRule `json:"intervals"`
+	FieldVal  string         `json:"field,omitempty"`
+	BoostVal  *Boost         `json:"boost,omitempty"`
+}
+
+// NewIntervalsQuery creates a new Query for finding documents with
+// intervals of token positions matching the rule, such as terms
+// following one another within a few positions.  Queried field must
+// have been indexed with IncludeTermVectors set to true.  Searches fail
+// when finding the intervals of a document takes more than
+// searcher.SpanNearMaxSteps steps.
+func NewIntervalsQuery(rule *IntervalsRule) *IntervalsQuery {
+	return &IntervalsQuery{
+		Intervals: rule,
+	}
+}
+
+func (q *IntervalsQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *IntervalsQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *IntervalsQuery) SetField(f string) {
+	q.FieldVal = f
+}
+
+func (q *IntervalsQuery) Field() string {
+	return q.FieldVal
+}
+
+func (q *IntervalsQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	field := q.FieldVal
+	if q.FieldVal == "" {
+		field = m.DefaultSearchField()
+	}
+	matcher, err := q.Intervals.spanMatcher(field, m)
+	if err != nil {
+		return nil, err
+	}
+	if matcher == nil {
+		return NewMatchNoneQuery().Searcher(ctx, i, m, options)
+	}
+	return searcher.NewSpanSearcher(ctx, i, matcher, field, q.BoostVal.Value(), options)
+}
+
+func (q *IntervalsQuery) Validate() error {
+	return q.Intervals.Validate()
+}
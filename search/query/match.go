@@ -142,10 +142,9 @@ func (q *MatchQuery) Searcher(ctx context.Context, i index.IndexReader, m mappin
 	tokens := analyzer.Analyze([]byte(q.Match))
 	if len(tokens) > 0 {
 
-		tqs := make([]Query, len(tokens))
-		if q.Fuzziness != 0 || q.autoFuzzy {
-			for i, token := range tokens {
-				query := NewFuzzyQuery(string(token.Term))
+		newTermQuery := func(term string) Query {
+			if q.Fuzziness != 0 || q.autoFuzzy {
+				query := NewFuzzyQuery(term)
 				if q.autoFuzzy {
 					query.SetAutoFuzziness(true)
 				} else {
@@ -154,15 +153,16 @@ func (q *MatchQuery) Searcher(ctx context.Context, i index.IndexReader, m mappin
 				query.SetPrefix(q.Prefix)
 				query.SetField(field)
 				query.SetBoost(q.BoostVal.Value())
-				tqs[i] = query
-			}
-		} else {
-			for i, token := range tokens {
-				tq := NewTermQuery(string(token.Term))
-				tq.SetField(field)
-				tq.SetBoost(q.BoostVal.Value())
-				tqs[i] = tq
+				return query
 			}
+			tq := NewTermQuery(term)
+			tq.SetField(field)
+			tq.SetBoost(q.BoostVal.Value())
+			return tq
+		}
+		tqs := make([]Query, len(tokens))
+		for i, token := range tokens {
+			tqs[i] = newTermQuery(string(token.Term))
 		}
 
 		switch q.Operator {
@@ -173,6 +173,35 @@ func (q *MatchQuery) Searcher(ctx context.Context, i index.IndexReader, m mappin
 			return shouldQuery.Searcher(ctx, i, m, options)
 
 		case MatchQueryOperatorAnd:
+			if isTokenGraph(tokens) {
+				// all the terms of any of the paths through the tokens,
+				// such as of either a phrase or its one word synonym
+				phrases, err := tokenGraphToPhrases(tokens)
+				if err != nil {
+					return nil, err
+				}
+				paths := make([]Query, 0, len(phrases))
+				for _, phrase := range phrases {
+					must := make([]Query, 0, len(phrase))
+					for _, terms := range phrase {
+						alternatives := make([]Query, 0, len(terms))
+						for _, term := range terms {
+							alternatives = append(alternatives, newTermQuery(term))
+						}
+						switch len(alternatives) {
+						case 0:
+						case 1:
+							must = append(must, alternatives[0])
+						default:
+							must = append(must, NewDisjunctionQuery(alternatives))
+						}
+					}
+					paths = append(paths, NewConjunctionQuery(must))
+				}
+				pathsQuery := NewDisjunctionQuery(paths)
+				pathsQuery.SetBoost(q.BoostVal.Value())
+				return pathsQuery.Searcher(ctx, i, m, options)
+			}
 			mustQuery := NewConjunctionQuery(tqs)
 			mustQuery.SetBoost(q.BoostVal.Value())
 			return mustQuery.Searcher(ctx, i, m, options)
@@ -21,6 +21,7 @@ import (
 	"github.com/blevesearch/bleve/v2/analysis"
 	"github.com/blevesearch/bleve/v2/mapping"
 	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/searcher"
 	"github.com/blevesearch/bleve/v2/util"
 	index "github.com/blevesearch/bleve_index_api"
 )
@@ -31,6 +32,7 @@ type MatchPhraseQuery struct {
 	Analyzer    string `json:"analyzer,omitempty"`
 	BoostVal    *Boost `json:"boost,omitempty"`
 	Fuzziness   int    `json:"fuzziness"`
+	Slop        int    `json:"slop,omitempty"`
 	autoFuzzy   bool
 }
 
@@ -65,6 +67,13 @@ func (q *MatchPhraseQuery) SetFuzziness(f int) {
 	q.Fuzziness = f
 }
 
+// SetSlop allows the terms of the phrase to be found at most
+// slop positions in total away from where the phrase places them,
+// so that swapping two terms takes a slop of 2.
+func (q *MatchPhraseQuery) SetSlop(slop int) {
+	q.Slop = slop
+}
+
 func (q *MatchPhraseQuery) SetAutoFuzziness(auto bool) {
 	q.autoFuzzy = auto
 }
@@ -91,19 +100,39 @@ func (q *MatchPhraseQuery) Searcher(ctx context.Context, i index.IndexReader, m
 	}
 
 	tokens := analyzer.Analyze([]byte(q.MatchPhrase))
-	if len(tokens) > 0 {
-		phrase := tokenStreamToPhrase(tokens)
+	if len(tokens) == 0 {
+		noneQuery := NewMatchNoneQuery()
+		return noneQuery.Searcher(ctx, i, m, options)
+	}
+
+	// token graphs, such as of synonyms of phrases, have a phrase per path
+	phrases := [][][]string{tokenStreamToPhrase(tokens)}
+	if isTokenGraph(tokens) {
+		var err error
+		phrases, err = tokenGraphToPhrases(tokens)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	phraseQueries := make([]Query, len(phrases))
+	for j, phrase := range phrases {
 		phraseQuery := NewMultiPhraseQuery(phrase, field)
 		phraseQuery.SetBoost(q.BoostVal.Value())
+		phraseQuery.SetSlop(q.Slop)
 		if q.autoFuzzy {
 			phraseQuery.SetAutoFuzziness(true)
 		} else {
 			phraseQuery.SetFuzziness(q.Fuzziness)
 		}
-		return phraseQuery.Searcher(ctx, i, m, options)
+		phraseQueries[j] = phraseQuery
+	}
+	if len(phraseQueries) == 1 {
+		return phraseQueries[0].Searcher(ctx, i, m, options)
 	}
-	noneQuery := NewMatchNoneQuery()
-	return noneQuery.Searcher(ctx, i, m, options)
+	disjunction := NewDisjunctionQuery(phraseQueries)
+	disjunction.SetBoost(q.BoostVal.Value())
+	return disjunction.Searcher(ctx, i, m, options)
 }
 
 func tokenStreamToPhrase(tokens analysis.TokenStream) [][]string {
@@ -129,6 +158,97 @@ func tokenStreamToPhrase(tokens analysis.TokenStream) [][]string {
 	return nil
 }
 
+// maxTokenGraphPhrases is the number of phrases the paths through
+// a token graph may have at most, for the phrase to be searched.
+const maxTokenGraphPhrases = 256
+
+// isTokenGraph reports whether any of the tokens spans several
+// positions, such as a one word synonym of a phrase.
+func isTokenGraph(tokens analysis.TokenStream) bool {
+	for _, token := range tokens {
+		if token.PositionLength > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenGraphToPhrases returns the phrases of the paths through the
+// tokens, in which tokens spanning several positions take the place of
+// the tokens at those positions, while other tokens at the same position
+// are alternatives, as for tokenStreamToPhrase.
+func tokenGraphToPhrases(tokens analysis.TokenStream) ([][][]string, error) {
+	first := int(^uint(0) >> 1)
+	end := 0
+	starting := make(map[int]analysis.TokenStream)
+	for _, token := range tokens {
+		if token.Position < first {
+			first = token.Position
+		}
+		length := token.PositionLength
+		if length < 1 {
+			length = 1
+		}
+		if token.Position+length > end {
+			end = token.Position + length
+		}
+		starting[token.Position] = append(starting[token.Position], token)
+	}
+
+	var rv [][][]string
+	var walk func(position int, phrase [][]string) error
+	walk = func(position int, phrase [][]string) error {
+		if position >= end {
+			if len(rv) >= maxTokenGraphPhrases {
+				return fmt.Errorf("phrase has more than %d paths through its tokens", maxTokenGraphPhrases)
+			}
+			rv = append(rv, phrase)
+			return nil
+		}
+		// each path has a phrase of its own
+		phrase = phrase[:len(phrase):len(phrase)]
+		var terms []string
+		for _, token := range starting[position] {
+			if token.PositionLength > 1 {
+				err := walk(position+token.PositionLength, append(phrase, []string{string(token.Term)}))
+				if err != nil {
+					return err
+				}
+			} else {
+				terms = append(terms, string(token.Term))
+			}
+		}
+		// positions without tokens, such as of stop words, are gaps
+		if len(terms) > 0 || len(starting[position]) == 0 {
+			return walk(position+1, append(phrase, terms))
+		}
+		return nil
+	}
+	err := walk(first, nil)
+	return rv, err
+}
+
+// phraseToSpanClauses returns a span matcher for each position of
+// the phrase, matching any of the terms at the position.
+func phraseToSpanClauses(phrase [][]string) []searcher.SpanMatcher {
+	clauses := make([]searcher.SpanMatcher, 0, len(phrase))
+	for _, terms := range phrase {
+		switch len(terms) {
+		case 0:
+			continue
+		case 1:
+			clauses = append(clauses, searcher.NewSpanTerm(terms[0]))
+		default:
+			alternatives := make([]searcher.SpanMatcher, 0, len(terms))
+			for _, term := range terms {
+				alternatives = append(alternatives, searcher.NewSpanTerm(term))
+			}
+			clauses = append(clauses, searcher.NewSpanOr(alternatives))
+		}
+	}
+	return clauses
+}
+
 func (q *MatchPhraseQuery) UnmarshalJSON(data []byte) error {
 	type Alias MatchPhraseQuery
 	aux := &struct {
@@ -164,6 +284,7 @@ func (f *MatchPhraseQuery) MarshalJSON() ([]byte, error) {
 		Analyzer    string      `json:"analyzer,omitempty"`
 		BoostVal    *Boost      `json:"boost,omitempty"`
 		Fuzziness   interface{} `json:"fuzziness"`
+		Slop        int         `json:"slop,omitempty"`
 	}
 	aux := matchPhrase{
 		MatchPhrase: f.MatchPhrase,
@@ -171,6 +292,7 @@ func (f *MatchPhraseQuery) MarshalJSON() ([]byte, error) {
 		Analyzer:    f.Analyzer,
 		BoostVal:    f.BoostVal,
 		Fuzziness:   fuzzyValue,
+		Slop:        f.Slop,
 	}
 	return util.MarshalJSON(aux)
 }
@@ -99,3 +99,45 @@ func TestTokenStreamToPhrase(t *testing.T) {
 		}
 	}
 }
+
+func TestTokenGraphToPhrases(t *testing.T) {
+	// in ny city, then a stop word and now, with nyc and new york
+	// for ny, which spans the positions of new york
+	tokens := analysis.TokenStream{
+		&analysis.Token{Term: []byte("in"), Position: 1},
+		&analysis.Token{Term: []byte("ny"), Position: 2, PositionLength: 2},
+		&analysis.Token{Term: []byte("nyc"), Position: 2, PositionLength: 2},
+		&analysis.Token{Term: []byte("new"), Position: 2},
+		&analysis.Token{Term: []byte("york"), Position: 3},
+		&analysis.Token{Term: []byte("city"), Position: 4},
+		&analysis.Token{Term: []byte("now"), Position: 6},
+	}
+	if !isTokenGraph(tokens) || isTokenGraph(tokens[3:]) {
+		t.Errorf("unexpected token graph detection")
+	}
+
+	actual, err := tokenGraphToPhrases(tokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][][]string{
+		{{"in"}, {"ny"}, {"city"}, nil, {"now"}},
+		{{"in"}, {"nyc"}, {"city"}, nil, {"now"}},
+		{{"in"}, {"new"}, {"york"}, {"city"}, nil, {"now"}},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %#v got %#v", expected, actual)
+	}
+
+	// too many paths
+	tokens = nil
+	for i := 0; i < 9; i++ {
+		tokens = append(tokens,
+			&analysis.Token{Term: []byte("a"), Position: 2*i + 1, PositionLength: 2},
+			&analysis.Token{Term: []byte("b"), Position: 2*i + 1},
+			&analysis.Token{Term: []byte("c"), Position: 2*i + 2})
+	}
+	if _, err = tokenGraphToPhrases(tokens); err == nil {
+		t.Errorf("expected error for too many paths")
+	}
+}
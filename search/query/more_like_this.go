@@ -0,0 +1,254 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+const (
+	defaultMoreLikeThisMinTermFreq   = 2
+	defaultMoreLikeThisMinDocFreq    = 5
+	defaultMoreLikeThisMaxQueryTerms = 25
+)
+
+// MoreLikeThisQuery finds documents similar to some text, or to the
+// stored fields of some documents, which are themselves not matched.
+// The text is analyzed with the analyzer of each of the Fields, and the
+// terms occurring at least MinTermFreq times (default 2), in at least
+// MinDocFreq documents (default 5) and at most MaxDocFreq documents
+// (default any), are ranked by TF-IDF.  The top MaxQueryTerms terms
+// (default 25) are then searched for in their field, weighted by rank.
+type MoreLikeThisQuery struct {
+	Like          string   `json:"more_like_this"`
+	LikeIDs       []string `json:"like_ids,omitempty"`
+	Fields        []string `json:"fields,omitempty"`
+	Analyzer      string   `json:"analyzer,omitempty"`
+	MinTermFreq   int      `json:"min_term_freq,omitempty"`
+	MinDocFreq    int      `json:"min_doc_freq,omitempty"`
+	MaxDocFreq    int      `json:"max_doc_freq,omitempty"`
+	MaxQueryTerms int      `json:"max_query_terms,omitempty"`
+	BoostVal      *Boost   `json:"boost,omitempty"`
+}
+
+// NewMoreLikeThisQuery creates a new Query for
+// finding documents similar to the text.
+func NewMoreLikeThisQuery(like string) *MoreLikeThisQuery {
+	return &MoreLikeThisQuery{
+		Like: like,
+	}
+}
+
+// NewMoreLikeThisDocumentsQuery creates a new Query for finding
+// documents similar to the stored fields of the documents.
+func NewMoreLikeThisDocumentsQuery(ids []string) *MoreLikeThisQuery {
+	return &MoreLikeThisQuery{
+		LikeIDs: ids,
+	}
+}
+
+func (q *MoreLikeThisQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *MoreLikeThisQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *MoreLikeThisQuery) Validate() error {
+	if q.Like == "" && len(q.LikeIDs) == 0 {
+		return fmt.Errorf("more like this query must have text or document ids")
+	}
+	if q.MinTermFreq < 0 || q.MinDocFreq < 0 || q.MaxDocFreq < 0 || q.MaxQueryTerms < 0 {
+		return fmt.Errorf("more like this query frequencies and max query terms must not be negative")
+	}
+	return nil
+}
+
+func (q *MoreLikeThisQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	terms, err := q.selectTerms(ctx, i, m)
+	if err != nil {
+		return nil, err
+	}
+	if len(terms) == 0 {
+		return NewMatchNoneQuery().Searcher(ctx, i, m, options)
+	}
+
+	maxScore := terms[0].score
+	termQueries := make([]Query, 0, len(terms))
+	for _, t := range terms {
+		tq := NewTermQuery(t.term)
+		tq.SetField(t.field)
+		tq.SetBoost(t.score / maxScore)
+		termQueries = append(termQueries, tq)
+	}
+	var rv Query = NewDisjunctionQuery(termQueries)
+	if len(q.LikeIDs) > 0 {
+		rv = NewBooleanQuery(nil, []Query{rv}, []Query{NewDocIDQuery(q.LikeIDs)})
+	}
+	if q.BoostVal != nil {
+		rv.(BoostableQuery).SetBoost(q.BoostVal.Value())
+	}
+	return rv.Searcher(ctx, i, m, options)
+}
+
+type moreLikeThisTerm struct {
+	field string
+	term  string
+	score float64
+}
+
+// selectTerms returns the terms to search for, best first.
+func (q *MoreLikeThisQuery) selectTerms(ctx context.Context, i index.IndexReader,
+	m mapping.IndexMapping) ([]*moreLikeThisTerm, error) {
+	fields := q.Fields
+	if len(fields) == 0 {
+		fields = []string{m.DefaultSearchField()}
+	}
+	texts, err := q.likeTexts(i, fields, m)
+	if err != nil {
+		return nil, err
+	}
+	numDocs, err := i.DocCount()
+	if err != nil {
+		return nil, err
+	}
+
+	minTermFreq := defaultIfZero(q.MinTermFreq, defaultMoreLikeThisMinTermFreq)
+	minDocFreq := defaultIfZero(q.MinDocFreq, defaultMoreLikeThisMinDocFreq)
+	maxQueryTerms := defaultIfZero(q.MaxQueryTerms, defaultMoreLikeThisMaxQueryTerms)
+
+	var rv []*moreLikeThisTerm
+	for _, field := range fields {
+		analyzerName := q.Analyzer
+		if analyzerName == "" {
+			analyzerName = m.AnalyzerNameForPath(field)
+		}
+		analyzer := m.AnalyzerNamed(analyzerName)
+		if analyzer == nil {
+			return nil, fmt.Errorf("no analyzer named '%s' registered", analyzerName)
+		}
+		termFreqs := make(map[string]int)
+		for _, text := range texts[field] {
+			for _, token := range analyzer.Analyze([]byte(text)) {
+				termFreqs[string(token.Term)]++
+			}
+		}
+		for term, tf := range termFreqs {
+			if tf < minTermFreq {
+				continue
+			}
+			df, err := docFrequency(ctx, i, term, field)
+			if err != nil {
+				return nil, err
+			}
+			if df == 0 || df < uint64(minDocFreq) || (q.MaxDocFreq > 0 && df > uint64(q.MaxDocFreq)) {
+				continue
+			}
+			idf := 1 + math.Log(float64(numDocs)/float64(df+1))
+			rv = append(rv, &moreLikeThisTerm{
+				field: field,
+				term:  term,
+				score: float64(tf) * idf,
+			})
+		}
+	}
+
+	sort.Slice(rv, func(a, b int) bool {
+		if rv[a].score != rv[b].score {
+			return rv[a].score > rv[b].score
+		}
+		if rv[a].field != rv[b].field {
+			return rv[a].field < rv[b].field
+		}
+		return rv[a].term < rv[b].term
+	})
+	if len(rv) > maxQueryTerms {
+		rv = rv[:maxQueryTerms]
+	}
+	// idf is not positive for terms in nearly every document
+	for len(rv) > 0 && rv[len(rv)-1].score <= 0 {
+		rv = rv[:len(rv)-1]
+	}
+	return rv, nil
+}
+
+// likeTexts returns the text to analyze for each of the fields, being
+// the text of the query, along with the stored text of the documents.
+// The default search field collects every stored text field.
+func (q *MoreLikeThisQuery) likeTexts(i index.IndexReader, fields []string,
+	m mapping.IndexMapping) (map[string][]string, error) {
+	rv := make(map[string][]string, len(fields))
+	wanted := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		wanted[field] = true
+		if q.Like != "" {
+			rv[field] = append(rv[field], q.Like)
+		}
+	}
+	defaultField := m.DefaultSearchField()
+	for _, id := range q.LikeIDs {
+		doc, err := i.Document(id)
+		if err != nil {
+			return nil, err
+		}
+		if doc == nil {
+			continue
+		}
+		doc.VisitFields(func(field index.Field) {
+			tf, ok := field.(index.TextField)
+			if !ok {
+				return
+			}
+			if wanted[field.Name()] {
+				rv[field.Name()] = append(rv[field.Name()], tf.Text())
+			}
+			// fields of the index itself, such as _id, are not composed
+			if wanted[defaultField] && field.Name() != defaultField &&
+				!strings.HasPrefix(field.Name(), "_") {
+				rv[defaultField] = append(rv[defaultField], tf.Text())
+			}
+		})
+	}
+	return rv, nil
+}
+
+func docFrequency(ctx context.Context, i index.IndexReader, term, field string) (uint64, error) {
+	tfr, err := i.TermFieldReader(ctx, []byte(term), field, false, false, false)
+	if err != nil {
+		return 0, err
+	}
+	rv := tfr.Count()
+	return rv, tfr.Close()
+}
+
+func defaultIfZero(v, def int) int {
+	if v == 0 {
+		return def
+	}
+	return v
+}
@@ -30,6 +30,7 @@ type MultiPhraseQuery struct {
 	FieldVal  string     `json:"field,omitempty"`
 	BoostVal  *Boost     `json:"boost,omitempty"`
 	Fuzziness int        `json:"fuzziness"`
+	Slop      int        `json:"slop,omitempty"`
 	autoFuzzy bool
 }
 
@@ -53,6 +54,13 @@ func (q *MultiPhraseQuery) SetFuzziness(f int) {
 	q.Fuzziness = f
 }
 
+// SetSlop allows the terms of the phrase to be found at most
+// slop positions in total away from where the phrase places them,
+// so that swapping two terms takes a slop of 2.
+func (q *MultiPhraseQuery) SetSlop(slop int) {
+	q.Slop = slop
+}
+
 func (q *MultiPhraseQuery) SetAutoFuzziness(auto bool) {
 	q.autoFuzzy = auto
 }
@@ -75,7 +83,7 @@ func (q *MultiPhraseQuery) SetField(f string) {
 }
 
 func (q *MultiPhraseQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
-	return searcher.NewMultiPhraseSearcher(ctx, i, q.Terms, q.Fuzziness, q.autoFuzzy, q.FieldVal, q.BoostVal.Value(), options)
+	return searcher.NewSloppyMultiPhraseSearcher(ctx, i, q.Terms, q.Slop, q.Fuzziness, q.autoFuzzy, q.FieldVal, q.BoostVal.Value(), options)
 }
 
 func (q *MultiPhraseQuery) Validate() error {
@@ -119,12 +127,14 @@ func (f *MultiPhraseQuery) MarshalJSON() ([]byte, error) {
 		FieldVal  string      `json:"field,omitempty"`
 		BoostVal  *Boost      `json:"boost,omitempty"`
 		Fuzziness interface{} `json:"fuzziness"`
+		Slop      int         `json:"slop,omitempty"`
 	}
 	aux := multiPhraseQuery{
 		Terms:     f.Terms,
 		FieldVal:  f.FieldVal,
 		BoostVal:  f.BoostVal,
 		Fuzziness: fuzzyValue,
+		Slop:      f.Slop,
 	}
 	return util.MarshalJSON(aux)
 }
@@ -0,0 +1,90 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/numeric"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/searcher"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// NumericTermsQuery matches documents having any of the numbers
+// exactly, looking up the full precision term each number is indexed
+// as, rather than searching a range per number.  Matches all score
+// the boost, however many of the numbers they have.  Boolean values
+// are matched by a BoolTermsQuery, and dates by a DateTermsQuery.
+type NumericTermsQuery struct {
+	Numbers  []float64 `json:"numeric_terms"`
+	FieldVal string    `json:"field,omitempty"`
+	BoostVal *Boost    `json:"boost,omitempty"`
+}
+
+// NewNumericTermsQuery creates a new Query for finding
+// documents having any of the numeric values exactly.
+func NewNumericTermsQuery(numbers []float64) *NumericTermsQuery {
+	return &NumericTermsQuery{
+		Numbers: numbers,
+	}
+}
+
+func (q *NumericTermsQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *NumericTermsQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *NumericTermsQuery) SetField(f string) {
+	q.FieldVal = f
+}
+
+func (q *NumericTermsQuery) Field() string {
+	return q.FieldVal
+}
+
+func (q *NumericTermsQuery) Validate() error {
+	if len(q.Numbers) == 0 {
+		return fmt.Errorf("numeric terms query must have at least one number")
+	}
+	for _, number := range q.Numbers {
+		if math.IsNaN(number) {
+			return fmt.Errorf("numeric terms query numbers must not be NaN")
+		}
+	}
+	return nil
+}
+
+func (q *NumericTermsQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	field := q.FieldVal
+	if q.FieldVal == "" {
+		field = m.DefaultSearchField()
+	}
+	terms := make([]string, 0, len(q.Numbers))
+	for _, number := range q.Numbers {
+		terms = append(terms, string(numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(number), 0)))
+	}
+	return searcher.NewConstantScoreMultiTermSearcher(ctx, i, terms, field, q.BoostVal.Value(), options)
+}
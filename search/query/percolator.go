@@ -0,0 +1,206 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/util"
+)
+
+// PercolatorAnyTerm is the term indexed for a percolator query whose
+// terms cannot be extracted, so that it is a candidate for any document.
+const PercolatorAnyTerm = "\x00"
+
+func init() {
+	mapping.PercolatorQueryTerms = percolatorQueryTerms
+}
+
+// PercolatorTerm returns the term indexed for a percolator
+// query requiring the term to be in the field.
+func PercolatorTerm(field, term string) string {
+	return field + "\x00" + term
+}
+
+// ParsePercolatorQuery parses a percolator query, as stored
+// in JSON, which is a query string when a JSON string.
+func ParsePercolatorQuery(input []byte) (Query, error) {
+	var queryString string
+	if err := util.UnmarshalJSON(input, &queryString); err == nil {
+		return NewQueryStringQuery(queryString), nil
+	}
+	return ParseQuery(input)
+}
+
+func percolatorQueryTerms(input []byte, m mapping.IndexMapping) ([]string, error) {
+	q, err := ParsePercolatorQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	return PercolatorTerms(q, m)
+}
+
+// PercolatorTerms returns the terms to index for a percolator query, at
+// least one of which a document must have for the query to match it,
+// as encoded by PercolatorTerm.  Queries whose terms cannot be worked
+// out, such as range queries, have PercolatorAnyTerm instead, while
+// queries matching no document have no terms at all.
+func PercolatorTerms(q Query, m mapping.IndexMapping) ([]string, error) {
+	terms, ok, err := requiredTerms(q, m)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []string{PercolatorAnyTerm}, nil
+	}
+	rv := make([]string, 0, len(terms))
+	seen := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		if _, exists := seen[term]; !exists {
+			seen[term] = struct{}{}
+			rv = append(rv, term)
+		}
+	}
+	return rv, nil
+}
+
+// requiredTerms returns the terms at least one of which a document must
+// have to match the query, reporting whether such terms were found.
+func requiredTerms(q Query, m mapping.IndexMapping) ([]string, bool, error) {
+	fieldOf := func(fq FieldableQuery) string {
+		if fq.Field() == "" {
+			return m.DefaultSearchField()
+		}
+		return fq.Field()
+	}
+	analyze := func(text, analyzerName, field string) ([]string, error) {
+		if analyzerName == "" {
+			analyzerName = m.AnalyzerNameForPath(field)
+		}
+		analyzer := m.AnalyzerNamed(analyzerName)
+		if analyzer == nil {
+			return nil, fmt.Errorf("no analyzer named '%s' registered", analyzerName)
+		}
+		var rv []string
+		for _, token := range analyzer.Analyze([]byte(text)) {
+			rv = append(rv, PercolatorTerm(field, string(token.Term)))
+		}
+		return rv, nil
+	}
+
+	switch q := q.(type) {
+	case *TermQuery:
+		return []string{PercolatorTerm(fieldOf(q), q.Term)}, true, nil
+	case *MatchQuery:
+		if q.Fuzziness != 0 || q.autoFuzzy {
+			return nil, false, nil
+		}
+		terms, err := analyze(q.Match, q.Analyzer, fieldOf(q))
+		return terms, err == nil, err
+	case *MatchPhraseQuery:
+		if q.Fuzziness != 0 || q.autoFuzzy {
+			return nil, false, nil
+		}
+		terms, err := analyze(q.MatchPhrase, q.Analyzer, fieldOf(q))
+		return terms, err == nil, err
+	case *PhraseQuery:
+		if q.Fuzziness != 0 || q.autoFuzzy {
+			return nil, false, nil
+		}
+		// any of the terms, all of which are required,
+		// other than the empty terms of the gaps
+		rv := make([]string, 0, len(q.Terms))
+		for _, term := range q.Terms {
+			if term != "" {
+				rv = append(rv, PercolatorTerm(fieldOf(q), term))
+			}
+		}
+		return rv, len(rv) > 0, nil
+	case *MultiPhraseQuery:
+		if q.Fuzziness != 0 || q.autoFuzzy {
+			return nil, false, nil
+		}
+		// any of the terms of the first position which has some
+		for _, terms := range q.Terms {
+			rv := make([]string, 0, len(terms))
+			for _, term := range terms {
+				if term != "" {
+					rv = append(rv, PercolatorTerm(fieldOf(q), term))
+				}
+			}
+			if len(rv) > 0 {
+				return rv, true, nil
+			}
+		}
+		return nil, false, nil
+	case *QueryStringQuery:
+		parsed, err := q.Parse()
+		if err != nil {
+			return nil, false, err
+		}
+		return requiredTerms(parsed, m)
+	case *ConjunctionQuery:
+		return requiredTermsOfAny(q.Conjuncts, m)
+	case *DisjunctionQuery:
+		if len(q.Disjuncts) == 0 {
+			return nil, false, nil
+		}
+		var rv []string
+		for _, disjunct := range q.Disjuncts {
+			terms, ok, err := requiredTerms(disjunct, m)
+			if err != nil || !ok {
+				return nil, false, err
+			}
+			rv = append(rv, terms...)
+		}
+		return rv, true, nil
+	case *BooleanQuery:
+		required := []Query{q.Must, q.Filter}
+		if should, ok := q.Should.(*DisjunctionQuery); ok &&
+			((q.Must == nil && q.Filter == nil) || should.Min > 0) {
+			required = append(required, should)
+		}
+		return requiredTermsOfAny(required, m)
+	case *FunctionScoreQuery:
+		if q.Query == nil {
+			return nil, false, nil
+		}
+		return requiredTerms(q.Query, m)
+	case *MatchNoneQuery:
+		return nil, true, nil
+	}
+	return nil, false, nil
+}
+
+// requiredTermsOfAny returns the required terms of whichever of the
+// queries, all of which must match, has the fewest required terms.
+func requiredTermsOfAny(queries []Query, m mapping.IndexMapping) ([]string, bool, error) {
+	var rv []string
+	found := false
+	for _, q := range queries {
+		if q == nil {
+			continue
+		}
+		terms, ok, err := requiredTerms(q, m)
+		if err != nil {
+			return nil, false, err
+		}
+		if ok && (!found || len(terms) < len(rv)) {
+			rv, found = terms, true
+		}
+	}
+	return rv, found, nil
+}
@@ -193,6 +193,79 @@ func ParseQuery(input []byte) (Query, error) {
 		}
 		return &rv, nil
 	}
+	_, isIntervalsQuery := tmp["intervals"]
+	if isIntervalsQuery {
+		var rv IntervalsQuery
+		err := util.UnmarshalJSON(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, isMoreLikeThisQuery := tmp["more_like_this"]
+	_, hasLikeIDs := tmp["like_ids"]
+	if isMoreLikeThisQuery || hasLikeIDs {
+		var rv MoreLikeThisQuery
+		err := util.UnmarshalJSON(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, isSpanTermQuery := tmp["span_term"]
+	if isSpanTermQuery {
+		var rv SpanTermQuery
+		err := util.UnmarshalJSON(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, isSpanNearQuery := tmp["span_near"]
+	if isSpanNearQuery {
+		var rv SpanNearQuery
+		err := util.UnmarshalJSON(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, isSpanOrQuery := tmp["span_or"]
+	if isSpanOrQuery {
+		var rv SpanOrQuery
+		err := util.UnmarshalJSON(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, isSpanNotQuery := tmp["span_not"]
+	if isSpanNotQuery {
+		var rv SpanNotQuery
+		err := util.UnmarshalJSON(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, isSpanFirstQuery := tmp["span_first"]
+	if isSpanFirstQuery {
+		var rv SpanFirstQuery
+		err := util.UnmarshalJSON(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, isSpanContainingQuery := tmp["span_containing"]
+	if isSpanContainingQuery {
+		var rv SpanContainingQuery
+		err := util.UnmarshalJSON(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
 	_, hasMust := tmp["must"]
 	_, hasShould := tmp["should"]
 	_, hasMustNot := tmp["must_not"]
@@ -308,6 +381,60 @@ func ParseQuery(input []byte) (Query, error) {
 		}
 		return &rv, nil
 	}
+	_, isFunctionScoreQuery := tmp["function_score"]
+	if isFunctionScoreQuery {
+		var rv FunctionScoreQuery
+		err := util.UnmarshalJSON(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, isTermsLookupQuery := tmp["terms_lookup"]
+	if isTermsLookupQuery {
+		var rv TermsLookupQuery
+		err := util.UnmarshalJSON(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, isExistsQuery := tmp["exists"]
+	if isExistsQuery {
+		var rv ExistsQuery
+		err := util.UnmarshalJSON(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, isNumericTermsQuery := tmp["numeric_terms"]
+	if isNumericTermsQuery {
+		var rv NumericTermsQuery
+		err := util.UnmarshalJSON(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, isBoolTermsQuery := tmp["bool_terms"]
+	if isBoolTermsQuery {
+		var rv BoolTermsQuery
+		err := util.UnmarshalJSON(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
+	_, isDateTermsQuery := tmp["date_terms"]
+	if isDateTermsQuery {
+		var rv DateTermsQuery
+		err := util.UnmarshalJSON(input, &rv)
+		if err != nil {
+			return nil, err
+		}
+		return &rv, nil
+	}
 	_, hasCustomFilter := tmp["custom_filter"]
 	if hasCustomFilter {
 		if CustomFilterQueryParser == nil {
@@ -412,7 +539,7 @@ func expandQuery(m mapping.IndexMapping, query Query) (Query, error) {
 	expand = func(query Query) (Query, error) {
 		switch q := query.(type) {
 		case *QueryStringQuery:
-			parsed, err := parseQuerySyntax(q.Query)
+			parsed, err := parseQuerySyntax(q.Query, q.BooleanSyntax)
 			if err != nil {
 				return nil, fmt.Errorf("could not parse '%s': %s", q.Query, err)
 			}
@@ -516,6 +643,17 @@ func ExtractFields(q Query, m mapping.IndexMapping, fs search.FieldSet) (search.
 				break
 			}
 		}
+	case SpanQuery:
+		var f string
+		f, _, err = q.SpanMatcher(m)
+		if err == nil && f != "" {
+			if fs == nil {
+				fs = search.NewFieldSet()
+			}
+			fs.AddField(f)
+		}
+	case *FunctionScoreQuery:
+		fs, err = ExtractFields(q.Query, m, fs)
 	case *DocIDQuery, *MatchAllQuery:
 		if fs == nil {
 			fs = search.NewFieldSet()
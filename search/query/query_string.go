@@ -25,6 +25,13 @@ import (
 type QueryStringQuery struct {
 	Query    string `json:"query"`
 	BoostVal *Boost `json:"boost,omitempty"`
+	// BooleanSyntax enables the boolean operators AND, OR, NOT, && and
+	// ||, grouping with parentheses and ranges such as [a TO b} in the
+	// query string.  It is disabled by default, as it changes the meaning
+	// of existing query strings, where these words and brackets are terms.
+	// Once enabled, the operators must be written in upper case, and
+	// quoted or written in lower case to be searched as terms.
+	BooleanSyntax bool `json:"boolean_syntax,omitempty"`
 }
 
 // NewQueryStringQuery creates a new Query used for
@@ -45,12 +52,18 @@ func (q *QueryStringQuery) Boost() float64 {
 	return q.BoostVal.Value()
 }
 
+// SetBooleanSyntax enables the boolean operators, grouping
+// and ranges in the query string, see BooleanSyntax.
+func (q *QueryStringQuery) SetBooleanSyntax(enabled bool) {
+	q.BooleanSyntax = enabled
+}
+
 func (q *QueryStringQuery) Parse() (Query, error) {
-	return parseQuerySyntax(q.Query)
+	return parseQuerySyntax(q.Query, q.BooleanSyntax)
 }
 
 func (q *QueryStringQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
-	newQuery, err := parseQuerySyntax(q.Query)
+	newQuery, err := parseQuerySyntax(q.Query, q.BooleanSyntax)
 	if err != nil {
 		return nil, err
 	}
@@ -58,7 +71,7 @@ func (q *QueryStringQuery) Searcher(ctx context.Context, i index.IndexReader, m
 }
 
 func (q *QueryStringQuery) Validate() error {
-	newQuery, err := parseQuerySyntax(q.Query)
+	newQuery, err := parseQuerySyntax(q.Query, q.BooleanSyntax)
 	if err != nil {
 		return err
 	}
@@ -19,10 +19,14 @@ s string
 n int
 f float64
 q Query
-pf *float64}
+pf *float64
+c queryStringClause
+cs []queryStringClause
+bq *BooleanQuery
+rb queryStringRangeBound}
 
 %token tSTRING tPHRASE tPLUS tMINUS tCOLON tBOOST tNUMBER tSTRING tGREATER tLESS
-tEQUAL tTILDE
+tEQUAL tTILDE tAND tOR tNOT tLPAREN tRPAREN tLBRACKET tRBRACKET tLBRACE tRBRACE tTO
 
 %type <s>                tSTRING
 %type <s>                tPHRASE
@@ -32,26 +36,76 @@ tEQUAL tTILDE
 %type <s>                tTILDE
 %type <s>                tBOOST
 %type <q>                searchBase
+%type <q>                searchGroup
 %type <pf>               searchSuffix
 %type <n>                searchPrefix
+%type <n>                rangeStart
+%type <n>                rangeEnd
+%type <rb>               rangeBound
+%type <c>                searchPart
+%type <c>                searchOr
+%type <c>                searchAnd
+%type <cs>               searchOrParts
+%type <cs>               searchAndParts
+%type <bq>               searchParts
 
 %%
 
 input:
 searchParts {
 	logDebugGrammar("INPUT")
+	yylex.(*lexerWrapper).query = $1
 };
 
 searchParts:
-searchPart searchParts {
+searchParts searchOr {
 	logDebugGrammar("SEARCH PARTS")
+	$1.addClause($2)
+	$$ = $1
 }
 |
-searchPart {
+searchOr {
 	logDebugGrammar("SEARCH PART")
+	$$ = NewBooleanQueryForQueryString(nil, nil, nil)
+	$$.addClause($1)
+};
+
+searchOr:
+searchOrParts {
+	$$ = disjunctionClause($1)
+};
+
+searchOrParts:
+searchOrParts tOR searchAnd {
+	logDebugGrammar("OR")
+	$$ = append($1, $3)
+}
+|
+searchAnd {
+	$$ = []queryStringClause{$1}
+};
+
+searchAnd:
+searchAndParts {
+	$$ = conjunctionClause($1)
+};
+
+searchAndParts:
+searchAndParts tAND searchPart {
+	logDebugGrammar("AND")
+	$$ = append($1, $3)
+}
+|
+searchPart {
+	$$ = []queryStringClause{$1}
 };
 
 searchPart:
+tNOT searchPart {
+	logDebugGrammar("NOT")
+	$$ = queryStringClause{occur: queryMustNot, q: $2.q}
+}
+|
 searchPrefix searchBase searchSuffix {
 	query := $2
 	if $3 != nil {
@@ -59,17 +113,9 @@ searchPrefix searchBase searchSuffix {
 			query.SetBoost(*$3)
 			}
 	}
-	switch($1) {
-		case queryShould:
-			yylex.(*lexerWrapper).query.AddShould(query)
-		case queryMust:
-			yylex.(*lexerWrapper).query.AddMust(query)
-		case queryMustNot:
-			yylex.(*lexerWrapper).query.AddMustNot(query)
-	}
+	$$ = queryStringClause{occur: $1, q: query}
 };
 
-
 searchPrefix:
 /* empty */ {
 	$$ = queryShould
@@ -148,19 +194,35 @@ tPHRASE {
 	$$ = q
 }
 |
+tPHRASE tTILDE {
+	phrase := $1
+	slop, err := strconv.ParseFloat($2, 64)
+	if err != nil {
+		yylex.(*lexerWrapper).lex.Error(fmt.Sprintf("invalid proximity value: %v", err))
+	}
+	logDebugGrammar("PROXIMITY PHRASE - %s %f", phrase, slop)
+	q := NewMatchPhraseQuery(phrase)
+	q.SetSlop(int(slop))
+	$$ = q
+}
+|
 fieldName tCOLON tSTRING {
 	field := $1
 	str := $3
 	logDebugGrammar("FIELD - %s STRING - %s", field, str)
 	var q FieldableQuery
-	if strings.HasPrefix(str, "/") && strings.HasSuffix(str, "/") {
-		q = NewRegexpQuery(str[1:len(str)-1])
-	} else if strings.ContainsAny(str, "*?"){
-	  q = NewWildcardQuery(str)
-	}  else {
-		q = NewMatchQuery(str)
+	if field == queryStringExistsField {
+		q = NewExistsQuery(str)
+	} else {
+		if strings.HasPrefix(str, "/") && strings.HasSuffix(str, "/") {
+			q = NewRegexpQuery(str[1:len(str)-1])
+		} else if strings.ContainsAny(str, "*?"){
+		  q = NewWildcardQuery(str)
+		}  else {
+			q = NewMatchQuery(str)
+		}
+		q.SetField(field)
 	}
-	q.SetField(field)
 	$$ = q
 }
 |
@@ -191,6 +253,20 @@ fieldName tCOLON tPHRASE {
 	$$ = q
 }
 |
+fieldName tCOLON tPHRASE tTILDE {
+	field := $1
+	phrase := $3
+	slop, err := strconv.ParseFloat($4, 64)
+	if err != nil {
+		yylex.(*lexerWrapper).lex.Error(fmt.Sprintf("invalid proximity value: %v", err))
+	}
+	logDebugGrammar("FIELD - %s PROXIMITY PHRASE - %s %f", field, phrase, slop)
+	q := NewMatchPhraseQuery(phrase)
+	q.SetSlop(int(slop))
+	q.SetField(field)
+	$$ = q
+}
+|
 fieldName tCOLON tGREATER posOrNegNumber {
 	field := $1
 	min, err := strconv.ParseFloat($4, 64)
@@ -301,6 +377,74 @@ fieldName tCOLON tLESS tEQUAL tPHRASE {
 	q := NewDateRangeInclusiveQuery(time.Time{}, maxTime, nil, &maxInclusive)
 	q.SetField(field)
 	$$ = q
+}
+|
+searchGroup {
+	$$ = $1
+}
+|
+fieldName tCOLON searchGroup {
+	field := $1
+	logDebugGrammar("FIELD - %s GROUP", field)
+	q := queryStringNumericTerms($3)
+	setQueryStringField(q, field)
+	$$ = q
+}
+|
+rangeStart rangeBound tTO rangeBound rangeEnd {
+	logDebugGrammar("RANGE")
+	q, err := newQueryStringRangeQuery("", $2, $4, $1 == 1, $5 == 1)
+	if err != nil {
+		yylex.(*lexerWrapper).lex.Error(err.Error())
+	}
+	$$ = q
+}
+|
+fieldName tCOLON rangeStart rangeBound tTO rangeBound rangeEnd {
+	field := $1
+	logDebugGrammar("FIELD - %s RANGE", field)
+	q, err := newQueryStringRangeQuery(field, $4, $6, $3 == 1, $7 == 1)
+	if err != nil {
+		yylex.(*lexerWrapper).lex.Error(err.Error())
+	}
+	$$ = q
+};
+
+searchGroup:
+tLPAREN searchParts tRPAREN {
+	logDebugGrammar("GROUP")
+	$$ = $2
+};
+
+rangeStart:
+tLBRACKET {
+	$$ = 1
+}
+|
+tLBRACE {
+	$$ = 0
+};
+
+rangeEnd:
+tRBRACKET {
+	$$ = 1
+}
+|
+tRBRACE {
+	$$ = 0
+};
+
+rangeBound:
+tSTRING {
+	$$ = queryStringRangeBound{value: $1, open: $1 == "*"}
+}
+|
+tPHRASE {
+	$$ = queryStringRangeBound{value: $1}
+}
+|
+posOrNegNumber {
+	$$ = queryStringRangeBound{value: $1, numeric: true}
 };
 
 searchSuffix:
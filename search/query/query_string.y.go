@@ -27,6 +27,10 @@ type yySymType struct {
 	f   float64
 	q   Query
 	pf  *float64
+	c   queryStringClause
+	cs  []queryStringClause
+	bq  *BooleanQuery
+	rb  queryStringRangeBound
 }
 
 const tSTRING = 57346
@@ -40,6 +44,16 @@ const tGREATER = 57353
 const tLESS = 57354
 const tEQUAL = 57355
 const tTILDE = 57356
+const tAND = 57357
+const tOR = 57358
+const tNOT = 57359
+const tLPAREN = 57360
+const tRPAREN = 57361
+const tLBRACKET = 57362
+const tRBRACKET = 57363
+const tLBRACE = 57364
+const tRBRACE = 57365
+const tTO = 57366
 
 var yyToknames = [...]string{
 	"$end",
@@ -56,6 +70,16 @@ var yyToknames = [...]string{
 	"tLESS",
 	"tEQUAL",
 	"tTILDE",
+	"tAND",
+	"tOR",
+	"tNOT",
+	"tLPAREN",
+	"tRPAREN",
+	"tLBRACKET",
+	"tRBRACKET",
+	"tLBRACE",
+	"tRBRACE",
+	"tTO",
 }
 
 var yyStatenames = [...]string{}
@@ -65,80 +89,101 @@ const yyErrCode = 2
 const yyInitialStackSize = 16
 
 //line yacctab:1
-var yyExca = [...]int{
+var yyExca = [...]int8{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 3,
-	1, 3,
-	-2, 5,
-	-1, 9,
-	8, 29,
-	-2, 8,
-	-1, 12,
-	8, 28,
+	-1, 2,
+	1, 1,
 	-2, 12,
+	-1, 17,
+	8, 50,
+	-2, 15,
+	-1, 20,
+	8, 49,
+	-2, 19,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 43
-
-var yyAct = [...]int{
-	18, 17, 19, 24, 23, 15, 31, 22, 20, 21,
-	30, 27, 23, 23, 3, 22, 22, 14, 29, 26,
-	16, 25, 28, 35, 33, 23, 23, 32, 22, 22,
-	34, 9, 12, 1, 5, 6, 2, 11, 4, 13,
-	7, 8, 10,
+const yyLast = 86
+
+var yyAct = [...]int8{
+	65, 33, 36, 3, 22, 21, 12, 40, 42, 66,
+	38, 67, 13, 37, 43, 44, 64, 47, 10, 11,
+	14, 23, 51, 24, 50, 25, 17, 20, 2, 8,
+	7, 49, 19, 57, 41, 38, 46, 45, 37, 15,
+	23, 56, 24, 12, 25, 27, 52, 55, 58, 59,
+	10, 11, 39, 32, 30, 54, 60, 38, 5, 62,
+	37, 8, 48, 53, 34, 35, 68, 38, 29, 69,
+	37, 63, 26, 38, 1, 61, 37, 38, 6, 31,
+	37, 4, 9, 28, 16, 18,
 }
 
-var yyPact = [...]int{
-	28, -1000, -1000, 28, 27, -1000, -1000, -1000, 8, -9,
-	12, -1000, -1000, -1000, -1000, -1000, -3, -11, -1000, -1000,
-	6, 5, -1000, -4, -1000, -1000, 19, -1000, -1000, 18,
-	-1000, -1000, -1000, -1000, -1000, -1000,
+var yyPact = [...]int16{
+	44, -1000, 44, -1000, -4, -1000, 5, -1000, 44, 22,
+	-1000, -1000, -1000, 44, 44, -1000, 59, 40, 71, -1000,
+	39, -1000, 60, 44, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, 3, -1000, -7, -1000, -1000, -1000, -1000, 52, 12,
+	10, -1000, 8, 50, 28, -1000, 60, 60, -1000, -1000,
+	-1000, -1000, -1000, 70, -1000, -1000, 66, -1000, -8, -12,
+	-1000, -1000, -1000, -1000, 60, -1000, -1000, -1000, -12, -1000,
 }
 
-var yyPgo = [...]int{
-	0, 0, 42, 41, 39, 38, 33, 36, 14,
+var yyPgo = [...]int8{
+	0, 2, 85, 84, 5, 83, 82, 4, 0, 1,
+	30, 3, 58, 81, 78, 28, 74,
 }
 
-var yyR1 = [...]int{
-	0, 6, 7, 7, 8, 5, 5, 5, 3, 3,
+var yyR1 = [...]int8{
+	0, 16, 15, 15, 11, 13, 13, 12, 14, 14,
+	10, 10, 6, 6, 6, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 4, 4, 1, 1, 2, 2,
+	3, 3, 3, 3, 3, 3, 3, 4, 7, 7,
+	8, 8, 9, 9, 9, 5, 5, 1, 1, 2,
+	2,
 }
 
-var yyR2 = [...]int{
-	0, 1, 2, 1, 3, 0, 1, 1, 1, 2,
-	4, 1, 1, 3, 3, 3, 4, 5, 4, 5,
-	4, 5, 4, 5, 0, 1, 1, 2, 1, 1,
+var yyR2 = [...]int8{
+	0, 1, 2, 1, 1, 3, 1, 1, 3, 1,
+	2, 3, 0, 1, 1, 1, 2, 4, 1, 1,
+	2, 3, 3, 3, 4, 4, 5, 4, 5, 4,
+	5, 4, 5, 1, 3, 5, 7, 3, 1, 1,
+	1, 1, 1, 1, 1, 0, 1, 1, 2, 1,
+	1,
 }
 
-var yyChk = [...]int{
-	-1000, -6, -7, -8, -5, 6, 7, -7, -3, 4,
-	-2, 10, 5, -4, 9, 14, 8, 4, -1, 5,
-	11, 12, 10, 7, 14, -1, 13, 5, -1, 13,
-	5, 10, -1, 5, -1, 5,
+var yyChk = [...]int16{
+	-1000, -16, -15, -11, -13, -12, -14, -10, 17, -6,
+	6, 7, -11, 16, 15, -10, -3, 4, -2, 10,
+	5, -4, -7, 18, 20, 22, -12, -10, -5, 9,
+	14, 8, 14, -9, 4, 5, -1, 10, 7, -15,
+	4, -1, 5, 11, 12, -4, -7, 24, 10, 19,
+	14, 14, -1, 13, 5, -1, 13, 5, -9, -9,
+	-1, 5, -1, 5, 24, -8, 21, 23, -9, -8,
 }
 
-var yyDef = [...]int{
-	5, -2, 1, -2, 0, 6, 7, 2, 24, -2,
-	0, 11, -2, 4, 25, 9, 0, 13, 14, 15,
-	0, 0, 26, 0, 10, 16, 0, 20, 18, 0,
-	22, 27, 17, 21, 19, 23,
+var yyDef = [...]int8{
+	12, -2, -2, 3, 4, 6, 7, 9, 12, 0,
+	13, 14, 2, 12, 12, 10, 45, -2, 0, 18,
+	-2, 33, 0, 12, 38, 39, 5, 8, 11, 46,
+	16, 0, 20, 0, 42, 43, 44, 47, 0, 12,
+	21, 22, 23, 0, 0, 34, 0, 0, 48, 37,
+	17, 24, 25, 0, 29, 27, 0, 31, 0, 0,
+	26, 30, 28, 32, 0, 35, 40, 41, 0, 36,
 }
 
-var yyTok1 = [...]int{
+var yyTok1 = [...]int8{
 	1,
 }
 
-var yyTok2 = [...]int{
+var yyTok2 = [...]int8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
-	12, 13, 14,
+	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
+	22, 23, 24,
 }
 
-var yyTok3 = [...]int{
+var yyTok3 = [...]int8{
 	0,
 }
 
@@ -220,9 +265,9 @@ func yyErrorMessage(state, lookAhead int) string {
 	expected := make([]int, 0, 4)
 
 	// Look for shiftable tokens.
-	base := yyPact[state]
+	base := int(yyPact[state])
 	for tok := TOKSTART; tok-1 < len(yyToknames); tok++ {
-		if n := base + tok; n >= 0 && n < yyLast && yyChk[yyAct[n]] == tok {
+		if n := base + tok; n >= 0 && n < yyLast && int(yyChk[int(yyAct[n])]) == tok {
 			if len(expected) == cap(expected) {
 				return res
 			}
@@ -232,13 +277,13 @@ func yyErrorMessage(state, lookAhead int) string {
 
 	if yyDef[state] == -2 {
 		i := 0
-		for yyExca[i] != -1 || yyExca[i+1] != state {
+		for yyExca[i] != -1 || int(yyExca[i+1]) != state {
 			i += 2
 		}
 
 		// Look for tokens that we accept or reduce.
 		for i += 2; yyExca[i] >= 0; i += 2 {
-			tok := yyExca[i]
+			tok := int(yyExca[i])
 			if tok < TOKSTART || yyExca[i+1] == 0 {
 				continue
 			}
@@ -269,30 +314,30 @@ func yylex1(lex yyLexer, lval *yySymType) (char, token int) {
 	token = 0
 	char = lex.Lex(lval)
 	if char <= 0 {
-		token = yyTok1[0]
+		token = int(yyTok1[0])
 		goto out
 	}
 	if char < len(yyTok1) {
-		token = yyTok1[char]
+		token = int(yyTok1[char])
 		goto out
 	}
 	if char >= yyPrivate {
 		if char < yyPrivate+len(yyTok2) {
-			token = yyTok2[char-yyPrivate]
+			token = int(yyTok2[char-yyPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(yyTok3); i += 2 {
-		token = yyTok3[i+0]
+		token = int(yyTok3[i+0])
 		if token == char {
-			token = yyTok3[i+1]
+			token = int(yyTok3[i+1])
 			goto out
 		}
 	}
 
 out:
 	if token == 0 {
-		token = yyTok2[1] /* unknown char */
+		token = int(yyTok2[1]) /* unknown char */
 	}
 	if yyDebug >= 3 {
 		__yyfmt__.Printf("lex %s(%d)\n", yyTokname(token), uint(char))
@@ -347,7 +392,7 @@ yystack:
 	yyS[yyp].yys = yystate
 
 yynewstate:
-	yyn = yyPact[yystate]
+	yyn = int(yyPact[yystate])
 	if yyn <= yyFlag {
 		goto yydefault /* simple state */
 	}
@@ -358,8 +403,8 @@ yynewstate:
 	if yyn < 0 || yyn >= yyLast {
 		goto yydefault
 	}
-	yyn = yyAct[yyn]
-	if yyChk[yyn] == yytoken { /* valid shift */
+	yyn = int(yyAct[yyn])
+	if int(yyChk[yyn]) == yytoken { /* valid shift */
 		yyrcvr.char = -1
 		yytoken = -1
 		yyVAL = yyrcvr.lval
@@ -372,7 +417,7 @@ yynewstate:
 
 yydefault:
 	/* default state action */
-	yyn = yyDef[yystate]
+	yyn = int(yyDef[yystate])
 	if yyn == -2 {
 		if yyrcvr.char < 0 {
 			yyrcvr.char, yytoken = yylex1(yylex, &yyrcvr.lval)
@@ -381,18 +426,18 @@ yydefault:
 		/* look through exception table */
 		xi := 0
 		for {
-			if yyExca[xi+0] == -1 && yyExca[xi+1] == yystate {
+			if yyExca[xi+0] == -1 && int(yyExca[xi+1]) == yystate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			yyn = yyExca[xi+0]
+			yyn = int(yyExca[xi+0])
 			if yyn < 0 || yyn == yytoken {
 				break
 			}
 		}
-		yyn = yyExca[xi+1]
+		yyn = int(yyExca[xi+1])
 		if yyn < 0 {
 			goto ret0
 		}
@@ -414,10 +459,10 @@ yydefault:
 
 			/* find a state where "error" is a legal shift action */
 			for yyp >= 0 {
-				yyn = yyPact[yyS[yyp].yys] + yyErrCode
+				yyn = int(yyPact[yyS[yyp].yys]) + yyErrCode
 				if yyn >= 0 && yyn < yyLast {
-					yystate = yyAct[yyn] /* simulate a shift of "error" */
-					if yyChk[yystate] == yyErrCode {
+					yystate = int(yyAct[yyn]) /* simulate a shift of "error" */
+					if int(yyChk[yystate]) == yyErrCode {
 						goto yystack
 					}
 				}
@@ -453,7 +498,7 @@ yydefault:
 	yypt := yyp
 	_ = yypt // guard against "declared and not used"
 
-	yyp -= yyR2[yyn]
+	yyp -= int(yyR2[yyn])
 	// yyp is now the index of $0. Perform the default action. Iff the
 	// reduced production is ε, $1 is possibly out of range.
 	if yyp+1 >= len(yyS) {
@@ -464,16 +509,16 @@ yydefault:
 	yyVAL = yyS[yyp+1]
 
 	/* consult goto table to find next state */
-	yyn = yyR1[yyn]
-	yyg := yyPgo[yyn]
+	yyn = int(yyR1[yyn])
+	yyg := int(yyPgo[yyn])
 	yyj := yyg + yyS[yyp].yys + 1
 
 	if yyj >= yyLast {
-		yystate = yyAct[yyg]
+		yystate = int(yyAct[yyg])
 	} else {
-		yystate = yyAct[yyj]
-		if yyChk[yystate] != -yyn {
-			yystate = yyAct[yyg]
+		yystate = int(yyAct[yyj])
+		if int(yyChk[yystate]) != -yyn {
+			yystate = int(yyAct[yyg])
 		}
 	}
 	// dummy call; replaced with literal code
@@ -481,25 +526,75 @@ yydefault:
 
 	case 1:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line query_string.y:41
+//line query_string.y:55
 		{
 			logDebugGrammar("INPUT")
+			yylex.(*lexerWrapper).query = yyDollar[1].bq
 		}
 	case 2:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line query_string.y:46
+//line query_string.y:61
 		{
 			logDebugGrammar("SEARCH PARTS")
+			yyDollar[1].bq.addClause(yyDollar[2].c)
+			yyVAL.bq = yyDollar[1].bq
 		}
 	case 3:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line query_string.y:50
+//line query_string.y:67
 		{
 			logDebugGrammar("SEARCH PART")
+			yyVAL.bq = NewBooleanQueryForQueryString(nil, nil, nil)
+			yyVAL.bq.addClause(yyDollar[1].c)
 		}
 	case 4:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line query_string.y:74
+		{
+			yyVAL.c = disjunctionClause(yyDollar[1].cs)
+		}
+	case 5:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line query_string.y:55
+//line query_string.y:79
+		{
+			logDebugGrammar("OR")
+			yyVAL.cs = append(yyDollar[1].cs, yyDollar[3].c)
+		}
+	case 6:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line query_string.y:84
+		{
+			yyVAL.cs = []queryStringClause{yyDollar[1].c}
+		}
+	case 7:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line query_string.y:89
+		{
+			yyVAL.c = conjunctionClause(yyDollar[1].cs)
+		}
+	case 8:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line query_string.y:94
+		{
+			logDebugGrammar("AND")
+			yyVAL.cs = append(yyDollar[1].cs, yyDollar[3].c)
+		}
+	case 9:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line query_string.y:99
+		{
+			yyVAL.cs = []queryStringClause{yyDollar[1].c}
+		}
+	case 10:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line query_string.y:104
+		{
+			logDebugGrammar("NOT")
+			yyVAL.c = queryStringClause{occur: queryMustNot, q: yyDollar[2].c.q}
+		}
+	case 11:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line query_string.y:109
 		{
 			query := yyDollar[2].q
 			if yyDollar[3].pf != nil {
@@ -507,38 +602,31 @@ yydefault:
 					query.SetBoost(*yyDollar[3].pf)
 				}
 			}
-			switch yyDollar[1].n {
-			case queryShould:
-				yylex.(*lexerWrapper).query.AddShould(query)
-			case queryMust:
-				yylex.(*lexerWrapper).query.AddMust(query)
-			case queryMustNot:
-				yylex.(*lexerWrapper).query.AddMustNot(query)
-			}
+			yyVAL.c = queryStringClause{occur: yyDollar[1].n, q: query}
 		}
-	case 5:
+	case 12:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line query_string.y:74
+//line query_string.y:120
 		{
 			yyVAL.n = queryShould
 		}
-	case 6:
+	case 13:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line query_string.y:78
+//line query_string.y:124
 		{
 			logDebugGrammar("PLUS")
 			yyVAL.n = queryMust
 		}
-	case 7:
+	case 14:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line query_string.y:83
+//line query_string.y:129
 		{
 			logDebugGrammar("MINUS")
 			yyVAL.n = queryMustNot
 		}
-	case 8:
+	case 15:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line query_string.y:89
+//line query_string.y:135
 		{
 			str := yyDollar[1].s
 			logDebugGrammar("STRING - %s", str)
@@ -552,9 +640,9 @@ yydefault:
 			}
 			yyVAL.q = q
 		}
-	case 9:
+	case 16:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line query_string.y:103
+//line query_string.y:149
 		{
 			str := yyDollar[1].s
 			fuzziness, err := strconv.ParseFloat(yyDollar[2].s, 64)
@@ -566,9 +654,9 @@ yydefault:
 			q.SetFuzziness(int(fuzziness))
 			yyVAL.q = q
 		}
-	case 10:
+	case 17:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line query_string.y:115
+//line query_string.y:161
 		{
 			field := yyDollar[1].s
 			str := yyDollar[3].s
@@ -582,9 +670,9 @@ yydefault:
 			q.SetField(field)
 			yyVAL.q = q
 		}
-	case 11:
+	case 18:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line query_string.y:129
+//line query_string.y:175
 		{
 			str := yyDollar[1].s
 			logDebugGrammar("STRING - %s", str)
@@ -599,36 +687,54 @@ yydefault:
 			q.queryStringMode = true
 			yyVAL.q = q
 		}
-	case 12:
+	case 19:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line query_string.y:144
+//line query_string.y:190
 		{
 			phrase := yyDollar[1].s
 			logDebugGrammar("PHRASE - %s", phrase)
 			q := NewMatchPhraseQuery(phrase)
 			yyVAL.q = q
 		}
-	case 13:
+	case 20:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line query_string.y:197
+		{
+			phrase := yyDollar[1].s
+			slop, err := strconv.ParseFloat(yyDollar[2].s, 64)
+			if err != nil {
+				yylex.(*lexerWrapper).lex.Error(fmt.Sprintf("invalid proximity value: %v", err))
+			}
+			logDebugGrammar("PROXIMITY PHRASE - %s %f", phrase, slop)
+			q := NewMatchPhraseQuery(phrase)
+			q.SetSlop(int(slop))
+			yyVAL.q = q
+		}
+	case 21:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line query_string.y:151
+//line query_string.y:209
 		{
 			field := yyDollar[1].s
 			str := yyDollar[3].s
 			logDebugGrammar("FIELD - %s STRING - %s", field, str)
 			var q FieldableQuery
-			if strings.HasPrefix(str, "/") && strings.HasSuffix(str, "/") {
-				q = NewRegexpQuery(str[1 : len(str)-1])
-			} else if strings.ContainsAny(str, "*?") {
-				q = NewWildcardQuery(str)
+			if field == queryStringExistsField {
+				q = NewExistsQuery(str)
 			} else {
-				q = NewMatchQuery(str)
+				if strings.HasPrefix(str, "/") && strings.HasSuffix(str, "/") {
+					q = NewRegexpQuery(str[1 : len(str)-1])
+				} else if strings.ContainsAny(str, "*?") {
+					q = NewWildcardQuery(str)
+				} else {
+					q = NewMatchQuery(str)
+				}
+				q.SetField(field)
 			}
-			q.SetField(field)
 			yyVAL.q = q
 		}
-	case 14:
+	case 22:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line query_string.y:167
+//line query_string.y:229
 		{
 			field := yyDollar[1].s
 			str := yyDollar[3].s
@@ -646,9 +752,9 @@ yydefault:
 			q.queryStringMode = true
 			yyVAL.q = q
 		}
-	case 15:
+	case 23:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line query_string.y:185
+//line query_string.y:247
 		{
 			field := yyDollar[1].s
 			phrase := yyDollar[3].s
@@ -657,9 +763,25 @@ yydefault:
 			q.SetField(field)
 			yyVAL.q = q
 		}
-	case 16:
+	case 24:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line query_string.y:194
+//line query_string.y:256
+		{
+			field := yyDollar[1].s
+			phrase := yyDollar[3].s
+			slop, err := strconv.ParseFloat(yyDollar[4].s, 64)
+			if err != nil {
+				yylex.(*lexerWrapper).lex.Error(fmt.Sprintf("invalid proximity value: %v", err))
+			}
+			logDebugGrammar("FIELD - %s PROXIMITY PHRASE - %s %f", field, phrase, slop)
+			q := NewMatchPhraseQuery(phrase)
+			q.SetSlop(int(slop))
+			q.SetField(field)
+			yyVAL.q = q
+		}
+	case 25:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line query_string.y:270
 		{
 			field := yyDollar[1].s
 			min, err := strconv.ParseFloat(yyDollar[4].s, 64)
@@ -672,9 +794,9 @@ yydefault:
 			q.SetField(field)
 			yyVAL.q = q
 		}
-	case 17:
+	case 26:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line query_string.y:207
+//line query_string.y:283
 		{
 			field := yyDollar[1].s
 			min, err := strconv.ParseFloat(yyDollar[5].s, 64)
@@ -687,9 +809,9 @@ yydefault:
 			q.SetField(field)
 			yyVAL.q = q
 		}
-	case 18:
+	case 27:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line query_string.y:220
+//line query_string.y:296
 		{
 			field := yyDollar[1].s
 			max, err := strconv.ParseFloat(yyDollar[4].s, 64)
@@ -702,9 +824,9 @@ yydefault:
 			q.SetField(field)
 			yyVAL.q = q
 		}
-	case 19:
+	case 28:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line query_string.y:233
+//line query_string.y:309
 		{
 			field := yyDollar[1].s
 			max, err := strconv.ParseFloat(yyDollar[5].s, 64)
@@ -717,9 +839,9 @@ yydefault:
 			q.SetField(field)
 			yyVAL.q = q
 		}
-	case 20:
+	case 29:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line query_string.y:246
+//line query_string.y:322
 		{
 			field := yyDollar[1].s
 			minInclusive := false
@@ -734,9 +856,9 @@ yydefault:
 			q.SetField(field)
 			yyVAL.q = q
 		}
-	case 21:
+	case 30:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line query_string.y:261
+//line query_string.y:337
 		{
 			field := yyDollar[1].s
 			minInclusive := true
@@ -751,9 +873,9 @@ yydefault:
 			q.SetField(field)
 			yyVAL.q = q
 		}
-	case 22:
+	case 31:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line query_string.y:276
+//line query_string.y:352
 		{
 			field := yyDollar[1].s
 			maxInclusive := false
@@ -768,9 +890,9 @@ yydefault:
 			q.SetField(field)
 			yyVAL.q = q
 		}
-	case 23:
+	case 32:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line query_string.y:291
+//line query_string.y:367
 		{
 			field := yyDollar[1].s
 			maxInclusive := true
@@ -785,15 +907,103 @@ yydefault:
 			q.SetField(field)
 			yyVAL.q = q
 		}
-	case 24:
+	case 33:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line query_string.y:382
+		{
+			yyVAL.q = yyDollar[1].q
+		}
+	case 34:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line query_string.y:386
+		{
+			field := yyDollar[1].s
+			logDebugGrammar("FIELD - %s GROUP", field)
+			q := queryStringNumericTerms(yyDollar[3].q)
+			setQueryStringField(q, field)
+			yyVAL.q = q
+		}
+	case 35:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line query_string.y:394
+		{
+			logDebugGrammar("RANGE")
+			q, err := newQueryStringRangeQuery("", yyDollar[2].rb, yyDollar[4].rb, yyDollar[1].n == 1, yyDollar[5].n == 1)
+			if err != nil {
+				yylex.(*lexerWrapper).lex.Error(err.Error())
+			}
+			yyVAL.q = q
+		}
+	case 36:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line query_string.y:403
+		{
+			field := yyDollar[1].s
+			logDebugGrammar("FIELD - %s RANGE", field)
+			q, err := newQueryStringRangeQuery(field, yyDollar[4].rb, yyDollar[6].rb, yyDollar[3].n == 1, yyDollar[7].n == 1)
+			if err != nil {
+				yylex.(*lexerWrapper).lex.Error(err.Error())
+			}
+			yyVAL.q = q
+		}
+	case 37:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line query_string.y:414
+		{
+			logDebugGrammar("GROUP")
+			yyVAL.q = yyDollar[2].bq
+		}
+	case 38:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line query_string.y:420
+		{
+			yyVAL.n = 1
+		}
+	case 39:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line query_string.y:424
+		{
+			yyVAL.n = 0
+		}
+	case 40:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line query_string.y:429
+		{
+			yyVAL.n = 1
+		}
+	case 41:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line query_string.y:433
+		{
+			yyVAL.n = 0
+		}
+	case 42:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line query_string.y:438
+		{
+			yyVAL.rb = queryStringRangeBound{value: yyDollar[1].s, open: yyDollar[1].s == "*"}
+		}
+	case 43:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line query_string.y:442
+		{
+			yyVAL.rb = queryStringRangeBound{value: yyDollar[1].s}
+		}
+	case 44:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line query_string.y:446
+		{
+			yyVAL.rb = queryStringRangeBound{value: yyDollar[1].s, numeric: true}
+		}
+	case 45:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line query_string.y:307
+//line query_string.y:451
 		{
 			yyVAL.pf = nil
 		}
-	case 25:
+	case 46:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line query_string.y:311
+//line query_string.y:455
 		{
 			yyVAL.pf = nil
 			boost, err := strconv.ParseFloat(yyDollar[1].s, 64)
@@ -804,27 +1014,27 @@ yydefault:
 			}
 			logDebugGrammar("BOOST %f", boost)
 		}
-	case 26:
+	case 47:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line query_string.y:323
+//line query_string.y:467
 		{
 			yyVAL.s = yyDollar[1].s
 		}
-	case 27:
+	case 48:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line query_string.y:327
+//line query_string.y:471
 		{
 			yyVAL.s = "-" + yyDollar[2].s
 		}
-	case 28:
+	case 49:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line query_string.y:332
+//line query_string.y:476
 		{
 			yyVAL.s = yyDollar[1].s
 		}
-	case 29:
+	case 50:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line query_string.y:336
+//line query_string.y:480
 		{
 			yyVAL.s = yyDollar[1].s
 		}
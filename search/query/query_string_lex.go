@@ -30,7 +30,7 @@ var queryStringLexPool = sync.Pool{
 	},
 }
 
-func getQueryStringLex(in io.Reader) *queryStringLex {
+func getQueryStringLex(in io.Reader, booleanSyntax bool) *queryStringLex {
 	l := queryStringLexPool.Get().(*queryStringLex)
 	l.in.Reset(in)
 	l.currState = startState
@@ -43,6 +43,9 @@ func getQueryStringLex(in io.Reader) *queryStringLex {
 	l.nextRune = 0
 	l.nextRuneSize = 0
 	l.atEOF = false
+	l.booleanSyntax = booleanSyntax
+	l.inRange = false
+	l.depth = 0
 	return l
 }
 
@@ -75,6 +78,9 @@ type queryStringLex struct {
 	nextRune      rune
 	nextRuneSize  int
 	atEOF         bool
+	booleanSyntax bool
+	inRange       bool
+	depth         int
 }
 
 func (l *queryStringLex) reset() {
@@ -133,6 +139,16 @@ func startState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
 	case '+', '-', ':', '>', '<', '=':
 		l.buf += string(next)
 		return singleCharOpState, true
+	case '(', '[', '{':
+		if l.booleanSyntax {
+			l.buf += string(next)
+			return singleCharOpState, true
+		}
+	case ')', ']', '}':
+		if l.isClosing(next) {
+			l.buf += string(next)
+			return singleCharOpState, true
+		}
 	case '^':
 		return inBoostState, true
 	case '~':
@@ -208,6 +224,30 @@ func singleCharOpState(l *queryStringLex, next rune, eof bool) (lexState, bool)
 	case "=":
 		l.nextTokenType = tEQUAL
 		logDebugTokens("EQUAL")
+	case "(":
+		l.nextTokenType = tLPAREN
+		l.depth++
+		logDebugTokens("LPAREN")
+	case ")":
+		l.nextTokenType = tRPAREN
+		l.depth--
+		logDebugTokens("RPAREN")
+	case "[":
+		l.nextTokenType = tLBRACKET
+		l.inRange = true
+		logDebugTokens("LBRACKET")
+	case "]":
+		l.nextTokenType = tRBRACKET
+		l.inRange = false
+		logDebugTokens("RBRACKET")
+	case "{":
+		l.nextTokenType = tLBRACE
+		l.inRange = true
+		logDebugTokens("LBRACE")
+	case "}":
+		l.nextTokenType = tRBRACE
+		l.inRange = false
+		logDebugTokens("RBRACE")
 	}
 
 	l.reset()
@@ -216,8 +256,8 @@ func singleCharOpState(l *queryStringLex, next rune, eof bool) (lexState, bool)
 
 func inBoostState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
 
-	// only a non-escaped space ends the boost (or eof)
-	if eof || (!l.inEscape && next == ' ') {
+	// only a non-escaped space or closing bracket ends the boost (or eof)
+	if eof || (!l.inEscape && (next == ' ' || l.isClosing(next))) {
 		// end boost
 		l.nextTokenType = tBOOST
 		if l.buf == "" {
@@ -228,7 +268,7 @@ func inBoostState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
 		}
 		logDebugTokens("BOOST - '%s'", l.nextToken.s)
 		l.reset()
-		return startState, true
+		return startState, eof || next == ' '
 	} else if !l.inEscape && next == '\\' {
 		l.inEscape = true
 	} else if l.inEscape {
@@ -244,8 +284,8 @@ func inBoostState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
 
 func inTildeState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
 
-	// only a non-escaped space ends the tilde (or eof)
-	if eof || (!l.inEscape && next == ' ') {
+	// only a non-escaped space or closing bracket ends the tilde (or eof)
+	if eof || (!l.inEscape && (next == ' ' || l.isClosing(next))) {
 		// end tilde
 		l.nextTokenType = tTILDE
 		if l.buf == "" {
@@ -256,7 +296,7 @@ func inTildeState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
 		}
 		logDebugTokens("TILDE - '%s'", l.nextToken.s)
 		l.reset()
-		return startState, true
+		return startState, eof || next == ' '
 	} else if !l.inEscape && next == '\\' {
 		l.inEscape = true
 	} else if l.inEscape {
@@ -271,8 +311,8 @@ func inTildeState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
 }
 
 func inNumOrStrState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
-	// end on non-escaped space, colon, tilde, boost (or eof)
-	if eof || (!l.inEscape && (next == ' ' || next == ':' || next == '^' || next == '~')) {
+	// end on non-escaped space, colon, tilde, boost, closing bracket (or eof)
+	if eof || (!l.inEscape && (next == ' ' || next == ':' || next == '^' || next == '~' || l.isClosing(next))) {
 		// end number
 		l.nextTokenType = tNUMBER
 		l.nextToken = &yySymType{
@@ -282,7 +322,7 @@ func inNumOrStrState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
 		l.reset()
 
 		consumed := true
-		if !eof && (next == ':' || next == '^' || next == '~') {
+		if !eof && next != ' ' {
 			consumed = false
 		}
 
@@ -316,10 +356,12 @@ func inNumOrStrState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
 }
 
 func inStrState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
-	// end on non-escaped space, colon, tilde, boost (or eof)
-	if eof || (!l.inEscape && (next == ' ' || next == ':' || next == '^' || next == '~')) {
+	// end on non-escaped space, colon, tilde, boost, closing bracket
+	// outside of a regexp (or eof)
+	if eof || (!l.inEscape && (next == ' ' || next == ':' || next == '^' || next == '~' ||
+		(l.isClosing(next) && !l.inRegexp()))) {
 		// end string
-		l.nextTokenType = tSTRING
+		l.nextTokenType = l.keywordType()
 		l.nextToken = &yySymType{
 			s: l.buf,
 		}
@@ -327,7 +369,7 @@ func inStrState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
 		l.reset()
 
 		consumed := true
-		if !eof && (next == ':' || next == '^' || next == '~') {
+		if !eof && next != ' ' {
 			consumed = false
 		}
 
@@ -345,6 +387,49 @@ func inStrState(l *queryStringLex, next rune, eof bool) (lexState, bool) {
 	return inStrState, true
 }
 
+// keywordType returns the token type of the string in the buffer,
+// recognizing the boolean operators, and TO within a range.
+func (l *queryStringLex) keywordType() int {
+	if !l.booleanSyntax {
+		return tSTRING
+	}
+	switch l.buf {
+	case "AND", "&&":
+		return tAND
+	case "OR", "||":
+		return tOR
+	case "NOT":
+		return tNOT
+	case "TO":
+		if l.inRange {
+			return tTO
+		}
+	}
+	return tSTRING
+}
+
+// inRegexp reports whether the buffer holds an unterminated
+// regexp, within which brackets do not end the string.
+func (l *queryStringLex) inRegexp() bool {
+	return strings.HasPrefix(l.buf, "/") &&
+		(len(l.buf) == 1 || !strings.HasSuffix(l.buf, "/"))
+}
+
+// isClosing reports whether r closes an open group or range,
+// elsewhere it is part of a term.
+func (l *queryStringLex) isClosing(r rune) bool {
+	if !l.booleanSyntax {
+		return false
+	}
+	switch r {
+	case ')':
+		return l.depth > 0
+	case ']', '}':
+		return l.inRange
+	}
+	return false
+}
+
 func logDebugTokens(format string, v ...interface{}) {
 	if debugLexer {
 		logger.Printf(format, v...)
@@ -9,8 +9,9 @@ import (
 func TestLexer(t *testing.T) {
 
 	tests := []struct {
-		input  string
-		tokens []token
+		input         string
+		booleanSyntax bool
+		tokens        []token
 	}{
 		{
 			input: "test",
@@ -1198,14 +1199,211 @@ func TestLexer(t *testing.T) {
 				},
 			},
 		},
+		{
+			input:         "a AND (b OR c)",
+			booleanSyntax: true,
+			tokens: []token{
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "a",
+					},
+				},
+				{
+					typ: tAND,
+					lval: yySymType{
+						s: "AND",
+					},
+				},
+				{
+					typ: tLPAREN,
+				},
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "b",
+					},
+				},
+				{
+					typ: tOR,
+					lval: yySymType{
+						s: "OR",
+					},
+				},
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "c",
+					},
+				},
+				{
+					typ: tRPAREN,
+				},
+			},
+		},
+		{
+			input:         "age:{18 TO *]",
+			booleanSyntax: true,
+			tokens: []token{
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "age",
+					},
+				},
+				{
+					typ: tCOLON,
+				},
+				{
+					typ: tLBRACE,
+				},
+				{
+					typ: tNUMBER,
+					lval: yySymType{
+						s: "18",
+					},
+				},
+				{
+					typ: tTO,
+					lval: yySymType{
+						s: "TO",
+					},
+				},
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "*",
+					},
+				},
+				{
+					typ: tRBRACKET,
+				},
+			},
+		},
+		{
+			input:         "TO /a[b]/",
+			booleanSyntax: true,
+			tokens: []token{
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "TO",
+					},
+				},
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "/a[b]/",
+					},
+				},
+			},
+		},
+		// brackets only close a group or range
+		{
+			input:         "(a b) c)",
+			booleanSyntax: true,
+			tokens: []token{
+				{
+					typ: tLPAREN,
+				},
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "a",
+					},
+				},
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "b",
+					},
+				},
+				{
+					typ: tRPAREN,
+				},
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "c)",
+					},
+				},
+			},
+		},
+		// without the boolean syntax these are all terms
+		{
+			input: "a AND (b OR c)",
+			tokens: []token{
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "a",
+					},
+				},
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "AND",
+					},
+				},
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "(b",
+					},
+				},
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "OR",
+					},
+				},
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "c)",
+					},
+				},
+			},
+		},
+		{
+			input: "age:[18 TO *}",
+			tokens: []token{
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "age",
+					},
+				},
+				{
+					typ: tCOLON,
+				},
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "[18",
+					},
+				},
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "TO",
+					},
+				},
+				{
+					typ: tSTRING,
+					lval: yySymType{
+						s: "*}",
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
 		test := test
 		t.Run(test.input, func(t *testing.T) {
-
 			r := strings.NewReader(test.input)
-			l := getQueryStringLex(r)
+			l := getQueryStringLex(r, test.booleanSyntax)
 			defer putQueryStringLex(l)
 
 			var tokens []token
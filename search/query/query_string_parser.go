@@ -27,17 +27,23 @@ package query
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 )
 
 var debugParser bool
 var debugLexer bool
 
-func parseQuerySyntax(query string) (rq Query, err error) {
+// queryStringExistsField is the pseudo field of the query string
+// syntax _exists_:field, matching documents with the field.
+const queryStringExistsField = "_exists_"
+
+func parseQuerySyntax(query string, booleanSyntax bool) (rq Query, err error) {
 	if query == "" {
 		return NewMatchNoneQuery(), nil
 	}
-	qsl := getQueryStringLex(strings.NewReader(query))
+	qsl := getQueryStringLex(strings.NewReader(query), booleanSyntax)
 	defer putQueryStringLex(qsl)
 	lex := newLexerWrapper(qsl)
 	doParse(lex)
@@ -85,3 +91,196 @@ func (l *lexerWrapper) Lex(lval *yySymType) int {
 func (l *lexerWrapper) Error(s string) {
 	l.errs = append(l.errs, s)
 }
+
+// queryStringClause is a query along with how it occurs
+// in the enclosing boolean query.
+type queryStringClause struct {
+	occur int
+	q     Query
+}
+
+func (q *BooleanQuery) addClause(c queryStringClause) {
+	switch c.occur {
+	case queryShould:
+		q.AddShould(c.q)
+	case queryMust:
+		q.AddMust(c.q)
+	case queryMustNot:
+		q.AddMustNot(c.q)
+	}
+}
+
+// conjunctionClause combines clauses joined by AND, requiring all
+// of them other than those excluded.  Like the clauses it combines,
+// the conjunction is required in the enclosing boolean query.
+func conjunctionClause(cs []queryStringClause) queryStringClause {
+	if len(cs) == 1 {
+		return cs[0]
+	}
+	q := NewBooleanQueryForQueryString(nil, nil, nil)
+	for _, c := range cs {
+		if c.occur == queryMustNot {
+			q.AddMustNot(c.q)
+		} else {
+			q.AddMust(c.q)
+		}
+	}
+	return queryStringClause{occur: queryMust, q: q}
+}
+
+// disjunctionClause combines clauses joined by OR, requiring any one of
+// them, where an excluded clause matches the documents it excludes.
+func disjunctionClause(cs []queryStringClause) queryStringClause {
+	if len(cs) == 1 {
+		return cs[0]
+	}
+	q := NewBooleanQueryForQueryString(nil, nil, nil)
+	for _, c := range cs {
+		if c.occur == queryMustNot {
+			q.AddShould(NewBooleanQueryForQueryString(nil, nil, []Query{c.q}))
+		} else {
+			q.AddShould(c.q)
+		}
+	}
+	return queryStringClause{occur: queryShould, q: q}
+}
+
+// setQueryStringField sets the field of the queries within
+// a group which were not given a field of their own.
+func setQueryStringField(q Query, field string) {
+	switch q := q.(type) {
+	case *BooleanQuery:
+		setQueryStringField(q.Must, field)
+		setQueryStringField(q.Should, field)
+		setQueryStringField(q.MustNot, field)
+		setQueryStringField(q.Filter, field)
+	case *ConjunctionQuery:
+		for _, cq := range q.Conjuncts {
+			setQueryStringField(cq, field)
+		}
+	case *DisjunctionQuery:
+		for _, dq := range q.Disjuncts {
+			setQueryStringField(dq, field)
+		}
+	case FieldableQuery:
+		if q.Field() == "" {
+			q.SetField(field)
+		}
+	}
+}
+
+// queryStringNumericTerms returns a query for a group of numbers joined
+// by OR, as in field:(1 OR 5 OR 9), matching the numbers as text, as for
+// each number alone, or exactly with a single numeric terms query, in
+// place of a range query per number.  Groups of anything else are
+// returned as they are.
+func queryStringNumericTerms(group Query) Query {
+	matches, numbers, ok := queryStringNumbers(group, nil, nil)
+	if !ok || len(numbers) == 0 {
+		return group
+	}
+	q := NewDisjunctionQuery(append(matches, NewNumericTermsQuery(numbers)))
+	q.queryStringMode = true
+	return q
+}
+
+// queryStringNumbers appends the text and value of each number of
+// disjunctions of numbers given no field or boost of their own,
+// reporting whether the query is made of nothing else.
+func queryStringNumbers(q Query, matches []Query, numbers []float64) ([]Query, []float64, bool) {
+	switch q := q.(type) {
+	case *BooleanQuery:
+		if q.Must != nil || q.MustNot != nil || q.Filter != nil || q.BoostVal != nil {
+			return nil, nil, false
+		}
+		return queryStringNumbers(q.Should, matches, numbers)
+	case *DisjunctionQuery:
+		if !q.queryStringMode || q.Min != 0 || q.BoostVal != nil {
+			return nil, nil, false
+		}
+		if len(q.Disjuncts) == 2 {
+			mq, isMatch := q.Disjuncts[0].(*MatchQuery)
+			nq, isNumeric := q.Disjuncts[1].(*NumericRangeQuery)
+			if isMatch && isNumeric {
+				if mq.FieldVal != "" || nq.FieldVal != "" ||
+					nq.Min == nil || nq.Max == nil || *nq.Min != *nq.Max {
+					return nil, nil, false
+				}
+				return append(matches, mq), append(numbers, *nq.Min), true
+			}
+		}
+		var ok bool
+		for _, disjunct := range q.Disjuncts {
+			matches, numbers, ok = queryStringNumbers(disjunct, matches, numbers)
+			if !ok {
+				return nil, nil, false
+			}
+		}
+		return matches, numbers, true
+	}
+	return nil, nil, false
+}
+
+// queryStringRangeBound is a bound of a bracket range,
+// where * leaves the range open.
+type queryStringRangeBound struct {
+	value   string
+	numeric bool
+	open    bool
+}
+
+// newQueryStringRangeQuery returns a numeric range query when the bounds
+// are numbers, a date range query when they are dates, and otherwise a
+// term range query.  A range open at both ends matches any value.
+func newQueryStringRangeQuery(field string, min, max queryStringRangeBound,
+	minInclusive, maxInclusive bool) (Query, error) {
+	if min.open && max.open {
+		return NewExistsQuery(field), nil
+	}
+
+	if (min.open || min.numeric) && (max.open || max.numeric) {
+		var minVal, maxVal *float64
+		if !min.open {
+			val, err := strconv.ParseFloat(min.value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing number: %v", err)
+			}
+			minVal = &val
+		}
+		if !max.open {
+			val, err := strconv.ParseFloat(max.value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing number: %v", err)
+			}
+			maxVal = &val
+		}
+		q := NewNumericRangeInclusiveQuery(minVal, maxVal, &minInclusive, &maxInclusive)
+		q.SetField(field)
+		return q, nil
+	}
+
+	var minTime, maxTime time.Time
+	var err error
+	if !min.open {
+		minTime, err = queryTimeFromString(min.value)
+	}
+	if err == nil && !max.open {
+		maxTime, err = queryTimeFromString(max.value)
+	}
+	if err == nil {
+		q := NewDateRangeInclusiveQuery(minTime, maxTime, &minInclusive, &maxInclusive)
+		q.SetField(field)
+		return q, nil
+	}
+
+	var minTerm, maxTerm string
+	if !min.open {
+		minTerm = min.value
+	}
+	if !max.open {
+		maxTerm = max.value
+	}
+	q := NewTermRangeInclusiveQuery(minTerm, maxTerm, &minInclusive, &maxInclusive)
+	q.SetField(field)
+	return q, nil
+}
@@ -28,6 +28,8 @@ func TestQuerySyntaxParserValid(t *testing.T) {
 	twoPointOh := 2.0
 	fivePointOh := 5.0
 	minusFivePointOh := -5.0
+	eighteen := 18.0
+	sixtyFive := 65.0
 	theTruth := true
 	theFalsehood := false
 	theDate, err := time.Parse(time.RFC3339, "2006-01-02T15:04:05Z")
@@ -35,9 +37,10 @@ func TestQuerySyntaxParserValid(t *testing.T) {
 		t.Fatal(err)
 	}
 	tests := []struct {
-		input   string
-		result  Query
-		mapping mapping.IndexMapping
+		input         string
+		booleanSyntax bool
+		result        Query
+		mapping       mapping.IndexMapping
 	}{
 		{
 			input:   "test",
@@ -870,6 +873,297 @@ func TestQuerySyntaxParserValid(t *testing.T) {
 				},
 				nil),
 		},
+		// AND binds tighter than OR
+		{
+			input:         `a AND b OR c`,
+			booleanSyntax: true,
+			mapping:       mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				nil,
+				[]Query{
+					NewBooleanQueryForQueryString(
+						nil,
+						[]Query{
+							NewBooleanQueryForQueryString(
+								[]Query{
+									NewMatchQuery("a"),
+									NewMatchQuery("b"),
+								},
+								nil,
+								nil),
+							NewMatchQuery("c"),
+						},
+						nil),
+				},
+				nil),
+		},
+		{
+			input:         `x a AND NOT b`,
+			booleanSyntax: true,
+			mapping:       mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				[]Query{
+					NewBooleanQueryForQueryString(
+						[]Query{
+							NewMatchQuery("a"),
+						},
+						nil,
+						[]Query{
+							NewMatchQuery("b"),
+						}),
+				},
+				[]Query{
+					NewMatchQuery("x"),
+				},
+				nil),
+		},
+		{
+			input:         `a OR -b`,
+			booleanSyntax: true,
+			mapping:       mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				nil,
+				[]Query{
+					NewBooleanQueryForQueryString(
+						nil,
+						[]Query{
+							NewMatchQuery("a"),
+							NewBooleanQueryForQueryString(
+								nil,
+								nil,
+								[]Query{
+									NewMatchQuery("b"),
+								}),
+						},
+						nil),
+				},
+				nil),
+		},
+		// grouping
+		{
+			input:         `(a OR b)^2 +c`,
+			booleanSyntax: true,
+			mapping:       mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				[]Query{
+					NewMatchQuery("c"),
+				},
+				[]Query{
+					func() Query {
+						q := NewBooleanQueryForQueryString(
+							nil,
+							[]Query{
+								NewBooleanQueryForQueryString(
+									nil,
+									[]Query{
+										NewMatchQuery("a"),
+										NewMatchQuery("b"),
+									},
+									nil),
+							},
+							nil)
+						q.SetBoost(2)
+						return q
+					}(),
+				},
+				nil),
+		},
+		{
+			input:         `title:(a other:b -"c d")`,
+			booleanSyntax: true,
+			mapping:       mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				nil,
+				[]Query{
+					NewBooleanQueryForQueryString(
+						nil,
+						[]Query{
+							func() Query {
+								q := NewMatchQuery("a")
+								q.SetField("title")
+								return q
+							}(),
+							func() Query {
+								q := NewMatchQuery("b")
+								q.SetField("other")
+								return q
+							}(),
+						},
+						[]Query{
+							func() Query {
+								q := NewMatchPhraseQuery("c d")
+								q.SetField("title")
+								return q
+							}(),
+						}),
+				},
+				nil),
+		},
+		// proximity
+		{
+			input:   `title:"quick fox"~3`,
+			mapping: mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				nil,
+				[]Query{
+					func() Query {
+						q := NewMatchPhraseQuery("quick fox")
+						q.SetSlop(3)
+						q.SetField("title")
+						return q
+					}(),
+				},
+				nil),
+		},
+		// bracket ranges
+		{
+			input:         `age:[18 TO 65}`,
+			booleanSyntax: true,
+			mapping:       mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				nil,
+				[]Query{
+					func() Query {
+						q := NewNumericRangeInclusiveQuery(&eighteen, &sixtyFive, &theTruth, &theFalsehood)
+						q.SetField("age")
+						return q
+					}(),
+				},
+				nil),
+		},
+		{
+			input:         `age:{-5 TO *]`,
+			booleanSyntax: true,
+			mapping:       mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				nil,
+				[]Query{
+					func() Query {
+						q := NewNumericRangeInclusiveQuery(&minusFivePointOh, nil, &theFalsehood, &theTruth)
+						q.SetField("age")
+						return q
+					}(),
+				},
+				nil),
+		},
+		{
+			input:         `age:[* TO *]`,
+			booleanSyntax: true,
+			mapping:       mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				nil,
+				[]Query{
+					NewExistsQuery("age"),
+				},
+				nil),
+		},
+		{
+			input:         `age:(1 OR 5 7)`,
+			booleanSyntax: true,
+			mapping:       mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				nil,
+				[]Query{
+					func() Query {
+						var disjuncts []Query
+						for _, number := range []string{"1", "5", "7"} {
+							mq := NewMatchQuery(number)
+							mq.SetField("age")
+							disjuncts = append(disjuncts, mq)
+						}
+						tq := NewNumericTermsQuery([]float64{1, 5, 7})
+						tq.SetField("age")
+						q := NewDisjunctionQuery(append(disjuncts, tq))
+						q.queryStringMode = true
+						return q
+					}(),
+				},
+				nil),
+		},
+		{
+			input:   `+_exists_:name -_exists_:age`,
+			mapping: mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				[]Query{
+					NewExistsQuery("name"),
+				},
+				nil,
+				[]Query{
+					NewExistsQuery("age"),
+				}),
+		},
+		{
+			input:         `created:[* TO "2006-01-02T15:04:05Z"]`,
+			booleanSyntax: true,
+			mapping:       mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				nil,
+				[]Query{
+					func() Query {
+						q := NewDateRangeInclusiveQuery(time.Time{}, theDate, &theTruth, &theTruth)
+						q.SetField("created")
+						return q
+					}(),
+				},
+				nil),
+		},
+		{
+			input:         `name:{alpha TO omega]`,
+			booleanSyntax: true,
+			mapping:       mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				nil,
+				[]Query{
+					func() Query {
+						q := NewTermRangeInclusiveQuery("alpha", "omega", &theFalsehood, &theTruth)
+						q.SetField("name")
+						return q
+					}(),
+				},
+				nil),
+		},
+		// brackets only close a group or range
+		{
+			input:         `a b)`,
+			booleanSyntax: true,
+			mapping:       mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				nil,
+				[]Query{
+					NewMatchQuery("a"),
+					NewMatchQuery("b)"),
+				},
+				nil),
+		},
+		// the operators are upper case
+		{
+			input:         `a and b`,
+			booleanSyntax: true,
+			mapping:       mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				nil,
+				[]Query{
+					NewMatchQuery("a"),
+					NewMatchQuery("and"),
+					NewMatchQuery("b"),
+				},
+				nil),
+		},
+		// without the boolean syntax these are all terms
+		{
+			input:   `foo) AND [a TO b]`,
+			mapping: mapping.NewIndexMapping(),
+			result: NewBooleanQueryForQueryString(
+				nil,
+				[]Query{
+					NewMatchQuery("foo)"),
+					NewMatchQuery("AND"),
+					NewMatchQuery("[a"),
+					NewMatchQuery("TO"),
+					NewMatchQuery("b]"),
+				},
+				nil),
+		},
 	}
 
 	// turn on lexer debugging
@@ -878,8 +1172,7 @@ func TestQuerySyntaxParserValid(t *testing.T) {
 	// logger = log.New(os.Stderr, "bleve ", log.LstdFlags)
 
 	for _, test := range tests {
-
-		q, err := parseQuerySyntax(test.input)
+		q, err := parseQuerySyntax(test.input, test.booleanSyntax)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -922,19 +1215,98 @@ func TestQuerySyntaxParserInvalid(t *testing.T) {
 	// logger = log.New(os.Stderr, "bleve", log.LstdFlags)
 
 	for _, test := range tests {
-		_, err := parseQuerySyntax(test.input)
+		_, err := parseQuerySyntax(test.input, false)
 		if err == nil {
 			t.Errorf("expected error, got nil for `%s`", test.input)
 		}
 	}
 }
 
+func TestQuerySyntaxParserInvalidBooleanSyntax(t *testing.T) {
+	tests := []struct {
+		input string
+	}{
+		{"(a b"},
+		{"a AND"},
+		{"OR b"},
+		{"field:[a TO"},
+		{"field:[a b]"},
+		{"field:[1 TO 2 TO 3]"},
+	}
+
+	for _, test := range tests {
+		_, err := parseQuerySyntax(test.input, true)
+		if err == nil {
+			t.Errorf("expected error, got nil for `%s`", test.input)
+		}
+	}
+}
+
+func TestSetQueryStringField(t *testing.T) {
+	must := NewTermQuery("a")
+	filter := NewTermQuery("b")
+	q := NewBooleanQuery([]Query{must}, nil, nil)
+	q.AddFilter(filter)
+	setQueryStringField(q, "f")
+	if must.Field() != "f" || filter.Field() != "f" {
+		t.Errorf("expected the field set on every clause, got %q and %q", must.Field(), filter.Field())
+	}
+}
+
+func TestQueryStringNumericTerms(t *testing.T) {
+	q := NewQueryStringQuery("rating:(1 OR 5 OR 9)")
+	q.SetBooleanSyntax(true)
+	parsed, err := q.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var numbers []float64
+	var visit func(q Query)
+	visit = func(q Query) {
+		switch q := q.(type) {
+		case *BooleanQuery:
+			visit(q.Must)
+			visit(q.Should)
+			visit(q.MustNot)
+		case *DisjunctionQuery:
+			for _, dq := range q.Disjuncts {
+				visit(dq)
+			}
+		case *ConjunctionQuery:
+			for _, cq := range q.Conjuncts {
+				visit(cq)
+			}
+		case *NumericTermsQuery:
+			if q.Field() != "rating" {
+				t.Errorf("expected numeric terms of rating, got %s", q.Field())
+			}
+			numbers = append(numbers, q.Numbers...)
+		}
+	}
+	visit(parsed)
+	if !reflect.DeepEqual(numbers, []float64{1, 5, 9}) {
+		t.Errorf("expected a numeric terms query of 1, 5 and 9, got %v", numbers)
+	}
+
+	// without the boolean syntax, the group is made of terms
+	q.SetBooleanSyntax(false)
+	parsed, err = q.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	numbers = nil
+	visit(parsed)
+	if numbers != nil {
+		t.Errorf("expected no numeric terms query, got %v", numbers)
+	}
+}
+
 func BenchmarkLexer(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		var tokenTypes []int
 		var tokens []yySymType
 		r := strings.NewReader(`+field4:"test phrase 1"`)
-		l := getQueryStringLex(r)
+		l := getQueryStringLex(r, false)
 
 		var lval yySymType
 		rv := l.Lex(&lval)
@@ -961,7 +1333,7 @@ func BenchmarkLexer_Parallel(b *testing.B) {
 			var tokenTypes []int
 			var tokens []yySymType
 			r := strings.NewReader(`+field4:"test phrase 1"`)
-			l := getQueryStringLex(r)
+			l := getQueryStringLex(r, false)
 
 			var lval yySymType
 			rv := l.Lex(&lval)
@@ -60,6 +60,96 @@ func TestParseQuery(t *testing.T) {
 				return q
 			}(),
 		},
+		{
+			input: []byte(`{"span_not":{"span_near":[{"span_term":"cold","field":"desc"},{"span_term":"beer","field":"desc"}],"slop":1,"in_order":true},"exclude":{"span_term":"warm","field":"desc"},"post":2}`),
+			output: func() Query {
+				term := func(t string) SpanQuery {
+					q := NewSpanTermQuery(t)
+					q.SetField("desc")
+					return q
+				}
+				near := NewSpanNearQuery([]SpanQuery{term("cold"), term("beer")}, 1)
+				near.SetInOrder(true)
+				q := NewSpanNotQuery(near, term("warm"))
+				q.Post = 2
+				return q
+			}(),
+		},
+		{
+			input: []byte(`{"span_or":[{"term":"beer","field":"desc"}]}`),
+			err:   true,
+		},
+		{
+			input: []byte(`{"intervals":{"all_of":[{"match":"cold"},{"any_of":[{"match":"beer"},{"match":"water"}]}],"ordered":true,"max_gaps":2,"filter":{"not_containing":{"match":"warm"}}},"field":"desc"}`),
+			output: func() Query {
+				rule := NewIntervalsAllOfRule(NewIntervalsMatchRule("cold"),
+					NewIntervalsAnyOfRule(NewIntervalsMatchRule("beer"), NewIntervalsMatchRule("water")))
+				rule.SetOrdered(true)
+				rule.SetMaxGaps(2)
+				rule.Filter = &IntervalsFilter{NotContaining: NewIntervalsMatchRule("warm")}
+				q := NewIntervalsQuery(rule)
+				q.SetField("desc")
+				return q
+			}(),
+		},
+		{
+			input: []byte(`{"more_like_this":"cold beer","fields":["desc"],"min_term_freq":1,"max_query_terms":10}`),
+			output: func() Query {
+				q := NewMoreLikeThisQuery("cold beer")
+				q.Fields = []string{"desc"}
+				q.MinTermFreq = 1
+				q.MaxQueryTerms = 10
+				return q
+			}(),
+		},
+		{
+			input:  []byte(`{"like_ids":["a","b"]}`),
+			output: NewMoreLikeThisDocumentsQuery([]string{"a", "b"}),
+		},
+		{
+			input:  []byte(`{"exists":"desc"}`),
+			output: NewExistsQuery("desc"),
+		},
+		{
+			input: []byte(`{"numeric_terms":[1,5.5],"field":"abv"}`),
+			output: func() Query {
+				q := NewNumericTermsQuery([]float64{1, 5.5})
+				q.SetField("abv")
+				return q
+			}(),
+		},
+		{
+			input: []byte(`{"bool_terms":[true],"field":"open"}`),
+			output: func() Query {
+				q := NewBoolTermsQuery([]bool{true})
+				q.SetField("open")
+				return q
+			}(),
+		},
+		{
+			input: []byte(`{"date_terms":["2026-01-05"],"field":"updated","datetime_parser":"dateOptional"}`),
+			output: func() Query {
+				q := NewDateTermsQuery([]string{"2026-01-05"})
+				q.SetField("updated")
+				q.SetDateTimeParser("dateOptional")
+				return q
+			}(),
+		},
+		{
+			input: []byte(`{"function_score":{"query":{"term":"beer","field":"desc"},"functions":[{"filter":{"term":"cold","field":"desc"},"weight":2,"gauss":{"field":"abv","origin":5,"scale":2}}],"score_mode":"sum"}}`),
+			output: func() Query {
+				term := NewTermQuery("beer")
+				term.SetField("desc")
+				filter := NewTermQuery("cold")
+				filter.SetField("desc")
+				f := NewGaussFunction("abv", 5.0, 2.0)
+				f.SetFilter(filter)
+				f.SetWeight(2)
+				q := NewFunctionScoreQuery(term, f)
+				q.SetScoreMode("sum")
+				return q
+			}(),
+		},
 		{
 			input: []byte(`{"match":"beer","field":"desc"}`),
 			output: func() Query {
@@ -158,6 +248,14 @@ func TestParseQuery(t *testing.T) {
 			input:  []byte(`{"query":"+beer \"light beer\" -devon"}`),
 			output: NewQueryStringQuery(`+beer "light beer" -devon`),
 		},
+		{
+			input: []byte(`{"query":"beer AND (light OR dark)","boolean_syntax":true}`),
+			output: func() Query {
+				q := NewQueryStringQuery(`beer AND (light OR dark)`)
+				q.SetBooleanSyntax(true)
+				return q
+			}(),
+		},
 		{
 			input: []byte(`{"min":5.1,"max":7.1,"field":"desc"}`),
 			output: func() Query {
@@ -1029,6 +1127,16 @@ func TestExtractFields(t *testing.T) {
 					}`,
 			expFields: []string{"_id"},
 		},
+		{
+			query: `{
+						"span_near": [
+							{"span_term": "a", "field": "title"},
+							{"span_or": [{"span_term": "b", "field": "title"}]}
+						],
+						"slop": 2
+					}`,
+			expFields: []string{"title"},
+		},
 	}
 
 	m := mapping.NewIndexMapping()
@@ -1052,3 +1160,75 @@ func TestExtractFields(t *testing.T) {
 		}
 	}
 }
+
+func TestPercolatorTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		terms []string
+	}{
+		{
+			query: `{"term":"water","field":"desc"}`,
+			terms: []string{"desc\x00water"},
+		},
+		{
+			query: `{"match":"the cold water","field":"desc"}`,
+			terms: []string{"desc\x00cold", "desc\x00water"},
+		},
+		{
+			query: `{"match":"water","field":"desc","fuzziness":1}`,
+			terms: []string{PercolatorAnyTerm},
+		},
+		{
+			query: `"+desc:water +desc:cold +name:beer"`,
+			terms: []string{"desc\x00water"},
+		},
+		{
+			query: `"desc:water name:beer"`,
+			terms: []string{"desc\x00water", "name\x00beer"},
+		},
+		{
+			query: `"desc:water desc:>5"`,
+			terms: []string{PercolatorAnyTerm},
+		},
+		{
+			query: `"desc:water -desc:beer"`,
+			terms: []string{"desc\x00water"},
+		},
+		{
+			query: `{"conjuncts":[{"min":1,"field":"abv"},{"match_phrase":"cold water","field":"desc"}]}`,
+			terms: []string{"desc\x00cold", "desc\x00water"},
+		},
+		{
+			query: `{"match_none":{}}`,
+			terms: []string{},
+		},
+		{
+			query: `{"terms":["","water"],"field":"desc"}`,
+			terms: []string{"desc\x00water"},
+		},
+		{
+			query: `{"terms":[[],["cold","icy"],["water"]],"field":"desc"}`,
+			terms: []string{"desc\x00cold", "desc\x00icy"},
+		},
+		{
+			query: `{"terms":[[],[""]],"field":"desc"}`,
+			terms: []string{PercolatorAnyTerm},
+		},
+	}
+
+	m := mapping.NewIndexMapping()
+	for i, test := range tests {
+		q, err := ParsePercolatorQuery([]byte(test.query))
+		if err != nil {
+			t.Fatal(err)
+		}
+		terms, err := PercolatorTerms(q, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(terms)
+		if !reflect.DeepEqual(terms, test.terms) {
+			t.Errorf("Test %d: expected %q, got %q", i, test.terms, terms)
+		}
+	}
+}
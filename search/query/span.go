@@ -0,0 +1,486 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/searcher"
+	"github.com/blevesearch/bleve/v2/util"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// A SpanQuery matches spans of positions within a single field, and
+// may be nested within other span queries to build up larger spans.
+// The field must have been indexed with IncludeTermVectors set to true.
+// Only the boost of the outermost span query applies.
+type SpanQuery interface {
+	Query
+	// SpanMatcher returns the field the spans are in,
+	// along with the matcher finding them.
+	SpanMatcher(m mapping.IndexMapping) (string, searcher.SpanMatcher, error)
+}
+
+func spanSearcher(ctx context.Context, q SpanQuery, i index.IndexReader, m mapping.IndexMapping,
+	boost float64, options search.SearcherOptions) (search.Searcher, error) {
+	field, matcher, err := q.SpanMatcher(m)
+	if err != nil {
+		return nil, err
+	}
+	return searcher.NewSpanSearcher(ctx, i, matcher, field, boost, options)
+}
+
+// spanMatchers returns the matchers of the clauses,
+// which must all be in the same field.
+func spanMatchers(m mapping.IndexMapping, clauses ...SpanQuery) (string, []searcher.SpanMatcher, error) {
+	var field string
+	rv := make([]searcher.SpanMatcher, 0, len(clauses))
+	for i, clause := range clauses {
+		if clause == nil {
+			return "", nil, fmt.Errorf("span query clause missing")
+		}
+		f, matcher, err := clause.SpanMatcher(m)
+		if err != nil {
+			return "", nil, err
+		}
+		if i > 0 && f != field {
+			return "", nil, fmt.Errorf("span query clauses must be in the same field, "+
+				"found %q and %q", field, f)
+		}
+		field = f
+		rv = append(rv, matcher)
+	}
+	return field, rv, nil
+}
+
+func validateSpanQueries(clauses ...SpanQuery) error {
+	for _, clause := range clauses {
+		if clause == nil {
+			return fmt.Errorf("span query clause missing")
+		}
+		if vq, ok := clause.(ValidatableQuery); ok {
+			if err := vq.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func parseSpanQuery(input json.RawMessage) (SpanQuery, error) {
+	if len(input) == 0 {
+		return nil, nil
+	}
+	q, err := ParseQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	sq, ok := q.(SpanQuery)
+	if !ok {
+		return nil, fmt.Errorf("span query clause must be a span query, got %T", q)
+	}
+	return sq, nil
+}
+
+func parseSpanQueries(input []json.RawMessage) ([]SpanQuery, error) {
+	rv := make([]SpanQuery, 0, len(input))
+	for _, raw := range input {
+		sq, err := parseSpanQuery(raw)
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, sq)
+	}
+	return rv, nil
+}
+
+type SpanTermQuery struct {
+	SpanTerm string `json:"span_term"`
+	FieldVal string `json:"field,omitempty"`
+	BoostVal *Boost `json:"boost,omitempty"`
+}
+
+// NewSpanTermQuery creates a new SpanQuery matching
+// each occurrence of the exact term.
+func NewSpanTermQuery(term string) *SpanTermQuery {
+	return &SpanTermQuery{
+		SpanTerm: term,
+	}
+}
+
+func (q *SpanTermQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *SpanTermQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *SpanTermQuery) SetField(f string) {
+	q.FieldVal = f
+}
+
+func (q *SpanTermQuery) Field() string {
+	return q.FieldVal
+}
+
+func (q *SpanTermQuery) SpanMatcher(m mapping.IndexMapping) (string, searcher.SpanMatcher, error) {
+	field := q.FieldVal
+	if q.FieldVal == "" {
+		field = m.DefaultSearchField()
+	}
+	return field, searcher.NewSpanTerm(q.SpanTerm), nil
+}
+
+func (q *SpanTermQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	return spanSearcher(ctx, q, i, m, q.BoostVal.Value(), options)
+}
+
+type SpanNearQuery struct {
+	Clauses  []SpanQuery `json:"span_near"`
+	Slop     int         `json:"slop"`
+	InOrder  bool        `json:"in_order,omitempty"`
+	BoostVal *Boost      `json:"boost,omitempty"`
+}
+
+// NewSpanNearQuery creates a new SpanQuery matching a span of each
+// clause, which do not overlap, with at most slop positions between
+// them, in any order unless SetInOrder is used.  Searches fail when
+// finding the spans of a document takes more than
+// searcher.SpanNearMaxSteps steps, as can many repeated terms.
+func NewSpanNearQuery(clauses []SpanQuery, slop int) *SpanNearQuery {
+	return &SpanNearQuery{
+		Clauses: clauses,
+		Slop:    slop,
+	}
+}
+
+// SetInOrder requires the spans of the clauses
+// to be in the order of the clauses.
+func (q *SpanNearQuery) SetInOrder(inOrder bool) {
+	q.InOrder = inOrder
+}
+
+func (q *SpanNearQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *SpanNearQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *SpanNearQuery) SpanMatcher(m mapping.IndexMapping) (string, searcher.SpanMatcher, error) {
+	field, matchers, err := spanMatchers(m, q.Clauses...)
+	if err != nil {
+		return "", nil, err
+	}
+	return field, searcher.NewSpanNear(matchers, q.Slop, q.InOrder), nil
+}
+
+func (q *SpanNearQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	return spanSearcher(ctx, q, i, m, q.BoostVal.Value(), options)
+}
+
+func (q *SpanNearQuery) Validate() error {
+	if len(q.Clauses) < 1 {
+		return fmt.Errorf("span near query must contain at least one clause")
+	}
+	if q.Slop < 0 {
+		return fmt.Errorf("span near query slop must not be negative")
+	}
+	return validateSpanQueries(q.Clauses...)
+}
+
+func (q *SpanNearQuery) UnmarshalJSON(data []byte) error {
+	tmp := struct {
+		Clauses  []json.RawMessage `json:"span_near"`
+		Slop     int               `json:"slop"`
+		InOrder  bool              `json:"in_order"`
+		BoostVal *Boost            `json:"boost,omitempty"`
+	}{}
+	err := util.UnmarshalJSON(data, &tmp)
+	if err != nil {
+		return err
+	}
+	q.Clauses, err = parseSpanQueries(tmp.Clauses)
+	if err != nil {
+		return err
+	}
+	q.Slop = tmp.Slop
+	q.InOrder = tmp.InOrder
+	q.BoostVal = tmp.BoostVal
+	return nil
+}
+
+type SpanOrQuery struct {
+	Clauses  []SpanQuery `json:"span_or"`
+	BoostVal *Boost      `json:"boost,omitempty"`
+}
+
+// NewSpanOrQuery creates a new SpanQuery
+// matching the spans of any of the clauses.
+func NewSpanOrQuery(clauses []SpanQuery) *SpanOrQuery {
+	return &SpanOrQuery{
+		Clauses: clauses,
+	}
+}
+
+func (q *SpanOrQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *SpanOrQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *SpanOrQuery) SpanMatcher(m mapping.IndexMapping) (string, searcher.SpanMatcher, error) {
+	field, matchers, err := spanMatchers(m, q.Clauses...)
+	if err != nil {
+		return "", nil, err
+	}
+	return field, searcher.NewSpanOr(matchers), nil
+}
+
+func (q *SpanOrQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	return spanSearcher(ctx, q, i, m, q.BoostVal.Value(), options)
+}
+
+func (q *SpanOrQuery) Validate() error {
+	if len(q.Clauses) < 1 {
+		return fmt.Errorf("span or query must contain at least one clause")
+	}
+	return validateSpanQueries(q.Clauses...)
+}
+
+func (q *SpanOrQuery) UnmarshalJSON(data []byte) error {
+	tmp := struct {
+		Clauses  []json.RawMessage `json:"span_or"`
+		BoostVal *Boost            `json:"boost,omitempty"`
+	}{}
+	err := util.UnmarshalJSON(data, &tmp)
+	if err != nil {
+		return err
+	}
+	q.Clauses, err = parseSpanQueries(tmp.Clauses)
+	if err != nil {
+		return err
+	}
+	q.BoostVal = tmp.BoostVal
+	return nil
+}
+
+type SpanNotQuery struct {
+	Include  SpanQuery `json:"span_not"`
+	Exclude  SpanQuery `json:"exclude"`
+	Pre      int       `json:"pre,omitempty"`
+	Post     int       `json:"post,omitempty"`
+	BoostVal *Boost    `json:"boost,omitempty"`
+}
+
+// NewSpanNotQuery creates a new SpanQuery matching the spans of
+// include which do not overlap a span of exclude.  Pre and Post
+// additionally exclude spans within that many positions after or
+// before a span of exclude.
+func NewSpanNotQuery(include, exclude SpanQuery) *SpanNotQuery {
+	return &SpanNotQuery{
+		Include: include,
+		Exclude: exclude,
+	}
+}
+
+func (q *SpanNotQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *SpanNotQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *SpanNotQuery) SpanMatcher(m mapping.IndexMapping) (string, searcher.SpanMatcher, error) {
+	field, matchers, err := spanMatchers(m, q.Include, q.Exclude)
+	if err != nil {
+		return "", nil, err
+	}
+	return field, searcher.NewSpanNot(matchers[0], matchers[1], q.Pre, q.Post), nil
+}
+
+func (q *SpanNotQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	return spanSearcher(ctx, q, i, m, q.BoostVal.Value(), options)
+}
+
+func (q *SpanNotQuery) Validate() error {
+	if q.Pre < 0 || q.Post < 0 {
+		return fmt.Errorf("span not query pre and post must not be negative")
+	}
+	return validateSpanQueries(q.Include, q.Exclude)
+}
+
+func (q *SpanNotQuery) UnmarshalJSON(data []byte) error {
+	tmp := struct {
+		Include  json.RawMessage `json:"span_not"`
+		Exclude  json.RawMessage `json:"exclude"`
+		Pre      int             `json:"pre"`
+		Post     int             `json:"post"`
+		BoostVal *Boost          `json:"boost,omitempty"`
+	}{}
+	err := util.UnmarshalJSON(data, &tmp)
+	if err != nil {
+		return err
+	}
+	q.Include, err = parseSpanQuery(tmp.Include)
+	if err != nil {
+		return err
+	}
+	q.Exclude, err = parseSpanQuery(tmp.Exclude)
+	if err != nil {
+		return err
+	}
+	q.Pre = tmp.Pre
+	q.Post = tmp.Post
+	q.BoostVal = tmp.BoostVal
+	return nil
+}
+
+type SpanFirstQuery struct {
+	Match    SpanQuery `json:"span_first"`
+	End      int       `json:"end"`
+	BoostVal *Boost    `json:"boost,omitempty"`
+}
+
+// NewSpanFirstQuery creates a new SpanQuery matching the spans
+// of match which end within the first end positions of the field.
+func NewSpanFirstQuery(match SpanQuery, end int) *SpanFirstQuery {
+	return &SpanFirstQuery{
+		Match: match,
+		End:   end,
+	}
+}
+
+func (q *SpanFirstQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *SpanFirstQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *SpanFirstQuery) SpanMatcher(m mapping.IndexMapping) (string, searcher.SpanMatcher, error) {
+	field, matchers, err := spanMatchers(m, q.Match)
+	if err != nil {
+		return "", nil, err
+	}
+	return field, searcher.NewSpanFirst(matchers[0], q.End), nil
+}
+
+func (q *SpanFirstQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	return spanSearcher(ctx, q, i, m, q.BoostVal.Value(), options)
+}
+
+func (q *SpanFirstQuery) Validate() error {
+	if q.End < 1 {
+		return fmt.Errorf("span first query end must be positive")
+	}
+	return validateSpanQueries(q.Match)
+}
+
+func (q *SpanFirstQuery) UnmarshalJSON(data []byte) error {
+	tmp := struct {
+		Match    json.RawMessage `json:"span_first"`
+		End      int             `json:"end"`
+		BoostVal *Boost          `json:"boost,omitempty"`
+	}{}
+	err := util.UnmarshalJSON(data, &tmp)
+	if err != nil {
+		return err
+	}
+	q.Match, err = parseSpanQuery(tmp.Match)
+	if err != nil {
+		return err
+	}
+	q.End = tmp.End
+	q.BoostVal = tmp.BoostVal
+	return nil
+}
+
+type SpanContainingQuery struct {
+	Big      SpanQuery `json:"span_containing"`
+	Little   SpanQuery `json:"little"`
+	BoostVal *Boost    `json:"boost,omitempty"`
+}
+
+// NewSpanContainingQuery creates a new SpanQuery matching
+// the spans of big which contain a span of little.
+func NewSpanContainingQuery(big, little SpanQuery) *SpanContainingQuery {
+	return &SpanContainingQuery{
+		Big:    big,
+		Little: little,
+	}
+}
+
+func (q *SpanContainingQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *SpanContainingQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *SpanContainingQuery) SpanMatcher(m mapping.IndexMapping) (string, searcher.SpanMatcher, error) {
+	field, matchers, err := spanMatchers(m, q.Big, q.Little)
+	if err != nil {
+		return "", nil, err
+	}
+	return field, searcher.NewSpanContaining(matchers[0], matchers[1]), nil
+}
+
+func (q *SpanContainingQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	return spanSearcher(ctx, q, i, m, q.BoostVal.Value(), options)
+}
+
+func (q *SpanContainingQuery) Validate() error {
+	return validateSpanQueries(q.Big, q.Little)
+}
+
+func (q *SpanContainingQuery) UnmarshalJSON(data []byte) error {
+	tmp := struct {
+		Big      json.RawMessage `json:"span_containing"`
+		Little   json.RawMessage `json:"little"`
+		BoostVal *Boost          `json:"boost,omitempty"`
+	}{}
+	err := util.UnmarshalJSON(data, &tmp)
+	if err != nil {
+		return err
+	}
+	q.Big, err = parseSpanQuery(tmp.Big)
+	if err != nil {
+		return err
+	}
+	q.Little, err = parseSpanQuery(tmp.Little)
+	if err != nil {
+		return err
+	}
+	q.BoostVal = tmp.BoostVal
+	return nil
+}
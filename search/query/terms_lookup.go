@@ -0,0 +1,181 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/numeric"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/searcher"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// DefaultTermsLookupMaxTerms is the maximum number of terms a terms
+// lookup query may find, unless it sets a maximum of its own.
+var DefaultTermsLookupMaxTerms = 65536
+
+// TermsLookup identifies the stored field of a document holding
+// terms, in the named index, or the index searched when unnamed.
+type TermsLookup struct {
+	Index string `json:"index,omitempty"`
+	ID    string `json:"id"`
+	Path  string `json:"path"`
+}
+
+// TermsLookupQuery matches documents having any of the terms found in
+// the stored field of another document, such as the authors a user
+// follows.  Text values are used as terms as they are, unanalyzed,
+// while numeric, datetime and boolean values are encoded as they are
+// indexed.  Matches all score the boost, however many terms they have.
+type TermsLookupQuery struct {
+	Lookup   *TermsLookup `json:"terms_lookup"`
+	MaxTerms int          `json:"max_terms,omitempty"`
+	FieldVal string       `json:"field,omitempty"`
+	BoostVal *Boost       `json:"boost,omitempty"`
+}
+
+// NewTermsLookupQuery creates a new Query for finding documents having
+// any of the terms stored at the path of the document with the id.
+func NewTermsLookupQuery(id, path string) *TermsLookupQuery {
+	return &TermsLookupQuery{
+		Lookup: &TermsLookup{
+			ID:   id,
+			Path: path,
+		},
+	}
+}
+
+func (q *TermsLookupQuery) SetBoost(b float64) {
+	boost := Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *TermsLookupQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *TermsLookupQuery) SetField(f string) {
+	q.FieldVal = f
+}
+
+func (q *TermsLookupQuery) Field() string {
+	return q.FieldVal
+}
+
+// SetIndex looks the document up in the named index, which the
+// search must provide with a search.TermsLookupDocumentCallbackFn
+// in its context, under the search.TermsLookupDocumentKey.
+func (q *TermsLookupQuery) SetIndex(indexName string) {
+	q.Lookup.Index = indexName
+}
+
+func (q *TermsLookupQuery) SetMaxTerms(maxTerms int) {
+	q.MaxTerms = maxTerms
+}
+
+func (q *TermsLookupQuery) Validate() error {
+	if q.Lookup == nil || q.Lookup.ID == "" || q.Lookup.Path == "" {
+		return fmt.Errorf("terms lookup query must have a document id and path")
+	}
+	if q.MaxTerms < 0 {
+		return fmt.Errorf("terms lookup query max terms must not be negative")
+	}
+	return nil
+}
+
+func (q *TermsLookupQuery) Searcher(ctx context.Context, i index.IndexReader, m mapping.IndexMapping, options search.SearcherOptions) (search.Searcher, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	terms, err := q.lookupTerms(ctx, i)
+	if err != nil {
+		return nil, err
+	}
+	if len(terms) == 0 {
+		return NewMatchNoneQuery().Searcher(ctx, i, m, options)
+	}
+	field := q.FieldVal
+	if q.FieldVal == "" {
+		field = m.DefaultSearchField()
+	}
+	return searcher.NewConstantScoreMultiTermSearcher(ctx, i, terms, field, q.BoostVal.Value(), options)
+}
+
+// lookupTerms returns the distinct terms of the document,
+// which are none when there is no such document.
+func (q *TermsLookupQuery) lookupTerms(ctx context.Context, i index.IndexReader) ([]string, error) {
+	var doc index.Document
+	var err error
+	if q.Lookup.Index == "" {
+		doc, err = i.Document(q.Lookup.ID)
+	} else if lookup, ok := ctx.Value(search.TermsLookupDocumentKey).(search.TermsLookupDocumentCallbackFn); ok {
+		doc, err = lookup(q.Lookup.Index, q.Lookup.ID)
+	} else {
+		err = fmt.Errorf("terms lookup in index '%s' requires the search to provide the index", q.Lookup.Index)
+	}
+	if err != nil || doc == nil {
+		return nil, err
+	}
+
+	var terms []string
+	seen := make(map[string]struct{})
+	doc.VisitFields(func(field index.Field) {
+		if field.Name() != q.Lookup.Path || err != nil {
+			return
+		}
+		var term string
+		switch field := field.(type) {
+		case index.TextField:
+			term = field.Text()
+		case index.NumericField:
+			var n float64
+			n, err = field.Number()
+			term = string(numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(n), 0))
+		case index.DateTimeField:
+			t, _, terr := field.DateTime()
+			err = terr
+			term = string(numeric.MustNewPrefixCodedInt64(t.UnixNano(), 0))
+		case index.BooleanField:
+			var b bool
+			b, err = field.Boolean()
+			term = "F"
+			if b {
+				term = "T"
+			}
+		default:
+			return
+		}
+		if _, exists := seen[term]; !exists && err == nil {
+			seen[term] = struct{}{}
+			terms = append(terms, term)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	maxTerms := q.MaxTerms
+	if maxTerms == 0 {
+		maxTerms = DefaultTermsLookupMaxTerms
+	}
+	if len(terms) > maxTerms {
+		return nil, fmt.Errorf("terms lookup of document '%s' found %d terms, more than the maximum of %d",
+			q.Lookup.ID, len(terms), maxTerms)
+	}
+	return terms, nil
+}
@@ -0,0 +1,827 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// runtimeKind is the kind of value of an expression,
+// known when the expression is parsed.
+type runtimeKind int
+
+const (
+	runtimeNumber runtimeKind = iota + 1
+	runtimeText
+	runtimeDateTime
+	runtimeBoolean
+)
+
+func (k runtimeKind) String() string {
+	switch k {
+	case runtimeNumber:
+		return "number"
+	case runtimeText:
+		return "text"
+	case runtimeDateTime:
+		return "datetime"
+	case runtimeBoolean:
+		return "boolean"
+	}
+	return "unknown"
+}
+
+// runtimeValue is the value of an expression for a document, holding
+// the member for the kind of the expression, unless the value is
+// missing, as when a field it uses has no value for the document.
+type runtimeValue struct {
+	ok  bool
+	num float64
+	str string
+	t   time.Time
+	b   bool
+}
+
+func runtimeNumberValue(n float64) runtimeValue {
+	// values such as the square root of a negative number are missing
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return runtimeValue{}
+	}
+	return runtimeValue{ok: true, num: n}
+}
+
+func runtimeTextValue(s string) runtimeValue {
+	return runtimeValue{ok: true, str: s}
+}
+
+func runtimeBooleanValue(b bool) runtimeValue {
+	return runtimeValue{ok: true, b: b}
+}
+
+// text returns the value, of the kind, as text.
+func (v runtimeValue) text(k runtimeKind) string {
+	switch k {
+	case runtimeNumber:
+		return strconv.FormatFloat(v.num, 'f', -1, 64)
+	case runtimeDateTime:
+		return v.t.Format(time.RFC3339)
+	case runtimeBoolean:
+		return strconv.FormatBool(v.b)
+	}
+	return v.str
+}
+
+// runtimeExpr is a parsed expression, evaluated against
+// the values of the indexed fields it uses for a document.
+type runtimeExpr interface {
+	kind() runtimeKind
+	eval(fields []runtimeValue) runtimeValue
+}
+
+type runtimeLiteral struct {
+	k runtimeKind
+	v runtimeValue
+}
+
+func (e *runtimeLiteral) kind() runtimeKind { return e.k }
+
+func (e *runtimeLiteral) eval(fields []runtimeValue) runtimeValue { return e.v }
+
+type runtimeFieldRef struct {
+	k     runtimeKind
+	index int
+}
+
+func (e *runtimeFieldRef) kind() runtimeKind { return e.k }
+
+func (e *runtimeFieldRef) eval(fields []runtimeValue) runtimeValue { return fields[e.index] }
+
+type runtimeUnary struct {
+	op string
+	x  runtimeExpr
+}
+
+func (e *runtimeUnary) kind() runtimeKind { return e.x.kind() }
+
+func (e *runtimeUnary) eval(fields []runtimeValue) runtimeValue {
+	x := e.x.eval(fields)
+	if !x.ok {
+		return x
+	}
+	if e.op == "-" {
+		return runtimeNumberValue(-x.num)
+	}
+	return runtimeBooleanValue(!x.b)
+}
+
+type runtimeBinary struct {
+	op   string
+	k    runtimeKind
+	x, y runtimeExpr
+}
+
+func (e *runtimeBinary) kind() runtimeKind { return e.k }
+
+func (e *runtimeBinary) eval(fields []runtimeValue) runtimeValue {
+	x := e.x.eval(fields)
+	if !x.ok {
+		return x
+	}
+	// the right of a logical operator is only needed
+	// when the left does not decide the result
+	if (e.op == "&&" && !x.b) || (e.op == "||" && x.b) {
+		return x
+	}
+	y := e.y.eval(fields)
+	if !y.ok {
+		return y
+	}
+
+	switch e.op {
+	case "&&", "||":
+		return y
+	case "+":
+		if e.k == runtimeText {
+			return runtimeTextValue(x.text(e.x.kind()) + y.text(e.y.kind()))
+		}
+		return runtimeNumberValue(x.num + y.num)
+	case "-":
+		return runtimeNumberValue(x.num - y.num)
+	case "*":
+		return runtimeNumberValue(x.num * y.num)
+	case "/":
+		return runtimeNumberValue(x.num / y.num)
+	case "%":
+		return runtimeNumberValue(math.Mod(x.num, y.num))
+	}
+
+	var cmp int
+	switch e.x.kind() {
+	case runtimeNumber:
+		cmp = compareFloat64(x.num, y.num)
+	case runtimeText:
+		cmp = strings.Compare(x.str, y.str)
+	case runtimeDateTime:
+		cmp = x.t.Compare(y.t)
+	case runtimeBoolean:
+		if x.b != y.b {
+			cmp = 1
+		}
+	}
+	switch e.op {
+	case "==":
+		return runtimeBooleanValue(cmp == 0)
+	case "!=":
+		return runtimeBooleanValue(cmp != 0)
+	case "<":
+		return runtimeBooleanValue(cmp < 0)
+	case "<=":
+		return runtimeBooleanValue(cmp <= 0)
+	case ">":
+		return runtimeBooleanValue(cmp > 0)
+	}
+	return runtimeBooleanValue(cmp >= 0)
+}
+
+func compareFloat64(x, y float64) int {
+	if x < y {
+		return -1
+	} else if x > y {
+		return 1
+	}
+	return 0
+}
+
+// runtimeCall is a call of a function whose
+// value is missing when any argument is.
+type runtimeCall struct {
+	k    runtimeKind
+	fn   func(args []runtimeValue) runtimeValue
+	args []runtimeExpr
+}
+
+func (e *runtimeCall) kind() runtimeKind { return e.k }
+
+func (e *runtimeCall) eval(fields []runtimeValue) runtimeValue {
+	var buf [4]runtimeValue
+	args := buf[:0]
+	for _, arg := range e.args {
+		v := arg.eval(fields)
+		if !v.ok {
+			return v
+		}
+		args = append(args, v)
+	}
+	return e.fn(args)
+}
+
+// runtimeDatePart is a call of a function extracting
+// part of a datetime, in the time zone of the call.
+type runtimeDatePart struct {
+	k       runtimeKind
+	x       runtimeExpr
+	loc     *time.Location
+	extract func(t time.Time) runtimeValue
+}
+
+func (e *runtimeDatePart) kind() runtimeKind { return e.k }
+
+func (e *runtimeDatePart) eval(fields []runtimeValue) runtimeValue {
+	x := e.x.eval(fields)
+	if !x.ok {
+		return x
+	}
+	return e.extract(x.t.In(e.loc))
+}
+
+type runtimeIf struct {
+	cond, then, els runtimeExpr
+}
+
+func (e *runtimeIf) kind() runtimeKind { return e.then.kind() }
+
+func (e *runtimeIf) eval(fields []runtimeValue) runtimeValue {
+	cond := e.cond.eval(fields)
+	if !cond.ok {
+		return cond
+	}
+	if cond.b {
+		return e.then.eval(fields)
+	}
+	return e.els.eval(fields)
+}
+
+type runtimeCoalesce struct {
+	args []runtimeExpr
+}
+
+func (e *runtimeCoalesce) kind() runtimeKind { return e.args[0].kind() }
+
+func (e *runtimeCoalesce) eval(fields []runtimeValue) runtimeValue {
+	for _, arg := range e.args {
+		if v := arg.eval(fields); v.ok {
+			return v
+		}
+	}
+	return runtimeValue{}
+}
+
+// runtimeFunction is a function taking arguments of the
+// params, the last of which may be repeated when variadic.
+type runtimeFunction struct {
+	params   []runtimeKind
+	variadic bool
+	result   runtimeKind
+	fn       func(args []runtimeValue) runtimeValue
+}
+
+func numberFunction(fn func(float64) float64) *runtimeFunction {
+	return &runtimeFunction{
+		params: []runtimeKind{runtimeNumber},
+		result: runtimeNumber,
+		fn: func(args []runtimeValue) runtimeValue {
+			return runtimeNumberValue(fn(args[0].num))
+		},
+	}
+}
+
+func textFunction(result runtimeKind, fn func(string) runtimeValue) *runtimeFunction {
+	return &runtimeFunction{
+		params: []runtimeKind{runtimeText},
+		result: result,
+		fn: func(args []runtimeValue) runtimeValue {
+			return fn(args[0].str)
+		},
+	}
+}
+
+func extremeFunction(better func(x, y float64) bool) *runtimeFunction {
+	return &runtimeFunction{
+		params:   []runtimeKind{runtimeNumber},
+		variadic: true,
+		result:   runtimeNumber,
+		fn: func(args []runtimeValue) runtimeValue {
+			rv := args[0]
+			for _, arg := range args[1:] {
+				if better(arg.num, rv.num) {
+					rv = arg
+				}
+			}
+			return rv
+		},
+	}
+}
+
+var runtimeFunctions = map[string]*runtimeFunction{
+	"abs":   numberFunction(math.Abs),
+	"ceil":  numberFunction(math.Ceil),
+	"floor": numberFunction(math.Floor),
+	"round": numberFunction(math.Round),
+	"sqrt":  numberFunction(math.Sqrt),
+	"log":   numberFunction(math.Log),
+	"pow": {
+		params: []runtimeKind{runtimeNumber, runtimeNumber},
+		result: runtimeNumber,
+		fn: func(args []runtimeValue) runtimeValue {
+			return runtimeNumberValue(math.Pow(args[0].num, args[1].num))
+		},
+	},
+	"min": extremeFunction(func(x, y float64) bool { return x < y }),
+	"max": extremeFunction(func(x, y float64) bool { return x > y }),
+	"lower": textFunction(runtimeText, func(s string) runtimeValue {
+		return runtimeTextValue(strings.ToLower(s))
+	}),
+	"upper": textFunction(runtimeText, func(s string) runtimeValue {
+		return runtimeTextValue(strings.ToUpper(s))
+	}),
+	"length": textFunction(runtimeNumber, func(s string) runtimeValue {
+		return runtimeNumberValue(float64(len([]rune(s))))
+	}),
+	"number": textFunction(runtimeNumber, func(s string) runtimeValue {
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return runtimeValue{}
+		}
+		return runtimeNumberValue(n)
+	}),
+}
+
+// runtimeDateParts are the functions extracting part of a datetime,
+// taking the datetime and, optionally, the name of a time zone.
+var runtimeDateParts = map[string]struct {
+	result  runtimeKind
+	extract func(t time.Time) runtimeValue
+}{
+	"year": {runtimeNumber, func(t time.Time) runtimeValue {
+		return runtimeNumberValue(float64(t.Year()))
+	}},
+	"month": {runtimeNumber, func(t time.Time) runtimeValue {
+		return runtimeNumberValue(float64(t.Month()))
+	}},
+	"dayOfMonth": {runtimeNumber, func(t time.Time) runtimeValue {
+		return runtimeNumberValue(float64(t.Day()))
+	}},
+	"dayOfYear": {runtimeNumber, func(t time.Time) runtimeValue {
+		return runtimeNumberValue(float64(t.YearDay()))
+	}},
+	// numbered from 1 for Monday to 7 for Sunday
+	"dayOfWeek": {runtimeNumber, func(t time.Time) runtimeValue {
+		return runtimeNumberValue(float64((int(t.Weekday())+6)%7 + 1))
+	}},
+	"hour": {runtimeNumber, func(t time.Time) runtimeValue {
+		return runtimeNumberValue(float64(t.Hour()))
+	}},
+	"minute": {runtimeNumber, func(t time.Time) runtimeValue {
+		return runtimeNumberValue(float64(t.Minute()))
+	}},
+	"second": {runtimeNumber, func(t time.Time) runtimeValue {
+		return runtimeNumberValue(float64(t.Second()))
+	}},
+	"dayName": {runtimeText, func(t time.Time) runtimeValue {
+		return runtimeTextValue(t.Weekday().String())
+	}},
+	"monthName": {runtimeText, func(t time.Time) runtimeValue {
+		return runtimeTextValue(t.Month().String())
+	}},
+}
+
+var runtimeBinaryPrecedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+type runtimeTokenType int
+
+const (
+	runtimeTokenEOF runtimeTokenType = iota
+	runtimeTokenNumber
+	runtimeTokenString
+	runtimeTokenIdent
+	runtimeTokenField
+	runtimeTokenOp
+)
+
+type runtimeToken struct {
+	typ  runtimeTokenType
+	text string
+	num  float64
+	pos  int
+}
+
+// runtimeParser parses an expression, resolving the kinds
+// of the indexed fields it uses using the field type.
+type runtimeParser struct {
+	input     string
+	pos       int
+	tok       runtimeToken
+	fieldType func(field string) string
+	fields    map[string]int
+	names     []string
+	kinds     []runtimeKind
+}
+
+func newRuntimeParser(fieldType func(field string) string) *runtimeParser {
+	return &runtimeParser{
+		fieldType: fieldType,
+		fields:    make(map[string]int),
+	}
+}
+
+// parse parses the expression, adding the fields it uses
+// to those of the expressions parsed before.
+func (p *runtimeParser) parse(input string) (runtimeExpr, error) {
+	p.input = input
+	p.pos = 0
+	err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	rv, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.typ != runtimeTokenEOF {
+		return nil, p.errorf("unexpected '%s'", p.tok.text)
+	}
+	return rv, nil
+}
+
+func (p *runtimeParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), p.tok.pos)
+}
+
+func (p *runtimeParser) next() error {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	p.tok = runtimeToken{pos: start}
+	if p.pos >= len(p.input) {
+		return nil
+	}
+
+	c := p.input[p.pos]
+	switch {
+	case c >= '0' && c <= '9' || c == '.':
+		for p.pos < len(p.input) && (isRuntimeDigit(p.input[p.pos]) || p.input[p.pos] == '.' ||
+			((p.input[p.pos] == 'e' || p.input[p.pos] == 'E') && p.pos+1 < len(p.input)) ||
+			((p.input[p.pos] == '+' || p.input[p.pos] == '-') &&
+				(p.input[p.pos-1] == 'e' || p.input[p.pos-1] == 'E'))) {
+			p.pos++
+		}
+		p.tok.text = p.input[start:p.pos]
+		n, err := strconv.ParseFloat(p.tok.text, 64)
+		if err != nil {
+			return p.errorf("invalid number '%s'", p.tok.text)
+		}
+		p.tok.typ = runtimeTokenNumber
+		p.tok.num = n
+	case c == '\'' || c == '"':
+		var sb strings.Builder
+		p.pos++
+		for {
+			if p.pos >= len(p.input) {
+				return p.errorf("unterminated string")
+			}
+			r := p.input[p.pos]
+			p.pos++
+			if r == c {
+				break
+			}
+			if r == '\\' && p.pos < len(p.input) {
+				r = p.input[p.pos]
+				p.pos++
+			}
+			sb.WriteByte(r)
+		}
+		p.tok.typ = runtimeTokenString
+		p.tok.text = sb.String()
+	case c == '`':
+		end := strings.IndexByte(p.input[start+1:], '`')
+		if end < 0 {
+			return p.errorf("unterminated field name")
+		}
+		p.tok.typ = runtimeTokenField
+		p.tok.text = p.input[start+1 : start+1+end]
+		p.pos = start + end + 2
+	case isRuntimeIdent(c):
+		for p.pos < len(p.input) && (isRuntimeIdent(p.input[p.pos]) ||
+			isRuntimeDigit(p.input[p.pos]) || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		p.tok.typ = runtimeTokenIdent
+		p.tok.text = p.input[start:p.pos]
+	default:
+		p.tok.typ = runtimeTokenOp
+		for _, op := range []string{"==", "!=", "<=", ">=", "&&", "||"} {
+			if strings.HasPrefix(p.input[start:], op) {
+				p.tok.text = op
+				p.pos += 2
+				return nil
+			}
+		}
+		if !strings.ContainsRune("+-*/%<>!(),", rune(c)) {
+			return p.errorf("unexpected character '%c'", c)
+		}
+		p.tok.text = string(c)
+		p.pos++
+	}
+	return nil
+}
+
+func isRuntimeDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isRuntimeIdent reports whether the byte may start a name, taking
+// any byte of a multi-byte character to be part of the name.
+func isRuntimeIdent(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= utf8.RuneSelf
+}
+
+func (p *runtimeParser) isOp(op string) bool {
+	return p.tok.typ == runtimeTokenOp && p.tok.text == op
+}
+
+func (p *runtimeParser) expectOp(op string) error {
+	if !p.isOp(op) {
+		if p.tok.typ == runtimeTokenEOF {
+			return p.errorf("expected '%s'", op)
+		}
+		return p.errorf("expected '%s', not '%s'", op, p.tok.text)
+	}
+	return p.next()
+}
+
+func (p *runtimeParser) parseBinary(minPrecedence int) (runtimeExpr, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.typ == runtimeTokenOp {
+		op := p.tok.text
+		precedence, ok := runtimeBinaryPrecedence[op]
+		if !ok || precedence < minPrecedence {
+			break
+		}
+		opErr := p.errorf("operator '%s'", op)
+		err = p.next()
+		if err != nil {
+			return nil, err
+		}
+		y, err := p.parseBinary(precedence + 1)
+		if err != nil {
+			return nil, err
+		}
+		k, err := runtimeBinaryKind(op, x.kind(), y.kind())
+		if err != nil {
+			return nil, fmt.Errorf("%v %v", opErr, err)
+		}
+		x = &runtimeBinary{op: op, k: k, x: x, y: y}
+	}
+	return x, nil
+}
+
+// runtimeBinaryKind returns the kind of the result of
+// the operator, for operands of the kinds given.
+func runtimeBinaryKind(op string, x, y runtimeKind) (runtimeKind, error) {
+	switch op {
+	case "&&", "||":
+		if x == runtimeBoolean && y == runtimeBoolean {
+			return runtimeBoolean, nil
+		}
+		return 0, fmt.Errorf("needs booleans, not %s and %s", x, y)
+	case "+":
+		if x == runtimeText || y == runtimeText {
+			return runtimeText, nil
+		}
+		fallthrough
+	case "-", "*", "/", "%":
+		if x == runtimeNumber && y == runtimeNumber {
+			return runtimeNumber, nil
+		}
+		return 0, fmt.Errorf("needs numbers, not %s and %s", x, y)
+	case "<", "<=", ">", ">=":
+		if x == runtimeBoolean {
+			return 0, fmt.Errorf("cannot order booleans")
+		}
+	}
+	if x != y {
+		return 0, fmt.Errorf("cannot compare %s with %s", x, y)
+	}
+	return runtimeBoolean, nil
+}
+
+func (p *runtimeParser) parseUnary() (runtimeExpr, error) {
+	if p.isOp("-") || p.isOp("!") {
+		op := p.tok.text
+		opErr := p.errorf("operator '%s'", op)
+		err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "-" && x.kind() != runtimeNumber {
+			return nil, fmt.Errorf("%v needs a number, not %s", opErr, x.kind())
+		} else if op == "!" && x.kind() != runtimeBoolean {
+			return nil, fmt.Errorf("%v needs a boolean, not %s", opErr, x.kind())
+		}
+		return &runtimeUnary{op: op, x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *runtimeParser) parsePrimary() (runtimeExpr, error) {
+	tok := p.tok
+	switch tok.typ {
+	case runtimeTokenNumber:
+		return &runtimeLiteral{k: runtimeNumber, v: runtimeNumberValue(tok.num)}, p.next()
+	case runtimeTokenString:
+		return &runtimeLiteral{k: runtimeText, v: runtimeTextValue(tok.text)}, p.next()
+	case runtimeTokenField:
+		err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		return p.fieldRef(tok)
+	case runtimeTokenIdent:
+		err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if p.isOp("(") {
+			return p.parseCall(tok)
+		}
+		switch tok.text {
+		case "true", "false":
+			return &runtimeLiteral{k: runtimeBoolean, v: runtimeBooleanValue(tok.text == "true")}, nil
+		}
+		return p.fieldRef(tok)
+	case runtimeTokenOp:
+		if tok.text == "(" {
+			err := p.next()
+			if err != nil {
+				return nil, err
+			}
+			rv, err := p.parseBinary(1)
+			if err != nil {
+				return nil, err
+			}
+			return rv, p.expectOp(")")
+		}
+		return nil, p.errorf("unexpected '%s'", tok.text)
+	}
+	return nil, p.errorf("unexpected end of expression")
+}
+
+// fieldRef returns the reference to the indexed field named by the token.
+func (p *runtimeParser) fieldRef(tok runtimeToken) (runtimeExpr, error) {
+	if index, ok := p.fields[tok.text]; ok {
+		return &runtimeFieldRef{k: p.kinds[index], index: index}, nil
+	}
+	var k runtimeKind
+	switch typ := p.fieldType(tok.text); typ {
+	case "number":
+		k = runtimeNumber
+	case "datetime":
+		k = runtimeDateTime
+	case "boolean":
+		k = runtimeBoolean
+	case "text", "":
+		k = runtimeText
+	default:
+		return nil, fmt.Errorf("field '%s' of type %s cannot be used at position %d", tok.text, typ, tok.pos)
+	}
+	index := len(p.names)
+	p.fields[tok.text] = index
+	p.names = append(p.names, tok.text)
+	p.kinds = append(p.kinds, k)
+	return &runtimeFieldRef{k: k, index: index}, nil
+}
+
+func (p *runtimeParser) parseCall(name runtimeToken) (runtimeExpr, error) {
+	err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	var args []runtimeExpr
+	for !p.isOp(")") {
+		if len(args) > 0 {
+			err = p.expectOp(",")
+			if err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	err = p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	callErr := func(format string, args ...interface{}) error {
+		return fmt.Errorf("function '%s' at position %d %s", name.text, name.pos, fmt.Sprintf(format, args...))
+	}
+	if part, ok := runtimeDateParts[name.text]; ok {
+		if len(args) < 1 || len(args) > 2 {
+			return nil, callErr("takes a datetime and optionally a time zone")
+		}
+		if args[0].kind() != runtimeDateTime {
+			return nil, callErr("needs a datetime, not %s", args[0].kind())
+		}
+		loc := time.UTC
+		if len(args) == 2 {
+			zone, ok := args[1].(*runtimeLiteral)
+			if !ok || zone.k != runtimeText {
+				return nil, callErr("needs the time zone as text")
+			}
+			loc, err = time.LoadLocation(zone.v.str)
+			if err != nil {
+				return nil, callErr("has invalid time zone '%s'", zone.v.str)
+			}
+		}
+		return &runtimeDatePart{k: part.result, x: args[0], loc: loc, extract: part.extract}, nil
+	}
+
+	switch name.text {
+	case "if":
+		if len(args) != 3 {
+			return nil, callErr("takes a condition and two values")
+		}
+		if args[0].kind() != runtimeBoolean {
+			return nil, callErr("needs a boolean condition, not %s", args[0].kind())
+		}
+		if args[1].kind() != args[2].kind() {
+			return nil, callErr("needs values of the same kind, not %s and %s", args[1].kind(), args[2].kind())
+		}
+		return &runtimeIf{cond: args[0], then: args[1], els: args[2]}, nil
+	case "coalesce":
+		if len(args) == 0 {
+			return nil, callErr("needs at least one value")
+		}
+		for _, arg := range args[1:] {
+			if arg.kind() != args[0].kind() {
+				return nil, callErr("needs values of the same kind, not %s and %s", args[0].kind(), arg.kind())
+			}
+		}
+		return &runtimeCoalesce{args: args}, nil
+	case "text":
+		if len(args) != 1 {
+			return nil, callErr("takes one value")
+		}
+		k := args[0].kind()
+		return &runtimeCall{k: runtimeText, args: args, fn: func(args []runtimeValue) runtimeValue {
+			return runtimeTextValue(args[0].text(k))
+		}}, nil
+	}
+
+	fn, ok := runtimeFunctions[name.text]
+	if !ok {
+		return nil, callErr("is unknown")
+	}
+	if len(args) < len(fn.params) || (!fn.variadic && len(args) > len(fn.params)) {
+		if fn.variadic {
+			return nil, callErr("takes at least %d arguments, not %d", len(fn.params), len(args))
+		}
+		return nil, callErr("takes %d arguments, not %d", len(fn.params), len(args))
+	}
+	for i, arg := range args {
+		param := fn.params[len(fn.params)-1]
+		if i < len(fn.params) {
+			param = fn.params[i]
+		}
+		if arg.kind() != param {
+			return nil, callErr("needs a %s, not %s", param, arg.kind())
+		}
+	}
+	return &runtimeCall{k: fn.result, fn: fn.fn, args: args}, nil
+}
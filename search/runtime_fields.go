@@ -0,0 +1,265 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/blevesearch/bleve/v2/numeric"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// RuntimeFields computes fields at search time, from the doc values of
+// indexed fields, using expressions such as:
+//
+//	price * quantity
+//	lower(first) + " " + lower(last)
+//	dayName(created, "Europe/Paris")
+//	if(stock > 0, "available", "sold out")
+//
+// Expressions combine numbers, text in quotes, true and false and the
+// indexed fields, which are named as they are, or in backquotes when
+// the name is not made of letters, digits, underscores and dots.  They
+// support arithmetic, + also joining text, comparisons, && and || and
+// the functions abs, ceil, floor, round, sqrt, log, pow, min, max,
+// lower, upper, length, number, text, if and coalesce, along with year,
+// month, dayOfMonth, dayOfYear, dayOfWeek (1 for Monday to 7 for
+// Sunday), hour, minute, second, dayName and monthName, which take
+// a datetime and optionally the name of a time zone, UTC otherwise.
+//
+// Fields are of the kind of their mapping, text when unmapped, and have
+// the first of their values for the document.  The values of text fields
+// are their terms as analysed, in order, so that a text field has its
+// smallest term, such as "engine" for "Notes on the Engine" analysed by
+// the standard analyzer.  Text fields are meant to be analysed by the
+// keyword analyzer, holding their value as a single term.  The value of a field
+// computed from a field with no value, or from an invalid operation such
+// as a division by zero, is missing.  Computed values are visited as if
+// indexed, so that they can be sorted and faceted like any other field,
+// taking the place of any indexed field of the same name.
+//
+// RuntimeFields visits one document at a time, so are not to be shared.
+type RuntimeFields struct {
+	names   []string
+	exprs   []runtimeExpr
+	byName  map[string]int
+	sources [][]string
+	terms   [][]byte
+
+	fields      map[string]int
+	fieldKinds  []runtimeKind
+	fieldValues []runtimeValue
+
+	next index.DocValueVisitor
+}
+
+// NewRuntimeFields parses the expressions of the runtime fields, keyed
+// by name, with fieldType returning the mapping type of indexed fields.
+func NewRuntimeFields(expressions map[string]string, fieldType func(field string) string) (*RuntimeFields, error) {
+	rv := &RuntimeFields{
+		byName: make(map[string]int, len(expressions)),
+	}
+	for name := range expressions {
+		rv.names = append(rv.names, name)
+	}
+	sort.Strings(rv.names)
+
+	p := newRuntimeParser(fieldType)
+	for i, name := range rv.names {
+		expr, err := p.parse(expressions[name])
+		if err != nil {
+			return nil, fmt.Errorf("runtime field '%s': %v", name, err)
+		}
+		rv.byName[name] = i
+		rv.exprs = append(rv.exprs, expr)
+		rv.terms = append(rv.terms, nil)
+		rv.sources = append(rv.sources, runtimeExprFields(expr, p.names, nil))
+	}
+	rv.fields = p.fields
+	rv.fieldKinds = p.kinds
+	rv.fieldValues = make([]runtimeValue, len(p.names))
+	return rv, nil
+}
+
+// runtimeExprFields appends the names of the indexed fields used by the expression.
+func runtimeExprFields(expr runtimeExpr, names []string, rv []string) []string {
+	var children []runtimeExpr
+	switch e := expr.(type) {
+	case *runtimeFieldRef:
+		for _, name := range rv {
+			if name == names[e.index] {
+				return rv
+			}
+		}
+		return append(rv, names[e.index])
+	case *runtimeUnary:
+		children = []runtimeExpr{e.x}
+	case *runtimeBinary:
+		children = []runtimeExpr{e.x, e.y}
+	case *runtimeCall:
+		children = e.args
+	case *runtimeDatePart:
+		children = []runtimeExpr{e.x}
+	case *runtimeIf:
+		children = []runtimeExpr{e.cond, e.then, e.els}
+	case *runtimeCoalesce:
+		children = e.args
+	}
+	for _, child := range children {
+		rv = runtimeExprFields(child, names, rv)
+	}
+	return rv
+}
+
+// Fields returns the names of the runtime fields, in order.
+func (rf *RuntimeFields) Fields() []string {
+	return rf.names
+}
+
+// Type returns the type of the values of the runtime field, named
+// as mapping types are, or the empty string for no such field.
+func (rf *RuntimeFields) Type(name string) string {
+	if i, ok := rf.byName[name]; ok {
+		return rf.exprs[i].kind().String()
+	}
+	return ""
+}
+
+// RequiredFields returns the fields whose doc values must be visited
+// in place of the fields given, which are the fields the runtime fields
+// among them are computed from, along with the other fields given.
+func (rf *RuntimeFields) RequiredFields(fields []string) []string {
+	var rv []string
+	add := func(field string) {
+		for _, f := range rv {
+			if f == field {
+				return
+			}
+		}
+		rv = append(rv, field)
+	}
+	for _, field := range fields {
+		if i, ok := rf.byName[field]; ok {
+			for _, source := range rf.sources[i] {
+				add(source)
+			}
+		} else {
+			add(field)
+		}
+	}
+	return rv
+}
+
+// StartDoc prepares to visit the doc values of a document, passing
+// them on to next, which may be nil, along with the computed values.
+func (rf *RuntimeFields) StartDoc(next index.DocValueVisitor) {
+	rf.next = next
+	for i := range rf.fieldValues {
+		rf.fieldValues[i] = runtimeValue{}
+	}
+}
+
+// UpdateVisitor is the index.DocValueVisitor, keeping the first value
+// of each field used by the runtime fields, before passing the term on
+// unless the field is hidden by a runtime field of the same name.
+func (rf *RuntimeFields) UpdateVisitor(field string, term []byte) {
+	if i, ok := rf.fields[field]; ok && !rf.fieldValues[i].ok {
+		rf.fieldValues[i] = decodeRuntimeTerm(rf.fieldKinds[i], term)
+	}
+	if _, ok := rf.byName[field]; !ok && rf.next != nil {
+		rf.next(field, term)
+	}
+}
+
+// decodeRuntimeTerm decodes the term of a field of the
+// kind, as it was indexed, unless a term of lower precision.
+func decodeRuntimeTerm(k runtimeKind, term []byte) runtimeValue {
+	switch k {
+	case runtimeNumber, runtimeDateTime:
+		valid, shift := numeric.ValidPrefixCodedTermBytes(term)
+		if !valid || shift != 0 {
+			return runtimeValue{}
+		}
+		i64, err := numeric.PrefixCoded(term).Int64()
+		if err != nil {
+			return runtimeValue{}
+		}
+		if k == runtimeDateTime {
+			return runtimeValue{ok: true, t: time.Unix(0, i64).UTC()}
+		}
+		return runtimeNumberValue(numeric.Int64ToFloat64(i64))
+	case runtimeBoolean:
+		return runtimeBooleanValue(len(term) > 0 && term[0] == 'T')
+	}
+	return runtimeTextValue(string(term))
+}
+
+// EndDoc computes the runtime fields of the document visited,
+// passing the values on to next encoded as they would be indexed.
+func (rf *RuntimeFields) EndDoc() {
+	if rf.next == nil {
+		return
+	}
+	for i, expr := range rf.exprs {
+		v := expr.eval(rf.fieldValues)
+		if !v.ok {
+			continue
+		}
+		// each term is kept until the next document, as
+		// visitors may hold on to the terms until then
+		term := rf.terms[i][:0]
+		prealloc := rf.terms[i][:cap(rf.terms[i])]
+		switch expr.kind() {
+		case runtimeNumber:
+			term = numeric.MustNewPrefixCodedInt64Prealloc(numeric.Float64ToInt64(v.num), 0, prealloc)
+		case runtimeDateTime:
+			term = numeric.MustNewPrefixCodedInt64Prealloc(v.t.UnixNano(), 0, prealloc)
+		case runtimeBoolean:
+			if v.b {
+				term = append(term, 'T')
+			} else {
+				term = append(term, 'F')
+			}
+		default:
+			term = append(term, v.str...)
+		}
+		rf.terms[i] = term
+		rf.next(rf.names[i], term)
+	}
+}
+
+// Value returns the value of the runtime field for the document visited,
+// as for a stored field, or nil when missing or there is no such field.
+func (rf *RuntimeFields) Value(name string) interface{} {
+	i, ok := rf.byName[name]
+	if !ok {
+		return nil
+	}
+	v := rf.exprs[i].eval(rf.fieldValues)
+	if !v.ok {
+		return nil
+	}
+	switch rf.exprs[i].kind() {
+	case runtimeNumber:
+		return v.num
+	case runtimeDateTime:
+		return v.t.Format(time.RFC3339)
+	case runtimeBoolean:
+		return v.b
+	}
+	return v.str
+}
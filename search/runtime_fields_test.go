@@ -0,0 +1,172 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/blevesearch/bleve/v2/numeric"
+)
+
+func TestRuntimeFields(t *testing.T) {
+	fieldTypes := map[string]string{
+		"price":    "number",
+		"quantity": "number",
+		"created":  "datetime",
+		"active":   "boolean",
+		"geo":      "geopoint",
+	}
+	fieldType := func(field string) string {
+		return fieldTypes[field]
+	}
+	// created on Sunday the 4th of January 2026, at 23:30 UTC
+	created := time.Date(2026, 1, 4, 23, 30, 0, 0, time.UTC)
+	doc := map[string][][]byte{
+		"price": {
+			numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(2.5), 0),
+			numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(2.5), 4),
+		},
+		"quantity":  {numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(4), 0)},
+		"created":   {numeric.MustNewPrefixCodedInt64(created.UnixNano(), 0)},
+		"active":    {[]byte("T")},
+		"name":      {[]byte("Widget")},
+		"name.tags": {[]byte("blue"), []byte("red")},
+	}
+
+	tests := []struct {
+		expression string
+		want       interface{}
+		err        bool
+	}{
+		{expression: "price * quantity", want: 10.0},
+		{expression: "-(price + 1.5) / 2 % 3", want: -2.0},
+		{expression: "price + quantity * 2", want: 10.5},
+		{expression: "max(price, quantity, 3) - min(price, 1)", want: 3.0},
+		{expression: "round(price) + abs(-1) + pow(2, 3)", want: 12.0},
+		{expression: "name + ' x' + quantity", want: "Widget x4"},
+		{expression: "lower(name) + `name.tags`", want: "widgetblue"},
+		{expression: "upper(\"it's \\\"so\\\"\")", want: `IT'S "SO"`},
+		{expression: "length(name) + number('1.5')", want: 7.5},
+		{expression: "text(active) + text(created)", want: "true2026-01-04T23:30:00Z"},
+		{expression: "created", want: "2026-01-04T23:30:00Z"},
+		{expression: "dayOfWeek(created)", want: 7.0},
+		{expression: "dayName(created) + ' ' + monthName(created)", want: "Sunday January"},
+		{expression: "dayName(created, 'Europe/Paris')", want: "Monday"},
+		{expression: "year(created) * 10000 + month(created) * 100 + dayOfMonth(created)", want: 20260104.0},
+		{expression: "hour(created) * 60 + minute(created) + second(created) + dayOfYear(created)", want: 1414.0},
+		{expression: "price > 2 && !(quantity == 3) || active", want: true},
+		{expression: "name != 'Widget' || price <= 1", want: false},
+		{expression: "if(price >= 3, 'expensive', 'cheap')", want: "cheap"},
+		{expression: "coalesce(missing, name)", want: "Widget"},
+		{expression: "missing + 1", want: nil},
+		{expression: "price / 0", want: nil},
+		{expression: "sqrt(-price)", want: nil},
+		{expression: "number(name)", want: nil},
+		{expression: "missing == 'x' || true", want: nil},
+		{expression: "price * name", err: true},
+		{expression: "active < true", err: true},
+		{expression: "price == name", err: true},
+		{expression: "if(price, 1, 2)", err: true},
+		{expression: "if(active, 1, 'two')", err: true},
+		{expression: "pow(2)", err: true},
+		{expression: "dayName(price)", err: true},
+		{expression: "dayName(created, 'Nowhere/Special')", err: true},
+		{expression: "unknown(price)", err: true},
+		{expression: "geo", err: true},
+		{expression: "(price", err: true},
+		{expression: "price quantity", err: true},
+		{expression: "'open", err: true},
+		{expression: "price # 2", err: true},
+	}
+
+	for _, test := range tests {
+		rf, err := NewRuntimeFields(map[string]string{"rt": test.expression}, fieldType)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: expected error", test.expression)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.expression, err)
+			continue
+		}
+		visited := make(map[string][]byte)
+		rf.StartDoc(func(field string, term []byte) {
+			visited[field] = append([]byte(nil), term...)
+		})
+		for _, field := range rf.RequiredFields([]string{"rt"}) {
+			for _, term := range doc[field] {
+				rf.UpdateVisitor(field, term)
+			}
+		}
+		rf.EndDoc()
+		got := rf.Value("rt")
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %#v, got %#v", test.expression, test.want, got)
+		}
+		if _, ok := visited["rt"]; ok != (test.want != nil) {
+			t.Errorf("%s: expected visited value %t", test.expression, test.want != nil)
+		}
+	}
+}
+
+func TestRuntimeFieldsVisit(t *testing.T) {
+	rf, err := NewRuntimeFields(map[string]string{
+		"price":  "price * 2",
+		"total":  "price * quantity",
+		"flag":   "quantity > 1",
+		"label":  "'n' + quantity",
+		"hidden": "price",
+	}, func(field string) string {
+		return "number"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	required := rf.RequiredFields([]string{"price", "other", "total"})
+	if !reflect.DeepEqual(required, []string{"price", "other", "quantity"}) {
+		t.Errorf("unexpected required fields %v", required)
+	}
+	if rf.Type("total") != "number" || rf.Type("flag") != "boolean" ||
+		rf.Type("label") != "text" || rf.Type("other") != "" {
+		t.Errorf("unexpected types")
+	}
+
+	visited := make(map[string][]byte)
+	rf.StartDoc(func(field string, term []byte) {
+		visited[field] = append([]byte(nil), term...)
+	})
+	rf.UpdateVisitor("price", numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(3), 0))
+	rf.UpdateVisitor("quantity", numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(2), 0))
+	rf.UpdateVisitor("other", []byte("x"))
+	rf.EndDoc()
+
+	want := map[string][]byte{
+		"price":    numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(6), 0),
+		"total":    numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(6), 0),
+		"hidden":   numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(3), 0),
+		"quantity": numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(2), 0),
+		"flag":     []byte("T"),
+		"label":    []byte("n2"),
+		"other":    []byte("x"),
+	}
+	if !reflect.DeepEqual(visited, want) {
+		t.Errorf("expected visited %v, got %v", want, visited)
+	}
+}
@@ -15,6 +15,7 @@
 package search
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"slices"
@@ -182,6 +183,27 @@ type DocumentMatch struct {
 	// Descendants holds the IDs of any child/descendant document that contributed
 	// to this root DocumentMatch.
 	Descendants []index.IndexInternalID `json:"-"`
+
+	// CollapseKey holds the raw doc value of the collapse field for this
+	// hit when the search request asks for results to be collapsed.
+	// Hits without a value for the field share an empty key.
+	CollapseKey []byte `json:"-"`
+
+	// CollapseValue holds the value of the collapse field for this hit,
+	// decoded from its CollapseKey according to the type of the field.
+	CollapseValue interface{} `json:"collapse_value,omitempty"`
+
+	// InnerHits holds the top hits sharing this hit's CollapseKey, when
+	// inner hits are requested alongside collapsing.
+	InnerHits DocumentMatchCollection `json:"inner_hits,omitempty"`
+
+	// Source holds the original JSON document, filtered as requested by
+	// SearchRequest.Source, for indexes whose mapping stores it.
+	Source json.RawMessage `json:"_source,omitempty"`
+
+	// Version holds the version of the document, for
+	// indexes which track document versions.
+	Version uint64 `json:"version,omitempty"`
 }
 
 func (dm *DocumentMatch) AddFieldValue(name string, value interface{}) {
@@ -244,6 +266,8 @@ func (dm *DocumentMatch) Reset() *DocumentMatch {
 	for i := range descendants { // recycle each IndexInternalID
 		descendants[i] = descendants[i][:0]
 	}
+	// remember the []byte used for the CollapseKey
+	collapseKey := dm.CollapseKey
 	// idiom to copy over from empty DocumentMatch (0 allocations)
 	*dm = DocumentMatch{}
 	// reuse the []byte already allocated (and reset len to 0)
@@ -258,6 +282,8 @@ func (dm *DocumentMatch) Reset() *DocumentMatch {
 	dm.Descendants = descendants[:0]
 	// reuse the score breakdown map already allocated (after clearing it)
 	dm.ScoreBreakdown = scoreBreakdown
+	// reuse the []byte already allocated (and reset len to 0)
+	dm.CollapseKey = collapseKey[:0]
 	return dm
 }
 
@@ -304,6 +330,12 @@ func (dm *DocumentMatch) Size() int {
 			size.SizeOfPtr
 	}
 
+	sizeInBytes += len(dm.CollapseKey) + len(dm.Source)
+
+	for _, entry := range dm.InnerHits {
+		sizeInBytes += entry.Size()
+	}
+
 	return sizeInBytes
 }
 
@@ -0,0 +1,104 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/scorer"
+	"github.com/blevesearch/bleve/v2/size"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+var reflectStaticSizeConstantScoreSearcher int
+
+func init() {
+	var css ConstantScoreSearcher
+	reflectStaticSizeConstantScoreSearcher = int(reflect.TypeOf(css).Size())
+}
+
+// ConstantScoreSearcher wraps any other searcher, giving
+// each of its matches the same score, that of the boost.
+type ConstantScoreSearcher struct {
+	child  search.Searcher
+	scorer *scorer.ConstantScorer
+}
+
+func NewConstantScoreSearcher(ctx context.Context, s search.Searcher, boost float64,
+	options search.SearcherOptions) *ConstantScoreSearcher {
+	return &ConstantScoreSearcher{
+		child:  s,
+		scorer: scorer.NewConstantScorer(1.0, boost, options),
+	}
+}
+
+func (s *ConstantScoreSearcher) Size() int {
+	return reflectStaticSizeConstantScoreSearcher + size.SizeOfPtr +
+		s.child.Size() + s.scorer.Size()
+}
+
+// score returns the match scored in place of the child's match.
+func (s *ConstantScoreSearcher) score(ctx *search.SearchContext, d *search.DocumentMatch) *search.DocumentMatch {
+	if d == nil {
+		return nil
+	}
+	rv := s.scorer.Score(ctx, d.IndexInternalID)
+	// the id is handed over to the new match, not to be reused with d
+	d.IndexInternalID = nil
+	ctx.DocumentMatchPool.Put(d)
+	return rv
+}
+
+func (s *ConstantScoreSearcher) Next(ctx *search.SearchContext) (*search.DocumentMatch, error) {
+	next, err := s.child.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.score(ctx, next), nil
+}
+
+func (s *ConstantScoreSearcher) Advance(ctx *search.SearchContext, ID index.IndexInternalID) (*search.DocumentMatch, error) {
+	adv, err := s.child.Advance(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.score(ctx, adv), nil
+}
+
+func (s *ConstantScoreSearcher) Close() error {
+	return s.child.Close()
+}
+
+func (s *ConstantScoreSearcher) Weight() float64 {
+	return s.scorer.Weight()
+}
+
+func (s *ConstantScoreSearcher) SetQueryNorm(qnorm float64) {
+	s.scorer.SetQueryNorm(qnorm)
+}
+
+func (s *ConstantScoreSearcher) Count() uint64 {
+	return s.child.Count()
+}
+
+func (s *ConstantScoreSearcher) Min() int {
+	return s.child.Min()
+}
+
+func (s *ConstantScoreSearcher) DocumentMatchPoolSize() int {
+	return s.child.DocumentMatchPoolSize() + 1
+}
@@ -0,0 +1,74 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"context"
+
+	"github.com/blevesearch/bleve/v2/search"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// ExistsReader is implemented by index readers able to find the
+// documents having any value for a field directly, such as from
+// a bitmap of the documents with the field, rather than by visiting
+// every term of the field.
+type ExistsReader interface {
+	ExistsReader(field string) (index.TermFieldReader, error)
+}
+
+// NewExistsSearcher returns a searcher matching the documents having any
+// value for the field, each scoring the boost.  Index readers that are
+// not an ExistsReader fall back to matching any of the terms of the
+// field, as found in its dictionary, which are all loaded, as for the
+// other searchers of many terms, so take memory in their number.
+func NewExistsSearcher(ctx context.Context, indexReader index.IndexReader, field string,
+	boost float64, options search.SearcherOptions) (search.Searcher, error) {
+	// the documents only filter, so need no scores
+	filterOptions := search.SearcherOptions{Score: "none"}
+
+	if er, ok := indexReader.(ExistsReader); ok {
+		reader, err := er.ExistsReader(field)
+		if err != nil {
+			return nil, err
+		}
+		child, err := newTermSearcherFromReader(ctx, indexReader, reader, nil, field, 1.0, filterOptions)
+		if err != nil {
+			return nil, err
+		}
+		return NewConstantScoreSearcher(ctx, child, boost, options), nil
+	}
+
+	fieldDict, err := indexReader.FieldDict(field)
+	if err != nil {
+		return nil, err
+	}
+	var terms []string
+	tfd, err := fieldDict.Next()
+	for err == nil && tfd != nil {
+		terms = append(terms, tfd.Term)
+		tfd, err = fieldDict.Next()
+	}
+	if cerr := fieldDict.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(terms) == 0 {
+		return NewMatchNoneSearcher(indexReader)
+	}
+	return NewConstantScoreMultiTermSearcher(ctx, indexReader, terms, field, boost, options)
+}
@@ -0,0 +1,400 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/blevesearch/bleve/v2/numeric"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/size"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+var reflectStaticSizeFunctionScoreSearcher int
+
+func init() {
+	var fss FunctionScoreSearcher
+	reflectStaticSizeFunctionScoreSearcher = int(reflect.TypeOf(fss).Size())
+}
+
+// Modes for combining the results of score functions with one another,
+// and the combined result with the score of the query.
+const (
+	ScoreModeMultiply = "multiply"
+	ScoreModeSum      = "sum"
+	ScoreModeAvg      = "avg"
+	ScoreModeFirst    = "first"
+	ScoreModeMax      = "max"
+	ScoreModeMin      = "min"
+	BoostModeReplace  = "replace"
+)
+
+// DocValueFunc computes a score for a document, given the doc value
+// terms of its fields, which hold only full precision numeric terms.
+type DocValueFunc func(d *search.DocumentMatch, values map[string][][]byte) (float64, error)
+
+// ScoreFunction computes a factor for the score of the documents
+// matching its Filter, or of every document when Filter is nil.  The
+// factor is the result of Func multiplied by Weight, or Weight alone
+// when Func is nil.
+type ScoreFunction struct {
+	Filter search.Searcher
+	Func   DocValueFunc
+	Weight float64
+	// Fields holds the fields whose doc values are passed to Func
+	Fields []string
+	// NeedsID is set when Func reads the external id of the document,
+	// which is otherwise not looked up for each document scored
+	NeedsID bool
+
+	curr *search.DocumentMatch
+	done bool
+}
+
+// FunctionScoreOptions describes how the results of the functions
+// are combined.  The functions applying to a document are combined
+// by ScoreMode, capped at MaxBoost when it is positive, then combined
+// with the score of the document by BoostMode, which is either a
+// score mode or BoostModeReplace.  Documents whose final score is
+// less than MinScore are not matched.
+type FunctionScoreOptions struct {
+	ScoreMode string
+	BoostMode string
+	MaxBoost  float64
+	MinScore  float64
+}
+
+// FunctionScoreSearcher wraps any other searcher, scoring its
+// matches by a combination of their score and score functions.
+type FunctionScoreSearcher struct {
+	child       search.Searcher
+	functions   []*ScoreFunction
+	options     FunctionScoreOptions
+	dvReader    index.DocValueReader
+	indexReader index.IndexReader
+	explain     bool
+
+	// the doc values of the current document, whose terms are
+	// copied into buf, as they are only valid during the visit
+	values  map[string][][]byte
+	buf     []byte
+	visited []visitedTerm
+}
+
+type visitedTerm struct {
+	field      string
+	start, end int
+}
+
+func NewFunctionScoreSearcher(ctx context.Context, s search.Searcher,
+	functions []*ScoreFunction, indexReader index.IndexReader,
+	options FunctionScoreOptions, explain bool) (*FunctionScoreSearcher, error) {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, f := range functions {
+		for _, field := range f.Fields {
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	var dvReader index.DocValueReader
+	if len(fields) > 0 {
+		var err error
+		dvReader, err = indexReader.DocValueReader(fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &FunctionScoreSearcher{
+		child:       s,
+		functions:   functions,
+		options:     options,
+		dvReader:    dvReader,
+		indexReader: indexReader,
+		explain:     explain,
+		values:      make(map[string][][]byte, len(fields)),
+	}, nil
+}
+
+func (s *FunctionScoreSearcher) Size() int {
+	sizeInBytes := reflectStaticSizeFunctionScoreSearcher + size.SizeOfPtr +
+		s.child.Size()
+	for _, f := range s.functions {
+		if f.Filter != nil {
+			sizeInBytes += f.Filter.Size()
+		}
+	}
+	return sizeInBytes
+}
+
+func (s *FunctionScoreSearcher) Next(ctx *search.SearchContext) (*search.DocumentMatch, error) {
+	next, err := s.child.Next(ctx)
+	for next != nil && err == nil {
+		var ok bool
+		ok, err = s.score(ctx, next)
+		if err != nil || ok {
+			break
+		}
+		ctx.DocumentMatchPool.Put(next)
+		next, err = s.child.Next(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+func (s *FunctionScoreSearcher) Advance(ctx *search.SearchContext, ID index.IndexInternalID) (*search.DocumentMatch, error) {
+	adv, err := s.child.Advance(ctx, ID)
+	if err != nil || adv == nil {
+		return nil, err
+	}
+	ok, err := s.score(ctx, adv)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return adv, nil
+	}
+	ctx.DocumentMatchPool.Put(adv)
+	return s.Next(ctx)
+}
+
+// score replaces the score of the document, reporting
+// whether it is at least the minimum score.
+func (s *FunctionScoreSearcher) score(ctx *search.SearchContext, d *search.DocumentMatch) (bool, error) {
+	values, err := s.docValues(d)
+	if err != nil {
+		return false, err
+	}
+
+	var factors []float64
+	var weights []float64
+	var expls []*search.Explanation
+	for i, f := range s.functions {
+		ok, err := f.matches(ctx, d.IndexInternalID)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			continue
+		}
+		factor := f.Weight
+		if f.Func != nil {
+			if f.NeedsID {
+				if err = s.resolveID(d); err != nil {
+					return false, err
+				}
+			}
+			v, err := f.Func(d, values)
+			if err != nil {
+				return false, err
+			}
+			factor *= v
+		}
+		if factor < 0 || math.IsNaN(factor) || math.IsInf(factor, 0) {
+			if err = s.resolveID(d); err != nil {
+				return false, err
+			}
+			return false, fmt.Errorf("score function %d computed invalid score for document %s", i, d.ID)
+		}
+		factors = append(factors, factor)
+		weights = append(weights, f.Weight)
+		if s.explain {
+			expls = append(expls, &search.Explanation{
+				Value:   factor,
+				Message: fmt.Sprintf("function %d, with weight %g", i, f.Weight),
+			})
+		}
+		if s.options.ScoreMode == ScoreModeFirst {
+			break
+		}
+	}
+
+	factor := 1.0
+	if len(factors) > 0 {
+		factor = combineScores(s.options.ScoreMode, factors, weights)
+	}
+	if s.options.MaxBoost > 0 && factor > s.options.MaxBoost {
+		factor = s.options.MaxBoost
+	}
+	score := factor
+	if s.options.BoostMode != BoostModeReplace {
+		score = combineScores(s.options.BoostMode,
+			[]float64{d.Score, factor}, []float64{1, 1})
+	}
+	if s.explain {
+		d.Expl = &search.Explanation{
+			Value:   score,
+			Message: fmt.Sprintf("function score, boost mode %s, of:", s.options.BoostMode),
+			Children: []*search.Explanation{
+				d.Expl,
+				{
+					Value:    factor,
+					Message:  fmt.Sprintf("functions, score mode %s, of:", s.options.ScoreMode),
+					Children: expls,
+				},
+			},
+		}
+	}
+	d.Score = score
+	return score >= s.options.MinScore, nil
+}
+
+// docValues returns the doc values of the document, which
+// are only valid until the next document is scored.
+func (s *FunctionScoreSearcher) docValues(d *search.DocumentMatch) (map[string][][]byte, error) {
+	if s.dvReader == nil {
+		return nil, nil
+	}
+	s.buf = s.buf[:0]
+	s.visited = s.visited[:0]
+	err := s.dvReader.VisitDocValues(d.IndexInternalID, func(field string, term []byte) {
+		if valid, shift := numeric.ValidPrefixCodedTermBytes(term); valid && shift != 0 {
+			return
+		}
+		start := len(s.buf)
+		s.buf = append(s.buf, term...)
+		s.visited = append(s.visited, visitedTerm{field: field, start: start, end: len(s.buf)})
+	})
+	if err != nil {
+		return nil, err
+	}
+	for field, terms := range s.values {
+		s.values[field] = terms[:0]
+	}
+	for _, v := range s.visited {
+		s.values[v.field] = append(s.values[v.field], s.buf[v.start:v.end:v.end])
+	}
+	return s.values, nil
+}
+
+func (s *FunctionScoreSearcher) resolveID(d *search.DocumentMatch) error {
+	if d.ID != "" {
+		return nil
+	}
+	extID, err := s.indexReader.ExternalID(d.IndexInternalID)
+	if err != nil {
+		return err
+	}
+	d.ID = extID
+	return nil
+}
+
+// matches reports whether the function applies to the document, advancing
+// its filter, as the documents are visited in increasing order.
+func (f *ScoreFunction) matches(ctx *search.SearchContext, ID index.IndexInternalID) (bool, error) {
+	if f.Filter == nil {
+		return true, nil
+	}
+	if f.curr != nil && f.curr.IndexInternalID.Compare(ID) >= 0 {
+		return f.curr.IndexInternalID.Equals(ID), nil
+	}
+	if f.done {
+		return false, nil
+	}
+	if f.curr != nil {
+		ctx.DocumentMatchPool.Put(f.curr)
+	}
+	var err error
+	f.curr, err = f.Filter.Advance(ctx, ID)
+	if err != nil {
+		return false, err
+	}
+	if f.curr == nil {
+		f.done = true
+		return false, nil
+	}
+	return f.curr.IndexInternalID.Equals(ID), nil
+}
+
+func combineScores(mode string, scores, weights []float64) float64 {
+	rv := scores[0]
+	switch mode {
+	case ScoreModeSum:
+		for _, score := range scores[1:] {
+			rv += score
+		}
+	case ScoreModeAvg:
+		var totalWeight float64
+		for _, weight := range weights {
+			totalWeight += weight
+		}
+		for _, score := range scores[1:] {
+			rv += score
+		}
+		if totalWeight != 0 {
+			rv /= totalWeight
+		}
+	case ScoreModeFirst:
+	case ScoreModeMax:
+		for _, score := range scores[1:] {
+			rv = math.Max(rv, score)
+		}
+	case ScoreModeMin:
+		for _, score := range scores[1:] {
+			rv = math.Min(rv, score)
+		}
+	default:
+		for _, score := range scores[1:] {
+			rv *= score
+		}
+	}
+	return rv
+}
+
+func (s *FunctionScoreSearcher) Close() error {
+	err := s.child.Close()
+	for _, f := range s.functions {
+		if f.Filter != nil {
+			if ferr := f.Filter.Close(); ferr != nil && err == nil {
+				err = ferr
+			}
+		}
+	}
+	return err
+}
+
+func (s *FunctionScoreSearcher) Weight() float64 {
+	return s.child.Weight()
+}
+
+func (s *FunctionScoreSearcher) SetQueryNorm(n float64) {
+	s.child.SetQueryNorm(n)
+}
+
+func (s *FunctionScoreSearcher) Count() uint64 {
+	return s.child.Count()
+}
+
+func (s *FunctionScoreSearcher) Min() int {
+	return s.child.Min()
+}
+
+func (s *FunctionScoreSearcher) DocumentMatchPoolSize() int {
+	rv := s.child.DocumentMatchPoolSize()
+	for _, f := range s.functions {
+		if f.Filter != nil {
+			rv += f.Filter.DocumentMatchPoolSize() + 1
+		}
+	}
+	return rv
+}
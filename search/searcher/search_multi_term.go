@@ -266,3 +266,45 @@ func makeBatchSearchersBytes(ctx context.Context, indexReader index.IndexReader,
 	}
 	return qsearchers, nil
 }
+
+// NewConstantScoreMultiTermSearcher returns a searcher matching the
+// documents having any of the terms in the field, each scoring the
+// boost, without limit on the number of terms.  Where supported by the
+// index, the terms are combined into a single bitmap, so that matching
+// a great many terms stays cheap.
+func NewConstantScoreMultiTermSearcher(ctx context.Context, indexReader index.IndexReader,
+	terms []string, field string, boost float64, options search.SearcherOptions) (
+	search.Searcher, error) {
+	// the terms only filter, so need no scores or term vectors
+	filterOptions := search.SearcherOptions{Score: "none"}
+	qsearchers, err := makeBatchSearchers(ctx, indexReader, terms, field, 1.0, filterOptions)
+	if err != nil {
+		return nil, err
+	}
+	closeAll := func() {
+		for _, searcher := range qsearchers {
+			_ = searcher.Close()
+		}
+	}
+
+	var child search.Searcher
+	if len(qsearchers) > 1 {
+		child, err = optimizeCompositeSearcher(ctx, "disjunction:unadorned",
+			indexReader, qsearchers, filterOptions)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		if child != nil {
+			closeAll()
+		}
+	}
+	if child == nil {
+		child, err = newMultiTermSearcherInternal(ctx, indexReader, qsearchers,
+			field, 1.0, filterOptions, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return NewConstantScoreSearcher(ctx, child, boost, options), nil
+}
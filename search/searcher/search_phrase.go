@@ -37,6 +37,7 @@ type PhraseSearcher struct {
 	queryNorm    float64
 	currMust     *search.DocumentMatch
 	terms        [][]string
+	slop         int
 	path         phrasePath
 	paths        []phrasePath
 	locations    []search.Location
@@ -79,6 +80,14 @@ func NewPhraseSearcher(ctx context.Context, indexReader index.IndexReader, terms
 
 func NewMultiPhraseSearcher(ctx context.Context, indexReader index.IndexReader, terms [][]string,
 	fuzziness int, autoFuzzy bool, field string, boost float64, options search.SearcherOptions) (*PhraseSearcher, error) {
+	return NewSloppyMultiPhraseSearcher(ctx, indexReader, terms, 0, fuzziness, autoFuzzy, field, boost, options)
+}
+
+// NewSloppyMultiPhraseSearcher returns a searcher for the phrase, whose
+// terms may be found at most slop positions in total away from where the
+// phrase places them, so that swapping two terms takes a slop of 2.
+func NewSloppyMultiPhraseSearcher(ctx context.Context, indexReader index.IndexReader, terms [][]string,
+	slop int, fuzziness int, autoFuzzy bool, field string, boost float64, options search.SearcherOptions) (*PhraseSearcher, error) {
 
 	options.IncludeTermVectors = true
 	var termPositionSearchers []search.Searcher
@@ -213,6 +222,7 @@ func NewMultiPhraseSearcher(ctx context.Context, indexReader index.IndexReader,
 	rv := PhraseSearcher{
 		mustSearcher:     mustSearcher,
 		terms:            terms,
+		slop:             slop,
 		fuzzyTermMatches: fuzzyTermMatches,
 	}
 	rv.computeQueryNorm()
@@ -351,7 +361,11 @@ func (s *PhraseSearcher) checkCurrMustMatchField(ctx *search.SearchContext,
 		s.expandFuzzyMatches(tlm, expandedTlm)
 		tlmPtr = &expandedTlm
 	}
-	s.paths = findPhrasePaths(0, nil, s.terms, *tlmPtr, s.path[:0], 0, s.paths[:0])
+	if s.slop > 0 {
+		s.paths = findSloppyPhrasePaths(s.terms, *tlmPtr, s.path[:0], s.slop, s.paths[:0])
+	} else {
+		s.paths = findPhrasePaths(0, nil, s.terms, *tlmPtr, s.path[:0], 0, s.paths[:0])
+	}
 	for _, p := range s.paths {
 		for _, pp := range p {
 			ftls = append(ftls, search.FieldTermLocation{
@@ -506,6 +520,120 @@ func editDistance(p1, p2 uint64) int {
 	return dist
 }
 
+// findSloppyPhrasePaths returns the first path found from each location
+// of the first phrase term, which is within remainingSlop positions of
+// the phrase as findPhrasePaths allows.  Rather than every path, which
+// there can be exponentially many of with a large slop, only one path is
+// kept for each location, and the locations from which no path can be
+// completed are remembered, so that they are not searched again.
+func findSloppyPhrasePaths(phraseTerms [][]string, tlm search.TermLocationMap,
+	p phrasePath, remainingSlop int, rv []phrasePath) []phrasePath {
+	// leading gaps do not move the first term
+	for len(phraseTerms) > 0 && (len(phraseTerms[0]) == 0 ||
+		(len(phraseTerms[0]) == 1 && phraseTerms[0][0] == "")) {
+		phraseTerms = phraseTerms[1:]
+	}
+	if len(phraseTerms) < 1 {
+		return rv
+	}
+	f := sloppyPhraseFinder{
+		tlm:      tlm,
+		failures: make(map[sloppyPhraseState]int),
+		repeated: hasRepeatedTerms(phraseTerms),
+	}
+	for _, carTerm := range phraseTerms[0] {
+		for _, loc := range tlm[carTerm] {
+			path := f.find(loc.Pos, loc.ArrayPositions, phraseTerms[1:],
+				append(p[:0], phrasePart{term: carTerm, loc: loc}), remainingSlop)
+			if path != nil {
+				rv = append(rv, append(phrasePath(nil), path...))
+			}
+		}
+	}
+	return rv
+}
+
+// sloppyPhraseState is a location chosen for a
+// phrase term, with the number of terms left.
+type sloppyPhraseState struct {
+	loc       *search.Location
+	remaining int
+}
+
+type sloppyPhraseFinder struct {
+	tlm search.TermLocationMap
+	// the most slop left with which no path could
+	// be completed from each state, which is not
+	// tracked when terms are repeated, as the
+	// locations used before then matter
+	failures map[sloppyPhraseState]int
+	repeated bool
+}
+
+func (f *sloppyPhraseFinder) find(prevPos uint64, ap search.ArrayPositions,
+	phraseTerms [][]string, p phrasePath, remainingSlop int) phrasePath {
+	if len(phraseTerms) < 1 {
+		return p
+	}
+
+	car := phraseTerms[0]
+	cdr := phraseTerms[1:]
+
+	// empty term is treated as match (continue)
+	if len(car) == 0 || (len(car) == 1 && car[0] == "") {
+		return f.find(prevPos+1, ap, cdr, p, remainingSlop)
+	}
+
+	for _, carTerm := range car {
+	LOCATIONS_LOOP:
+		for _, loc := range f.tlm[carTerm] {
+			if !loc.ArrayPositions.Equals(ap) {
+				continue
+			}
+			slop := remainingSlop - editDistance(prevPos+1, loc.Pos)
+			if slop < 0 {
+				continue
+			}
+			state := sloppyPhraseState{loc: loc, remaining: len(cdr)}
+			if failed, ok := f.failures[state]; ok && failed >= slop {
+				continue
+			}
+			for _, ppart := range p {
+				if ppart.term == carTerm && ppart.loc == loc {
+					continue LOCATIONS_LOOP
+				}
+			}
+			path := f.find(loc.Pos, loc.ArrayPositions, cdr,
+				append(p, phrasePart{term: carTerm, loc: loc}), slop)
+			if path != nil {
+				return path
+			}
+			if !f.repeated {
+				f.failures[state] = slop
+			}
+		}
+	}
+	return nil
+}
+
+// hasRepeatedTerms reports whether any term
+// is found at several positions of the phrase.
+func hasRepeatedTerms(phraseTerms [][]string) bool {
+	seen := make(map[string]struct{})
+	for _, terms := range phraseTerms {
+		for _, term := range terms {
+			if term == "" {
+				continue
+			}
+			if _, ok := seen[term]; ok {
+				return true
+			}
+			seen[term] = struct{}{}
+		}
+	}
+	return false
+}
+
 func (s *PhraseSearcher) Advance(ctx *search.SearchContext, ID index.IndexInternalID) (*search.DocumentMatch, error) {
 	if !s.initialized {
 		err := s.initSearchers(ctx)
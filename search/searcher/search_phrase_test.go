@@ -722,6 +722,86 @@ func TestFindPhrasePathsSloppyPalyndrome(t *testing.T) {
 	}
 }
 
+func TestFindSloppyPhrasePaths(t *testing.T) {
+	loc := func(pos uint64) *search.Location {
+		return &search.Location{Pos: pos}
+	}
+	tlm := search.TermLocationMap{
+		"two":   search.Locations{loc(2), loc(4)},
+		"three": search.Locations{loc(3)},
+		"six":   search.Locations{loc(6)},
+	}
+
+	tests := []struct {
+		phrase [][]string
+		paths  []phrasePath
+		slop   int
+	}{
+		// one path from each location of the first term
+		{
+			phrase: [][]string{{"two"}, {"three"}},
+			slop:   2,
+			paths: []phrasePath{
+				{phrasePart{"two", loc(2)}, phrasePart{"three", loc(3)}},
+				{phrasePart{"two", loc(4)}, phrasePart{"three", loc(3)}},
+			},
+		},
+		// the first path found is kept
+		{
+			phrase: [][]string{{"three"}, {"two"}},
+			slop:   2,
+			paths: []phrasePath{
+				{phrasePart{"three", loc(3)}, phrasePart{"two", loc(2)}},
+			},
+		},
+		// gaps are kept
+		{
+			phrase: [][]string{{"three"}, nil, {"six"}},
+			slop:   0,
+			paths:  nil,
+		},
+		{
+			phrase: [][]string{{"three"}, nil, {"six"}},
+			slop:   1,
+			paths: []phrasePath{
+				{phrasePart{"three", loc(3)}, phrasePart{"six", loc(6)}},
+			},
+		},
+		// leading gaps are not
+		{
+			phrase: [][]string{nil, {"three"}, {""}, {"six"}},
+			slop:   1,
+			paths: []phrasePath{
+				{phrasePart{"three", loc(3)}, phrasePart{"six", loc(6)}},
+			},
+		},
+	}
+
+	for i, test := range tests {
+		actualPaths := findSloppyPhrasePaths(test.phrase, tlm, nil, test.slop, nil)
+		if !reflect.DeepEqual(actualPaths, test.paths) {
+			t.Fatalf("expected: %v got %v for test %d", test.paths, actualPaths, i)
+		}
+	}
+
+	// a document repeating the terms of the phrase has many paths
+	terms := []string{"alpha", "beta", "gamma", "delta"}
+	tlm = make(search.TermLocationMap)
+	for i := 0; i < 400; i++ {
+		term := terms[i%len(terms)]
+		tlm[term] = append(tlm[term], loc(uint64(i+1)))
+	}
+	phrase := [][]string{{"delta"}, {"gamma"}, {"beta"}, {"alpha"}, {"epsilon"}}
+	actualPaths := findSloppyPhrasePaths(phrase, tlm, nil, 1000, nil)
+	if len(actualPaths) != 0 {
+		t.Errorf("expected no paths without the last term, got %d", len(actualPaths))
+	}
+	actualPaths = findSloppyPhrasePaths(phrase[:4], tlm, nil, 1000, nil)
+	if len(actualPaths) != 100 {
+		t.Errorf("expected a path from each delta, got %d", len(actualPaths))
+	}
+}
+
 func TestFindMultiPhrasePaths(t *testing.T) {
 	tlm := search.TermLocationMap{
 		"cat": search.Locations{
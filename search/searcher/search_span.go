@@ -0,0 +1,890 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/size"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+var reflectStaticSizeSpanSearcher int
+
+// SpanNearMaxSteps is a compile time setting that applications can adjust
+// to bound the work of finding the spans of a near matcher in a document.
+// Searches taking more steps in any document fail, rather than missing
+// spans which would have been found after the steps were taken.
+var SpanNearMaxSteps = 1 << 16
+
+func init() {
+	var ss SpanSearcher
+	reflectStaticSizeSpanSearcher = int(reflect.TypeOf(ss).Size())
+}
+
+// span is a run of positions within a field, from start up to but
+// excluding end, along with the term locations it is made of.
+type span struct {
+	start uint64
+	end   uint64
+	ap    search.ArrayPositions
+	parts phrasePath
+}
+
+func (s *span) width() int {
+	return int(s.end - s.start)
+}
+
+func (s *span) same(o *span) bool {
+	return s.start == o.start && s.end == o.end && s.ap.Equals(o.ap)
+}
+
+// SpanMatcher finds the spans matching a span query within the term
+// locations of a document.  SpanMatchers are created by the NewSpan*
+// functions, and combined into a SpanSearcher.
+type SpanMatcher interface {
+	// candidates returns a searcher for the documents
+	// which may hold a matching span
+	candidates(ctx context.Context, indexReader index.IndexReader,
+		field string, boost float64, options search.SearcherOptions) (search.Searcher, error)
+	// addTerms adds the terms whose locations are needed to find spans
+	addTerms(terms map[string]struct{})
+	// addCandidateTerms adds the terms whose locations the candidates
+	// find, in every candidate document holding the term
+	addCandidateTerms(terms map[string]struct{})
+	// spans returns the matching spans, ordered by start then end
+	spans(tlm search.TermLocationMap) ([]span, error)
+}
+
+// NewSpanTerm returns a SpanMatcher matching each location of the term.
+func NewSpanTerm(term string) SpanMatcher {
+	return &spanTerm{term: term}
+}
+
+// NewSpanNear returns a SpanMatcher matching spans made of a span of
+// each clause, which do not overlap, with at most slop positions
+// between them, or any number when slop is negative.  When inOrder is
+// set, the clause spans must also be in the order of the clauses.
+// As for Lucene, rather than every combination of the clause spans,
+// only those of the minimal windows holding a span of each clause are
+// matched, or, in order, those of the first spans following each span
+// of the first clause.
+func NewSpanNear(clauses []SpanMatcher, slop int, inOrder bool) SpanMatcher {
+	return &spanNear{clauses: clauses, slop: slop, inOrder: inOrder}
+}
+
+// NewSpanIntervals returns a SpanMatcher matching the minimal intervals
+// made of a span of each clause, which do not overlap, and are in the
+// order of the clauses when ordered is set.  Minimal intervals contain
+// no other matching interval, whatever the gaps between their spans.
+func NewSpanIntervals(clauses []SpanMatcher, ordered bool) SpanMatcher {
+	return &spanNear{clauses: clauses, slop: -1, inOrder: ordered, minimal: true}
+}
+
+// NewSpanIntervalsMaxGaps returns a SpanMatcher matching the minimal
+// intervals as NewSpanIntervals does, whose spans are separated by at
+// most maxGaps positions in total.
+func NewSpanIntervalsMaxGaps(clauses []SpanMatcher, maxGaps int, ordered bool) SpanMatcher {
+	if maxGaps < 0 {
+		return NewSpanIntervals(clauses, ordered)
+	}
+	return &spanNear{clauses: clauses, slop: maxGaps, inOrder: ordered, minimal: true}
+}
+
+// NewSpanOr returns a SpanMatcher matching the spans of any of the clauses.
+func NewSpanOr(clauses []SpanMatcher) SpanMatcher {
+	return &spanOr{clauses: clauses}
+}
+
+// NewSpanNot returns a SpanMatcher matching the spans of include which
+// do not overlap any span of exclude, nor come within pre positions
+// after or post positions before one.
+func NewSpanNot(include, exclude SpanMatcher, pre, post int) SpanMatcher {
+	return &spanNot{include: include, exclude: exclude, pre: pre, post: post}
+}
+
+// NewSpanFirst returns a SpanMatcher matching the spans of match
+// which end within the first end positions of the field.
+func NewSpanFirst(match SpanMatcher, end int) SpanMatcher {
+	return &spanFirst{match: match, end: end}
+}
+
+// NewSpanContaining returns a SpanMatcher matching
+// the spans of big which contain a span of little.
+func NewSpanContaining(big, little SpanMatcher) SpanMatcher {
+	return NewSpanFilter(big, little, SpanContaining)
+}
+
+// SpanRelation is the relation of a span to the spans filtering it.
+type SpanRelation int
+
+const (
+	// SpanContaining spans contain a filter span.
+	SpanContaining SpanRelation = iota
+	// SpanNotContaining spans contain no filter span.
+	SpanNotContaining
+	// SpanContainedBy spans are contained by a filter span.
+	SpanContainedBy
+	// SpanNotContainedBy spans are contained by no filter span.
+	SpanNotContainedBy
+	// SpanBefore spans end before a filter span starts.
+	SpanBefore
+	// SpanAfter spans start after a filter span ends.
+	SpanAfter
+)
+
+// NewSpanFilter returns a SpanMatcher matching the
+// spans of match in the relation to the spans of filter.
+func NewSpanFilter(match, filter SpanMatcher, relation SpanRelation) SpanMatcher {
+	return &spanFilter{match: match, filter: filter, relation: relation}
+}
+
+type spanTerm struct {
+	term string
+}
+
+func (m *spanTerm) candidates(ctx context.Context, indexReader index.IndexReader,
+	field string, boost float64, options search.SearcherOptions) (search.Searcher, error) {
+	return NewTermSearcher(ctx, indexReader, m.term, field, boost, options)
+}
+
+func (m *spanTerm) addTerms(terms map[string]struct{}) {
+	terms[m.term] = struct{}{}
+}
+
+func (m *spanTerm) addCandidateTerms(terms map[string]struct{}) {
+	terms[m.term] = struct{}{}
+}
+
+func (m *spanTerm) spans(tlm search.TermLocationMap) ([]span, error) {
+	locations := tlm[m.term]
+	if len(locations) == 0 {
+		return nil, nil
+	}
+	rv := make([]span, 0, len(locations))
+	for _, loc := range locations {
+		rv = append(rv, span{
+			start: loc.Pos,
+			end:   loc.Pos + 1,
+			ap:    loc.ArrayPositions,
+			parts: phrasePath{{term: m.term, loc: loc}},
+		})
+	}
+	sortSpans(rv)
+	// terms of several clauses are located by the candidates of each
+	unique := rv[:0]
+SPANS:
+	for _, s := range rv {
+		for i := len(unique) - 1; i >= 0 && unique[i].start == s.start; i-- {
+			if unique[i].same(&s) {
+				continue SPANS
+			}
+		}
+		unique = append(unique, s)
+	}
+	return unique, nil
+}
+
+type spanNear struct {
+	clauses []SpanMatcher
+	slop    int
+	inOrder bool
+	// minimal keeps only the spans containing no other
+	minimal bool
+}
+
+func (m *spanNear) candidates(ctx context.Context, indexReader index.IndexReader,
+	field string, boost float64, options search.SearcherOptions) (search.Searcher, error) {
+	searchers, err := spanCandidates(ctx, indexReader, m.clauses, field, boost, options)
+	if err != nil {
+		return nil, err
+	}
+	rv, err := NewConjunctionSearcher(ctx, indexReader, searchers, options)
+	if err != nil {
+		closeSearchers(searchers)
+		return nil, err
+	}
+	return rv, nil
+}
+
+func (m *spanNear) addTerms(terms map[string]struct{}) {
+	for _, c := range m.clauses {
+		c.addTerms(terms)
+	}
+}
+
+func (m *spanNear) addCandidateTerms(terms map[string]struct{}) {
+	for _, c := range m.clauses {
+		c.addCandidateTerms(terms)
+	}
+}
+
+func (m *spanNear) spans(tlm search.TermLocationMap) ([]span, error) {
+	clauseSpans := make([][]span, len(m.clauses))
+	for i, c := range m.clauses {
+		var err error
+		clauseSpans[i], err = c.spans(tlm)
+		if err != nil {
+			return nil, err
+		}
+		if len(clauseSpans[i]) == 0 {
+			return nil, nil
+		}
+	}
+
+	var rv []span
+	steps := 0
+	for _, group := range groupSpans(clauseSpans) {
+		from := len(rv)
+		if m.inOrder {
+			rv = m.orderedSpans(group, rv, &steps)
+		} else {
+			rv = m.unorderedSpans(group, rv, &steps)
+		}
+		if steps > SpanNearMaxSteps {
+			return nil, fmt.Errorf("span near took more than %d steps in a document, "+
+				"which is set by SpanNearMaxSteps", SpanNearMaxSteps)
+		}
+		if m.minimal {
+			rv = append(rv[:from], minimalSpans(rv[from:])...)
+		}
+	}
+	sortSpans(rv)
+	return rv, nil
+}
+
+// minimalSpans returns the spans which contain no other of the spans,
+// which are all of the same array positions.
+func minimalSpans(spans []span) []span {
+	// visiting the spans by start, latest first, and then by end, the
+	// spans contained by a span are all visited before it
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start > spans[j].start
+		}
+		return spans[i].end < spans[j].end
+	})
+	rv := make([]span, 0, len(spans))
+	for _, s := range spans {
+		if len(rv) == 0 || s.end < rv[len(rv)-1].end {
+			rv = append(rv, s)
+		}
+	}
+	return rv
+}
+
+// orderedSpans appends the spans made of each span of the first clause
+// followed by the first span of each next clause starting after the end
+// of the previous one, as Lucene's NearSpansOrdered finds them, rather
+// than every combination of the spans of the clauses.
+func (m *spanNear) orderedSpans(clauseSpans [][]span, rv []span, steps *int) []span {
+	chosen := make([]*span, len(clauseSpans))
+FIRST:
+	for j := range clauseSpans[0] {
+		chosen[0] = &clauseSpans[0][j]
+		end := chosen[0].end
+		widths := chosen[0].width()
+		for i := 1; i < len(clauseSpans); i++ {
+			*steps++
+			if *steps > SpanNearMaxSteps {
+				return rv
+			}
+			spans := clauseSpans[i]
+			k := sort.Search(len(spans), func(k int) bool {
+				return spans[k].start >= end
+			})
+			if k == len(spans) {
+				continue FIRST
+			}
+			chosen[i] = &spans[k]
+			end = spans[k].end
+			widths += spans[k].width()
+		}
+		if m.slop < 0 || int(end-chosen[0].start)-widths <= m.slop {
+			rv = appendSpan(rv, joinSpans(chosen, chosen[0].start, end))
+		}
+	}
+	return rv
+}
+
+// unorderedSpans appends the spans made of a span of each clause as
+// Lucene's NearSpansUnordered finds them, moving on the clause whose span
+// comes first each time, so that only the minimal windows holding a span
+// of each clause are considered.
+func (m *spanNear) unorderedSpans(clauseSpans [][]span, rv []span, steps *int) []span {
+	next := make([]int, len(clauseSpans))
+	chosen := make([]*span, len(clauseSpans))
+	for {
+		*steps++
+		if *steps > SpanNearMaxSteps {
+			return rv
+		}
+		var start, end uint64
+		widths := 0
+		first := 0
+		for i := range clauseSpans {
+			s := &clauseSpans[i][next[i]]
+			chosen[i] = s
+			if i == 0 || s.start < start {
+				start = s.start
+			}
+			if i == 0 || s.end > end {
+				end = s.end
+			}
+			widths += s.width()
+			if s.start < chosen[first].start ||
+				(s.start == chosen[first].start && s.end < chosen[first].end) {
+				first = i
+			}
+		}
+		if (m.slop < 0 || int(end-start)-widths <= m.slop) && !overlapping(chosen) {
+			rv = appendSpan(rv, joinSpans(chosen, start, end))
+		}
+		next[first]++
+		if next[first] == len(clauseSpans[first]) {
+			return rv
+		}
+	}
+}
+
+// overlapping reports whether any two of the spans overlap.
+func overlapping(spans []*span) bool {
+	for i, a := range spans {
+		for _, b := range spans[i+1:] {
+			if a.start < b.end && b.start < a.end {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// groupSpans splits the spans of each clause by their array positions,
+// as spans of different array positions are never near one another.
+// Groups missing the spans of a clause are left out.
+func groupSpans(clauseSpans [][]span) [][][]span {
+	groups := make(map[string][][]span)
+	var keys []string
+	var key []byte
+	for i, spans := range clauseSpans {
+		for _, s := range spans {
+			key = key[:0]
+			for _, p := range s.ap {
+				key = binary.AppendUvarint(key, p)
+			}
+			group, ok := groups[string(key)]
+			if !ok {
+				if i > 0 {
+					continue
+				}
+				group = make([][]span, len(clauseSpans))
+				keys = append(keys, string(key))
+			}
+			group[i] = append(group[i], s)
+			groups[string(key)] = group
+		}
+	}
+	rv := make([][][]span, 0, len(keys))
+GROUPS:
+	for _, k := range keys {
+		for _, spans := range groups[k] {
+			if len(spans) == 0 {
+				continue GROUPS
+			}
+		}
+		rv = append(rv, groups[k])
+	}
+	return rv
+}
+
+type spanOr struct {
+	clauses []SpanMatcher
+}
+
+func (m *spanOr) candidates(ctx context.Context, indexReader index.IndexReader,
+	field string, boost float64, options search.SearcherOptions) (search.Searcher, error) {
+	searchers, err := spanCandidates(ctx, indexReader, m.clauses, field, boost, options)
+	if err != nil {
+		return nil, err
+	}
+	rv, err := NewDisjunctionSearcher(ctx, indexReader, searchers, 1, options)
+	if err != nil {
+		closeSearchers(searchers)
+		return nil, err
+	}
+	return rv, nil
+}
+
+func (m *spanOr) addTerms(terms map[string]struct{}) {
+	for _, c := range m.clauses {
+		c.addTerms(terms)
+	}
+}
+
+func (m *spanOr) addCandidateTerms(terms map[string]struct{}) {
+	for _, c := range m.clauses {
+		c.addCandidateTerms(terms)
+	}
+}
+
+func (m *spanOr) spans(tlm search.TermLocationMap) ([]span, error) {
+	var all []span
+	for _, c := range m.clauses {
+		spans, err := c.spans(tlm)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, spans...)
+	}
+	sortSpans(all)
+	var rv []span
+	for _, s := range all {
+		rv = appendSpan(rv, s)
+	}
+	return rv, nil
+}
+
+type spanNot struct {
+	include SpanMatcher
+	exclude SpanMatcher
+	pre     int
+	post    int
+}
+
+func (m *spanNot) candidates(ctx context.Context, indexReader index.IndexReader,
+	field string, boost float64, options search.SearcherOptions) (search.Searcher, error) {
+	return m.include.candidates(ctx, indexReader, field, boost, options)
+}
+
+func (m *spanNot) addTerms(terms map[string]struct{}) {
+	m.include.addTerms(terms)
+	m.exclude.addTerms(terms)
+}
+
+func (m *spanNot) addCandidateTerms(terms map[string]struct{}) {
+	m.include.addCandidateTerms(terms)
+}
+
+func (m *spanNot) spans(tlm search.TermLocationMap) ([]span, error) {
+	include, err := m.include.spans(tlm)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := m.exclude.spans(tlm)
+	if err != nil {
+		return nil, err
+	}
+	if len(exclude) == 0 {
+		return include, nil
+	}
+	rv := include[:0]
+INCLUDE:
+	for _, in := range include {
+		for _, ex := range exclude {
+			if ex.ap.Equals(in.ap) &&
+				ex.start < in.end+uint64(m.post) && ex.end+uint64(m.pre) > in.start {
+				continue INCLUDE
+			}
+		}
+		rv = append(rv, in)
+	}
+	return rv, nil
+}
+
+type spanFirst struct {
+	match SpanMatcher
+	end   int
+}
+
+func (m *spanFirst) candidates(ctx context.Context, indexReader index.IndexReader,
+	field string, boost float64, options search.SearcherOptions) (search.Searcher, error) {
+	return m.match.candidates(ctx, indexReader, field, boost, options)
+}
+
+func (m *spanFirst) addTerms(terms map[string]struct{}) {
+	m.match.addTerms(terms)
+}
+
+func (m *spanFirst) addCandidateTerms(terms map[string]struct{}) {
+	m.match.addCandidateTerms(terms)
+}
+
+func (m *spanFirst) spans(tlm search.TermLocationMap) ([]span, error) {
+	spans, err := m.match.spans(tlm)
+	if err != nil {
+		return nil, err
+	}
+	rv := spans[:0]
+	for _, s := range spans {
+		// positions start at 1, so the first end positions end before end+1
+		if s.end <= uint64(m.end)+1 {
+			rv = append(rv, s)
+		}
+	}
+	return rv, nil
+}
+
+type spanFilter struct {
+	match    SpanMatcher
+	filter   SpanMatcher
+	relation SpanRelation
+}
+
+// negated reports whether the relation excludes the spans
+// related to a filter span, rather than requiring one.
+func (m *spanFilter) negated() bool {
+	return m.relation == SpanNotContaining || m.relation == SpanNotContainedBy
+}
+
+func (m *spanFilter) related(s, f *span) bool {
+	if !s.ap.Equals(f.ap) {
+		return false
+	}
+	switch m.relation {
+	case SpanContaining, SpanNotContaining:
+		return s.start <= f.start && f.end <= s.end
+	case SpanContainedBy, SpanNotContainedBy:
+		return f.start <= s.start && s.end <= f.end
+	case SpanBefore:
+		return s.end <= f.start
+	case SpanAfter:
+		return s.start >= f.end
+	}
+	return false
+}
+
+func (m *spanFilter) candidates(ctx context.Context, indexReader index.IndexReader,
+	field string, boost float64, options search.SearcherOptions) (search.Searcher, error) {
+	if m.negated() {
+		return m.match.candidates(ctx, indexReader, field, boost, options)
+	}
+	searchers, err := spanCandidates(ctx, indexReader,
+		[]SpanMatcher{m.match, m.filter}, field, boost, options)
+	if err != nil {
+		return nil, err
+	}
+	rv, err := NewConjunctionSearcher(ctx, indexReader, searchers, options)
+	if err != nil {
+		closeSearchers(searchers)
+		return nil, err
+	}
+	return rv, nil
+}
+
+func (m *spanFilter) addTerms(terms map[string]struct{}) {
+	m.match.addTerms(terms)
+	m.filter.addTerms(terms)
+}
+
+func (m *spanFilter) addCandidateTerms(terms map[string]struct{}) {
+	m.match.addCandidateTerms(terms)
+	if !m.negated() {
+		m.filter.addCandidateTerms(terms)
+	}
+}
+
+func (m *spanFilter) spans(tlm search.TermLocationMap) ([]span, error) {
+	spans, err := m.match.spans(tlm)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := m.filter.spans(tlm)
+	if err != nil {
+		return nil, err
+	}
+	rv := spans[:0]
+	for i := range spans {
+		related := false
+		for j := range filter {
+			if m.related(&spans[i], &filter[j]) {
+				related = true
+				break
+			}
+		}
+		if related != m.negated() {
+			rv = append(rv, spans[i])
+		}
+	}
+	return rv, nil
+}
+
+func spanCandidates(ctx context.Context, indexReader index.IndexReader, clauses []SpanMatcher,
+	field string, boost float64, options search.SearcherOptions) ([]search.Searcher, error) {
+	rv := make([]search.Searcher, 0, len(clauses))
+	for _, c := range clauses {
+		s, err := c.candidates(ctx, indexReader, field, boost, options)
+		if err != nil {
+			closeSearchers(rv)
+			return nil, err
+		}
+		rv = append(rv, s)
+	}
+	return rv, nil
+}
+
+func closeSearchers(searchers []search.Searcher) {
+	for _, s := range searchers {
+		_ = s.Close()
+	}
+}
+
+func sortSpans(spans []span) {
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end < spans[j].end
+	})
+}
+
+// appendSpan appends the span unless the same span was already found.
+func appendSpan(spans []span, s span) []span {
+	for i := len(spans) - 1; i >= 0; i-- {
+		if spans[i].same(&s) {
+			return spans
+		}
+	}
+	return append(spans, s)
+}
+
+func joinSpans(chosen []*span, start, end uint64) span {
+	rv := span{start: start, end: end, ap: chosen[0].ap}
+	for _, s := range chosen {
+		rv.parts = append(rv.parts, s.parts...)
+	}
+	return rv
+}
+
+// spanTermReader reads the locations of a term in the candidate
+// documents, which are visited in increasing order.
+type spanTermReader struct {
+	term   string
+	reader index.TermFieldReader
+	curr   *index.TermFieldDoc
+	tfd    index.TermFieldDoc
+	done   bool
+}
+
+func (r *spanTermReader) locations(ID index.IndexInternalID) (*index.TermFieldDoc, error) {
+	if r.done {
+		return nil, nil
+	}
+	if r.curr == nil || r.curr.ID.Compare(ID) < 0 {
+		var err error
+		r.curr, err = r.reader.Advance(ID, r.tfd.Reset())
+		if err != nil {
+			return nil, err
+		}
+		if r.curr == nil {
+			// the term is in no further documents
+			r.done = true
+			return nil, nil
+		}
+	}
+	if r.curr.ID.Compare(ID) != 0 {
+		return nil, nil
+	}
+	return r.curr, nil
+}
+
+// SpanSearcher searches for documents holding spans matched
+// by a SpanMatcher in a field, which must have been indexed with
+// IncludeTermVectors set to true.  Documents are scored by the
+// terms of the spans, as the searcher for their candidates does,
+// which also finds the locations of their terms.  Only the terms
+// the candidates do not search, such as those excluded, have
+// readers of their own.
+type SpanSearcher struct {
+	candidates         search.Searcher
+	matcher            SpanMatcher
+	field              string
+	readers            []*spanTermReader
+	tlm                search.TermLocationMap
+	includeTermVectors bool
+}
+
+func NewSpanSearcher(ctx context.Context, indexReader index.IndexReader, matcher SpanMatcher,
+	field string, boost float64, options search.SearcherOptions) (*SpanSearcher, error) {
+	terms := make(map[string]struct{})
+	matcher.addTerms(terms)
+	candidateTerms := make(map[string]struct{})
+	matcher.addCandidateTerms(candidateTerms)
+
+	rv := &SpanSearcher{
+		matcher:            matcher,
+		field:              field,
+		tlm:                make(search.TermLocationMap, len(terms)),
+		includeTermVectors: options.IncludeTermVectors,
+	}
+	for term := range terms {
+		if _, ok := candidateTerms[term]; ok {
+			continue
+		}
+		reader, err := indexReader.TermFieldReader(ctx, []byte(term), field, true, false, true)
+		if err != nil {
+			_ = rv.Close()
+			return nil, fmt.Errorf("span searcher error building term reader: %v", err)
+		}
+		rv.readers = append(rv.readers, &spanTermReader{term: term, reader: reader})
+	}
+
+	options.IncludeTermVectors = true
+	var err error
+	rv.candidates, err = matcher.candidates(ctx, indexReader, field, boost, options)
+	if err != nil {
+		_ = rv.Close()
+		return nil, fmt.Errorf("span searcher error building candidate searcher: %v", err)
+	}
+	return rv, nil
+}
+
+func (s *SpanSearcher) Size() int {
+	sizeInBytes := reflectStaticSizeSpanSearcher + size.SizeOfPtr +
+		len(s.field)
+	if s.candidates != nil {
+		sizeInBytes += s.candidates.Size()
+	}
+	for _, r := range s.readers {
+		sizeInBytes += size.SizeOfPtr + len(r.term) + r.reader.Size()
+	}
+	return sizeInBytes
+}
+
+func (s *SpanSearcher) Weight() float64 {
+	return s.candidates.Weight()
+}
+
+func (s *SpanSearcher) SetQueryNorm(qnorm float64) {
+	s.candidates.SetQueryNorm(qnorm)
+}
+
+func (s *SpanSearcher) Next(ctx *search.SearchContext) (*search.DocumentMatch, error) {
+	d, err := s.candidates.Next(ctx)
+	return s.nextMatch(ctx, d, err)
+}
+
+func (s *SpanSearcher) Advance(ctx *search.SearchContext, ID index.IndexInternalID) (*search.DocumentMatch, error) {
+	d, err := s.candidates.Advance(ctx, ID)
+	return s.nextMatch(ctx, d, err)
+}
+
+// nextMatch returns the first of the candidates, starting at d,
+// holding a matching span.
+func (s *SpanSearcher) nextMatch(ctx *search.SearchContext, d *search.DocumentMatch,
+	err error) (*search.DocumentMatch, error) {
+	for err == nil && d != nil {
+		var spans []span
+		spans, err = s.spans(d)
+		if err == nil && len(spans) > 0 {
+			// the locations of the candidates are replaced by those of the spans
+			d.FieldTermLocations = d.FieldTermLocations[:0]
+			if s.includeTermVectors {
+				d.FieldTermLocations = s.fieldTermLocations(d.FieldTermLocations, spans)
+			}
+			return d, nil
+		}
+		ctx.DocumentMatchPool.Put(d)
+		if err == nil {
+			d, err = s.candidates.Next(ctx)
+		}
+	}
+	return nil, err
+}
+
+func (s *SpanSearcher) spans(d *search.DocumentMatch) ([]span, error) {
+	for term := range s.tlm {
+		delete(s.tlm, term)
+	}
+	// the locations are copied, as those of the document match
+	// are replaced by the locations of the spans found in them
+	for _, ftl := range d.FieldTermLocations {
+		if ftl.Field != s.field {
+			continue
+		}
+		loc := ftl.Location
+		if len(loc.ArrayPositions) > 0 {
+			loc.ArrayPositions = append(search.ArrayPositions(nil), loc.ArrayPositions...)
+		}
+		s.tlm[ftl.Term] = append(s.tlm[ftl.Term], &loc)
+	}
+	for _, r := range s.readers {
+		tfd, err := r.locations(d.IndexInternalID)
+		if err != nil {
+			return nil, err
+		}
+		if tfd == nil {
+			continue
+		}
+		locations := make(search.Locations, 0, len(tfd.Vectors))
+		for _, v := range tfd.Vectors {
+			var ap search.ArrayPositions
+			if len(v.ArrayPositions) > 0 {
+				ap = append(ap, v.ArrayPositions...)
+			}
+			locations = append(locations, &search.Location{
+				Pos:            v.Pos,
+				Start:          v.Start,
+				End:            v.End,
+				ArrayPositions: ap,
+			})
+		}
+		s.tlm[r.term] = locations
+	}
+	return s.matcher.spans(s.tlm)
+}
+
+func (s *SpanSearcher) fieldTermLocations(ftls []search.FieldTermLocation,
+	spans []span) []search.FieldTermLocation {
+	for _, sp := range spans {
+		for _, p := range sp.parts {
+			ftls = append(ftls, search.FieldTermLocation{
+				Field:    s.field,
+				Term:     p.term,
+				Location: *p.loc,
+			})
+		}
+	}
+	return ftls
+}
+
+func (s *SpanSearcher) Count() uint64 {
+	// for now return a worst case
+	return s.candidates.Count()
+}
+
+func (s *SpanSearcher) Close() (err error) {
+	if s.candidates != nil {
+		err = s.candidates.Close()
+	}
+	for _, r := range s.readers {
+		if cerr := r.reader.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func (s *SpanSearcher) Min() int {
+	return 0
+}
+
+func (s *SpanSearcher) DocumentMatchPoolSize() int {
+	return s.candidates.DocumentMatchPoolSize() + 1
+}
@@ -0,0 +1,121 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searcher
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/search"
+)
+
+func matchSpans(t *testing.T, m SpanMatcher, tlm search.TermLocationMap) []span {
+	spans, err := m.spans(tlm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spans
+}
+
+func TestSpanNearSpans(t *testing.T) {
+	loc := func(pos uint64, ap ...uint64) *search.Location {
+		return &search.Location{Pos: pos, ArrayPositions: ap}
+	}
+	tlm := search.TermLocationMap{
+		"a": {loc(1), loc(4)},
+		"b": {loc(2), loc(6)},
+		"x": {loc(1, 0), loc(5, 1)},
+		"y": {loc(2, 1), loc(6, 1)},
+		"c": {loc(1), loc(2)},
+		"d": {loc(3)},
+	}
+	a, b := NewSpanTerm("a"), NewSpanTerm("b")
+	x, y := NewSpanTerm("x"), NewSpanTerm("y")
+	c, d := NewSpanTerm("c"), NewSpanTerm("d")
+
+	tests := []struct {
+		name    string
+		matcher SpanMatcher
+		spans   [][2]uint64
+	}{
+		{"in order", NewSpanNear([]SpanMatcher{a, b}, 10, true), [][2]uint64{{1, 3}, {4, 7}}},
+		{"in order slop", NewSpanNear([]SpanMatcher{a, b}, 0, true), [][2]uint64{{1, 3}}},
+		{"any order", NewSpanNear([]SpanMatcher{a, b}, 0, false), [][2]uint64{{1, 3}}},
+		{"any order slop", NewSpanNear([]SpanMatcher{a, b}, 1, false), [][2]uint64{{1, 3}, {2, 5}, {4, 7}}},
+		{"unbounded", NewSpanNear([]SpanMatcher{b, a}, -1, false), [][2]uint64{{1, 3}, {2, 5}, {4, 7}}},
+		{"same clause", NewSpanNear([]SpanMatcher{a, a}, 5, false), [][2]uint64{{1, 5}}},
+		{"array positions", NewSpanNear([]SpanMatcher{x, y}, 5, false), [][2]uint64{{2, 6}, {5, 7}}},
+		{"not minimal", NewSpanNear([]SpanMatcher{c, d}, -1, false), [][2]uint64{{1, 4}, {2, 4}}},
+		{"intervals", NewSpanIntervals([]SpanMatcher{c, d}, false), [][2]uint64{{2, 4}}},
+		{"intervals ordered", NewSpanIntervals([]SpanMatcher{c, d}, true), [][2]uint64{{2, 4}}},
+		{"intervals max gaps", NewSpanIntervalsMaxGaps([]SpanMatcher{b, a}, 0, false), [][2]uint64{{1, 3}}},
+	}
+	for _, test := range tests {
+		var spans [][2]uint64
+		for _, s := range matchSpans(t, test.matcher, tlm) {
+			spans = append(spans, [2]uint64{s.start, s.end})
+		}
+		if !reflect.DeepEqual(spans, test.spans) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.spans, spans)
+		}
+	}
+}
+
+func TestSpanNearSpansBounded(t *testing.T) {
+	// a document repeating its terms does not
+	// make every combination of them a span
+	terms := []string{"alpha", "beta", "gamma", "delta"}
+	tlm := make(search.TermLocationMap)
+	for i := 0; i < 240; i++ {
+		term := terms[i%len(terms)]
+		tlm[term] = append(tlm[term], &search.Location{Pos: uint64(i + 1)})
+	}
+	clauses := make([]SpanMatcher, len(terms))
+	for i, term := range terms {
+		clauses[len(terms)-1-i] = NewSpanTerm(term)
+	}
+
+	spans := matchSpans(t, NewSpanNear(clauses, 1000, false), tlm)
+	if len(spans) != 237 {
+		t.Errorf("expected a span per window of 4 positions, got %d", len(spans))
+	}
+	spans = matchSpans(t, NewSpanNear(clauses, 1000, true), tlm)
+	if len(spans) != 57 {
+		t.Errorf("expected a span per delta followed by the others, got %d", len(spans))
+	}
+
+	spans = matchSpans(t, NewSpanIntervals(clauses, false), tlm)
+	if len(spans) != 237 {
+		t.Errorf("expected an interval per window of 4 positions, got %d", len(spans))
+	}
+	spans = matchSpans(t, NewSpanIntervals(clauses, true), tlm)
+	if len(spans) != 57 {
+		t.Errorf("expected an interval per delta followed by the others, got %d", len(spans))
+	}
+
+	defer func(steps int) {
+		SpanNearMaxSteps = steps
+	}(SpanNearMaxSteps)
+	SpanNearMaxSteps = 10
+	_, err := NewSpanNear(clauses, 1000, false).spans(tlm)
+	if err == nil {
+		t.Errorf("expected error once the steps are taken")
+	}
+	// spans within other matchers fail them too
+	_, err = NewSpanOr([]SpanMatcher{NewSpanTerm("alpha"), NewSpanNear(clauses, 1000, true)}).spans(tlm)
+	if err == nil {
+		t.Errorf("expected error from a span or once the steps are taken")
+	}
+}
@@ -0,0 +1,133 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sketch provides mergeable approximate data structures used
+// to summarize the values seen while collecting search results.
+package sketch
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+const (
+	// DefaultHyperLogLogPrecision uses 2^14 registers,
+	// which gives a standard error of roughly 0.8%.
+	DefaultHyperLogLogPrecision = 14
+
+	minHyperLogLogPrecision = 4
+	maxHyperLogLogPrecision = 18
+)
+
+// HyperLogLog estimates the number of distinct 64-bit hashes added to it.
+// Sketches with the same precision can be merged, which makes them
+// suitable for combining the results of several indexes.
+type HyperLogLog struct {
+	Precision uint8  `json:"precision"`
+	Registers []byte `json:"registers"`
+}
+
+// NewHyperLogLog returns an empty sketch with 2^precision registers.
+func NewHyperLogLog(precision uint8) (*HyperLogLog, error) {
+	if precision < minHyperLogLogPrecision || precision > maxHyperLogLogPrecision {
+		return nil, fmt.Errorf("hyperloglog precision must be between %d and %d, got %d",
+			minHyperLogLogPrecision, maxHyperLogLogPrecision, precision)
+	}
+	return &HyperLogLog{
+		Precision: precision,
+		Registers: make([]byte, 1<<precision),
+	}, nil
+}
+
+// Add records a hashed value, the hash must be well distributed
+// over all 64 bits, see Hash64.
+func (h *HyperLogLog) Add(hash uint64) {
+	idx := hash >> (64 - h.Precision)
+	// the remaining bits, with a sentinel so the rank is bounded
+	w := hash<<h.Precision | 1<<(h.Precision-1)
+	rank := uint8(bits.LeadingZeros64(w)) + 1
+	if rank > h.Registers[idx] {
+		h.Registers[idx] = rank
+	}
+}
+
+// Merge folds other into this sketch.
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if other == nil {
+		return nil
+	}
+	if h.Precision != other.Precision || len(h.Registers) != len(other.Registers) {
+		return fmt.Errorf("cannot merge hyperloglog of precision %d with precision %d",
+			other.Precision, h.Precision)
+	}
+	for i, r := range other.Registers {
+		if r > h.Registers[i] {
+			h.Registers[i] = r
+		}
+	}
+	return nil
+}
+
+// Count returns the estimated number of distinct values added.
+func (h *HyperLogLog) Count() uint64 {
+	m := float64(len(h.Registers))
+	if m == 0 {
+		return 0
+	}
+
+	var sum float64
+	var zeros int
+	for _, r := range h.Registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := alpha(m) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// small range correction, linear counting is more accurate
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+func (h *HyperLogLog) Size() int {
+	return reflectStaticSizeHyperLogLog + len(h.Registers)
+}
+
+func alpha(m float64) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/m)
+}
+
+// Hash64 mixes the bits of v so that it can be added to a HyperLogLog.
+// The result is stable across processes, so sketches built on different
+// nodes remain mergeable.
+func Hash64(v uint64) uint64 {
+	v ^= v >> 33
+	v *= 0xff51afd7ed558ccd
+	v ^= v >> 33
+	v *= 0xc4ceb9fe1a85ec53
+	v ^= v >> 33
+	return v
+}
@@ -0,0 +1,123 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sketch
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	h1, err := NewHyperLogLog(DefaultHyperLogLogPrecision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, _ := NewHyperLogLog(DefaultHyperLogLogPrecision)
+
+	// small cardinalities are exact thanks to linear counting
+	for i := 0; i < 10; i++ {
+		h1.Add(Hash64(uint64(i)))
+		h1.Add(Hash64(uint64(i)))
+	}
+	if h1.Count() != 10 {
+		t.Errorf("expected 10, got %d", h1.Count())
+	}
+
+	// overlapping ranges [0,60000) and [40000,100000)
+	for i := 0; i < 60000; i++ {
+		h1.Add(Hash64(uint64(i)))
+	}
+	for i := 40000; i < 100000; i++ {
+		h2.Add(Hash64(uint64(i)))
+	}
+
+	// merge a copy which went through json, as remote results would
+	buf, err := json.Marshal(h2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var h2copy HyperLogLog
+	if err := json.Unmarshal(buf, &h2copy); err != nil {
+		t.Fatal(err)
+	}
+	if err := h1.Merge(&h2copy); err != nil {
+		t.Fatal(err)
+	}
+
+	count := float64(h1.Count())
+	if math.Abs(count-100000)/100000 > 0.03 {
+		t.Errorf("expected approximately 100000, got %f", count)
+	}
+
+	h3, _ := NewHyperLogLog(10)
+	if err := h1.Merge(h3); err == nil {
+		t.Errorf("expected error merging sketches of different precision")
+	}
+	if _, err := NewHyperLogLog(30); err == nil {
+		t.Errorf("expected error for invalid precision")
+	}
+}
+
+func TestTDigest(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	t1 := NewTDigest(DefaultTDigestCompression)
+	t2 := NewTDigest(DefaultTDigestCompression)
+	for i := 0; i < 50000; i++ {
+		t1.Add(r.Float64() * 1000)
+		t2.Add(r.Float64() * 1000)
+	}
+	t2.Compress()
+
+	buf, err := json.Marshal(t2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var t2copy TDigest
+	if err := json.Unmarshal(buf, &t2copy); err != nil {
+		t.Fatal(err)
+	}
+	t1.Merge(&t2copy)
+
+	if t1.Count != 100000 {
+		t.Errorf("expected count 100000, got %f", t1.Count)
+	}
+	if len(t1.Centroids) > 5*DefaultTDigestCompression {
+		t.Errorf("expected digest to be compressed, got %d centroids", len(t1.Centroids))
+	}
+
+	for _, q := range []float64{0.01, 0.25, 0.5, 0.75, 0.99} {
+		got := t1.Quantile(q)
+		if math.Abs(got-q*1000) > 10 {
+			t.Errorf("expected quantile %f to be approximately %f, got %f", q, q*1000, got)
+		}
+	}
+	if t1.Quantile(0) != t1.Min || t1.Quantile(1) != t1.Max {
+		t.Errorf("expected extreme quantiles to be min and max")
+	}
+
+	empty := NewTDigest(0)
+	if !math.IsNaN(empty.Quantile(0.5)) {
+		t.Errorf("expected NaN from an empty digest")
+	}
+
+	single := NewTDigest(0)
+	single.Add(42)
+	if single.Quantile(0.5) != 42 {
+		t.Errorf("expected 42, got %f", single.Quantile(0.5))
+	}
+}
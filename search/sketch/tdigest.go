@@ -0,0 +1,204 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sketch
+
+import (
+	"math"
+	"reflect"
+	"sort"
+)
+
+var (
+	reflectStaticSizeHyperLogLog int
+	reflectStaticSizeTDigest     int
+	reflectStaticSizeCentroid    int
+)
+
+func init() {
+	var h HyperLogLog
+	reflectStaticSizeHyperLogLog = int(reflect.TypeOf(h).Size())
+	var td TDigest
+	reflectStaticSizeTDigest = int(reflect.TypeOf(td).Size())
+	var c Centroid
+	reflectStaticSizeCentroid = int(reflect.TypeOf(c).Size())
+}
+
+// DefaultTDigestCompression bounds a digest to a few hundred centroids,
+// with quantile errors well under 1% for most distributions.
+const DefaultTDigestCompression = 100
+
+// Centroid summarizes Count values with the given Mean.
+type Centroid struct {
+	Mean  float64 `json:"mean"`
+	Count float64 `json:"count"`
+}
+
+// TDigest estimates quantiles of the values added to it, using a
+// merging t-digest. Digests can be merged with each other, which makes
+// them suitable for combining the results of several indexes.
+type TDigest struct {
+	Compression float64    `json:"compression"`
+	Centroids   []Centroid `json:"centroids"`
+	Count       float64    `json:"count"`
+	Min         float64    `json:"min"`
+	Max         float64    `json:"max"`
+
+	unmerged []Centroid
+}
+
+// NewTDigest returns an empty digest, higher compression
+// retains more centroids and gives more accurate quantiles.
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = DefaultTDigestCompression
+	}
+	return &TDigest{
+		Compression: compression,
+		Min:         math.Inf(1),
+		Max:         math.Inf(-1),
+	}
+}
+
+// Add records a single value.
+func (t *TDigest) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	t.unmerged = append(t.unmerged, Centroid{Mean: x, Count: 1})
+	if x < t.Min {
+		t.Min = x
+	}
+	if x > t.Max {
+		t.Max = x
+	}
+	if len(t.unmerged) >= t.bufferSize() {
+		t.Compress()
+	}
+}
+
+// Merge folds other into this digest.
+func (t *TDigest) Merge(other *TDigest) {
+	if other == nil {
+		return
+	}
+	t.unmerged = append(t.unmerged, other.Centroids...)
+	t.unmerged = append(t.unmerged, other.unmerged...)
+	if other.Min < t.Min {
+		t.Min = other.Min
+	}
+	if other.Max > t.Max {
+		t.Max = other.Max
+	}
+	t.Compress()
+}
+
+func (t *TDigest) bufferSize() int {
+	return int(5 * t.Compression)
+}
+
+// Compress merges any buffered values into the centroids.
+func (t *TDigest) Compress() {
+	if len(t.unmerged) == 0 {
+		return
+	}
+
+	all := append(t.Centroids, t.unmerged...)
+	t.unmerged = t.unmerged[:0]
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Mean < all[j].Mean
+	})
+
+	var total float64
+	for _, c := range all {
+		total += c.Count
+	}
+
+	merged := make([]Centroid, 0, int(t.Compression))
+	cur := all[0]
+	var soFar float64
+	kLeft := t.scale(0)
+	for _, c := range all[1:] {
+		// a centroid may span at most one unit of the scale function,
+		// keeping centroids near the tails small and accurate
+		qRight := (soFar + cur.Count + c.Count) / total
+		if t.scale(qRight)-kLeft <= 1 {
+			// fold c into the current centroid
+			cur.Count += c.Count
+			cur.Mean += (c.Mean - cur.Mean) * c.Count / cur.Count
+			continue
+		}
+		soFar += cur.Count
+		kLeft = t.scale(soFar / total)
+		merged = append(merged, cur)
+		cur = c
+	}
+	merged = append(merged, cur)
+
+	t.Centroids = merged
+	t.Count = total
+}
+
+// scale is the k1 scale function, mapping quantile q to an index
+// in [-compression/4, compression/4].
+func (t *TDigest) scale(q float64) float64 {
+	return t.Compression / (2 * math.Pi) * math.Asin(2*math.Min(q, 1)-1)
+}
+
+// Quantile returns the estimated value at quantile q, which must be
+// between 0 and 1. It returns NaN if no values were added.
+func (t *TDigest) Quantile(q float64) float64 {
+	t.Compress()
+	if len(t.Centroids) == 0 || q < 0 || q > 1 {
+		return math.NaN()
+	}
+	if len(t.Centroids) == 1 || q == 0 {
+		if q == 1 {
+			return t.Max
+		}
+		if q == 0 {
+			return t.Min
+		}
+		return t.Centroids[0].Mean
+	}
+	if q == 1 {
+		return t.Max
+	}
+
+	target := q * t.Count
+	// each centroid is treated as centered on its cumulative midpoint,
+	// values in between are interpolated, the extremes towards min/max
+	var cumulative float64
+	for i, c := range t.Centroids {
+		mid := cumulative + c.Count/2
+		if target < mid {
+			if i == 0 {
+				return t.Min + (c.Mean-t.Min)*target/mid
+			}
+			prev := t.Centroids[i-1]
+			prevMid := cumulative - prev.Count/2
+			return prev.Mean + (c.Mean-prev.Mean)*(target-prevMid)/(mid-prevMid)
+		}
+		cumulative += c.Count
+	}
+
+	last := t.Centroids[len(t.Centroids)-1]
+	lastMid := t.Count - last.Count/2
+	return last.Mean + (t.Max-last.Mean)*(target-lastMid)/(t.Count-lastMid)
+}
+
+func (t *TDigest) Size() int {
+	return reflectStaticSizeTDigest +
+		(cap(t.Centroids)+cap(t.unmerged))*reflectStaticSizeCentroid
+}
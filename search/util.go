@@ -17,6 +17,7 @@ package search
 import (
 	"context"
 
+	index "github.com/blevesearch/bleve_index_api"
 	"github.com/blevesearch/geo/s2"
 )
 
@@ -176,6 +177,16 @@ const (
 	// NestedSearchKey is used to communicate whether the search is performed
 	// in an index with nested documents
 	NestedSearchKey ContextKey = "_nested_search_key"
+
+	// MergeFacetsKey is used to communicate that the facet results of the
+	// search are merged with those of other indexes, so the histograms
+	// are to be filled in once merged rather than by each index
+	MergeFacetsKey ContextKey = "_merge_facets_key"
+
+	// TermsLookupDocumentKey is used to provide the search with a
+	// TermsLookupDocumentCallbackFn, finding the documents of terms
+	// lookup queries which name the index to look them up in
+	TermsLookupDocumentKey ContextKey = "_terms_lookup_document_key"
 )
 
 func RecordSearchCost(ctx context.Context,
@@ -231,6 +242,9 @@ type (
 	// DocumentMatch is merged into its parent. This allows different descendant addition strategies for
 	// different use cases (e.g., TopN vs KNN collection).
 	DescendantAdderCallbackFn func(parent *DocumentMatch, descendant *DocumentMatch) error
+	// TermsLookupDocumentCallbackFn is a callback function type used to get the
+	// document with the id from the named index, for terms lookup queries.
+	TermsLookupDocumentCallbackFn func(indexName, id string) (index.Document, error)
 	// GeoBufferPoolCallbackFunc is a callback function type used to get the geo buffer pool
 	// to be used during geo searches.
 	GeoBufferPoolCallbackFunc func() *s2.GeoBufferPool
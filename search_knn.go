@@ -39,19 +39,27 @@ type knnOperator string
 var BleveMaxK = int64(10000)
 
 type SearchRequest struct {
-	ClientContextID  string            `json:"client_context_id,omitempty"`
-	Query            query.Query       `json:"query"`
-	Size             int               `json:"size"`
-	From             int               `json:"from"`
-	Highlight        *HighlightRequest `json:"highlight,omitempty"`
-	Fields           []string          `json:"fields,omitempty"`
-	Facets           FacetsRequest     `json:"facets,omitempty"`
-	Explain          bool              `json:"explain"`
-	Sort             search.SortOrder  `json:"sort"`
-	IncludeLocations bool              `json:"includeLocations"`
-	Score            string            `json:"score,omitempty"`
-	SearchAfter      []string          `json:"search_after,omitempty"`
-	SearchBefore     []string          `json:"search_before,omitempty"`
+	ClientContextID  string               `json:"client_context_id,omitempty"`
+	Query            query.Query          `json:"query"`
+	Size             int                  `json:"size"`
+	From             int                  `json:"from"`
+	Highlight        *HighlightRequest    `json:"highlight,omitempty"`
+	Fields           []string             `json:"fields,omitempty"`
+	Facets           FacetsRequest        `json:"facets,omitempty"`
+	Explain          bool                 `json:"explain"`
+	Sort             search.SortOrder     `json:"sort"`
+	IncludeLocations bool                 `json:"includeLocations"`
+	Score            string               `json:"score,omitempty"`
+	SearchAfter      []string             `json:"search_after,omitempty"`
+	SearchBefore     []string             `json:"search_before,omitempty"`
+	Collapse         *CollapseRequest     `json:"collapse,omitempty"`
+	Source           *SourceFilter        `json:"source,omitempty"`
+	RuntimeFields    RuntimeFieldsRequest `json:"runtime_fields,omitempty"`
+
+	// PointInTimeID targets the search at a point in time opened using
+	// PointInTimeIndex.OpenPointInTime, rather than the latest state
+	// of the index.
+	PointInTimeID string `json:"point_in_time_id,omitempty"`
 
 	KNN         []*KNNRequest `json:"knn,omitempty"`
 	KNNOperator knnOperator   `json:"knn_operator,omitempty"`
@@ -136,22 +144,26 @@ func (r *SearchRequest) UnmarshalJSON(input []byte) error {
 	}
 
 	var temp struct {
-		Q                json.RawMessage    `json:"query"`
-		Size             *int               `json:"size"`
-		From             int                `json:"from"`
-		Highlight        *HighlightRequest  `json:"highlight"`
-		Fields           []string           `json:"fields"`
-		Facets           FacetsRequest      `json:"facets"`
-		Explain          bool               `json:"explain"`
-		Sort             []json.RawMessage  `json:"sort"`
-		IncludeLocations bool               `json:"includeLocations"`
-		Score            string             `json:"score"`
-		SearchAfter      []string           `json:"search_after"`
-		SearchBefore     []string           `json:"search_before"`
-		KNN              []*tempKNNReq      `json:"knn"`
-		KNNOperator      knnOperator        `json:"knn_operator"`
-		PreSearchData    OptionalRawMessage `json:"pre_search_data"`
-		Params           OptionalRawMessage `json:"params"`
+		Q                json.RawMessage      `json:"query"`
+		Size             *int                 `json:"size"`
+		From             int                  `json:"from"`
+		Highlight        *HighlightRequest    `json:"highlight"`
+		Fields           []string             `json:"fields"`
+		Facets           FacetsRequest        `json:"facets"`
+		Explain          bool                 `json:"explain"`
+		Sort             []json.RawMessage    `json:"sort"`
+		IncludeLocations bool                 `json:"includeLocations"`
+		Score            string               `json:"score"`
+		SearchAfter      []string             `json:"search_after"`
+		SearchBefore     []string             `json:"search_before"`
+		Collapse         *CollapseRequest     `json:"collapse"`
+		Source           *SourceFilter        `json:"source"`
+		RuntimeFields    RuntimeFieldsRequest `json:"runtime_fields"`
+		PointInTimeID    string               `json:"point_in_time_id"`
+		KNN              []*tempKNNReq        `json:"knn"`
+		KNNOperator      knnOperator          `json:"knn_operator"`
+		PreSearchData    OptionalRawMessage   `json:"pre_search_data"`
+		Params           OptionalRawMessage   `json:"params"`
 	}
 
 	err := util.UnmarshalJSON(input, &temp)
@@ -181,6 +193,10 @@ func (r *SearchRequest) UnmarshalJSON(input []byte) error {
 	r.Score = temp.Score
 	r.SearchAfter = temp.SearchAfter
 	r.SearchBefore = temp.SearchBefore
+	r.Collapse = temp.Collapse
+	r.Source = temp.Source
+	r.RuntimeFields = temp.RuntimeFields
+	r.PointInTimeID = temp.PointInTimeID
 	r.Query, err = query.ParseQuery(temp.Q)
 	if err != nil {
 		return err
@@ -259,6 +275,9 @@ func copySearchRequest(req *SearchRequest, preSearchData map[string]interface{})
 		Score:            req.Score,
 		SearchAfter:      req.SearchAfter,
 		SearchBefore:     req.SearchBefore,
+		Collapse:         req.Collapse.withoutInnerHits(),
+		Source:           req.Source,
+		RuntimeFields:    req.RuntimeFields,
 		KNN:              req.KNN,
 		KNNOperator:      req.KNNOperator,
 		PreSearchData:    preSearchData,
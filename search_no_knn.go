@@ -48,23 +48,34 @@ const supportForVectorSearch = false
 // Score controls the kind of scoring performed
 // SearchAfter supports deep paging by providing a minimum sort key
 // SearchBefore supports deep paging by providing a maximum sort key
+// Collapse returns only the top hit for each distinct value of a field
+// Source returns the original source of hits, when stored by the mapping
+// RuntimeFields describes fields computed at search time from doc values
 // sortFunc specifies the sort implementation to use for sorting results.
 //
 // A special field named "*" can be used to return all fields.
 type SearchRequest struct {
-	ClientContextID  string            `json:"client_context_id,omitempty"`
-	Query            query.Query       `json:"query"`
-	Size             int               `json:"size"`
-	From             int               `json:"from"`
-	Highlight        *HighlightRequest `json:"highlight,omitempty"`
-	Fields           []string          `json:"fields,omitempty"`
-	Facets           FacetsRequest     `json:"facets,omitempty"`
-	Explain          bool              `json:"explain"`
-	Sort             search.SortOrder  `json:"sort"`
-	IncludeLocations bool              `json:"includeLocations"`
-	Score            string            `json:"score,omitempty"`
-	SearchAfter      []string          `json:"search_after,omitempty"`
-	SearchBefore     []string          `json:"search_before,omitempty"`
+	ClientContextID  string               `json:"client_context_id,omitempty"`
+	Query            query.Query          `json:"query"`
+	Size             int                  `json:"size"`
+	From             int                  `json:"from"`
+	Highlight        *HighlightRequest    `json:"highlight,omitempty"`
+	Fields           []string             `json:"fields,omitempty"`
+	Facets           FacetsRequest        `json:"facets,omitempty"`
+	Explain          bool                 `json:"explain"`
+	Sort             search.SortOrder     `json:"sort"`
+	IncludeLocations bool                 `json:"includeLocations"`
+	Score            string               `json:"score,omitempty"`
+	SearchAfter      []string             `json:"search_after,omitempty"`
+	SearchBefore     []string             `json:"search_before,omitempty"`
+	Collapse         *CollapseRequest     `json:"collapse,omitempty"`
+	Source           *SourceFilter        `json:"source,omitempty"`
+	RuntimeFields    RuntimeFieldsRequest `json:"runtime_fields,omitempty"`
+
+	// PointInTimeID targets the search at a point in time opened using
+	// PointInTimeIndex.OpenPointInTime, rather than the latest state
+	// of the index.
+	PointInTimeID string `json:"point_in_time_id,omitempty"`
 
 	// PreSearchData will be a  map that will be used
 	// in the second phase of any 2-phase search, to provide additional
@@ -87,20 +98,24 @@ type SearchRequest struct {
 // a SearchRequest
 func (r *SearchRequest) UnmarshalJSON(input []byte) error {
 	var temp struct {
-		Q                json.RawMessage    `json:"query"`
-		Size             *int               `json:"size"`
-		From             int                `json:"from"`
-		Highlight        *HighlightRequest  `json:"highlight"`
-		Fields           []string           `json:"fields"`
-		Facets           FacetsRequest      `json:"facets"`
-		Explain          bool               `json:"explain"`
-		Sort             []json.RawMessage  `json:"sort"`
-		IncludeLocations bool               `json:"includeLocations"`
-		Score            string             `json:"score"`
-		SearchAfter      []string           `json:"search_after"`
-		SearchBefore     []string           `json:"search_before"`
-		PreSearchData    OptionalRawMessage `json:"pre_search_data"`
-		Params           OptionalRawMessage `json:"params"`
+		Q                json.RawMessage      `json:"query"`
+		Size             *int                 `json:"size"`
+		From             int                  `json:"from"`
+		Highlight        *HighlightRequest    `json:"highlight"`
+		Fields           []string             `json:"fields"`
+		Facets           FacetsRequest        `json:"facets"`
+		Explain          bool                 `json:"explain"`
+		Sort             []json.RawMessage    `json:"sort"`
+		IncludeLocations bool                 `json:"includeLocations"`
+		Score            string               `json:"score"`
+		SearchAfter      []string             `json:"search_after"`
+		SearchBefore     []string             `json:"search_before"`
+		Collapse         *CollapseRequest     `json:"collapse"`
+		Source           *SourceFilter        `json:"source"`
+		RuntimeFields    RuntimeFieldsRequest `json:"runtime_fields"`
+		PointInTimeID    string               `json:"point_in_time_id"`
+		PreSearchData    OptionalRawMessage   `json:"pre_search_data"`
+		Params           OptionalRawMessage   `json:"params"`
 	}
 
 	err := util.UnmarshalJSON(input, &temp)
@@ -130,6 +145,10 @@ func (r *SearchRequest) UnmarshalJSON(input []byte) error {
 	r.Score = temp.Score
 	r.SearchAfter = temp.SearchAfter
 	r.SearchBefore = temp.SearchBefore
+	r.Collapse = temp.Collapse
+	r.Source = temp.Source
+	r.RuntimeFields = temp.RuntimeFields
+	r.PointInTimeID = temp.PointInTimeID
 	r.Query, err = query.ParseQuery(temp.Q)
 	if err != nil {
 		return err
@@ -185,6 +204,9 @@ func copySearchRequest(req *SearchRequest, preSearchData map[string]interface{})
 		Score:            req.Score,
 		SearchAfter:      req.SearchAfter,
 		SearchBefore:     req.SearchBefore,
+		Collapse:         req.Collapse.withoutInnerHits(),
+		Source:           req.Source,
+		RuntimeFields:    req.RuntimeFields,
 		PreSearchData:    preSearchData,
 	}
 	return &rv
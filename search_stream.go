@@ -0,0 +1,258 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/blevesearch/bleve/v2/numeric"
+	"github.com/blevesearch/bleve/v2/search"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// StreamingIndex supports visiting every document matching a search
+// request, without the cost of retaining and ranking the top hits.
+type StreamingIndex interface {
+	Index
+	// SearchStream invokes fn for each document matching the request's
+	// query, in index order, stopping at the first error returned by fn.
+	// Hits carry the requested Fields, read from stored fields where
+	// available and otherwise from doc values, along with highlighting
+	// when requested.  Hits are not scored, and the request's Size,
+	// From, Sort, SearchAfter, SearchBefore, Facets and Collapse are
+	// ignored.  Hits are recycled once fn returns, so fn must copy
+	// anything it needs to retain.
+	SearchStream(ctx context.Context, req *SearchRequest, fn func(*search.DocumentMatch) error) error
+}
+
+// SearchStream invokes fn for each document matching the request,
+// in index order.
+func (i *indexImpl) SearchStream(ctx context.Context, req *SearchRequest,
+	fn func(*search.DocumentMatch) error) (err error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	searchStart := time.Now()
+
+	if !i.open {
+		return ErrorIndexClosed
+	}
+
+	indexReader, closeReader, err := i.searchReader(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := closeReader(); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+
+	err = i.searchStreamReader(ctx, req, indexReader, fn)
+	if err != nil {
+		return err
+	}
+
+	atomic.AddUint64(&i.stats.searches, 1)
+	atomic.AddUint64(&i.stats.searchTime, uint64(time.Since(searchStart)))
+	return nil
+}
+
+// searchStreamReader invokes fn for each document in the reader matching
+// the request, it does not require the index lock to be held.
+func (i *indexImpl) searchStreamReader(ctx context.Context, req *SearchRequest,
+	indexReader index.IndexReader, fn func(*search.DocumentMatch) error) (err error) {
+	ctx = i.searchContext(ctx)
+
+	highlighter, err := requestHighlighter(req)
+	if err != nil {
+		return err
+	}
+
+	// fields not loaded from stored fields fall back to
+	// their doc values, if any
+	var dvFields []string
+	for _, field := range req.Fields {
+		if field != "*" {
+			dvFields = append(dvFields, field)
+		}
+	}
+	var dvReader index.DocValueReader
+	if len(dvFields) > 0 {
+		dvReader, err = indexReader.DocValueReader(dvFields)
+		if err != nil {
+			return err
+		}
+	}
+
+	// collect with a request that retains no hits, every hit is
+	// handed to fn by the document match handler instead
+	streamReq := &SearchRequest{
+		Query: req.Query,
+		Sort:  search.SortOrder{&search.SortScore{Desc: true}},
+	}
+	coll, err := i.buildTopNCollector(ctx, streamReq, indexReader)
+	if err != nil {
+		return err
+	}
+
+	var streamFieldValues func(hit *search.DocumentMatch) error
+	if dvReader != nil {
+		dvFieldPos := make(map[string]int, len(dvFields))
+		dvFieldTypes := make([]string, len(dvFields))
+		for pos, field := range dvFields {
+			dvFieldPos[field] = pos
+			dvFieldTypes[pos] = i.m.FieldMappingForPath(field).Type
+		}
+		// fields already loaded from stored fields for the current hit
+		stored := make([]bool, len(dvFields))
+		var hit *search.DocumentMatch
+		visitor := func(field string, term []byte) {
+			pos, ok := dvFieldPos[field]
+			if !ok || stored[pos] {
+				return
+			}
+			if value, ok := decodeDocValue(dvFieldTypes[pos], term); ok {
+				hit.AddFieldValue(field, value)
+			}
+		}
+		streamFieldValues = func(d *search.DocumentMatch) error {
+			hit = d
+			for pos, field := range dvFields {
+				_, stored[pos] = hit.Fields[field]
+			}
+			return dvReader.VisitDocValues(hit.IndexInternalID, visitor)
+		}
+	}
+
+	makeHandler := func(sctx *search.SearchContext) (search.DocumentMatchHandler, bool, error) {
+		return func(hit *search.DocumentMatch) error {
+			if hit == nil {
+				return nil
+			}
+			if i.name != "" {
+				hit.Index = i.name
+			}
+			err, _ := LoadAndHighlightAllFields(hit, req, i.name, indexReader, highlighter)
+			if err != nil {
+				return err
+			}
+			if streamFieldValues != nil {
+				err = streamFieldValues(hit)
+				if err != nil {
+					return err
+				}
+			}
+			hit.Complete(nil)
+			err = fn(hit)
+			sctx.DocumentMatchPool.Put(hit)
+			return err
+		}, true, nil
+	}
+	ctx = context.WithValue(ctx, search.MakeDocumentMatchHandlerKey,
+		search.MakeDocumentMatchHandler(makeHandler))
+
+	searcher, err := req.Query.Searcher(ctx, indexReader, i.m, search.SearcherOptions{
+		IncludeTermVectors: req.IncludeLocations || req.Highlight != nil,
+		Score:              "none",
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if serr := searcher.Close(); err == nil && serr != nil {
+			err = serr
+		}
+	}()
+
+	return coll.Collect(ctx, searcher, indexReader)
+}
+
+// decodeDocValue converts a doc value term into the value returned
+// in hit fields, according to the mapped type of its field.
+func decodeDocValue(fieldType string, term []byte) (interface{}, bool) {
+	switch fieldType {
+	case "number", "datetime":
+		// only full precision terms represent the value
+		pc := numeric.PrefixCoded(term)
+		if shift, err := pc.Shift(); err != nil || shift != 0 {
+			return nil, false
+		}
+		i64, err := pc.Int64()
+		if err != nil {
+			return nil, false
+		}
+		if fieldType == "datetime" {
+			return time.Unix(0, i64).UTC().Format(time.RFC3339Nano), true
+		}
+		return numeric.Int64ToFloat64(i64), true
+	case "boolean":
+		return string(term) == "T", true
+	case "geopoint", "geoshape", "IP", "vector", "vector_base64":
+		return nil, false
+	default:
+		return string(term), true
+	}
+}
+
+// SearchStream invokes fn for each document matching the request in
+// each index of the alias in turn.  Every index in the alias must
+// support streaming.
+func (i *indexAliasImpl) SearchStream(ctx context.Context, req *SearchRequest,
+	fn func(*search.DocumentMatch) error) error {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	if !i.open {
+		return ErrorIndexClosed
+	}
+
+	indexes := i.indexes
+	if req.PointInTimeID != "" {
+		pit, err := i.pits.acquire(req.PointInTimeID)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = i.pits.done(pit)
+		}()
+		indexes = pit.indexes
+	}
+
+	for _, in := range indexes {
+		streamIndex, ok := in.(StreamingIndex)
+		if !ok {
+			return ErrorStreamingNotSupported
+		}
+		err := streamIndex.SearchStream(ctx, req, fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *pointInTimeIndex) SearchStream(ctx context.Context, req *SearchRequest,
+	fn func(*search.DocumentMatch) error) error {
+	streamIndex, ok := p.PointInTimeIndex.(StreamingIndex)
+	if !ok {
+		return ErrorStreamingNotSupported
+	}
+	pitReq := *req
+	pitReq.PointInTimeID = p.id
+	return streamIndex.SearchStream(ctx, &pitReq, fn)
+}
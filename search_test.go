@@ -47,6 +47,7 @@ import (
 	"github.com/blevesearch/bleve/v2/analysis/token/length"
 	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
 	"github.com/blevesearch/bleve/v2/analysis/token/shingle"
+	"github.com/blevesearch/bleve/v2/analysis/token/synonym"
 	"github.com/blevesearch/bleve/v2/analysis/tokenizer/single"
 	"github.com/blevesearch/bleve/v2/analysis/tokenizer/whitespace"
 	"github.com/blevesearch/bleve/v2/document"
@@ -5794,3 +5795,1938 @@ func TestSearchRequestValidatePagination(t *testing.T) {
 		})
 	}
 }
+
+func TestNestedFacets(t *testing.T) {
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+
+	idx, err := New(tmpIndexPath, NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := map[string]map[string]interface{}{
+		"1": {"brand": "acme", "color": "red", "price": 5.0},
+		"2": {"brand": "acme", "color": "blue", "price": 15.0},
+		"3": {"brand": "acme", "color": "red", "price": 25.0},
+		"4": {"brand": "globex", "color": "red", "price": 7.0},
+		"5": {"brand": "globex", "price": 12.0},
+	}
+	batch := idx.NewBatch()
+	for id, doc := range docs {
+		if err := batch.Index(id, doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := idx.Batch(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	ten := 10.0
+	priceFacet := NewFacetRequest("price", 10)
+	priceFacet.AddNumericRange("cheap", nil, &ten)
+	priceFacet.AddNumericRange("expensive", &ten, nil)
+	priceFacet.AddFacet("colors", NewFacetRequest("color", 10))
+
+	brandFacet := NewFacetRequest("brand", 10)
+	brandFacet.AddFacet("prices", priceFacet)
+
+	req := NewSearchRequest(NewMatchAllQuery())
+	req.AddFacet("brands", brandFacet)
+
+	// the request must survive a round trip through JSON
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jsonReq SearchRequest
+	if err := json.Unmarshal(reqBytes, &jsonReq); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, req := range []*SearchRequest{req, &jsonReq} {
+		res, err := idx.Search(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		brands := res.Facets["brands"]
+		if brands == nil || brands.Terms.Len() != 2 {
+			t.Fatalf("expected 2 brand buckets, got %#v", brands)
+		}
+
+		expectedPrices := map[string]map[string]int{
+			"acme":   {"cheap": 1, "expensive": 2},
+			"globex": {"cheap": 1, "expensive": 1},
+		}
+		expectedColors := map[string]map[string]map[string]int{
+			"acme": {
+				"cheap":     {"red": 1},
+				"expensive": {"blue": 1, "red": 1},
+			},
+			"globex": {
+				"cheap": {"red": 1},
+			},
+		}
+		for _, brand := range brands.Terms.Terms() {
+			prices := brand.Facets["prices"]
+			if prices == nil {
+				t.Fatalf("expected price facet for brand %s", brand.Term)
+			}
+			if len(prices.NumericRanges) != len(expectedPrices[brand.Term]) {
+				t.Fatalf("expected %d price ranges for brand %s, got %d",
+					len(expectedPrices[brand.Term]), brand.Term, len(prices.NumericRanges))
+			}
+			for _, nr := range prices.NumericRanges {
+				if nr.Count != expectedPrices[brand.Term][nr.Name] {
+					t.Errorf("expected %d for %s/%s, got %d",
+						expectedPrices[brand.Term][nr.Name], brand.Term, nr.Name, nr.Count)
+				}
+				colors := nr.Facets["colors"]
+				if colors == nil {
+					t.Fatalf("expected color facet for %s/%s", brand.Term, nr.Name)
+				}
+				expected := expectedColors[brand.Term][nr.Name]
+				if colors.Terms.Len() != len(expected) {
+					t.Errorf("expected %d colors for %s/%s, got %d",
+						len(expected), brand.Term, nr.Name, colors.Terms.Len())
+				}
+				for _, color := range colors.Terms.Terms() {
+					if color.Count != expected[color.Term] {
+						t.Errorf("expected %d for %s/%s/%s, got %d",
+							expected[color.Term], brand.Term, nr.Name, color.Term, color.Count)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMetricFacets(t *testing.T) {
+	var indexes []Index
+	for i := 0; i < 2; i++ {
+		tmpIndexPath := createTmpIndexPath(t)
+		defer cleanupTmpIndexPath(t, tmpIndexPath)
+
+		idx, err := New(tmpIndexPath, NewIndexMapping())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			err := idx.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}()
+		indexes = append(indexes, idx)
+	}
+
+	docs := []map[string]interface{}{
+		{"brand": "acme", "price": 10.0},
+		{"brand": "acme", "price": 20.0},
+		{"brand": "globex", "price": 5.0},
+		{"brand": "acme", "price": 30.0},
+		{"brand": "globex", "price": 5.0},
+		{"brand": "globex"},
+	}
+	for i, doc := range docs {
+		// spread the documents across both indexes
+		if err := indexes[i%2].Index(strconv.Itoa(i), doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	brandFacet := NewFacetRequest("brand", 10)
+	brandFacet.AddFacet("avg_price", NewMetricFacetRequest("price", search.MetricAvg))
+
+	req := NewSearchRequest(NewMatchAllQuery())
+	req.AddFacet("avg_price", NewMetricFacetRequest("price", search.MetricAvg))
+	req.AddFacet("max_price", NewMetricFacetRequest("price", search.MetricMax))
+	req.AddFacet("distinct_prices", NewMetricFacetRequest("price", search.MetricCardinality))
+	req.AddFacet("brands", brandFacet)
+
+	alias := NewIndexAlias(indexes...)
+	res, err := alias.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]float64{
+		"avg_price":       14,
+		"max_price":       30,
+		"distinct_prices": 4,
+	}
+	for name, value := range expected {
+		fr := res.Facets[name]
+		if fr == nil || fr.Metric == nil || fr.Metric.Value == nil {
+			t.Fatalf("expected metric result for %s", name)
+		}
+		if *fr.Metric.Value != value {
+			t.Errorf("expected %s to be %f, got %f", name, value, *fr.Metric.Value)
+		}
+	}
+	if res.Facets["avg_price"].Missing != 1 {
+		t.Errorf("expected 1 missing value, got %d", res.Facets["avg_price"].Missing)
+	}
+
+	expectedBrandAvg := map[string]float64{"acme": 20, "globex": 5}
+	for _, brand := range res.Facets["brands"].Terms.Terms() {
+		avg := brand.Facets["avg_price"]
+		if avg == nil || avg.Metric.Value == nil {
+			t.Fatalf("expected average price for brand %s", brand.Term)
+		}
+		if *avg.Metric.Value != expectedBrandAvg[brand.Term] {
+			t.Errorf("expected average price %f for brand %s, got %f",
+				expectedBrandAvg[brand.Term], brand.Term, *avg.Metric.Value)
+		}
+	}
+
+	badReq := NewSearchRequest(NewMatchAllQuery())
+	badReq.AddFacet("bad", NewMetricFacetRequest("price", "median"))
+	if _, err := indexes[0].Search(badReq); err == nil {
+		t.Errorf("expected error for unknown metric")
+	}
+}
+
+func TestHistogramFacets(t *testing.T) {
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+
+	idx, err := New(tmpIndexPath, NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := []map[string]interface{}{
+		{"price": 3.0, "created": "2024-01-05T10:00:00Z"},
+		{"price": 12.0, "created": "2024-01-20T10:00:00Z"},
+		{"price": 14.0, "created": "2024-03-02T10:00:00Z"},
+		{"price": 41.0, "created": "2024-03-30T10:00:00Z"},
+	}
+	for i, doc := range docs {
+		if err := idx.Index(strconv.Itoa(i), doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	priceFacet := NewHistogramFacetRequest("price", 100, 10)
+	priceFacet.MinDocCount = 1
+
+	createdFacet := NewDateHistogramFacetRequest("created", 100, "month")
+	createdFacet.AddFacet("prices", NewMetricFacetRequest("price", search.MetricSum))
+
+	req := NewSearchRequest(NewMatchAllQuery())
+	req.AddFacet("prices", priceFacet)
+	req.AddFacet("created", createdFacet)
+	res, err := idx.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPrices := map[float64]int{0: 1, 10: 2, 40: 1}
+	prices := res.Facets["prices"]
+	if len(prices.Histogram) != len(expectedPrices) {
+		t.Fatalf("expected %d price buckets, got %d", len(expectedPrices), len(prices.Histogram))
+	}
+	for _, h := range prices.Histogram {
+		if h.Count != expectedPrices[h.Key] {
+			t.Errorf("expected %d for price bucket %v, got %d", expectedPrices[h.Key], h.Key, h.Count)
+		}
+	}
+
+	expectedMonths := []struct {
+		key   string
+		count int
+		sum   float64
+	}{
+		{key: "2024-01-01T00:00:00Z", count: 2, sum: 15},
+		{key: "2024-02-01T00:00:00Z", count: 0},
+		{key: "2024-03-01T00:00:00Z", count: 2, sum: 55},
+	}
+	created := res.Facets["created"]
+	if len(created.Histogram) != len(expectedMonths) {
+		t.Fatalf("expected %d months, got %d", len(expectedMonths), len(created.Histogram))
+	}
+	for i, h := range created.Histogram {
+		if h.KeyAsString != expectedMonths[i].key || h.Count != expectedMonths[i].count {
+			t.Errorf("expected %s(%d), got %s(%d)", expectedMonths[i].key,
+				expectedMonths[i].count, h.KeyAsString, h.Count)
+		}
+		if h.Count == 0 {
+			continue
+		}
+		sum := h.Facets["prices"]
+		if sum == nil || sum.Metric.Value == nil || *sum.Metric.Value != expectedMonths[i].sum {
+			t.Errorf("expected price sum %f for %s", expectedMonths[i].sum, h.KeyAsString)
+		}
+	}
+
+	// the histograms of an alias are filled in once merged
+	tmpIndexPath2 := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath2)
+
+	idx2, err := New(tmpIndexPath2, NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx2.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := idx2.Index("4", map[string]interface{}{
+		"price": 15.0, "created": "2024-05-10T10:00:00Z"}); err != nil {
+		t.Fatal(err)
+	}
+
+	priceFacet = NewHistogramFacetRequest("price", 100, 10)
+	priceFacet.MinDocCount = 2
+	req = NewSearchRequest(NewMatchAllQuery())
+	req.AddFacet("prices", priceFacet)
+	req.AddFacet("created", NewDateHistogramFacetRequest("created", 100, "month"))
+	res, err = NewIndexAlias(idx, idx2).Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prices = res.Facets["prices"]
+	if len(prices.Histogram) != 1 || prices.Histogram[0].Key != 10 || prices.Histogram[0].Count != 3 {
+		t.Errorf("expected a single price bucket 10(3), got %v", prices.Histogram)
+	}
+	expectedCounts := []int{2, 0, 2, 0, 1}
+	created = res.Facets["created"]
+	if len(created.Histogram) != len(expectedCounts) {
+		t.Fatalf("expected %d months, got %d", len(expectedCounts), len(created.Histogram))
+	}
+	for i, h := range created.Histogram {
+		if h.Count != expectedCounts[i] {
+			t.Errorf("expected %d for %s, got %d", expectedCounts[i], h.KeyAsString, h.Count)
+		}
+	}
+
+	badFacet := NewDateHistogramFacetRequest("created", 100, "fortnight")
+	if err := badFacet.Validate(); err == nil {
+		t.Errorf("expected error for unknown calendar interval")
+	}
+	badFacet = NewHistogramFacetRequest("price", 100, 10)
+	badFacet.TimeZone = "Not/AZone"
+	if err := badFacet.Validate(); err == nil {
+		t.Errorf("expected error for invalid time zone")
+	}
+}
+
+func TestCollapse(t *testing.T) {
+	docs := []map[string]interface{}{
+		{"product": "p1", "price": 10.0, "rating": 1},
+		{"product": "p2", "price": 9.0, "rating": 2},
+		{"product": "p1", "price": 5.0, "rating": 1},
+		{"product": "p1", "price": 8.0, "rating": 1},
+		{"product": "p3", "price": 7.0, "rating": 2},
+		{"product": "p2", "price": 6.0, "rating": 2},
+	}
+	ids := []string{"a", "b", "c", "d", "e", "f"}
+
+	indexes := make([]Index, 2)
+	for i := range indexes {
+		tmpIndexPath := createTmpIndexPath(t)
+		defer cleanupTmpIndexPath(t, tmpIndexPath)
+
+		idx, err := New(tmpIndexPath, NewIndexMapping())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			err := idx.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}()
+		indexes[i] = idx
+	}
+	for i, doc := range docs {
+		if err := indexes[i/3].Index(ids[i], doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	alias := NewIndexAlias(indexes...)
+
+	var req SearchRequest
+	err := json.Unmarshal([]byte(`{
+		"query": {"match_all": {}},
+		"sort": ["-price"],
+		"size": 2,
+		"from": 1,
+		"collapse": {"field": "product", "inner_hits": 2}
+	}`), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := alias.Search(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != uint64(len(docs)) {
+		t.Errorf("expected total %d, got %d", len(docs), res.Total)
+	}
+
+	expected := []struct {
+		id        string
+		innerHits []string
+	}{
+		{id: "b", innerHits: []string{"b", "f"}},
+		{id: "e", innerHits: []string{"e"}},
+	}
+	if len(res.Hits) != len(expected) {
+		t.Fatalf("expected %d hits, got %d", len(expected), len(res.Hits))
+	}
+	for i, hit := range res.Hits {
+		if hit.ID != expected[i].id {
+			t.Errorf("expected hit %d to be %s, got %s", i, expected[i].id, hit.ID)
+		}
+		var innerIDs []string
+		for _, innerHit := range hit.InnerHits {
+			innerIDs = append(innerIDs, innerHit.ID)
+		}
+		if !reflect.DeepEqual(innerIDs, expected[i].innerHits) {
+			t.Errorf("expected inner hits %v for %s, got %v", expected[i].innerHits, hit.ID, innerIDs)
+		}
+	}
+	if res.Hits[0].CollapseValue != "p2" {
+		t.Errorf("expected collapse value p2, got %v", res.Hits[0].CollapseValue)
+	}
+	data, err := json.Marshal(res.Hits[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "collapse_key") {
+		t.Errorf("expected no raw collapse key, got %s", data)
+	}
+
+	// the inner hits of every key are found, even when
+	// the top hits of the keys all have the same key
+	req.From = 0
+	req.Size = 3
+	req.Collapse.InnerHits = 1
+	res, err = alias.Search(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var innerIDs [][]string
+	for _, hit := range res.Hits {
+		var ids []string
+		for _, innerHit := range hit.InnerHits {
+			ids = append(ids, innerHit.ID)
+			if len(innerHit.Sort) != 1 {
+				t.Errorf("expected the requested sort only, got %v", innerHit.Sort)
+			}
+		}
+		innerIDs = append(innerIDs, ids)
+	}
+	if !reflect.DeepEqual(innerIDs, [][]string{{"a"}, {"b"}, {"e"}}) {
+		t.Errorf("unexpected inner hits %v", innerIDs)
+	}
+
+	// numeric values are decoded
+	req.Collapse = NewCollapseRequest("rating")
+	req.Collapse.InnerHits = 2
+	res, err = alias.Search(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hits) != 2 || res.Hits[0].CollapseValue != 1.0 || res.Hits[1].CollapseValue != 2.0 {
+		t.Fatalf("unexpected collapsed hits %v", res.Hits)
+	}
+	innerIDs = nil
+	for _, hit := range res.Hits {
+		var ids []string
+		for _, innerHit := range hit.InnerHits {
+			ids = append(ids, innerHit.ID)
+		}
+		innerIDs = append(innerIDs, ids)
+	}
+	if !reflect.DeepEqual(innerIDs, [][]string{{"a", "d"}, {"b", "e"}}) {
+		t.Errorf("unexpected inner hits %v", innerIDs)
+	}
+
+	// searching a single index collapses within it
+	req.Size = 2
+	req.Collapse = NewCollapseRequest("product")
+	res, err = indexes[1].Search(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hits) != 2 || res.Hits[0].ID != "d" || res.Hits[1].ID != "e" {
+		t.Errorf("unexpected hits %v", res.Hits)
+	}
+	for _, hit := range res.Hits {
+		if hit.InnerHits != nil {
+			t.Errorf("expected no inner hits for %s", hit.ID)
+		}
+	}
+
+	req.SearchBefore = []string{"1"}
+	if err = req.Validate(); err == nil {
+		t.Errorf("expected error using search before with collapse")
+	}
+}
+
+func TestPointInTime(t *testing.T) {
+	indexes := make([]Index, 3)
+	for i := range indexes {
+		tmpIndexPath := createTmpIndexPath(t)
+		defer cleanupTmpIndexPath(t, tmpIndexPath)
+
+		idx, err := New(tmpIndexPath, NewIndexMapping())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			err := idx.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}()
+		indexes[i] = idx
+	}
+	for i := 0; i < 6; i++ {
+		err := indexes[i%2].Index(strconv.Itoa(i), map[string]interface{}{"n": float64(i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	alias := NewIndexAlias(indexes[0], indexes[1])
+	aliasPIT, err := alias.OpenPointInTime(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexPIT, err := indexes[0].(PointInTimeIndex).OpenPointInTime(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// changes after the point in time was opened
+	if err = indexes[0].Delete("0"); err != nil {
+		t.Fatal(err)
+	}
+	if err = indexes[1].Index("6", map[string]interface{}{"n": 6.0}); err != nil {
+		t.Fatal(err)
+	}
+	if err = indexes[2].Index("7", map[string]interface{}{"n": 7.0}); err != nil {
+		t.Fatal(err)
+	}
+	alias.Add(indexes[2])
+
+	searchIDs := func(idx Index, pitID string, after []string) []string {
+		req := NewSearchRequestOptions(NewMatchAllQuery(), 4, 0, false)
+		req.SortByCustom(search.SortOrder{&search.SortField{Field: "n", Type: search.SortFieldAsNumber}})
+		req.SearchAfter = after
+		req.PointInTimeID = pitID
+		res, err := idx.Search(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, hit := range res.Hits {
+			ids = append(ids, hit.ID)
+		}
+		return ids
+	}
+
+	// paging through the point in time sees the original documents
+	if ids := searchIDs(alias, aliasPIT, nil); !reflect.DeepEqual(ids, []string{"0", "1", "2", "3"}) {
+		t.Errorf("unexpected first page %v", ids)
+	}
+	if ids := searchIDs(alias, aliasPIT, []string{"3"}); !reflect.DeepEqual(ids, []string{"4", "5"}) {
+		t.Errorf("unexpected second page %v", ids)
+	}
+	if ids := searchIDs(indexes[0], indexPIT, nil); !reflect.DeepEqual(ids, []string{"0", "2", "4"}) {
+		t.Errorf("unexpected index hits %v", ids)
+	}
+	// while the latest state reflects the changes
+	if ids := searchIDs(alias, "", []string{"3"}); !reflect.DeepEqual(ids, []string{"4", "5", "6", "7"}) {
+		t.Errorf("unexpected latest hits %v", ids)
+	}
+
+	if err = alias.ReleasePointInTime(aliasPIT); err != nil {
+		t.Fatal(err)
+	}
+	req := NewSearchRequest(NewMatchAllQuery())
+	req.PointInTimeID = aliasPIT
+	if _, err = alias.Search(req); err != ErrorPointInTimeNotFound {
+		t.Errorf("expected point in time not found, got %v", err)
+	}
+	if err = alias.ReleasePointInTime(aliasPIT); err != ErrorPointInTimeNotFound {
+		t.Errorf("expected point in time not found, got %v", err)
+	}
+
+	// unused points in time expire
+	expiringPIT, err := indexes[1].(PointInTimeIndex).OpenPointInTime(10 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	req.PointInTimeID = expiringPIT
+	if _, err = indexes[1].Search(req); err != ErrorPointInTimeNotFound {
+		t.Errorf("expected point in time not found, got %v", err)
+	}
+
+	// the index point in time is still open, and released on close
+	req.PointInTimeID = indexPIT
+	if _, err = indexes[0].Search(req); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchStream(t *testing.T) {
+	indexMapping := NewIndexMapping()
+	numberMapping := NewNumericFieldMapping()
+	numberMapping.Store = false
+	indexMapping.DefaultMapping.AddFieldMappingsAt("n", numberMapping)
+
+	indexes := make([]Index, 2)
+	for i := range indexes {
+		tmpIndexPath := createTmpIndexPath(t)
+		defer cleanupTmpIndexPath(t, tmpIndexPath)
+
+		idx, err := New(tmpIndexPath, indexMapping)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			err := idx.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}()
+		indexes[i] = idx
+	}
+
+	numDocs := 3000
+	for i := range indexes {
+		batch := indexes[i].NewBatch()
+		for j := i; j < numDocs; j += len(indexes) {
+			err := batch.Index(fmt.Sprintf("%05d", j), map[string]interface{}{
+				"name": fmt.Sprintf("doc %d", j),
+				"n":    float64(j),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := indexes[i].Batch(batch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req := NewSearchRequest(NewMatchAllQuery())
+	req.Fields = []string{"name", "n"}
+
+	seen := make(map[string]struct{}, numDocs)
+	alias := NewIndexAlias(indexes...)
+	err := alias.SearchStream(context.Background(), req, func(hit *search.DocumentMatch) error {
+		if _, ok := seen[hit.ID]; ok {
+			return fmt.Errorf("duplicate hit %s", hit.ID)
+		}
+		seen[hit.ID] = struct{}{}
+		j, err := strconv.Atoi(hit.ID)
+		if err != nil {
+			return err
+		}
+		if hit.Fields["name"] != fmt.Sprintf("doc %d", j) {
+			return fmt.Errorf("unexpected name %v for %s", hit.Fields["name"], hit.ID)
+		}
+		if hit.Fields["n"] != float64(j) {
+			return fmt.Errorf("unexpected n %v for %s", hit.Fields["n"], hit.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != numDocs {
+		t.Errorf("expected %d hits, got %d", numDocs, len(seen))
+	}
+
+	// errors from the callback stop the stream
+	errStop := fmt.Errorf("stop")
+	var count int
+	err = alias.SearchStream(context.Background(), req, func(hit *search.DocumentMatch) error {
+		count++
+		if count == 10 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop || count != 10 {
+		t.Errorf("expected stream to stop after 10 hits, got %d hits and error %v", count, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = indexes[0].(StreamingIndex).SearchStream(ctx, req, func(hit *search.DocumentMatch) error {
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+}
+
+func TestSourceFilter(t *testing.T) {
+	source := `{"title":"t","author":{"name":"marty","email":"m@x"},` +
+		`"comments":[{"user":"a","text":"x"},{"user":"b"}],"tags":["p","q"]}`
+	tests := []struct {
+		filter   *SourceFilter
+		expected string
+	}{
+		{nil, source},
+		{NewSourceFilter(), source},
+		{NewSourceFilter("title"), `{"title":"t"}`},
+		{NewSourceFilter("author.name", "tags"), `{"author":{"name":"marty"},"tags":["p","q"]}`},
+		{NewSourceFilter("comments.text"), `{"comments":[{"text":"x"}]}`},
+		{NewSourceFilter("author.*"), `{"author":{"name":"marty","email":"m@x"}}`},
+		{NewSourceFilter("missing"), `{}`},
+		{&SourceFilter{Excludes: []string{"author.email", "comments"}},
+			`{"title":"t","author":{"name":"marty"},"tags":["p","q"]}`},
+		{&SourceFilter{Includes: []string{"author"}, Excludes: []string{"*.email"}},
+			`{"author":{"name":"marty"}}`},
+	}
+
+	for _, test := range tests {
+		actual, err := test.filter.apply([]byte(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var actualValue, expectedValue interface{}
+		if err = json.Unmarshal(actual, &actualValue); err != nil {
+			t.Fatal(err)
+		}
+		if err = json.Unmarshal([]byte(test.expected), &expectedValue); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actualValue, expectedValue) {
+			t.Errorf("filter %+v: expected %s, got %s", test.filter, test.expected, actual)
+		}
+	}
+}
+
+func TestStoreSource(t *testing.T) {
+	indexMapping := NewIndexMapping()
+	indexMapping.StoreSource = true
+
+	// the mapping setting survives serialization
+	buf, err := json.Marshal(indexMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexMapping = mapping.NewIndexMapping()
+	if err = json.Unmarshal(buf, indexMapping); err != nil {
+		t.Fatal(err)
+	}
+	if !indexMapping.StoreSource {
+		t.Fatalf("expected store source to be unmarshalled")
+	}
+
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+	idx, err := New(tmpIndexPath, indexMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	doc := map[string]interface{}{
+		"title":    "bleve",
+		"comments": []interface{}{map[string]interface{}{"user": "a", "text": "nice"}, map[string]interface{}{"user": "b", "text": "ok"}},
+	}
+	if err = idx.Index("a", doc); err != nil {
+		t.Fatal(err)
+	}
+
+	req := NewSearchRequest(NewMatchQuery("bleve"))
+	req.Fields = []string{"*"}
+	req.Source = NewSourceFilter("comments.user")
+	res, err := idx.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hits) != 1 {
+		t.Fatalf("expected 1 hit, got %d", len(res.Hits))
+	}
+	if string(res.Hits[0].Source) != `{"comments":[{"user":"a"},{"user":"b"}]}` {
+		t.Errorf("unexpected filtered source %s", res.Hits[0].Source)
+	}
+	if _, ok := res.Hits[0].Fields[document.SourceFieldName]; ok {
+		t.Errorf("expected source to be excluded from stored fields")
+	}
+
+	storedDoc, err := idx.Document("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := DocumentSource(storedDoc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sourceValue interface{}
+	if err = json.Unmarshal(source, &sourceValue); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sourceValue, doc) {
+		t.Errorf("expected source %v, got %v", doc, sourceValue)
+	}
+
+	// updates apply to the stored source, keeping arrays of objects intact
+	batch := idx.NewBatch()
+	if err = batch.Update("a", `{"title": "bleve search"}`); err != nil {
+		t.Fatal(err)
+	}
+	if err = idx.Batch(batch); err != nil {
+		t.Fatal(err)
+	}
+	storedDoc, err = idx.Document("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err = DocumentSource(storedDoc, NewSourceFilter("comments"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(source) != `{"comments":[{"text":"nice","user":"a"},{"text":"ok","user":"b"}]}` {
+		t.Errorf("unexpected source after update %s", source)
+	}
+
+	// reindex into an index mapping the title as a keyword
+	dstMapping := NewIndexMapping()
+	keywordMapping := NewKeywordFieldMapping()
+	dstMapping.DefaultMapping.AddFieldMappingsAt("title", keywordMapping)
+	dstPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, dstPath)
+	dst, err := New(dstPath, dstMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := dst.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+	result, err := Reindex(context.Background(), idx.(StreamingIndex), dst, NewMatchAllQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Matched != 1 || result.Applied != 1 || len(result.Failures) != 0 {
+		t.Errorf("unexpected reindex result %+v", result)
+	}
+	tq := NewTermQuery("bleve search")
+	tq.SetField("title")
+	res, err = dst.Search(NewSearchRequest(tq))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 1 {
+		t.Errorf("expected reindexed document to match keyword title, got %d hits", res.Total)
+	}
+}
+
+func TestSpanQueries(t *testing.T) {
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+	idx, err := New(tmpIndexPath, NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := map[string]string{
+		"1": "the quick brown fox jumps over the lazy dog",
+		"2": "the lazy dog jumps over the quick brown fox",
+		"3": "quick thinking saves the brown bear",
+		// many near spans, which are only found in minimal windows
+		"4": strings.Repeat("alpha beta gamma delta ", 60),
+	}
+	batch := idx.NewBatch()
+	for id, text := range docs {
+		if err = batch.Index(id, map[string]interface{}{"text": text}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = idx.Batch(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	term := func(t string) query.SpanQuery {
+		q := NewSpanTermQuery(t)
+		q.SetField("text")
+		return q
+	}
+	near := func(slop int, inOrder bool, clauses ...query.SpanQuery) *query.SpanNearQuery {
+		q := NewSpanNearQuery(clauses, slop)
+		q.SetInOrder(inOrder)
+		return q
+	}
+	spanNot := func(include, exclude query.SpanQuery, pre, post int) query.SpanQuery {
+		q := NewSpanNotQuery(include, exclude)
+		q.Pre, q.Post = pre, post
+		return q
+	}
+	parse := func(s string) query.Query {
+		q, err := query.ParseQuery([]byte(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return q
+	}
+	phrase := func(s string, slop int) query.Query {
+		q := NewMatchPhraseQuery(s)
+		q.SetField("text")
+		q.SetSlop(slop)
+		return q
+	}
+
+	tests := []struct {
+		name  string
+		query query.Query
+		ids   []string
+	}{
+		{"near in order", near(1, true, term("quick"), term("fox")), []string{"1", "2"}},
+		{"near out of order", near(1, true, term("fox"), term("quick")), nil},
+		{"near any order", near(1, false, term("fox"), term("quick")), []string{"1", "2"}},
+		{"near slop", near(3, true, term("quick"), term("brown")), []string{"1", "2", "3"}},
+		{"near or", near(0, false, NewSpanOrQuery([]query.SpanQuery{term("fox"), term("bear")}), term("brown")), []string{"1", "2", "3"}},
+		{"first", NewSpanFirstQuery(term("quick"), 2), []string{"1", "3"}},
+		{"not overlapping", spanNot(near(1, true, term("quick"), term("fox")), term("brown"), 0, 0), nil},
+		{"not elsewhere", spanNot(near(1, true, term("quick"), term("fox")), term("dog"), 0, 0), []string{"1", "2"}},
+		{"not post", spanNot(term("fox"), term("jumps"), 0, 1), []string{"2"}},
+		{"containing", NewSpanContainingQuery(near(2, false, term("lazy"), term("jumps")), term("dog")), []string{"2"}},
+		{"json", parse(`{"span_near":[{"span_term":"jumps","field":"text"},{"span_first":{"span_term":"lazy","field":"text"},"end":3}],"slop":2}`), []string{"2"}},
+		{"phrase slop", phrase("quick fox", 1), []string{"1", "2"}},
+		{"phrase slop out of order", phrase("fox quick", 1), nil},
+		{"phrase slop swapped", phrase("fox quick", 3), []string{"1", "2"}},
+		// the positions of stop words are kept as gaps
+		{"phrase slop gap", phrase("jumps the lazy", 1), []string{"1"}},
+		{"phrase slop gaps too wide", phrase("fox a a a jumps", 1), nil},
+		{"phrase slop gaps", phrase("fox a a a jumps", 3), []string{"1"}},
+		{"query string proximity", NewQueryStringQuery(`text:"fox quick"~3`), []string{"1", "2"}},
+		{"repeated phrase slop", phrase("alpha beta gamma delta", 1000), []string{"4"}},
+		{"repeated near in order", near(1000, true, term("delta"), term("gamma"), term("beta"), term("alpha")), []string{"4"}},
+		{"repeated query string proximity", NewQueryStringQuery(`text:"delta gamma beta alpha"~1000`), []string{"4"}},
+	}
+	for _, test := range tests {
+		req := NewSearchRequest(test.query)
+		req.SortBy([]string{"_id"})
+		req.IncludeLocations = true
+		res, err := idx.Search(req)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		var ids []string
+		for _, hit := range res.Hits {
+			ids = append(ids, hit.ID)
+			if len(hit.Locations["text"]) == 0 {
+				t.Errorf("%s: expected locations for hit %s", test.name, hit.ID)
+			}
+		}
+		if !reflect.DeepEqual(ids, test.ids) {
+			t.Errorf("%s: expected hits %v, got %v", test.name, test.ids, ids)
+		}
+	}
+
+	other := NewSpanTermQuery("fox")
+	other.SetField("other")
+	_, err = idx.Search(NewSearchRequest(NewSpanNearQuery([]query.SpanQuery{term("quick"), other}, 1)))
+	if err == nil {
+		t.Errorf("expected error for span clauses in different fields")
+	}
+	_, err = query.ParseQuery([]byte(`{"span_or":[{"term":"fox"}]}`))
+	if err == nil {
+		t.Errorf("expected error for span clause which is not a span query")
+	}
+}
+
+func TestIntervalsQuery(t *testing.T) {
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+	idx, err := New(tmpIndexPath, NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := map[string]string{
+		"1": "the quick brown fox jumps over the lazy dog",
+		"2": "the lazy dog jumps over the quick brown fox",
+		"3": "quick thinking saves the brown bear",
+		// many intervals, of which only the minimal are found
+		"4": strings.Repeat("alpha beta gamma delta ", 60),
+	}
+	batch := idx.NewBatch()
+	for id, text := range docs {
+		if err = batch.Index(id, map[string]interface{}{"text": text}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = idx.Batch(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	match := query.NewIntervalsMatchRule
+	allOf := func(ordered bool, maxGaps int, rules ...*query.IntervalsRule) *query.IntervalsRule {
+		r := query.NewIntervalsAllOfRule(rules...)
+		r.SetOrdered(ordered)
+		r.SetMaxGaps(maxGaps)
+		return r
+	}
+	filtered := func(r *query.IntervalsRule, f *query.IntervalsFilter) *query.IntervalsRule {
+		r.Filter = f
+		return r
+	}
+	followedBy := allOf(true, 3, match("quick"), query.NewIntervalsAnyOfRule(match("fox"), match("bear")))
+	unordered := match("fox quick")
+	unordered.SetMaxGaps(1)
+	ordered := match("fox quick")
+	ordered.SetOrdered(true)
+
+	tests := []struct {
+		name string
+		rule *query.IntervalsRule
+		ids  []string
+	}{
+		{"followed by any of", followedBy, []string{"1", "2"}},
+		{"followed by any of with more gaps", allOf(true, 4, match("quick"), query.NewIntervalsAnyOfRule(match("fox"), match("bear"))), []string{"1", "2", "3"}},
+		{"match unordered", unordered, []string{"1", "2"}},
+		{"match ordered", ordered, nil},
+		{"containing", filtered(match("quick fox"), &query.IntervalsFilter{Containing: match("brown")}), []string{"1", "2"}},
+		{"not containing", filtered(match("quick fox"), &query.IntervalsFilter{NotContaining: match("brown")}), nil},
+		{"contained by", filtered(match("brown"), &query.IntervalsFilter{ContainedBy: allOf(true, -1, match("quick"), match("fox"))}), []string{"1", "2"}},
+		{"not contained by", filtered(match("brown"), &query.IntervalsFilter{NotContainedBy: allOf(true, -1, match("quick"), match("fox"))}), []string{"3"}},
+		{"before", filtered(match("lazy"), &query.IntervalsFilter{Before: match("fox")}), []string{"2"}},
+		{"after", filtered(match("lazy"), &query.IntervalsFilter{After: match("fox")}), []string{"1"}},
+		{"stop words only", match("the"), nil},
+		{"repeated all of", query.NewIntervalsAllOfRule(match("delta"), match("gamma"), match("beta"), match("alpha")), []string{"4"}},
+		{"repeated all of ordered", allOf(true, -1, match("delta"), match("gamma"), match("beta"), match("alpha")), []string{"4"}},
+	}
+	for _, test := range tests {
+		q := NewIntervalsQuery(test.rule)
+		q.SetField("text")
+		req := NewSearchRequest(q)
+		req.SortBy([]string{"_id"})
+		req.IncludeLocations = true
+		res, err := idx.Search(req)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		var ids []string
+		for _, hit := range res.Hits {
+			ids = append(ids, hit.ID)
+			if len(hit.Locations["text"]) == 0 {
+				t.Errorf("%s: expected locations for hit %s", test.name, hit.ID)
+			}
+		}
+		if !reflect.DeepEqual(ids, test.ids) {
+			t.Errorf("%s: expected hits %v, got %v", test.name, test.ids, ids)
+		}
+	}
+
+	q, err := query.ParseQuery([]byte(`{"intervals":{"match":"jumps","filter":{"after":{"match":"dog"}}},"field":"text"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := idx.Search(NewSearchRequest(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hits) != 1 || res.Hits[0].ID != "2" {
+		t.Errorf("expected parsed intervals query to match document 2, got %v", res.Hits)
+	}
+
+	_, err = idx.Search(NewSearchRequest(NewIntervalsQuery(&query.IntervalsRule{Match: "fox", AnyOf: []*query.IntervalsRule{match("dog")}})))
+	if err == nil {
+		t.Errorf("expected error for intervals rule with more than one kind")
+	}
+}
+
+func TestMoreLikeThisQuery(t *testing.T) {
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+	idx, err := New(tmpIndexPath, NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := map[string]string{
+		"1": "golang concurrency with goroutines and channels, goroutines everywhere",
+		"2": "goroutines and channels make golang concurrency simple",
+		"3": "python scripts for data science",
+		"4": "channels on television",
+	}
+	batch := idx.NewBatch()
+	for id, text := range docs {
+		if err = batch.Index(id, map[string]interface{}{"text": text}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = idx.Batch(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	text := NewMoreLikeThisQuery("goroutines goroutines golang channels")
+	text.Fields = []string{"text"}
+	text.MinTermFreq = 1
+	text.MinDocFreq = 1
+
+	liked := NewMoreLikeThisDocumentsQuery([]string{"1"})
+	liked.MinTermFreq = 1
+	liked.MinDocFreq = 1
+
+	frequent := NewMoreLikeThisDocumentsQuery([]string{"1"})
+	frequent.MinDocFreq = 1
+
+	common := NewMoreLikeThisQuery("goroutines goroutines golang channels")
+	common.MinTermFreq = 1
+
+	tests := []struct {
+		name string
+		q    *query.MoreLikeThisQuery
+		ids  []string
+	}{
+		{"text", text, []string{"1", "2", "4"}},
+		{"liked documents excluded", liked, []string{"2", "4"}},
+		{"min term freq", frequent, []string{"2"}},
+		{"min doc freq", common, nil},
+	}
+	for _, test := range tests {
+		res, err := idx.Search(NewSearchRequest(test.q))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		var ids []string
+		for _, hit := range res.Hits {
+			ids = append(ids, hit.ID)
+		}
+		sort.Strings(ids)
+		if !reflect.DeepEqual(ids, test.ids) {
+			t.Errorf("%s: expected hits %v, got %v", test.name, test.ids, ids)
+		}
+	}
+
+	_, err = idx.Search(NewSearchRequest(NewMoreLikeThisQuery("")))
+	if err == nil {
+		t.Errorf("expected error for more like this query without text or ids")
+	}
+}
+
+func TestFunctionScoreQuery(t *testing.T) {
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+	indexMapping := NewIndexMapping()
+	indexMapping.DefaultMapping.AddFieldMappingsAt("likes", NewNumericFieldMapping())
+	indexMapping.DefaultMapping.AddFieldMappingsAt("published", NewDateTimeFieldMapping())
+	indexMapping.DefaultMapping.AddFieldMappingsAt("location", NewGeoPointFieldMapping())
+	idx, err := New(tmpIndexPath, indexMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := map[string]map[string]interface{}{
+		"a": {"kind": "post", "likes": 10, "published": "2024-01-01T00:00:00Z",
+			"location": map[string]interface{}{"lon": 0.0, "lat": 0.0}},
+		"b": {"kind": "post", "likes": 100, "published": "2024-01-10T00:00:00Z",
+			"location": map[string]interface{}{"lon": 1.0, "lat": 0.0}},
+		"c": {"kind": "page", "likes": 1, "published": "2023-06-01T00:00:00Z",
+			"location": map[string]interface{}{"lon": 0.1, "lat": 0.0}},
+		"d": {"kind": "page", "published": "2024-01-05T00:00:00Z"},
+	}
+	batch := idx.NewBatch()
+	for id, doc := range docs {
+		if err = batch.Index(id, doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = idx.Batch(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	replacing := func(functions ...*query.ScoreFunction) *query.FunctionScoreQuery {
+		q := NewFunctionScoreQuery(nil, functions...)
+		q.SetBoostMode("replace")
+		return q
+	}
+	fieldValueFactor := func(modifier string, missing float64) *query.ScoreFunction {
+		f := query.NewFieldValueFactorFunction("likes")
+		f.FieldValueFactor.SetModifier(modifier)
+		f.FieldValueFactor.SetMissing(missing)
+		return f
+	}
+	pageFilter := NewTermQuery("page")
+	pageFilter.SetField("kind")
+	pages := query.NewWeightFunction(3)
+	pages.SetFilter(pageFilter)
+	summed := replacing(pages, query.NewWeightFunction(2))
+	summed.SetScoreMode("sum")
+	popular := replacing(query.NewFieldValueFactorFunction("likes"))
+	popular.SetMinScore(10)
+
+	tests := []struct {
+		name string
+		q    *query.FunctionScoreQuery
+		ids  []string
+	}{
+		{"field value factor", replacing(fieldValueFactor("log1p", 0)), []string{"b", "a", "c", "d"}},
+		{"gauss numeric", replacing(query.NewGaussFunction("likes", 5, 10)), []string{"d", "c", "a", "b"}},
+		{"exp datetime", replacing(query.NewExpFunction("published", "2024-01-09T00:00:00Z", "5d")), []string{"b", "d", "a", "c"}},
+		{"linear geopoint", replacing(query.NewLinearFunction("location",
+			map[string]interface{}{"lon": 0.08, "lat": 0.0}, "20km")), []string{"d", "c", "a", "b"}},
+		{"filtered weights summed", summed, []string{"c", "d", "a", "b"}},
+		{"min score", popular, []string{"b", "a"}},
+	}
+	for _, test := range tests {
+		req := NewSearchRequest(test.q)
+		req.SortBy([]string{"-_score", "_id"})
+		res, err := idx.Search(req)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		// the query survives serialization, as when sent to remote indexes
+		data, err := json.Marshal(test.q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := query.ParseQuery(data)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		req.Query = parsed
+		res2, err := idx.Search(req)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		for _, r := range []*SearchResult{res, res2} {
+			var ids []string
+			for _, hit := range r.Hits {
+				ids = append(ids, hit.ID)
+			}
+			if !reflect.DeepEqual(ids, test.ids) {
+				t.Errorf("%s: expected hits %v, got %v", test.name, test.ids, ids)
+			}
+		}
+	}
+
+	posts := NewTermQuery("post")
+	posts.SetField("kind")
+	res, err := idx.Search(NewSearchRequest(posts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res2, err := idx.Search(NewSearchRequest(NewFunctionScoreQuery(posts, query.NewWeightFunction(2))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res2.Hits) != 2 || math.Abs(res2.Hits[0].Score-2*res.Hits[0].Score) > 1e-9 {
+		t.Errorf("expected scores multiplied by the weight, got %v and %v", res.Hits, res2.Hits)
+	}
+
+	random := replacing(query.NewRandomScoreFunction(42))
+	var first []string
+	for i := 0; i < 2; i++ {
+		res, err := idx.Search(NewSearchRequest(random))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, hit := range res.Hits {
+			ids = append(ids, hit.ID)
+			if hit.Score < 0 || hit.Score >= 1 {
+				t.Errorf("expected random score between 0 and 1, got %f", hit.Score)
+			}
+		}
+		if i > 0 && !reflect.DeepEqual(ids, first) {
+			t.Errorf("expected the same order for the same seed, got %v and %v", first, ids)
+		}
+		first = ids
+	}
+
+	// documents with the same value of the field score the same
+	byKind := query.NewRandomScoreFunction(42)
+	byKind.RandomScore.Field = "kind"
+	res, err = idx.Search(NewSearchRequest(replacing(byKind)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scores := make(map[string]float64)
+	for _, hit := range res.Hits {
+		scores[hit.ID] = hit.Score
+	}
+	if len(scores) != 4 || scores["a"] != scores["b"] || scores["c"] != scores["d"] ||
+		scores["a"] == scores["c"] {
+		t.Errorf("expected random scores by kind, got %v", scores)
+	}
+
+	invalid := replacing(query.NewWeightFunction(2))
+	invalid.SetScoreMode("median")
+	_, err = idx.Search(NewSearchRequest(invalid))
+	if err == nil {
+		t.Errorf("expected error for unknown score mode")
+	}
+}
+
+func TestPercolate(t *testing.T) {
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+	indexMapping := NewIndexMapping()
+	indexMapping.DefaultMapping.AddFieldMappingsAt("query", NewPercolatorFieldMapping())
+	idx, err := New(tmpIndexPath, indexMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	termQuery := func(term string) *query.TermQuery {
+		q := NewTermQuery(term)
+		q.SetField("title")
+		return q
+	}
+	queries := map[string]interface{}{
+		"fox":    map[string]interface{}{"match": "quick fox", "field": "title"},
+		"phrase": map[string]interface{}{"match_phrase": "lazy dog", "field": "title"},
+		"string": "+title:brown -title:cat",
+		"range":  map[string]interface{}{"min": 10, "max": 20, "field": "price"},
+		"none":   map[string]interface{}{"term": "elephant", "field": "title"},
+		"any": []interface{}{
+			map[string]interface{}{"term": "zebra", "field": "title"},
+			map[string]interface{}{"term": "dog", "field": "title"},
+		},
+		"go": termQuery("cat"),
+		// without a field, in the default search field
+		"all": "jumps",
+	}
+	batch := idx.NewBatch()
+	for id, q := range queries {
+		if err = batch.Index(id, map[string]interface{}{"query": q}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = idx.Batch(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	// queries are only candidates for documents having their terms
+	candidates, err := idx.Search(NewSearchRequest(NewTermQuery(query.PercolatorAnyTerm)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if candidates.Total != 0 {
+		t.Errorf("expected no query in the default field, got %d", candidates.Total)
+	}
+	anyTerm := NewTermQuery(query.PercolatorAnyTerm)
+	anyTerm.SetField("query")
+	candidates, err = idx.Search(NewSearchRequest(anyTerm))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates.Hits) != 1 || candidates.Hits[0].ID != "range" {
+		t.Errorf("expected only the range query to be a candidate for any document, got %v", candidates.Hits)
+	}
+
+	tests := []struct {
+		doc map[string]interface{}
+		ids []string
+	}{
+		{
+			doc: map[string]interface{}{"title": "the quick brown fox jumps over the lazy dog", "price": 15},
+			ids: []string{"all", "any", "fox", "phrase", "range", "string"},
+		},
+		{
+			doc: map[string]interface{}{"title": "a brown cat", "price": 5},
+			ids: []string{"go"},
+		},
+	}
+	for _, test := range tests {
+		ids, err := Percolate(idx, "query", test.doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ids, test.ids) {
+			t.Errorf("expected %v to match queries %v, got %v", test.doc, test.ids, ids)
+		}
+	}
+
+	PercolateBatchSize = 2
+	defer func() {
+		PercolateBatchSize = 1000
+	}()
+	ids, err := Percolate(idx, "query", tests[0].doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, tests[0].ids) {
+		t.Errorf("expected queries %v in batches, got %v", tests[0].ids, ids)
+	}
+}
+
+func TestTermsLookupQuery(t *testing.T) {
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+	idx, err := New(tmpIndexPath, NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+	usersIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, usersIndexPath)
+	users, err := New(usersIndexPath, NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := users.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := map[string]map[string]interface{}{
+		"p1": {"author": "alice", "rank": 1},
+		"p2": {"author": "bob", "rank": 2},
+		"p3": {"author": "carol", "rank": 3},
+		"p4": {"author": "dave", "rank": 4},
+		"u1": {"follows": []string{"alice", "carol", "erin"}, "ranks": []int{2, 4}},
+	}
+	batch := idx.NewBatch()
+	for id, doc := range docs {
+		if err = batch.Index(id, doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = idx.Batch(batch); err != nil {
+		t.Fatal(err)
+	}
+	if err = users.Index("u2", map[string]interface{}{"follows": "bob"}); err != nil {
+		t.Fatal(err)
+	}
+
+	lookup := func(id, path, field string) *query.TermsLookupQuery {
+		q := NewTermsLookupQuery(id, path)
+		q.SetField(field)
+		return q
+	}
+	otherIndex := lookup("u2", "follows", "author")
+	otherIndex.SetIndex(users.Name())
+	parsed, err := query.ParseQuery([]byte(`{"terms_lookup":{"id":"u1","path":"follows"},"field":"author"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		q    query.Query
+		ids  []string
+	}{
+		{"text", lookup("u1", "follows", "author"), []string{"p1", "p3"}},
+		{"numeric", lookup("u1", "ranks", "rank"), []string{"p2", "p4"}},
+		{"other index", otherIndex, []string{"p2"}},
+		{"missing document", lookup("u3", "follows", "author"), nil},
+		{"parsed", parsed, []string{"p1", "p3"}},
+	}
+	ctx := WithTermsLookupIndexes(context.Background(), users)
+	for _, test := range tests {
+		req := NewSearchRequest(test.q)
+		req.SortBy([]string{"_id"})
+		res, err := idx.SearchInContext(ctx, req)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		var ids []string
+		for _, hit := range res.Hits {
+			ids = append(ids, hit.ID)
+			if hit.Score != res.Hits[0].Score {
+				t.Errorf("%s: expected the same score for every hit, got %f and %f",
+					test.name, res.Hits[0].Score, hit.Score)
+			}
+		}
+		if !reflect.DeepEqual(ids, test.ids) {
+			t.Errorf("%s: expected hits %v, got %v", test.name, test.ids, ids)
+		}
+	}
+
+	limited := lookup("u1", "follows", "author")
+	limited.SetMaxTerms(2)
+	_, err = idx.Search(NewSearchRequest(limited))
+	if err == nil {
+		t.Errorf("expected error for more terms than the maximum")
+	}
+	unknown := lookup("u1", "follows", "author")
+	unknown.SetIndex("no-such-index")
+	_, err = idx.SearchInContext(ctx, NewSearchRequest(unknown))
+	if err == nil {
+		t.Errorf("expected error for unknown index")
+	}
+	// indexes are only found when provided with the search
+	_, err = idx.Search(NewSearchRequest(otherIndex))
+	if err == nil {
+		t.Errorf("expected error for index not provided with the search")
+	}
+}
+
+func TestRuntimeFields(t *testing.T) {
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+	indexMapping := NewIndexMapping()
+	indexMapping.DefaultMapping.AddFieldMappingsAt("price", NewNumericFieldMapping())
+	indexMapping.DefaultMapping.AddFieldMappingsAt("quantity", NewNumericFieldMapping())
+	indexMapping.DefaultMapping.AddFieldMappingsAt("created", NewDateTimeFieldMapping())
+	nameMapping := NewTextFieldMapping()
+	nameMapping.Analyzer = keyword.Name
+	indexMapping.DefaultMapping.AddFieldMappingsAt("name", nameMapping)
+	idx, err := New(tmpIndexPath, indexMapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := map[string]map[string]interface{}{
+		"a": {"price": 2, "quantity": 5, "created": "2026-01-05T10:00:00Z",
+			"name": "Ada Lovelace", "title": "Notes on the Engine"},
+		"b": {"price": 10, "quantity": 2, "created": "2026-01-06T10:00:00Z"},
+		"c": {"price": 3, "quantity": 3, "created": "2026-01-12T10:00:00Z"},
+		"d": {"price": 1, "created": "2026-01-07T10:00:00Z"},
+	}
+	batch := idx.NewBatch()
+	for id, doc := range docs {
+		if err = batch.Index(id, doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = idx.Batch(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	req := NewSearchRequest(NewMatchAllQuery())
+	req.AddRuntimeField("total", "price * quantity")
+	req.AddRuntimeField("day", "dayName(created)")
+	req.Fields = []string{"total", "day"}
+	req.SortBy([]string{"-total"})
+	req.AddFacet("days", NewFacetRequest("day", 5))
+	totals := NewFacetRequest("total", 2)
+	ten := 10.0
+	totals.AddNumericRange("cheap", nil, &ten)
+	totals.AddNumericRange("dear", &ten, nil)
+	req.AddFacet("totals", totals)
+
+	res, err := idx.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, hit := range res.Hits {
+		ids = append(ids, hit.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"b", "a", "c", "d"}) {
+		t.Errorf("expected hits sorted by total, got %v", ids)
+	}
+	if !reflect.DeepEqual(res.Hits[0].Fields, map[string]interface{}{"total": 20.0, "day": "Tuesday"}) {
+		t.Errorf("unexpected fields %v", res.Hits[0].Fields)
+	}
+	if !reflect.DeepEqual(res.Hits[3].Fields, map[string]interface{}{"day": "Wednesday"}) {
+		t.Errorf("unexpected fields %v", res.Hits[3].Fields)
+	}
+	days := map[string]int{}
+	for _, term := range res.Facets["days"].Terms.Terms() {
+		days[term.Term] = term.Count
+	}
+	if !reflect.DeepEqual(days, map[string]int{"Monday": 2, "Tuesday": 1, "Wednesday": 1}) {
+		t.Errorf("unexpected day facet %v", days)
+	}
+	ranges := map[string]int{}
+	for _, nr := range res.Facets["totals"].NumericRanges {
+		ranges[nr.Name] = nr.Count
+	}
+	if !reflect.DeepEqual(ranges, map[string]int{"cheap": 1, "dear": 2}) {
+		t.Errorf("unexpected total facet %v", ranges)
+	}
+
+	var parsed SearchRequest
+	err = json.Unmarshal([]byte(`{
+		"query": {"match_all": {}},
+		"runtime_fields": {"weekday": {"expression": "dayOfWeek(created) <= 5"}},
+		"sort": ["weekday", "_id"],
+		"fields": ["*"]
+	}`), &parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = idx.Search(&parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hits) != 4 || res.Hits[0].Fields["weekday"] != true {
+		t.Errorf("unexpected hits %v", res.Hits)
+	}
+
+	// text fields have their smallest term, which is their
+	// value only when analysed by the keyword analyzer
+	req = NewSearchRequest(NewDocIDQuery([]string{"a"}))
+	req.AddRuntimeField("label", "upper(name)")
+	req.AddRuntimeField("word", "upper(title)")
+	req.Fields = []string{"label", "word"}
+	res, err = idx.Search(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hits) != 1 || !reflect.DeepEqual(res.Hits[0].Fields,
+		map[string]interface{}{"label": "ADA LOVELACE", "word": "ENGINE"}) {
+		t.Errorf("unexpected text fields %v", res.Hits)
+	}
+
+	req = NewSearchRequest(NewMatchAllQuery())
+	req.AddRuntimeField("bad", "price * created")
+	if _, err = idx.Search(req); err == nil {
+		t.Errorf("expected error for invalid expression")
+	}
+	req.RuntimeFields["bad"].Expression = ""
+	if err = req.Validate(); err == nil {
+		t.Errorf("expected error for missing expression")
+	}
+}
+
+func TestExistsQueryUpsidedown(t *testing.T) {
+	testExistsQuery(t, upsidedown.Name)
+}
+
+// booleanQueryString returns a query string query
+// with the boolean syntax enabled.
+func booleanQueryString(q string) *query.QueryStringQuery {
+	rv := NewQueryStringQuery(q)
+	rv.SetBooleanSyntax(true)
+	return rv
+}
+
+func TestExistsQueryScorch(t *testing.T) {
+	testExistsQuery(t, scorch.Name)
+}
+
+func testExistsQuery(t *testing.T, indexName string) {
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+	idx, err := NewUsing(tmpIndexPath, NewIndexMapping(), indexName, Config.DefaultKVStore, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	// indexed separately, so that scorch has several segments
+	docs := []struct {
+		id  string
+		doc map[string]interface{}
+	}{
+		{"a", map[string]interface{}{"name": "apple", "price": 1.5, "created": "2026-01-05T10:00:00Z"}},
+		{"b", map[string]interface{}{"name": "banana", "organic": true}},
+		{"c", map[string]interface{}{"price": 3}},
+		{"d", map[string]interface{}{"name": "date", "price": 4}},
+		{"e", map[string]interface{}{"other": "x"}},
+	}
+	for _, d := range docs {
+		if err = idx.Index(d.id, d.doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = idx.Delete("d"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		q    query.Query
+		want []string
+	}{
+		{q: NewExistsQuery("name"), want: []string{"a", "b"}},
+		{q: NewExistsQuery("price"), want: []string{"a", "c"}},
+		{q: NewExistsQuery("created"), want: []string{"a"}},
+		{q: NewExistsQuery("organic"), want: []string{"b"}},
+		{q: NewExistsQuery("missing"), want: nil},
+		{q: NewQueryStringQuery("_exists_:price -_exists_:name"), want: []string{"c"}},
+		{q: booleanQueryString("price:[* TO *]"), want: []string{"a", "c"}},
+		{q: func() query.Query {
+			q := NewBooleanQuery()
+			q.AddMust(NewMatchAllQuery())
+			q.AddMustNot(NewExistsQuery("price"))
+			return q
+		}(), want: []string{"b", "e"}},
+	}
+	for _, test := range tests {
+		req := NewSearchRequest(test.q)
+		req.SortBy([]string{"_id"})
+		res, err := idx.Search(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, hit := range res.Hits {
+			ids = append(ids, hit.ID)
+			if hit.Score != 1 {
+				t.Errorf("%v: expected constant score, got %f", test.q, hit.Score)
+			}
+		}
+		if !reflect.DeepEqual(ids, test.want) {
+			t.Errorf("%v: expected %v, got %v", test.q, test.want, ids)
+		}
+	}
+}
+
+func TestNumericAndDateTermsQueries(t *testing.T) {
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+	idx, err := New(tmpIndexPath, NewIndexMapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := map[string]map[string]interface{}{
+		"a": {"rating": 1, "created": "2026-01-05T10:00:00Z", "code": "5", "open": true},
+		"b": {"rating": 5.5, "created": "2026-01-06T10:00:00Z", "open": false},
+		"c": {"rating": 9, "created": "2026-01-05T10:00:01Z", "open": true},
+		"d": {"rating": []interface{}{2, 9}, "created": "2026-01-07T10:00:00Z"},
+	}
+	for id, doc := range docs {
+		if err = idx.Index(id, doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		q    query.Query
+		want []string
+	}{
+		{q: func() query.Query {
+			q := NewNumericTermsQuery([]float64{1, 9, 100})
+			q.SetField("rating")
+			return q
+		}(), want: []string{"a", "c", "d"}},
+		{q: func() query.Query {
+			q := NewNumericTermsQuery([]float64{5.5})
+			q.SetField("rating")
+			return q
+		}(), want: []string{"b"}},
+		{q: func() query.Query {
+			q := NewNumericTermsQuery([]float64{5})
+			q.SetField("rating")
+			return q
+		}(), want: nil},
+		{q: func() query.Query {
+			q := NewDateTermsQuery([]string{"2026-01-05T10:00:00Z", "2026-01-07T10:00:00Z"})
+			q.SetField("created")
+			return q
+		}(), want: []string{"a", "d"}},
+		{q: func() query.Query {
+			q := NewBoolTermsQuery([]bool{true})
+			q.SetField("open")
+			return q
+		}(), want: []string{"a", "c"}},
+		{q: func() query.Query {
+			q := NewBoolTermsQuery([]bool{false, true})
+			q.SetField("open")
+			return q
+		}(), want: []string{"a", "b", "c"}},
+		{q: booleanQueryString("rating:(1 OR 5.5)"), want: []string{"a", "b"}},
+		{q: booleanQueryString("code:(5 OR 9)"), want: []string{"a"}},
+		{q: func() query.Query {
+			q, err := query.ParseQuery([]byte(`{"query": "rating:(5.5 OR 9)", "boolean_syntax": true}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return q
+		}(), want: []string{"b", "c", "d"}},
+	}
+	for _, test := range tests {
+		req := NewSearchRequest(test.q)
+		req.SortBy([]string{"_id"})
+		res, err := idx.Search(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, hit := range res.Hits {
+			ids = append(ids, hit.ID)
+		}
+		if !reflect.DeepEqual(ids, test.want) {
+			t.Errorf("%v: expected %v, got %v", test.q, test.want, ids)
+		}
+	}
+
+	invalid := NewDateTermsQuery([]string{"not a date"})
+	invalid.SetField("created")
+	if _, err = idx.Search(NewSearchRequest(invalid)); err == nil {
+		t.Errorf("expected error for invalid date")
+	}
+}
+
+func TestSynonymGraphFilterUpsidedown(t *testing.T) {
+	testSynonymGraphFilter(t, upsidedown.Name)
+}
+
+func TestSynonymGraphFilterScorch(t *testing.T) {
+	testSynonymGraphFilter(t, scorch.Name)
+}
+
+func testSynonymGraphFilter(t *testing.T, indexName string) {
+	tmpIndexPath := createTmpIndexPath(t)
+	defer cleanupTmpIndexPath(t, tmpIndexPath)
+
+	idxMapping := NewIndexMapping()
+	if err := idxMapping.AddCustomTokenFilter("city_synonyms", map[string]interface{}{
+		"type":     synonym.Name,
+		"synonyms": []interface{}{"ny, new york"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := idxMapping.AddCustomAnalyzer("synonyms", map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     "unicode",
+		"token_filters": []interface{}{lowercase.Name, "city_synonyms"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := idxMapping.AddCustomAnalyzer("plain", map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     "unicode",
+		"token_filters": []interface{}{lowercase.Name},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	idxMapping.DefaultAnalyzer = "plain"
+	// synonyms added when indexing, as well as when querying
+	synonymous := mapping.NewTextFieldMapping()
+	synonymous.Analyzer = "synonyms"
+	idxMapping.DefaultMapping.AddFieldMappingsAt("synonymous", synonymous)
+
+	idx, err := NewUsing(tmpIndexPath, idxMapping, indexName, Config.DefaultKVStore, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := idx.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	docs := map[string]string{
+		"a": "lunch in New York city",
+		"b": "lunch in NY city",
+		"c": "new jersey city york",
+	}
+	for id, text := range docs {
+		err = idx.Index(id, map[string]interface{}{"plain": text, "synonymous": text})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	phrase := func(text, field string, slop int) query.Query {
+		q := NewMatchPhraseQuery(text)
+		q.SetField(field)
+		q.Analyzer = "synonyms"
+		q.Slop = slop
+		return q
+	}
+	tests := []struct {
+		q    query.Query
+		want []string
+	}{
+		{q: phrase("ny city", "synonymous", 0), want: []string{"a", "b"}},
+		{q: phrase("in new york city", "synonymous", 0), want: []string{"a", "b"}},
+		{q: phrase("ny city", "plain", 0), want: []string{"a", "b"}},
+		{q: phrase("in new york", "plain", 0), want: []string{"a", "b"}},
+		{q: phrase("lunch ny", "plain", 1), want: []string{"a", "b"}},
+		{q: phrase("new jersey", "synonymous", 0), want: []string{"c"}},
+		{q: func() query.Query {
+			q := NewMatchQuery("ny lunch")
+			q.SetField("plain")
+			q.Analyzer = "synonyms"
+			q.SetOperator(query.MatchQueryOperatorAnd)
+			return q
+		}(), want: []string{"a", "b"}},
+	}
+	for _, test := range tests {
+		req := NewSearchRequest(test.q)
+		req.SortBy([]string{"_id"})
+		res, err := idx.Search(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, hit := range res.Hits {
+			ids = append(ids, hit.ID)
+		}
+		if !reflect.DeepEqual(ids, test.want) {
+			t.Errorf("%v: expected %v, got %v", test.q, test.want, ids)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/blevesearch/bleve/v2/document"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/blevesearch/bleve/v2/util"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// SourceFilter requests the original source of documents, stored when
+// the index mapping enables StoreSource.  Includes and Excludes are
+// dotted paths into the source, whose elements may use the wildcards
+// supported by path.Match.  A path selects the value at that path along
+// with everything beneath it.  When Includes is empty the whole source
+// is included, and Excludes are removed from what is included.
+type SourceFilter struct {
+	Includes []string `json:"includes,omitempty"`
+	Excludes []string `json:"excludes,omitempty"`
+}
+
+// NewSourceFilter creates a SourceFilter returning
+// the source including only the given paths, or the
+// whole source when none are given.
+func NewSourceFilter(includes ...string) *SourceFilter {
+	return &SourceFilter{
+		Includes: includes,
+	}
+}
+
+// DocumentSource returns the stored source of the document, such as one
+// returned by Index.Document, filtered as requested by filter, which may
+// be nil to return the whole source.  It returns nil when the document
+// has no stored source.
+func DocumentSource(doc index.Document, filter *SourceFilter) (json.RawMessage, error) {
+	var source []byte
+	var err error
+	doc.VisitFields(func(field index.Field) {
+		if field.Name() == document.SourceFieldName && source == nil && err == nil {
+			source, err = document.DecodeSource(field)
+		}
+	})
+	if err != nil || source == nil {
+		return nil, err
+	}
+	return filter.apply(source)
+}
+
+func (f *SourceFilter) apply(source []byte) (json.RawMessage, error) {
+	if f == nil || (len(f.Includes) == 0 && len(f.Excludes) == 0) {
+		return source, nil
+	}
+	var value interface{}
+	err := util.UnmarshalJSON(source, &value)
+	if err != nil {
+		return nil, err
+	}
+	sf := &sourceFilter{
+		includes: splitSourcePaths(f.Includes),
+		excludes: splitSourcePaths(f.Excludes),
+	}
+	filtered, ok := sf.filter(value, nil, len(sf.includes) == 0)
+	if !ok {
+		filtered = map[string]interface{}{}
+	}
+	return util.MarshalJSON(filtered)
+}
+
+func splitSourcePaths(paths []string) [][]string {
+	rv := make([][]string, 0, len(paths))
+	for _, p := range paths {
+		rv = append(rv, strings.Split(p, "."))
+	}
+	return rv
+}
+
+type sourceFilter struct {
+	includes [][]string
+	excludes [][]string
+}
+
+// filter returns the value at the path with excluded paths removed,
+// and, unless the value is already included, only the included paths
+// kept.  It reports false when nothing of the value remains.
+func (f *sourceFilter) filter(value interface{}, at []string, included bool) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		rv := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			childPath := append(at[:len(at):len(at)], k)
+			if f.matchAny(f.excludes, childPath) {
+				continue
+			}
+			childIncluded := included || f.matchAny(f.includes, childPath)
+			if !childIncluded && !f.prefixOfAny(f.includes, childPath) {
+				continue
+			}
+			if filtered, ok := f.filter(child, childPath, childIncluded); ok {
+				rv[k] = filtered
+			}
+		}
+		// an empty object is only kept when it was itself included
+		return rv, len(rv) > 0 || included
+	case []interface{}:
+		// arrays are transparent to paths, applying them to each element
+		rv := make([]interface{}, 0, len(v))
+		for _, elem := range v {
+			if filtered, ok := f.filter(elem, at, included); ok {
+				rv = append(rv, filtered)
+			}
+		}
+		return rv, len(rv) > 0 || included
+	default:
+		return value, included
+	}
+}
+
+// matchAny reports whether any of the paths selects the given
+// path, being the path itself or one of its ancestors.
+func (f *sourceFilter) matchAny(paths [][]string, at []string) bool {
+	for _, p := range paths {
+		if len(p) <= len(at) && matchSourcePath(p, at[:len(p)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixOfAny reports whether the given path is an ancestor
+// of a path selected by any of the paths.
+func (f *sourceFilter) prefixOfAny(paths [][]string, at []string) bool {
+	for _, p := range paths {
+		if len(at) < len(p) && matchSourcePath(p[:len(at)], at) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSourcePath(pattern, at []string) bool {
+	for i := range pattern {
+		if ok, _ := path.Match(pattern[i], at[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Reindex indexes the stored source of every document in src matching
+// the query into dst, typically an index created with an updated mapping.
+// The documents are indexed in batches of at most ByQueryBatchSize, and
+// documents without a stored source are reported as failures.  Cancelling
+// the context stops any further batches from being indexed, and returns
+// the result so far along with the context error.
+func Reindex(ctx context.Context, src StreamingIndex, dst Index, q query.Query) (*ByQueryResult, error) {
+	start := time.Now()
+
+	rv := &ByQueryResult{}
+	batch := newByQueryBatch(dst, rv)
+
+	req := NewSearchRequest(q)
+	req.Source = NewSourceFilter()
+	err := src.SearchStream(ctx, req, func(hit *search.DocumentMatch) error {
+		rv.Matched++
+		if hit.Source == nil {
+			rv.Failures = append(rv.Failures, &ByQueryFailure{ID: hit.ID, Err: ErrorSourceNotStored})
+			return nil
+		}
+		var data interface{}
+		err := util.UnmarshalJSON(hit.Source, &data)
+		if err == nil {
+			err = batch.batch.Index(hit.ID, data)
+		}
+		if err != nil {
+			rv.Failures = append(rv.Failures, &ByQueryFailure{ID: hit.ID, Err: err})
+			return nil
+		}
+		return batch.added(hit.ID)
+	})
+	if err == nil {
+		err = batch.finish(ctx)
+	}
+	rv.Took = time.Since(start)
+	return rv, err
+}
@@ -0,0 +1,41 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2/search"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// WithTermsLookupIndexes returns a context for searching with terms
+// lookup queries which name any of the indexes, by their Name, as the
+// index to look up their document in.
+func WithTermsLookupIndexes(ctx context.Context, indexes ...Index) context.Context {
+	byName := make(map[string]Index, len(indexes))
+	for _, i := range indexes {
+		byName[i.Name()] = i
+	}
+	return context.WithValue(ctx, search.TermsLookupDocumentKey,
+		search.TermsLookupDocumentCallbackFn(func(indexName, id string) (index.Document, error) {
+			i, ok := byName[indexName]
+			if !ok {
+				return nil, fmt.Errorf("no index named '%s' for terms lookup", indexName)
+			}
+			return i.Document(id)
+		}))
+}
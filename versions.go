@@ -0,0 +1,62 @@
+//  Copyright (c) 2026 Couchbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 		http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bleve
+
+import (
+	"github.com/blevesearch/bleve/v2/document"
+	index "github.com/blevesearch/bleve_index_api"
+)
+
+// VersionConflictError is returned when executing a batch whose
+// operations' preconditions do not hold, none of the batch is applied.
+type VersionConflictError = document.VersionConflictError
+
+// versionedIndex is implemented by index implementations
+// supporting preconditions on batch operations.
+type versionedIndex interface {
+	BatchWithPreconditions(batch *index.Batch, preconditions map[string]document.Precondition) error
+}
+
+// documentVersionReader is implemented by index readers
+// of indexes tracking document versions.
+type documentVersionReader interface {
+	DocumentVersion(id index.IndexInternalID) (uint64, bool, error)
+}
+
+// DocumentVersion returns the version of a document, such as one
+// returned by Index.Document, and whether it has a version.  Scorch
+// indexes track document versions when created with the "versions"
+// config option set to true, in which case every batch executed is
+// assigned the next version in sequence, which is also returned in
+// search hits.
+func DocumentVersion(doc index.Document) (uint64, bool) {
+	var rv uint64
+	var ok bool
+	doc.VisitFields(func(field index.Field) {
+		if field.Name() == document.VersionFieldName {
+			version, err := document.DecodeVersion(field.Value())
+			if err == nil {
+				rv, ok = version, true
+			}
+		}
+	})
+	return rv, ok
+}
+
+// isMetadataField reports whether the stored field is maintained by
+// bleve itself, rather than mapped from the document.
+func isMetadataField(name string) bool {
+	return name == document.SourceFieldName || name == document.VersionFieldName
+}